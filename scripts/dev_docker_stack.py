@@ -6,6 +6,7 @@ from __future__ import annotations
 
 import argparse
 import errno
+import secrets
 import shutil
 import socket
 import subprocess
@@ -227,7 +228,7 @@ def up(args: argparse.Namespace) -> None:
                 "-e",
                 "JWT_EXP=604800",
                 "-e",
-                "JWT_SECRET=dev-secret-change-me",
+                f"JWT_SECRET={secrets.token_hex(32)}",
                 "-p",
                 f"{ports.server}:8000",
                 SERVER_IMAGE,
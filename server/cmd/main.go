@@ -5,7 +5,19 @@ import (
 	"VyacheslavKuchumov/test-backend/config"
 	"VyacheslavKuchumov/test-backend/db"
 	_ "VyacheslavKuchumov/test-backend/docs"
+	"VyacheslavKuchumov/test-backend/logging"
+	"VyacheslavKuchumov/test-backend/migrator"
+	"VyacheslavKuchumov/test-backend/tracing"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"log"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 // @title Task Tracker API
@@ -17,13 +29,87 @@ import (
 // @in header
 // @name Authorization
 func main() {
-	db, err := db.NewPostgresStorage(config.Envs)
+	args := os.Args[1:]
+	if len(args) > 0 && args[0] == "config" {
+		if err := configCommand(args[1:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, "config:", err)
+			os.Exit(2)
+		}
+		return
+	}
+
+	cfg, err := config.Load(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err == nil {
+		err = cfg.ValidateServer()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid configuration:", err)
+		os.Exit(2)
+	}
+	logger, err := logging.New(os.Stdout, cfg.LogLevel, cfg.LogFormat)
 	if err != nil {
 		log.Fatal(err)
 	}
+	slog.SetDefault(logger)
 
-	srv := server.NewServer(config.Envs.Port, db)
-	if err := srv.Run(); err != nil {
-		log.Fatal(err)
+	if err := run(cfg); err != nil {
+		slog.Error("server failed", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("server stopped")
+}
+
+func run(cfg config.Config) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	shutdownTracing, err := tracing.Setup(ctx, cfg.TracingExporter, cfg.OTLPEndpoint, cfg.ServiceName)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			slog.Error("failed to flush traces", "error", err)
+		}
+	}()
+
+	db, err := db.Open(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if cfg.AutoMigrate {
+		slog.Info("applying pending migrations")
+		if err := migrator.Up(ctx, db, cfg.DBDriver); err != nil {
+			return fmt.Errorf("auto-migrate: %w", err)
+		}
+	}
+
+	srv := server.NewServer(cfg, db)
+	return srv.Run(ctx)
+}
+
+// configCommand implements "server config print [flags]", which prints the
+// effective configuration as YAML with secrets redacted. The flags are the
+// same as the server's, so the output shows what a run with them would use.
+func configCommand(args []string, out io.Writer) error {
+	if len(args) == 0 || args[0] != "print" {
+		return errors.New("usage: server config print [flags]")
+	}
+
+	cfg, err := config.Load(args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err == nil {
+		err = cfg.ValidateServer()
+	}
+	if err != nil {
+		return err
 	}
+	return cfg.Redacted().WriteYAML(out)
 }
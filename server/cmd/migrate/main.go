@@ -3,53 +3,292 @@ package main
 import (
 	"VyacheslavKuchumov/test-backend/config"
 	"VyacheslavKuchumov/test-backend/db"
-	"log"
+	"VyacheslavKuchumov/test-backend/migrator"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
 	"os"
+	"strconv"
 
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
+const usage = `Usage: migrate [flags] <command> [argument]
+
+Commands:
+  status      show the applied version, dirty flag and pending migrations
+  version     print the applied version
+  up [N]      apply the next N migrations (all pending when N is omitted)
+  down [N]    roll back N migrations (all when N is omitted)
+  goto V      migrate up or down to version V
+  force V     set the version to V and clear the dirty flag without running SQL
+  verify      compare the live schema with the expected snapshot and report drift
+  snapshot    print the live schema in snapshot format
+
+Flags:
+`
+
 func main() {
-	db, err := db.NewPostgresStorage(config.Envs)
-	if err != nil {
-		log.Fatal(err)
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "print the migrations that would run without applying them")
+	loadConfig := config.RegisterFlags(flags)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("missing command")
+	}
+
+	cmd, cmdArgs := flags.Arg(0), flags.Args()[1:]
+	if err := checkArgs(cmd, cmdArgs); err != nil {
+		return err
 	}
 
-	driver, err := postgres.WithInstance(db, &postgres.Config{})
+	cfg, err := loadConfig()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://cmd/migrate/migrations",
-		"postgres",
-		driver,
-	)
+	database, err := db.Open(context.Background(), cfg)
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+	defer database.Close()
+
+	switch cmd {
+	case "verify", "snapshot":
+		if cfg.DBDriver != config.DriverPostgres {
+			return fmt.Errorf("%s is only supported with DB_DRIVER=%s", cmd, config.DriverPostgres)
+		}
+	}
+
+	switch cmd {
+	case "verify":
+		return verify(database, out)
+	case "snapshot":
+		lines, err := migrator.Snapshot(context.Background(), database)
+		if err != nil {
+			return err
+		}
+		fmt.Fprint(out, snapshotHeader)
+		for _, line := range lines {
+			fmt.Fprintln(out, line)
+		}
+		return nil
 	}
 
-	cmd := os.Args[(len(os.Args) - 1)]
+	m, err := migrator.New(context.Background(), database, cfg.DBDriver)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
 
-	if cmd == "force" {
-		// Force a specific version (usually 1 to mark as clean)
-		version := 1
+	switch cmd {
+	case "status":
+		return status(m, out)
+	case "version":
+		version, dirty, ok, err := m.CurrentVersion()
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Fprintln(out, "no migrations applied")
+			return nil
+		}
+		fmt.Fprintf(out, "%d%s\n", version, dirtySuffix(dirty))
+		return nil
+	case "force":
+		version, _ := strconv.Atoi(cmdArgs[0])
+		if *dryRun {
+			fmt.Fprintf(out, "would force version to %d\n", version)
+			return nil
+		}
 		if err := m.Force(version); err != nil {
-			log.Fatal(err)
+			return err
 		}
-		log.Printf("Forced version to %d\n", version)
-		return
+		fmt.Fprintf(out, "forced version to %d\n", version)
+		return nil
+	default:
+		return migrateSteps(m, cmd, cmdArgs, *dryRun, out)
 	}
-	if cmd == "up" {
-		if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-			log.Fatal(err)
+}
+
+// checkArgs validates the command and its argument before connecting to the
+// database, so typos fail fast.
+func checkArgs(cmd string, args []string) error {
+	switch cmd {
+	case "status", "version", "verify", "snapshot":
+		if len(args) != 0 {
+			return fmt.Errorf("%s takes no arguments", cmd)
 		}
+	case "up", "down":
+		if len(args) > 1 {
+			return fmt.Errorf("%s takes at most one argument", cmd)
+		}
+		if len(args) == 1 {
+			if n, err := strconv.Atoi(args[0]); err != nil || n < 1 {
+				return fmt.Errorf("%s: step count must be a positive integer, got %q", cmd, args[0])
+			}
+		}
+	case "goto", "force":
+		if len(args) != 1 {
+			return fmt.Errorf("%s requires a version", cmd)
+		}
+		if n, err := strconv.Atoi(args[0]); err != nil || n < 0 {
+			return fmt.Errorf("%s: version must be a non-negative integer, got %q", cmd, args[0])
+		}
+	default:
+		return fmt.Errorf("unknown command %q", cmd)
 	}
-	if cmd == "down" {
-		if err := m.Down(); err != nil && err != migrate.ErrNoChange {
-			log.Fatal(err)
+	return nil
+}
+
+func status(m *migrator.Migrator, out io.Writer) error {
+	version, dirty, ok, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	latest, err := latestVersion(m)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		fmt.Fprintf(out, "current: %d%s\n", version, dirtySuffix(dirty))
+	} else {
+		fmt.Fprintln(out, "current: none")
+	}
+	fmt.Fprintf(out, "latest:  %d\n", latest)
+
+	pending, err := migrator.PlanUp(m.Source, version, ok, 0)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Fprintln(out, "pending: none")
+		return nil
+	}
+	fmt.Fprintf(out, "pending: %d\n", len(pending))
+	for _, step := range pending {
+		fmt.Fprintf(out, "  %s\n", step)
+	}
+	return nil
+}
+
+const snapshotHeader = `# Expected schema after all embedded migrations. One line per column,
+# constraint and index; order does not matter. See migrator.Snapshot.
+
+`
+
+func verify(database *sql.DB, out io.Writer) error {
+	drift, err := migrator.Verify(context.Background(), database)
+	if err != nil {
+		return err
+	}
+	if drift.Empty() {
+		fmt.Fprintln(out, "schema matches snapshot")
+		return nil
+	}
+
+	for _, line := range drift.Missing {
+		fmt.Fprintf(out, "- %s\n", line)
+	}
+	for _, line := range drift.Unexpected {
+		fmt.Fprintf(out, "+ %s\n", line)
+	}
+	return fmt.Errorf("schema drift: %d missing, %d unexpected", len(drift.Missing), len(drift.Unexpected))
+}
+
+func migrateSteps(m *migrator.Migrator, cmd string, args []string, dryRun bool, out io.Writer) error {
+	version, dirty, ok, err := m.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	if dirty {
+		return fmt.Errorf("database is dirty at version %d; fix it and run force first", version)
+	}
+
+	n := 0
+	if len(args) == 1 {
+		n, _ = strconv.Atoi(args[0])
+	}
+
+	var steps []migrator.Step
+	switch cmd {
+	case "up":
+		steps, err = migrator.PlanUp(m.Source, version, ok, n)
+	case "down":
+		steps, err = migrator.PlanDown(m.Source, version, ok, n)
+	case "goto":
+		steps, err = migrator.PlanGoto(m.Source, version, ok, uint(n))
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(steps) == 0 {
+		fmt.Fprintln(out, "no change")
+		return nil
+	}
+	verb := "applying"
+	if dryRun {
+		verb = "would apply"
+	}
+	for _, step := range steps {
+		fmt.Fprintf(out, "%s %s\n", verb, step)
+	}
+	if dryRun {
+		return nil
+	}
+
+	switch cmd {
+	case "up":
+		if n == 0 {
+			err = m.Up()
+		} else {
+			err = m.Steps(len(steps))
+		}
+	case "down":
+		if n == 0 {
+			err = m.Down()
+		} else {
+			err = m.Steps(-len(steps))
 		}
+	case "goto":
+		err = m.Migrate.Migrate(uint(n))
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+func latestVersion(m *migrator.Migrator) (uint, error) {
+	steps, err := migrator.PlanUp(m.Source, 0, false, 0)
+	if err != nil || len(steps) == 0 {
+		return 0, err
+	}
+	return steps[len(steps)-1].Version, nil
+}
+
+func dirtySuffix(dirty bool) string {
+	if dirty {
+		return " (dirty)"
 	}
+	return ""
 }
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_due_date;
+ALTER TABLE tasks DROP COLUMN due_date;
+ALTER TABLE goals DROP COLUMN due_date;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_tasks_recurrence_pending;
+ALTER TABLE tasks DROP COLUMN recurred;
+ALTER TABLE tasks DROP COLUMN recurrence;
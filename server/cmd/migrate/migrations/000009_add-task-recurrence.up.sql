@@ -0,0 +1,3 @@
+ALTER TABLE tasks ADD COLUMN recurrence TEXT;
+ALTER TABLE tasks ADD COLUMN recurred BOOLEAN NOT NULL DEFAULT FALSE;
+CREATE INDEX IF NOT EXISTS idx_tasks_recurrence_pending ON tasks(id) WHERE recurrence IS NOT NULL AND recurred = FALSE;
@@ -0,0 +1,18 @@
+ALTER TABLE tasks ADD COLUMN estimate_minutes INTEGER;
+
+CREATE TABLE IF NOT EXISTS time_entries (
+  id BIGSERIAL PRIMARY KEY,
+  task_id BIGINT NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  started_at TIMESTAMPTZ NOT NULL,
+  ended_at TIMESTAMPTZ,
+  duration_seconds INTEGER,
+  note TEXT NOT NULL DEFAULT '',
+  CONSTRAINT time_entries_duration_check CHECK (duration_seconds >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON time_entries(task_id);
+CREATE INDEX IF NOT EXISTS idx_time_entries_user_started ON time_entries(user_id, started_at);
+
+-- A running timer is an entry without an end; each user has at most one.
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_running ON time_entries(user_id) WHERE ended_at IS NULL;
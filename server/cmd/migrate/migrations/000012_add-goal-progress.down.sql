@@ -0,0 +1,2 @@
+ALTER TABLE goals DROP COLUMN progress_weight;
+ALTER TABLE goals DROP COLUMN auto_status;
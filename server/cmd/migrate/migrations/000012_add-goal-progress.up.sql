@@ -0,0 +1,5 @@
+-- An auto-status goal derives its status from its tasks; progress_weight is
+-- how tasks count towards the goal's progress.
+ALTER TABLE goals ADD COLUMN auto_status BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE goals ADD COLUMN progress_weight VARCHAR(20) NOT NULL DEFAULT 'count'
+  CONSTRAINT goals_progress_weight_check CHECK (progress_weight IN ('count', 'priority', 'estimate'));
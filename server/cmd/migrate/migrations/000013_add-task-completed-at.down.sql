@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_tasks_completed_at;
+ALTER TABLE tasks DROP COLUMN completed_at;
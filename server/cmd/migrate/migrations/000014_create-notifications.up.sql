@@ -0,0 +1,32 @@
+-- Notifications are delivered in the app or by email, per the recipient's
+-- preference for the event type. sent_at marks emails handed to the mailer.
+CREATE TABLE IF NOT EXISTS notifications (
+  id BIGSERIAL PRIMARY KEY,
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  type VARCHAR(20) NOT NULL,
+  channel VARCHAR(10) NOT NULL,
+  actor_id BIGINT REFERENCES users(id) ON DELETE SET NULL,
+  goal_id BIGINT NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+  task_id BIGINT REFERENCES tasks(id) ON DELETE CASCADE,
+  read_at TIMESTAMPTZ,
+  sent_at TIMESTAMPTZ,
+  created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+  CONSTRAINT notifications_type_check CHECK (type IN ('assigned', 'mentioned', 'commented', 'due_soon', 'goal_achieved')),
+  CONSTRAINT notifications_channel_check CHECK (channel IN ('in_app', 'email'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, id) WHERE channel = 'in_app';
+CREATE INDEX IF NOT EXISTS idx_notifications_unsent ON notifications(id) WHERE channel = 'email' AND sent_at IS NULL;
+
+-- Event types without a row are delivered in the app.
+CREATE TABLE IF NOT EXISTS notification_preferences (
+  user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  type VARCHAR(20) NOT NULL,
+  channel VARCHAR(10) NOT NULL,
+  PRIMARY KEY (user_id, type),
+  CONSTRAINT notification_preferences_type_check CHECK (type IN ('assigned', 'mentioned', 'commented', 'due_soon', 'goal_achieved')),
+  CONSTRAINT notification_preferences_channel_check CHECK (channel IN ('in_app', 'email', 'none'))
+);
+
+-- due_notified_on is the due date the assignee was last reminded of.
+ALTER TABLE tasks ADD COLUMN due_notified_on DATE;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN assigned_at;
+DROP TABLE IF EXISTS digest_settings;
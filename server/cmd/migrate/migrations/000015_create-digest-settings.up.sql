@@ -0,0 +1,18 @@
+-- Users without a row receive no digest. last_sent_on is the local date
+-- of the last digest and last_sent_at the instant it was built.
+CREATE TABLE IF NOT EXISTS digest_settings (
+  user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+  frequency VARCHAR(10) NOT NULL,
+  time_zone VARCHAR(64) NOT NULL,
+  send_hour SMALLINT NOT NULL,
+  weekday SMALLINT NOT NULL,
+  last_sent_on DATE,
+  last_sent_at TIMESTAMPTZ,
+  CONSTRAINT digest_settings_frequency_check CHECK (frequency IN ('off', 'daily', 'weekly')),
+  CONSTRAINT digest_settings_send_hour_check CHECK (send_hour BETWEEN 0 AND 23),
+  CONSTRAINT digest_settings_weekday_check CHECK (weekday BETWEEN 0 AND 6)
+);
+
+-- assigned_at is when the current assignee was given the task.
+ALTER TABLE tasks ADD COLUMN assigned_at TIMESTAMPTZ;
+UPDATE tasks SET assigned_at = created_at WHERE assignee_id IS NOT NULL;
@@ -0,0 +1,40 @@
+-- position orders the tasks of a goal and assignee_position the tasks on
+-- their assignee's list. Moves put a task halfway between its neighbours,
+-- so both are fractional; the stores renumber a list once two positions
+-- get too close.
+ALTER TABLE tasks ADD COLUMN position DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN assignee_position DOUBLE PRECISION;
+
+-- Existing lists keep the order they were shown in.
+UPDATE tasks SET position = ranked.new_position
+FROM (
+  SELECT
+    id,
+    ROW_NUMBER() OVER (
+      PARTITION BY goal_id
+      ORDER BY
+        CASE WHEN is_completed THEN 1 ELSE 0 END,
+        CASE priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 WHEN 'low' THEN 2 ELSE 3 END,
+        created_at,
+        id
+    ) AS new_position
+  FROM tasks
+) ranked
+WHERE tasks.id = ranked.id;
+
+UPDATE tasks SET assignee_position = ranked.new_position
+FROM (
+  SELECT
+    id,
+    ROW_NUMBER() OVER (
+      PARTITION BY assignee_id
+      ORDER BY
+        CASE WHEN is_completed THEN 1 ELSE 0 END,
+        CASE priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 WHEN 'low' THEN 2 ELSE 3 END,
+        created_at DESC,
+        id DESC
+    ) AS new_position
+  FROM tasks
+  WHERE assignee_id IS NOT NULL
+) ranked
+WHERE tasks.id = ranked.id;
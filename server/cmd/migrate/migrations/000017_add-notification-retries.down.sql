@@ -0,0 +1,2 @@
+ALTER TABLE notifications DROP COLUMN retry_at;
+ALTER TABLE notifications DROP COLUMN send_attempts;
@@ -0,0 +1,6 @@
+ALTER TABLE notifications
+  DROP CONSTRAINT notifications_type_check,
+  ADD CONSTRAINT notifications_type_check CHECK (type IN ('assigned', 'mentioned', 'commented', 'due_soon', 'goal_achieved'));
+ALTER TABLE notification_preferences
+  DROP CONSTRAINT notification_preferences_type_check,
+  ADD CONSTRAINT notification_preferences_type_check CHECK (type IN ('assigned', 'mentioned', 'commented', 'due_soon', 'goal_achieved'));
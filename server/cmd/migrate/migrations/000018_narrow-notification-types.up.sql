@@ -0,0 +1,11 @@
+-- Nothing emits 'commented' notifications, since tasks have no comments, so
+-- it is no longer an event type.
+DELETE FROM notifications WHERE type = 'commented';
+DELETE FROM notification_preferences WHERE type = 'commented';
+
+ALTER TABLE notifications
+  DROP CONSTRAINT notifications_type_check,
+  ADD CONSTRAINT notifications_type_check CHECK (type IN ('assigned', 'mentioned', 'due_soon', 'goal_achieved'));
+ALTER TABLE notification_preferences
+  DROP CONSTRAINT notification_preferences_type_check,
+  ADD CONSTRAINT notification_preferences_type_check CHECK (type IN ('assigned', 'mentioned', 'due_soon', 'goal_achieved'));
@@ -0,0 +1,47 @@
+// Package migrations embeds the SQL migration files so binaries can run and
+// verify them without depending on the working directory.
+package migrations
+
+import (
+	"embed"
+	"io/fs"
+	"strconv"
+	"strings"
+)
+
+//go:embed *.sql
+var FS embed.FS
+
+// SQLiteFS holds the SQLite flavour of the migrations under sqlite/. Version
+// numbers match FS so both backends report the same schema version.
+//
+//go:embed sqlite/*.sql
+var SQLiteFS embed.FS
+
+// LatestVersion returns the highest migration version shipped with the binary.
+func LatestVersion() (uint, error) {
+	entries, err := fs.ReadDir(FS, ".")
+	if err != nil {
+		return 0, err
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasSuffix(name, ".up.sql") {
+			continue
+		}
+		prefix, _, ok := strings.Cut(name, "_")
+		if !ok {
+			continue
+		}
+		version, err := strconv.ParseUint(prefix, 10, 64)
+		if err != nil {
+			continue
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	return latest, nil
+}
@@ -0,0 +1,12 @@
+CREATE TABLE tasks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  goal_id INTEGER NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL,
+  priority VARCHAR(20) NOT NULL DEFAULT 'medium',
+  is_completed BOOLEAN NOT NULL DEFAULT FALSE,
+  assignee_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  created_by INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+  CONSTRAINT tasks_priority_check CHECK (priority IN ('high', 'medium', 'low'))
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tasks_assignee_id;
+DROP INDEX IF EXISTS idx_tasks_goal_id;
+DROP INDEX IF EXISTS idx_tasks_completion_priority;
+DROP INDEX IF EXISTS idx_goals_status_priority;
+DROP INDEX IF EXISTS idx_goals_owner_id;
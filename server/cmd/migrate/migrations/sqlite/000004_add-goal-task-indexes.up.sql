@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS idx_goals_owner_id ON goals(owner_id);
+CREATE INDEX IF NOT EXISTS idx_goals_status_priority ON goals(status, priority);
+CREATE INDEX IF NOT EXISTS idx_tasks_goal_id ON tasks(goal_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_assignee_id ON tasks(assignee_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_completion_priority ON tasks(is_completed, priority);
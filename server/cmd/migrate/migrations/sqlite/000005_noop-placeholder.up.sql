@@ -0,0 +1,2 @@
+-- Keeps version numbers aligned with the PostgreSQL migrations.
+SELECT 1;
@@ -0,0 +1,4 @@
+-- The PostgreSQL version patches databases created from older migration
+-- definitions. SQLite databases never had those, so this only keeps version
+-- numbers aligned.
+SELECT 1;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS template_tasks;
+DROP TABLE IF EXISTS templates;
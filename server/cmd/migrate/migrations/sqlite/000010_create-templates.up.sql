@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS templates (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  name VARCHAR(255) NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL,
+  priority VARCHAR(20) NOT NULL DEFAULT 'medium',
+  due_in_days INTEGER,
+  created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+  CONSTRAINT templates_priority_check CHECK (priority IN ('high', 'medium', 'low'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_templates_owner_id ON templates(owner_id);
+
+CREATE TABLE IF NOT EXISTS template_tasks (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  template_id INTEGER NOT NULL REFERENCES templates(id) ON DELETE CASCADE,
+  position INTEGER NOT NULL,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL,
+  priority VARCHAR(20) NOT NULL DEFAULT 'medium',
+  due_in_days INTEGER,
+  CONSTRAINT template_tasks_priority_check CHECK (priority IN ('high', 'medium', 'low')),
+  CONSTRAINT template_tasks_template_id_position_key UNIQUE (template_id, position)
+);
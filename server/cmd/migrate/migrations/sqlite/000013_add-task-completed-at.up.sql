@@ -0,0 +1,4 @@
+-- Tasks completed before this migration keep a NULL completed_at.
+ALTER TABLE tasks ADD COLUMN completed_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_completed_at ON tasks(completed_at) WHERE completed_at IS NOT NULL;
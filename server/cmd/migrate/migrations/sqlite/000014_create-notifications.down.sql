@@ -0,0 +1,3 @@
+ALTER TABLE tasks DROP COLUMN due_notified_on;
+DROP TABLE IF EXISTS notification_preferences;
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,2 @@
+ALTER TABLE tasks DROP COLUMN assignee_position;
+ALTER TABLE tasks DROP COLUMN position;
@@ -0,0 +1,4 @@
+-- send_attempts counts the failed sends of an email notification, and the
+-- dispatcher skips it until retry_at.
+ALTER TABLE notifications ADD COLUMN send_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notifications ADD COLUMN retry_at TIMESTAMP;
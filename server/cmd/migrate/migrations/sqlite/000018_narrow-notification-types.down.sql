@@ -0,0 +1,40 @@
+-- SQLite cannot alter a CHECK constraint, so both tables are rebuilt.
+CREATE TABLE notifications_new (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  type VARCHAR(20) NOT NULL,
+  channel VARCHAR(10) NOT NULL,
+  actor_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+  goal_id INTEGER NOT NULL REFERENCES goals(id) ON DELETE CASCADE,
+  task_id INTEGER REFERENCES tasks(id) ON DELETE CASCADE,
+  read_at TIMESTAMP,
+  sent_at TIMESTAMP,
+  created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+  send_attempts INTEGER NOT NULL DEFAULT 0,
+  retry_at TIMESTAMP,
+  CONSTRAINT notifications_type_check CHECK (type IN ('assigned', 'mentioned', 'commented', 'due_soon', 'goal_achieved')),
+  CONSTRAINT notifications_channel_check CHECK (channel IN ('in_app', 'email'))
+);
+INSERT INTO notifications_new
+SELECT id, user_id, type, channel, actor_id, goal_id, task_id, read_at, sent_at, created_at, send_attempts, retry_at
+FROM notifications
+WHERE type IN ('assigned', 'mentioned', 'commented', 'due_soon', 'goal_achieved');
+DROP TABLE notifications;
+ALTER TABLE notifications_new RENAME TO notifications;
+CREATE INDEX idx_notifications_user_id ON notifications(user_id, id) WHERE channel = 'in_app';
+CREATE INDEX idx_notifications_unsent ON notifications(id) WHERE channel = 'email' AND sent_at IS NULL;
+
+CREATE TABLE notification_preferences_new (
+  user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  type VARCHAR(20) NOT NULL,
+  channel VARCHAR(10) NOT NULL,
+  PRIMARY KEY (user_id, type),
+  CONSTRAINT notification_preferences_type_check CHECK (type IN ('assigned', 'mentioned', 'commented', 'due_soon', 'goal_achieved')),
+  CONSTRAINT notification_preferences_channel_check CHECK (channel IN ('in_app', 'email', 'none'))
+);
+INSERT INTO notification_preferences_new
+SELECT user_id, type, channel
+FROM notification_preferences
+WHERE type IN ('assigned', 'mentioned', 'commented', 'due_soon', 'goal_achieved');
+DROP TABLE notification_preferences;
+ALTER TABLE notification_preferences_new RENAME TO notification_preferences;
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/db"
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "seed:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, out io.Writer) error {
+	opts, loadConfig, err := parseFlags(args)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	database, err := db.Open(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	// Timestamps are offsets from the start of the current UTC day, so reruns
+	// on the same day produce identical rows.
+	base := time.Now().UTC().Truncate(24 * time.Hour)
+	data := generate(opts, base)
+
+	hashedPassword, err := auth.HashPassword(opts.Password)
+	if err != nil {
+		return err
+	}
+
+	if err := write(context.Background(), database, data, hashedPassword, opts.Reset, cfg.DBDriver == config.DriverSQLite); err != nil {
+		return err
+	}
+
+	tasks := 0
+	for _, goal := range data.Goals {
+		tasks += len(goal.Tasks)
+	}
+	fmt.Fprintf(out, "seeded %d users, %d goals, %d tasks (password %q, emails user1@%s ...)\n",
+		len(data.Users), len(data.Goals), tasks, opts.Password, emailDomain)
+	return nil
+}
+
+// parseFlags reads the seed options and the configuration flags; the
+// returned function loads the configuration they select.
+func parseFlags(args []string) (options, func() (config.Config, error), error) {
+	var opts options
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	flags.Uint64Var(&opts.Seed, "seed", 1, "random seed; the same seed produces the same data")
+	flags.IntVar(&opts.Users, "users", 8, "number of users")
+	flags.IntVar(&opts.GoalsPerUser, "goals", 3, "goals per user")
+	flags.IntVar(&opts.TasksPerGoal, "tasks", 5, "tasks per goal")
+	flags.StringVar(&opts.Password, "password", "password", "password for every seeded user")
+	flags.BoolVar(&opts.Reset, "reset", false, "truncate goals and tasks before seeding")
+	loadConfig := config.RegisterFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return options{}, nil, err
+	}
+	if flags.NArg() > 0 {
+		return options{}, nil, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+
+	if opts.Users < 1 {
+		return options{}, nil, errors.New("--users must be at least 1")
+	}
+	if opts.GoalsPerUser < 0 || opts.TasksPerGoal < 0 {
+		return options{}, nil, errors.New("--goals and --tasks must not be negative")
+	}
+	if len(opts.Password) < 3 {
+		return options{}, nil, errors.New("--password must be at least 3 characters")
+	}
+	return opts, loadConfig, nil
+}
+
+// write replaces previously seeded users (and, through ON DELETE CASCADE,
+// their goals and tasks) with data in a single transaction.
+func write(ctx context.Context, database *sql.DB, data dataset, hashedPassword string, reset, sqlite bool) error {
+	tx, err := database.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// CASCADE empties the tables that reference goals and tasks, such as
+	// time_entries and notifications; SQLite's foreign keys cascade the
+	// deletes the same way.
+	if reset {
+		truncate := "TRUNCATE tasks, goals RESTART IDENTITY CASCADE"
+		if sqlite {
+			truncate = "DELETE FROM tasks; DELETE FROM goals"
+		}
+		if _, err := tx.ExecContext(ctx, truncate); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(
+		ctx,
+		"DELETE FROM users WHERE email LIKE $1",
+		"%@"+emailDomain,
+	); err != nil {
+		return err
+	}
+
+	userIDs := make([]int, len(data.Users))
+	for i, user := range data.Users {
+		if err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO users (first_name, last_name, email, password)
+			 VALUES ($1, $2, $3, $4)
+			 RETURNING id`,
+			user.FirstName, user.LastName, user.Email, hashedPassword,
+		).Scan(&userIDs[i]); err != nil {
+			return fmt.Errorf("insert user %s: %w", user.Email, err)
+		}
+	}
+
+	for _, goal := range data.Goals {
+		var goalID int
+		if err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO goals (title, description, priority, status, owner_id, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6)
+			 RETURNING id`,
+			goal.Title, goal.Description, goal.Priority, goal.Status, userIDs[goal.OwnerIndex], goal.CreatedAt,
+		).Scan(&goalID); err != nil {
+			return fmt.Errorf("insert goal %q: %w", goal.Title, err)
+		}
+
+		for _, task := range goal.Tasks {
+			var assigneeID *int
+			if task.AssigneeIndex >= 0 {
+				assigneeID = &userIDs[task.AssigneeIndex]
+			}
+			var taskID int
+			err := tx.QueryRowContext(
+				ctx,
+				`INSERT INTO tasks (goal_id, title, description, priority, is_completed, assignee_id, created_by, created_at, completed_at, assigned_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, CASE WHEN $6 IS NULL THEN NULL ELSE $8 END)
+				 RETURNING id`,
+				goalID, task.Title, task.Description, task.Priority, task.IsCompleted, assigneeID, userIDs[task.CreatorIndex], task.CreatedAt, task.CompletedAt,
+			).Scan(&taskID)
+			if err == nil {
+				err = tracker.PlaceTask(ctx, tx, taskID)
+			}
+			if err != nil {
+				return fmt.Errorf("insert task %q: %w", task.Title, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/db"
+	"VyacheslavKuchumov/test-backend/migrator"
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+func TestWriteReseedsSQLite(t *testing.T) {
+	database, err := db.NewSQLiteStorage(context.Background(), config.Config{SQLitePath: filepath.Join(t.TempDir(), "tracker.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	if err := migrator.Up(context.Background(), database, config.DriverSQLite); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	testReseed(t, database, true)
+}
+
+// TestWriteReseedsPostgres seeds the database in TEST_DATABASE_URL, so
+// point it at a throwaway database.
+func TestWriteReseedsPostgres(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	database, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+	if err := migrator.Up(context.Background(), database, config.DriverPostgres); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+	testReseed(t, database, false)
+}
+
+// testReseed seeds, adds rows that reference the seeded tasks and goals,
+// and seeds again with and without --reset.
+func testReseed(t *testing.T, database *sql.DB, sqlite bool) {
+	ctx := context.Background()
+	opts := options{Seed: 3, Users: 3, GoalsPerUser: 2, TasksPerGoal: 3}
+	data := generate(opts, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	if err := write(ctx, database, data, "hashed", false, sqlite); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	for _, statement := range []string{
+		`INSERT INTO time_entries (task_id, user_id, started_at, ended_at, duration_seconds)
+		 SELECT t.id, t.created_by, $1, $1, 0 FROM tasks t`,
+		`INSERT INTO notifications (user_id, type, channel, goal_id, task_id, created_at)
+		 SELECT t.created_by, 'assigned', 'in_app', t.goal_id, t.id, $1 FROM tasks t`,
+	} {
+		if _, err := database.ExecContext(ctx, statement, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, reset := range []bool{true, false} {
+		if err := write(ctx, database, data, "hashed", reset, sqlite); err != nil {
+			t.Fatalf("reseed with reset %v: %v", reset, err)
+		}
+	}
+
+	var tasks, entries int
+	if err := database.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks").Scan(&tasks); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.QueryRowContext(ctx, "SELECT COUNT(*) FROM time_entries").Scan(&entries); err != nil {
+		t.Fatal(err)
+	}
+	if tasks != opts.Users*opts.GoalsPerUser*opts.TasksPerGoal || entries != 0 {
+		t.Fatalf("expected only the reseeded tasks, got %d tasks and %d time entries", tasks, entries)
+	}
+}
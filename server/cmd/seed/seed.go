@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// Seeded users share this email domain so a rerun can find and replace them
+// without touching accounts created through the API.
+const emailDomain = "seed.example.com"
+
+type options struct {
+	Seed         uint64
+	Users        int
+	GoalsPerUser int
+	TasksPerGoal int
+	Password     string
+	Reset        bool
+}
+
+type dataset struct {
+	Users []seedUser
+	Goals []seedGoal
+}
+
+type seedUser struct {
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+type seedGoal struct {
+	OwnerIndex  int
+	Title       string
+	Description string
+	Priority    string
+	Status      string
+	CreatedAt   time.Time
+	Tasks       []seedTask
+}
+
+type seedTask struct {
+	Title         string
+	Description   string
+	Priority      string
+	IsCompleted   bool
+	AssigneeIndex int // -1 when unassigned
+	CreatorIndex  int
+	CreatedAt     time.Time
+	CompletedAt   *time.Time // nil when open
+}
+
+var (
+	firstNames = []string{"Alice", "Boris", "Chen", "Dana", "Emeka", "Farah", "Goran", "Hana", "Ivan", "Julia", "Kofi", "Lena", "Mateo", "Nadia", "Oskar", "Priya"}
+	lastNames  = []string{"Ivanova", "Smith", "Kowalski", "Okafor", "Tanaka", "Garcia", "Petrov", "Nguyen", "Schmidt", "Haddad", "Silva", "Larsen"}
+
+	goalVerbs   = []string{"Launch", "Improve", "Migrate", "Redesign", "Automate", "Document", "Reduce", "Prepare"}
+	goalObjects = []string{"customer onboarding", "billing pipeline", "mobile app", "release process", "support backlog", "analytics dashboard", "hiring plan", "Q3 roadmap"}
+	taskVerbs   = []string{"Draft", "Review", "Implement", "Test", "Estimate", "Deploy", "Research", "Sync on"}
+	taskObjects = []string{"requirements", "API contract", "UI mockups", "database schema", "edge cases", "rollout plan", "metrics", "feedback"}
+)
+
+type weighted struct {
+	value  string
+	weight int
+}
+
+var (
+	priorityWeights   = []weighted{{"high", 20}, {"medium", 50}, {"low", 30}}
+	goalStatusWeights = []weighted{{"todo", 35}, {"in_progress", 45}, {"achieved", 20}}
+)
+
+// generate builds the demo dataset. The same options and base time always
+// produce the same dataset.
+func generate(opts options, base time.Time) dataset {
+	rng := rand.New(rand.NewPCG(opts.Seed, opts.Seed))
+
+	var data dataset
+	for i := 0; i < opts.Users; i++ {
+		first := pick(rng, firstNames)
+		last := pick(rng, lastNames)
+		data.Users = append(data.Users, seedUser{
+			FirstName: first,
+			LastName:  last,
+			Email:     fmt.Sprintf("user%d@%s", i+1, emailDomain),
+		})
+	}
+
+	for owner := range data.Users {
+		for g := 0; g < opts.GoalsPerUser; g++ {
+			goalAge := time.Duration(rng.IntN(60*24)) * time.Hour
+			goal := seedGoal{
+				OwnerIndex:  owner,
+				Title:       pick(rng, goalVerbs) + " " + pick(rng, goalObjects),
+				Description: "Demo goal generated by cmd/seed.",
+				Priority:    pickWeighted(rng, priorityWeights),
+				Status:      pickWeighted(rng, goalStatusWeights),
+				CreatedAt:   base.Add(-goalAge),
+			}
+
+			for t := 0; t < opts.TasksPerGoal; t++ {
+				task := seedTask{
+					Title:         pick(rng, taskVerbs) + " " + pick(rng, taskObjects),
+					Description:   "Demo task generated by cmd/seed.",
+					Priority:      pickWeighted(rng, priorityWeights),
+					IsCompleted:   rng.Float64() < completionRate(goal.Status),
+					AssigneeIndex: -1,
+					CreatorIndex:  owner,
+					CreatedAt:     goal.CreatedAt.Add(time.Duration(rng.IntN(72)) * time.Hour),
+				}
+				// Most tasks are assigned to one of the seeded users.
+				if rng.Float64() < 0.8 {
+					task.AssigneeIndex = rng.IntN(len(data.Users))
+				}
+				// Completed tasks took up to two weeks, finishing by the
+				// base time unless they were created after it.
+				if task.IsCompleted {
+					completedAt := task.CreatedAt.Add(time.Duration(rng.IntN(14*24)) * time.Hour)
+					if completedAt.After(base) {
+						completedAt = base
+					}
+					if completedAt.Before(task.CreatedAt) {
+						completedAt = task.CreatedAt
+					}
+					task.CompletedAt = &completedAt
+				}
+				goal.Tasks = append(goal.Tasks, task)
+			}
+
+			data.Goals = append(data.Goals, goal)
+		}
+	}
+	return data
+}
+
+// completionRate is the share of completed tasks for a goal in status.
+func completionRate(status string) float64 {
+	switch status {
+	case "achieved":
+		return 1
+	case "in_progress":
+		return 0.5
+	default:
+		return 0.1
+	}
+}
+
+func pick(rng *rand.Rand, values []string) string {
+	return values[rng.IntN(len(values))]
+}
+
+func pickWeighted(rng *rand.Rand, values []weighted) string {
+	total := 0
+	for _, v := range values {
+		total += v.weight
+	}
+	n := rng.IntN(total)
+	for _, v := range values {
+		if n < v.weight {
+			return v.value
+		}
+		n -= v.weight
+	}
+	return values[len(values)-1].value
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenerateIsDeterministic(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	opts := options{Seed: 42, Users: 5, GoalsPerUser: 2, TasksPerGoal: 4}
+
+	first := generate(opts, base)
+	second := generate(opts, base)
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("expected the same seed to produce the same dataset")
+	}
+
+	other := generate(options{Seed: 43, Users: 5, GoalsPerUser: 2, TasksPerGoal: 4}, base)
+	if reflect.DeepEqual(first, other) {
+		t.Fatal("expected a different seed to produce a different dataset")
+	}
+}
+
+func TestGenerateShape(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	data := generate(options{Seed: 7, Users: 6, GoalsPerUser: 3, TasksPerGoal: 5}, base)
+
+	if len(data.Users) != 6 || len(data.Goals) != 18 {
+		t.Fatalf("expected 6 users and 18 goals, got %d and %d", len(data.Users), len(data.Goals))
+	}
+
+	emails := map[string]bool{}
+	for _, user := range data.Users {
+		if !strings.HasSuffix(user.Email, "@"+emailDomain) {
+			t.Fatalf("expected seeded email domain, got %q", user.Email)
+		}
+		if emails[user.Email] {
+			t.Fatalf("duplicate email %q", user.Email)
+		}
+		emails[user.Email] = true
+	}
+
+	for _, goal := range data.Goals {
+		if len(goal.Tasks) != 5 {
+			t.Fatalf("expected 5 tasks per goal, got %d", len(goal.Tasks))
+		}
+		if goal.CreatedAt.After(base) {
+			t.Fatalf("expected goal created before base time, got %s", goal.CreatedAt)
+		}
+		for _, task := range goal.Tasks {
+			if goal.Status == "achieved" && !task.IsCompleted {
+				t.Fatalf("expected every task of an achieved goal to be completed")
+			}
+			if task.IsCompleted != (task.CompletedAt != nil) {
+				t.Fatalf("expected completed tasks, and only them, to have a completion time")
+			}
+			if task.CompletedAt != nil && task.CompletedAt.Before(task.CreatedAt) {
+				t.Fatalf("expected a task completed after it was created, got %s before %s", task.CompletedAt, task.CreatedAt)
+			}
+			if task.AssigneeIndex >= len(data.Users) {
+				t.Fatalf("assignee index %d out of range", task.AssigneeIndex)
+			}
+		}
+	}
+}
+
+func TestParseFlagsValidates(t *testing.T) {
+	if _, _, err := parseFlags([]string{"--users", "0"}); err == nil {
+		t.Fatal("expected an error for zero users")
+	}
+
+	opts, _, err := parseFlags([]string{"--seed", "9", "--reset"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.Seed != 9 || !opts.Reset || opts.Users != 8 {
+		t.Fatalf("unexpected options %+v", opts)
+	}
+}
+
+func TestParseFlagsReadsConfigFlags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tracker.db")
+	_, loadConfig, err := parseFlags([]string{"--db-driver", "sqlite", "--sqlite-path", path, "--users", "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DBDriver != "sqlite" || cfg.SQLitePath != path {
+		t.Fatalf("expected the flags to select the database, got %q %q", cfg.DBDriver, cfg.SQLitePath)
+	}
+}
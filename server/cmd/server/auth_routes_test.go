@@ -8,7 +8,7 @@ import (
 )
 
 func TestProtectedEndpointsRequireAuthorization(t *testing.T) {
-	srv := NewServer(":0", nil)
+	srv := NewServer(testConfig(":0"), nil)
 	handler := srv.router()
 
 	protectedCases := []struct {
@@ -43,15 +43,38 @@ func TestProtectedEndpointsRequireAuthorization(t *testing.T) {
 			rr := httptest.NewRecorder()
 			handler.ServeHTTP(rr, req)
 
-			if rr.Code != http.StatusForbidden {
-				t.Fatalf("expected %d, got %d", http.StatusForbidden, rr.Code)
+			if rr.Code != http.StatusUnauthorized {
+				t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rr.Code)
+			}
+			if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Fatalf("expected problem+json content type, got %q", ct)
+			}
+			if rr.Header().Get("X-Request-ID") == "" {
+				t.Fatal("expected X-Request-ID response header")
+			}
+		})
+	}
+}
+
+func TestProbeEndpointsArePublic(t *testing.T) {
+	srv := NewServer(testConfig(":0"), nil)
+	handler := srv.router()
+
+	for _, path := range []string{"/healthz", "/version"} {
+		t.Run(path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, path, nil)
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
 			}
 		})
 	}
 }
 
 func TestLoginAndRegisterArePublic(t *testing.T) {
-	srv := NewServer(":0", nil)
+	srv := NewServer(testConfig(":0"), nil)
 	handler := srv.router()
 
 	cases := []struct {
@@ -70,8 +93,8 @@ func TestLoginAndRegisterArePublic(t *testing.T) {
 			rr := httptest.NewRecorder()
 			handler.ServeHTTP(rr, req)
 
-			if rr.Code == http.StatusForbidden {
-				t.Fatalf("expected non-%d for public endpoint, got %d", http.StatusForbidden, rr.Code)
+			if rr.Code == http.StatusUnauthorized {
+				t.Fatalf("expected non-%d for public endpoint, got %d", http.StatusUnauthorized, rr.Code)
 			}
 		})
 	}
@@ -1,58 +1,297 @@
 package server
 
 import (
+	"VyacheslavKuchumov/test-backend/cmd/migrate/migrations"
+	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/logging"
+	"VyacheslavKuchumov/test-backend/metrics"
+	"VyacheslavKuchumov/test-backend/recurrence"
 	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/service/calendar"
+	"VyacheslavKuchumov/test-backend/service/digest"
+	"VyacheslavKuchumov/test-backend/service/export"
+	"VyacheslavKuchumov/test-backend/service/health"
+	"VyacheslavKuchumov/test-backend/service/importer"
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/service/report"
+	"VyacheslavKuchumov/test-backend/service/template"
+	"VyacheslavKuchumov/test-backend/service/timetrack"
 	"VyacheslavKuchumov/test-backend/service/tracker"
 	"VyacheslavKuchumov/test-backend/service/user"
+	"VyacheslavKuchumov/test-backend/tracing"
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/chi/v5/middleware"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 )
 
 type Server struct {
-	addr string
-	db   *sql.DB
+	cfg                config.Config
+	addr               string
+	db                 *sql.DB
+	dbDriver           string
+	readTimeout        time.Duration
+	writeTimeout       time.Duration
+	idleTimeout        time.Duration
+	shutdownGrace      time.Duration
+	tlsCertFile        string
+	tlsKeyFile         string
+	recurrenceInterval time.Duration
+	notifyInterval     time.Duration
+	digestInterval     time.Duration
 }
 
-func NewServer(addr string, db *sql.DB) *Server {
+// NewServer returns a server listening on cfg.Port.
+func NewServer(cfg config.Config, db *sql.DB) *Server {
 	return &Server{
-		addr: addr,
-		db:   db,
+		cfg:           cfg,
+		addr:          cfg.Port,
+		db:            db,
+		dbDriver:      cfg.DBDriver,
+		readTimeout:   seconds(cfg.ReadTimeoutInSeconds),
+		writeTimeout:  seconds(cfg.WriteTimeoutInSeconds),
+		idleTimeout:   seconds(cfg.IdleTimeoutInSeconds),
+		shutdownGrace: seconds(cfg.ShutdownGraceInSeconds),
+		tlsCertFile:   cfg.TLSCertFile,
+		tlsKeyFile:    cfg.TLSKeyFile,
+
+		recurrenceInterval: seconds(cfg.RecurrenceIntervalInSeconds),
+		notifyInterval:     seconds(cfg.NotifyIntervalInSeconds),
+		digestInterval:     seconds(cfg.DigestIntervalInSeconds),
 	}
 }
 
-func (s *Server) Run() error {
-	log.Println("Listening on", s.addr)
-	return http.ListenAndServe(s.addr, s.router())
+// Run serves HTTP (or HTTPS when a certificate and key are configured) and
+// runs the recurring task scheduler, the notification dispatcher and the
+// digest scheduler until ctx is cancelled, then stops
+// accepting connections and waits up to the shutdown grace period for
+// in-flight requests to finish.
+func (s *Server) Run(ctx context.Context) error {
+	useTLS := s.tlsCertFile != "" || s.tlsKeyFile != ""
+	if useTLS && (s.tlsCertFile == "" || s.tlsKeyFile == "") {
+		return fmt.Errorf("both TLS_CERT_FILE and TLS_KEY_FILE must be set to serve TLS")
+	}
+
+	if s.db != nil && s.recurrenceInterval > 0 {
+		schedulerCtx, stopScheduler := context.WithCancel(ctx)
+		schedulerDone := make(chan struct{})
+		go func() {
+			defer close(schedulerDone)
+			recurrence.NewScheduler(s.trackerStore(), s.recurrenceInterval).Run(schedulerCtx)
+		}()
+		defer func() {
+			stopScheduler()
+			<-schedulerDone
+		}()
+	}
+	if s.db != nil && s.notifyInterval > 0 {
+		dispatcherCtx, stopDispatcher := context.WithCancel(ctx)
+		dispatcherDone := make(chan struct{})
+		go func() {
+			defer close(dispatcherDone)
+			dispatcher := notification.NewDispatcher(s.notificationStore(), s.mailer(), s.notifyInterval, int(s.cfg.DueSoonDays))
+			dispatcher.Run(dispatcherCtx)
+		}()
+		defer func() {
+			stopDispatcher()
+			<-dispatcherDone
+		}()
+	}
+	if s.db != nil && s.digestInterval > 0 {
+		digestCtx, stopDigests := context.WithCancel(ctx)
+		digestDone := make(chan struct{})
+		go func() {
+			defer close(digestDone)
+			digest.NewScheduler(s.digestStore(), s.mailer(), s.digestInterval).Run(digestCtx)
+		}()
+		defer func() {
+			stopDigests()
+			<-digestDone
+		}()
+	}
+
+	httpServer := &http.Server{
+		Addr:              s.addr,
+		Handler:           s.router(),
+		ReadTimeout:       s.readTimeout,
+		ReadHeaderTimeout: s.readTimeout,
+		WriteTimeout:      s.writeTimeout,
+		IdleTimeout:       s.idleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if useTLS {
+			slog.Info("listening with TLS", "addr", s.addr)
+			serveErr <- httpServer.ListenAndServeTLS(s.tlsCertFile, s.tlsKeyFile)
+			return
+		}
+		slog.Info("listening", "addr", s.addr)
+		serveErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutting down, draining in-flight requests", "grace", s.shutdownGrace)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownGrace)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown: %w", err)
+	}
+	return nil
 }
 
 func (s *Server) router() http.Handler {
 	r := chi.NewRouter()
-	r.Use(middleware.Logger)
+	r.Use(logging.RequestID)
+	r.Use(tracing.Middleware)
+	r.Use(logging.AccessLog)
+	r.Use(metrics.Middleware)
+
+	if s.db != nil {
+		if err := metrics.RegisterDBStats(s.db, s.cfg.DBName); err != nil {
+			slog.Error("failed to register database metrics", "error", err)
+		}
+	}
+	r.Handle("/metrics", metrics.Handler(s.cfg.MetricsToken))
 
 	userStore := user.NewStore(s.db)
-	userHandler := user.NewHandler(userStore)
+	userHandler := user.NewHandler(userStore, []byte(s.cfg.JWTSecret), seconds(s.cfg.JWTExpirationInSeconds))
 
-	trackerStore := tracker.NewStore(s.db)
+	trackerStore := s.trackerStore()
 	trackerHandler := tracker.NewHandler(trackerStore)
-	authMiddleware := auth.JWTAuthMiddleware(userStore)
+
+	exportStore := export.NewStore(s.db)
+	if s.dbDriver == config.DriverSQLite {
+		exportStore = export.NewSQLiteStore(s.db)
+	}
+	exportHandler := export.NewHandler(exportStore, s.writeTimeout)
+
+	importStore := importer.NewStore(s.db)
+	importHandler := importer.NewHandler(importStore, userStore)
+	templateHandler := template.NewHandler(template.NewStore(s.db), trackerStore, importStore)
+	calendarHandler := calendar.NewHandler(calendar.NewStore(s.db), trackerStore, s.cfg.PublicHost)
+	timeHandler := timetrack.NewHandler(timetrack.NewStore(s.db))
+	reportStore := report.NewStore(s.db)
+	if s.dbDriver == config.DriverSQLite {
+		reportStore = report.NewSQLiteStore(s.db)
+	}
+	reportHandler := report.NewHandler(reportStore, int(s.cfg.ReportOverloadThreshold))
+	notificationHandler := notification.NewHandler(s.notificationStore())
+	digestHandler := digest.NewHandler(s.digestStore())
+	jwtSecret := []byte(s.cfg.JWTSecret)
+	authMiddleware := auth.JWTAuthMiddleware(userStore, jwtSecret)
 	apiAuthMiddleware := auth.JWTAuthMiddlewareWithExclusions(
 		userStore,
+		jwtSecret,
 		"/api/v1/login",
 		"/api/v1/register",
 	)
 
+	expectedVersion, err := migrations.LatestVersion()
+	if err != nil {
+		slog.Error("failed to read embedded migrations", "error", err)
+	}
+	health.RegisterRoutes(r, health.NewHandler(health.NewStore(s.db), expectedVersion))
+
 	r.With(authMiddleware).Handle("/swagger/*", httpSwagger.Handler())
 
+	// Calendar apps cannot send a JWT; the feed URL carries its own token.
+	r.Group(func(r chi.Router) {
+		r.Use(requestTimeout(seconds(s.cfg.DBTimeoutInSeconds)))
+		calendar.RegisterFeedRoutes(r, calendarHandler)
+	})
+
 	r.Route("/api/v1", func(api chi.Router) {
 		api.Use(apiAuthMiddleware)
-		user.RegisterRoutes(api, userHandler)
-		tracker.RegisterRoutes(api, trackerHandler)
+		api.Group(func(api chi.Router) {
+			api.Use(requestTimeout(seconds(s.cfg.DBTimeoutInSeconds)))
+			user.RegisterRoutes(api, userHandler)
+			tracker.RegisterRoutes(api, trackerHandler)
+			calendar.RegisterRoutes(api, calendarHandler)
+			template.RegisterRoutes(api, templateHandler)
+			timetrack.RegisterRoutes(api, timeHandler)
+			report.RegisterRoutes(api, reportHandler)
+			notification.RegisterRoutes(api, notificationHandler)
+			digest.RegisterRoutes(api, digestHandler)
+		})
+		// Exports and imports scale with the instance or the uploaded file,
+		// so they are not bound by DB_TIMEOUT; they end when the client
+		// disconnects, and exports when it stops reading.
+		export.RegisterRoutes(api, exportHandler, auth.RequireAdmin(userStore, s.cfg.IsAdmin))
+		importer.RegisterRoutes(api, importHandler)
 	})
 
 	return r
 }
+
+// trackerStore returns the goal and task store for the configured driver.
+func (s *Server) trackerStore() *tracker.Store {
+	if s.dbDriver == config.DriverSQLite {
+		return tracker.NewSQLiteStore(s.db)
+	}
+	return tracker.NewStore(s.db)
+}
+
+// digestStore returns the digest store for the configured driver.
+func (s *Server) digestStore() *digest.Store {
+	if s.dbDriver == config.DriverSQLite {
+		return digest.NewSQLiteStore(s.db)
+	}
+	return digest.NewStore(s.db)
+}
+
+// notificationStore returns the notification store for the configured
+// driver.
+func (s *Server) notificationStore() *notification.Store {
+	if s.dbDriver == config.DriverSQLite {
+		return notification.NewSQLiteStore(s.db)
+	}
+	return notification.NewStore(s.db)
+}
+
+// mailer returns the configured SMTP mailer, or one that only logs when
+// SMTP_ADDR is not set.
+func (s *Server) mailer() notification.Mailer {
+	if s.cfg.SMTPAddr == "" {
+		return notification.LogMailer{}
+	}
+	return notification.SMTPMailer{
+		Addr:     s.cfg.SMTPAddr,
+		From:     s.cfg.SMTPFrom,
+		Username: s.cfg.SMTPUsername,
+		Password: s.cfg.SMTPPassword,
+	}
+}
+
+// requestTimeout cancels the request context after timeout so that store
+// queries started by the handler are aborted. A non-positive timeout disables it.
+func requestTimeout(timeout time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func seconds(n int64) time.Duration {
+	return time.Duration(n) * time.Second
+}
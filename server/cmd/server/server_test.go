@@ -0,0 +1,45 @@
+package server
+
+import (
+	"VyacheslavKuchumov/test-backend/config"
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRunStopsWhenContextIsCancelled(t *testing.T) {
+	srv := NewServer(testConfig("127.0.0.1:0"), nil)
+	srv.shutdownGrace = time.Second
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("expected clean shutdown, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+}
+
+func TestRunRequiresBothTLSFiles(t *testing.T) {
+	srv := NewServer(testConfig("127.0.0.1:0"), nil)
+	srv.tlsCertFile = "cert.pem"
+
+	if err := srv.Run(context.Background()); err == nil {
+		t.Fatal("expected error when only the certificate is configured")
+	}
+}
+
+// testConfig returns the default configuration listening on port.
+func testConfig(port string) config.Config {
+	cfg := config.Defaults()
+	cfg.Port = port
+	return cfg
+}
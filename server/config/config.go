@@ -0,0 +1,307 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/lpernett/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Supported values of DB_DRIVER.
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// Config is the full application configuration. Each field is read from the
+// environment variable in its env tag, the config file key in its yaml tag
+// and the flag of the same name with dashes (db_host becomes --db-host).
+// Fields tagged secret are redacted by Redacted.
+type Config struct {
+	PublicHost             string `env:"PUBLIC_HOST" yaml:"public_host"`
+	Port                   string `env:"PORT" yaml:"port"`
+	ReadTimeoutInSeconds   int64  `env:"HTTP_READ_TIMEOUT" yaml:"http_read_timeout"`
+	WriteTimeoutInSeconds  int64  `env:"HTTP_WRITE_TIMEOUT" yaml:"http_write_timeout"`
+	IdleTimeoutInSeconds   int64  `env:"HTTP_IDLE_TIMEOUT" yaml:"http_idle_timeout"`
+	ShutdownGraceInSeconds int64  `env:"SHUTDOWN_GRACE" yaml:"shutdown_grace"`
+	TLSCertFile            string `env:"TLS_CERT_FILE" yaml:"tls_cert_file"`
+	TLSKeyFile             string `env:"TLS_KEY_FILE" yaml:"tls_key_file"`
+	DBDriver               string `env:"DB_DRIVER" yaml:"db_driver"`
+	SQLitePath             string `env:"SQLITE_PATH" yaml:"sqlite_path"`
+	DatabaseURL            string `env:"DATABASE_URL" yaml:"database_url" secret:"true"`
+	DBUser                 string `env:"DB_USER" yaml:"db_user"`
+	DBPassword             string `env:"DB_PASSWORD" yaml:"db_password" secret:"true"`
+	DBHost                 string `env:"DB_HOST" yaml:"db_host"`
+	DBPort                 string `env:"DB_PORT" yaml:"db_port"`
+	DBName                 string `env:"DB_NAME" yaml:"db_name"`
+	DBSSLMode              string `env:"DB_SSLMODE" yaml:"db_sslmode"`
+	DBTimeoutInSeconds     int64  `env:"DB_TIMEOUT" yaml:"db_timeout"`
+	// Connection pool and session settings, PostgreSQL only.
+	DBMaxOpenConns                 int64  `env:"DB_MAX_OPEN_CONNS" yaml:"db_max_open_conns"`
+	DBMaxIdleConns                 int64  `env:"DB_MAX_IDLE_CONNS" yaml:"db_max_idle_conns"`
+	DBConnMaxLifetimeInSeconds     int64  `env:"DB_CONN_MAX_LIFETIME" yaml:"db_conn_max_lifetime"`
+	DBConnMaxIdleTimeInSeconds     int64  `env:"DB_CONN_MAX_IDLE_TIME" yaml:"db_conn_max_idle_time"`
+	DBStatementTimeoutInSeconds    int64  `env:"DB_STATEMENT_TIMEOUT" yaml:"db_statement_timeout"`
+	DBApplicationName              string `env:"DB_APPLICATION_NAME" yaml:"db_application_name"`
+	DBConnectRetryTimeoutInSeconds int64  `env:"DB_CONNECT_RETRY_TIMEOUT" yaml:"db_connect_retry_timeout"`
+	AutoMigrate                    bool   `env:"AUTO_MIGRATE" yaml:"auto_migrate"`
+	JWTExpirationInSeconds         int64  `env:"JWT_EXP" yaml:"jwt_exp"`
+	JWTSecret                      string `env:"JWT_SECRET" yaml:"jwt_secret" secret:"true"`
+	MetricsToken                   string `env:"METRICS_TOKEN" yaml:"metrics_token" secret:"true"`
+	AdminEmails                    string `env:"ADMIN_EMAILS" yaml:"admin_emails"`
+	RecurrenceIntervalInSeconds    int64  `env:"RECURRENCE_INTERVAL" yaml:"recurrence_interval"`
+	ReportOverloadThreshold        int64  `env:"REPORT_OVERLOAD_THRESHOLD" yaml:"report_overload_threshold"`
+	NotifyIntervalInSeconds        int64  `env:"NOTIFY_INTERVAL" yaml:"notify_interval"`
+	DueSoonDays                    int64  `env:"DUE_SOON_DAYS" yaml:"due_soon_days"`
+	DigestIntervalInSeconds        int64  `env:"DIGEST_INTERVAL" yaml:"digest_interval"`
+	SMTPAddr                       string `env:"SMTP_ADDR" yaml:"smtp_addr"`
+	SMTPFrom                       string `env:"SMTP_FROM" yaml:"smtp_from"`
+	SMTPUsername                   string `env:"SMTP_USERNAME" yaml:"smtp_username"`
+	SMTPPassword                   string `env:"SMTP_PASSWORD" yaml:"smtp_password" secret:"true"`
+	LogLevel                       string `env:"LOG_LEVEL" yaml:"log_level"`
+	LogFormat                      string `env:"LOG_FORMAT" yaml:"log_format"`
+	TracingExporter                string `env:"TRACING_EXPORTER" yaml:"tracing_exporter"`
+	OTLPEndpoint                   string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" yaml:"otel_exporter_otlp_endpoint"`
+	ServiceName                    string `env:"OTEL_SERVICE_NAME" yaml:"otel_service_name"`
+}
+
+// Defaults returns the configuration used for anything not set elsewhere.
+// JWTSecret has no default and must always be provided.
+func Defaults() Config {
+	return Config{
+		PublicHost:                     "http://localhost",
+		Port:                           ":8000",
+		ReadTimeoutInSeconds:           15,
+		WriteTimeoutInSeconds:          30,
+		IdleTimeoutInSeconds:           120,
+		ShutdownGraceInSeconds:         20,
+		DBDriver:                       DriverPostgres,
+		SQLitePath:                     "task_tracker.db",
+		DBUser:                         "postgres",
+		DBPassword:                     "postgres",
+		DBHost:                         "127.0.0.1",
+		DBPort:                         "5433",
+		DBName:                         "task_tracker",
+		DBSSLMode:                      "disable",
+		DBTimeoutInSeconds:             5,
+		DBMaxOpenConns:                 25,
+		DBMaxIdleConns:                 25,
+		DBConnMaxLifetimeInSeconds:     1800,
+		DBConnMaxIdleTimeInSeconds:     300,
+		DBApplicationName:              "task-tracker-api",
+		DBConnectRetryTimeoutInSeconds: 30,
+		JWTExpirationInSeconds:         3600 * 24 * 7,
+		RecurrenceIntervalInSeconds:    60,
+		ReportOverloadThreshold:        10,
+		NotifyIntervalInSeconds:        60,
+		DueSoonDays:                    1,
+		DigestIntervalInSeconds:        60,
+		LogLevel:                       "info",
+		LogFormat:                      "json",
+		TracingExporter:                "none",
+		OTLPEndpoint:                   "http://localhost:4318",
+		ServiceName:                    "task-tracker-api",
+	}
+}
+
+// Load builds and validates the configuration, apart from the server-only
+// settings checked by ValidateServer. Sources are applied in
+// increasing precedence: defaults, the YAML config file, environment
+// variables (including those from the env file) and command-line flags.
+//
+// args are the flags without the program name. Besides one flag per field
+// they accept --config (or CONFIG_FILE) naming the YAML file and --env-file
+// (or ENV_FILE) naming the env file; without one, .env in the working
+// directory is used if it exists.
+func Load(args []string) (Config, error) {
+	flags := flag.NewFlagSet("config", flag.ContinueOnError)
+	load := RegisterFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		return Config{}, err
+	}
+	if flags.NArg() > 0 {
+		return Config{}, fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	return load()
+}
+
+// RegisterFlags adds Load's flags to a command's own flag set. The returned
+// function loads the configuration as Load does once flags are parsed.
+func RegisterFlags(flags *flag.FlagSet) func() (Config, error) {
+	configFile := flags.String("config", os.Getenv("CONFIG_FILE"), "YAML config file")
+	envFile := flags.String("env-file", os.Getenv("ENV_FILE"), "file of KEY=value lines loaded into the environment")
+
+	overrides := map[int]string{}
+	t := reflect.TypeFor[Config]()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name := strings.ReplaceAll(field.Tag.Get("yaml"), "_", "-")
+		register := flags.Func
+		if field.Type.Kind() == reflect.Bool {
+			register = flags.BoolFunc
+		}
+		register(name, "overrides "+field.Tag.Get("env"), func(value string) error {
+			overrides[i] = value
+			return nil
+		})
+	}
+
+	return func() (Config, error) {
+		return load(overrides, *configFile, *envFile)
+	}
+}
+
+// load applies the sources in order of precedence; overrides holds the
+// parsed flags by field index.
+func load(overrides map[int]string, configFile, envFile string) (Config, error) {
+	cfg := Defaults()
+	if err := loadEnvFile(envFile); err != nil {
+		return Config{}, err
+	}
+	if configFile != "" {
+		if err := cfg.loadFile(configFile); err != nil {
+			return Config{}, err
+		}
+	}
+
+	var errs []error
+	t := reflect.TypeFor[Config]()
+	v := reflect.ValueOf(&cfg).Elem()
+	for i := range t.NumField() {
+		key := t.Field(i).Tag.Get("env")
+		if value, ok := os.LookupEnv(key); ok {
+			if err := setField(v.Field(i), value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", key, err))
+			}
+		}
+	}
+	for i, value := range overrides {
+		if err := setField(v.Field(i), value); err != nil {
+			errs = append(errs, fmt.Errorf("--%s: %w", strings.ReplaceAll(t.Field(i).Tag.Get("yaml"), "_", "-"), err))
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return Config{}, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// loadEnvFile loads path into the environment without replacing variables
+// that are already set. An empty path means an optional .env.
+func loadEnvFile(path string) error {
+	if path == "" {
+		if _, err := os.Stat(".env"); errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		path = ".env"
+	}
+	if err := godotenv.Load(path); err != nil {
+		return fmt.Errorf("env file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadFile(path string) error {
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("config file %s: unsupported format, use .yaml or .yml", path)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func setField(field reflect.Value, value string) error {
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", value)
+		}
+		field.SetInt(i)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", value)
+		}
+		field.SetBool(b)
+	default:
+		panic("config: unsupported field kind " + field.Kind().String())
+	}
+	return nil
+}
+
+// IsAdmin reports whether email is listed in the comma-separated
+// ADMIN_EMAILS. Matching ignores case and surrounding spaces.
+func (c Config) IsAdmin(email string) bool {
+	for _, admin := range strings.Split(c.AdminEmails, ",") {
+		admin = strings.TrimSpace(admin)
+		if admin != "" && strings.EqualFold(admin, email) {
+			return true
+		}
+	}
+	return false
+}
+
+const redacted = "REDACTED"
+
+// Redacted returns a copy of c with secrets replaced, safe to log or print.
+// DATABASE_URL keeps everything but its password when it parses as a URL.
+func (c Config) Redacted() Config {
+	databaseURL := c.DatabaseURL
+	v := reflect.ValueOf(&c).Elem()
+	t := v.Type()
+	for i := range t.NumField() {
+		if t.Field(i).Tag.Get("secret") != "true" || v.Field(i).String() == "" {
+			continue
+		}
+		v.Field(i).SetString(redacted)
+	}
+
+	if u, err := url.Parse(databaseURL); err == nil && u.Scheme != "" {
+		query := u.Query()
+		if query.Has("password") {
+			query.Set("password", "xxxxx")
+			u.RawQuery = query.Encode()
+		}
+		c.DatabaseURL = u.Redacted()
+	}
+	return c
+}
+
+// WriteYAML writes c in the config file format.
+func (c Config) WriteYAML(w io.Writer) error {
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
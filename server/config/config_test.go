@@ -0,0 +1,230 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+// isolate runs the test in an empty directory so no .env is picked up.
+func isolate(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("ENV_FILE", "")
+	return dir
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := isolate(t)
+	configFile := filepath.Join(dir, "config.yaml")
+	writeFile(t, configFile, "port: \":7000\"\ndb_host: file-host\ndb_name: file-db\njwt_secret: "+testSecret+"\n")
+	t.Setenv("DB_HOST", "env-host")
+	t.Setenv("DB_NAME", "env-db")
+	t.Setenv("JWT_EXP", "60")
+
+	cfg, err := Load([]string{"--config", configFile, "--db-name", "flag-db", "--auto-migrate"})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if cfg.Port != ":7000" {
+		t.Errorf("Port = %q, want the file value", cfg.Port)
+	}
+	if cfg.DBHost != "env-host" {
+		t.Errorf("DBHost = %q, want env to override the file", cfg.DBHost)
+	}
+	if cfg.DBName != "flag-db" {
+		t.Errorf("DBName = %q, want the flag to override env", cfg.DBName)
+	}
+	if !cfg.AutoMigrate {
+		t.Error("AutoMigrate = false, want a bare bool flag to enable it")
+	}
+	if cfg.JWTExpirationInSeconds != 60 {
+		t.Errorf("JWTExpirationInSeconds = %d, want 60", cfg.JWTExpirationInSeconds)
+	}
+	if cfg.ShutdownGraceInSeconds != Defaults().ShutdownGraceInSeconds {
+		t.Errorf("ShutdownGraceInSeconds = %d, want the default", cfg.ShutdownGraceInSeconds)
+	}
+}
+
+func TestLoadEnvFile(t *testing.T) {
+	dir := isolate(t)
+	writeFile(t, filepath.Join(dir, ".env"), "JWT_SECRET="+testSecret+"\nDB_NAME=dotenv-db\n")
+	// godotenv sets variables for the rest of the process; restore them.
+	t.Setenv("JWT_SECRET", "")
+	os.Unsetenv("JWT_SECRET")
+	t.Setenv("DB_NAME", "")
+	os.Unsetenv("DB_NAME")
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.DBName != "dotenv-db" {
+		t.Errorf("DBName = %q, want the .env value", cfg.DBName)
+	}
+
+	if _, err := Load([]string{"--env-file", filepath.Join(dir, "missing.env")}); err == nil {
+		t.Error("expected an error for a missing explicit env file")
+	}
+}
+
+func TestLoadRejectsInvalidValues(t *testing.T) {
+	isolate(t)
+	t.Setenv("JWT_SECRET", "CHANGE_ME")
+	t.Setenv("JWT_EXP", "a week")
+	t.Setenv("AUTO_MIGRATE", "sometimes")
+
+	_, err := Load(nil)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{`JWT_EXP: invalid integer "a week"`, `AUTO_MIGRATE: invalid boolean "sometimes"`} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestLoadRejectsUnknownFileKeys(t *testing.T) {
+	dir := isolate(t)
+	configFile := filepath.Join(dir, "config.yaml")
+	writeFile(t, configFile, "jwt_secret: "+testSecret+"\ndb_hots: typo\n")
+
+	if _, err := Load([]string{"--config", configFile}); err == nil || !strings.Contains(err.Error(), "db_hots") {
+		t.Fatalf("err = %v, want it to name the unknown key", err)
+	}
+
+	tomlFile := filepath.Join(dir, "config.toml")
+	writeFile(t, tomlFile, "")
+	if _, err := Load([]string{"--config", tomlFile}); err == nil {
+		t.Fatal("expected an error for an unsupported file format")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	// migrate and seed do not sign tokens, so they run without a secret.
+	valid := Defaults()
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("defaults should be valid: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"bad port", func(c *Config) { c.Port = "8000" }, "PORT:"},
+		{"bad driver", func(c *Config) { c.DBDriver = "mysql" }, "DB_DRIVER:"},
+		{"bad db port", func(c *Config) { c.DBPort = "abc" }, "DB_PORT:"},
+		{"negative timeout", func(c *Config) { c.DBTimeoutInSeconds = -1 }, "DB_TIMEOUT: must not be negative"},
+		{"half tls", func(c *Config) { c.TLSCertFile = "cert.pem" }, "TLS_CERT_FILE:"},
+		{"log format", func(c *Config) { c.LogFormat = "xml" }, "LOG_FORMAT:"},
+		{"admin without at sign", func(c *Config) { c.AdminEmails = "ops@example.com, ops" }, `ADMIN_EMAILS: "ops" is not an email address`},
+		{"sqlite without path", func(c *Config) { c.DBDriver = DriverSQLite; c.SQLitePath = "" }, "SQLITE_PATH:"},
+		{"smtp without from", func(c *Config) { c.SMTPAddr = "smtp.example.com:587" }, "SMTP_FROM:"},
+		{"negative digest interval", func(c *Config) { c.DigestIntervalInSeconds = -1 }, "DIGEST_INTERVAL: must not be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("database url replaces the parts", func(t *testing.T) {
+		cfg := valid
+		cfg.DatabaseURL = "postgres://app@db/tracker"
+		cfg.DBHost, cfg.DBPort, cfg.DBSSLMode = "", "", ""
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("Validate: %v", err)
+		}
+	})
+}
+
+func TestValidateServer(t *testing.T) {
+	valid := Defaults()
+	valid.JWTSecret = testSecret
+	if err := valid.ValidateServer(); err != nil {
+		t.Fatalf("defaults with a secret should be valid: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(*Config)
+		want   string
+	}{
+		{"missing secret", func(c *Config) { c.JWTSecret = "" }, "JWT_SECRET: is required"},
+		{"placeholder secret", func(c *Config) { c.JWTSecret = "CHANGE_ME" }, "JWT_SECRET: replace the placeholder"},
+		{"short secret", func(c *Config) { c.JWTSecret = "short" }, "JWT_SECRET: must be at least 32 bytes"},
+		{"zero expiry", func(c *Config) { c.JWTExpirationInSeconds = 0 }, "JWT_EXP: must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.mutate(&cfg)
+			err := cfg.ValidateServer()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	cfg := Config{AdminEmails: " Ops@Example.com ,root@example.com,"}
+	for email, want := range map[string]bool{
+		"ops@example.com":  true,
+		"root@example.com": true,
+		"user@example.com": false,
+		"":                 false,
+	} {
+		if got := cfg.IsAdmin(email); got != want {
+			t.Errorf("IsAdmin(%q) = %v, want %v", email, got, want)
+		}
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := Defaults()
+	cfg.JWTSecret = testSecret
+	cfg.DBPassword = "db-pass"
+	cfg.DatabaseURL = "postgres://app:url-pass@db:5432/tracker?sslmode=require&password=query-pass"
+
+	var out bytes.Buffer
+	if err := cfg.Redacted().WriteYAML(&out); err != nil {
+		t.Fatalf("WriteYAML: %v", err)
+	}
+
+	text := out.String()
+	for _, secret := range []string{testSecret, "db-pass", "url-pass", "query-pass"} {
+		if strings.Contains(text, secret) {
+			t.Errorf("output leaks %q:\n%s", secret, text)
+		}
+	}
+	for _, want := range []string{"jwt_secret: REDACTED", "metrics_token: \"\"", "db:5432/tracker"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output missing %q:\n%s", want, text)
+		}
+	}
+	if cfg.JWTSecret != testSecret {
+		t.Error("Redacted modified the original config")
+	}
+}
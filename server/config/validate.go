@@ -0,0 +1,103 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// minJWTSecretLength is the shortest accepted signing key: 32 bytes matches
+// the output size of HS256.
+const minJWTSecretLength = 32
+
+// Validate reports every invalid or missing setting at once, each prefixed
+// with its environment variable. Settings only the API server uses, such as
+// JWT_SECRET, are checked by ValidateServer instead, so that migrate and
+// seed run without them.
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: "+format, append([]any{key}, args...)...))
+		}
+	}
+	oneOf := func(value, key string, allowed ...string) {
+		check(slices.Contains(allowed, value), key, "must be one of %q, got %q", allowed, value)
+	}
+
+	_, _, err := net.SplitHostPort(c.Port)
+	check(err == nil, "PORT", "must be host:port or :port, got %q", c.Port)
+	_, err = url.ParseRequestURI(c.PublicHost)
+	check(err == nil, "PUBLIC_HOST", "must be an absolute URL, got %q", c.PublicHost)
+	check(c.ReadTimeoutInSeconds >= 0, "HTTP_READ_TIMEOUT", "must not be negative")
+	check(c.WriteTimeoutInSeconds >= 0, "HTTP_WRITE_TIMEOUT", "must not be negative")
+	check(c.IdleTimeoutInSeconds >= 0, "HTTP_IDLE_TIMEOUT", "must not be negative")
+	check(c.ShutdownGraceInSeconds >= 0, "SHUTDOWN_GRACE", "must not be negative")
+	check((c.TLSCertFile == "") == (c.TLSKeyFile == ""), "TLS_CERT_FILE", "TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+
+	oneOf(c.DBDriver, "DB_DRIVER", DriverPostgres, DriverSQLite)
+	switch c.DBDriver {
+	case DriverSQLite:
+		check(c.SQLitePath != "", "SQLITE_PATH", "is required when DB_DRIVER=%s", DriverSQLite)
+	case DriverPostgres:
+		if c.DatabaseURL == "" {
+			check(c.DBHost != "", "DB_HOST", "is required unless DATABASE_URL is set")
+			check(c.DBUser != "", "DB_USER", "is required unless DATABASE_URL is set")
+			check(c.DBName != "", "DB_NAME", "is required unless DATABASE_URL is set")
+			_, err := strconv.ParseUint(c.DBPort, 10, 16)
+			check(err == nil, "DB_PORT", "must be a port number, got %q", c.DBPort)
+			oneOf(c.DBSSLMode, "DB_SSLMODE", "disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+		}
+	}
+	check(c.DBTimeoutInSeconds >= 0, "DB_TIMEOUT", "must not be negative")
+	check(c.DBMaxOpenConns >= 0, "DB_MAX_OPEN_CONNS", "must not be negative")
+	check(c.DBMaxIdleConns >= 0, "DB_MAX_IDLE_CONNS", "must not be negative")
+	check(c.DBConnMaxLifetimeInSeconds >= 0, "DB_CONN_MAX_LIFETIME", "must not be negative")
+	check(c.DBConnMaxIdleTimeInSeconds >= 0, "DB_CONN_MAX_IDLE_TIME", "must not be negative")
+	check(c.DBStatementTimeoutInSeconds >= 0, "DB_STATEMENT_TIMEOUT", "must not be negative")
+	check(c.DBConnectRetryTimeoutInSeconds >= 0, "DB_CONNECT_RETRY_TIMEOUT", "must not be negative")
+
+	for _, admin := range strings.Split(c.AdminEmails, ",") {
+		admin = strings.TrimSpace(admin)
+		check(admin == "" || strings.Contains(admin, "@"), "ADMIN_EMAILS", "%q is not an email address", admin)
+	}
+	check(c.RecurrenceIntervalInSeconds >= 0, "RECURRENCE_INTERVAL", "must not be negative")
+	check(c.ReportOverloadThreshold >= 0, "REPORT_OVERLOAD_THRESHOLD", "must not be negative")
+	check(c.NotifyIntervalInSeconds >= 0, "NOTIFY_INTERVAL", "must not be negative")
+	check(c.DueSoonDays >= 0, "DUE_SOON_DAYS", "must not be negative")
+	check(c.DigestIntervalInSeconds >= 0, "DIGEST_INTERVAL", "must not be negative")
+	if c.SMTPAddr != "" {
+		_, _, err := net.SplitHostPort(c.SMTPAddr)
+		check(err == nil, "SMTP_ADDR", "must be host:port, got %q", c.SMTPAddr)
+		check(strings.Contains(c.SMTPFrom, "@"), "SMTP_FROM", "must be an email address when SMTP_ADDR is set")
+	}
+
+	// The logging and tracing packages match these case-insensitively.
+	oneOf(strings.ToLower(c.LogLevel), "LOG_LEVEL", "debug", "info", "warn", "error")
+	oneOf(strings.ToLower(c.LogFormat), "LOG_FORMAT", "json", "text")
+	oneOf(strings.ToLower(c.TracingExporter), "TRACING_EXPORTER", "none", "otlp", "stdout")
+
+	return errors.Join(errs...)
+}
+
+// ValidateServer reports the invalid or missing settings that only the API
+// server uses, in the same form as Validate.
+func (c Config) ValidateServer() error {
+	var errs []error
+	if c.JWTExpirationInSeconds <= 0 {
+		errs = append(errs, errors.New("JWT_EXP: must be positive"))
+	}
+	switch {
+	case c.JWTSecret == "":
+		errs = append(errs, errors.New("JWT_SECRET: is required"))
+	case c.JWTSecret == "CHANGE_ME":
+		errs = append(errs, errors.New("JWT_SECRET: replace the placeholder value, e.g. with the output of `openssl rand -base64 48`"))
+	case len(c.JWTSecret) < minJWTSecretLength:
+		errs = append(errs, fmt.Errorf("JWT_SECRET: must be at least %d bytes", minJWTSecretLength))
+	}
+	return errors.Join(errs...)
+}
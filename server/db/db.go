@@ -2,27 +2,163 @@ package db
 
 import (
 	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/tracing"
+	"context"
 	"database/sql"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/url"
+	"strconv"
+	"time"
 
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	_ "modernc.org/sqlite"
 )
 
-func NewPostgresStorage(cfg config.Config) (*sql.DB, error) {
-	dsn := (&url.URL{
-		Scheme: "postgres",
-		User:   url.UserPassword(cfg.DBUser, cfg.DBPassword),
-		Host:   cfg.DBHost + ":" + cfg.DBPort,
-		Path:   cfg.DBName,
-	}).String() + "?sslmode=" + cfg.DBSSLMode
+// Bounds for the delay between connection attempts while the database is
+// still starting.
+const (
+	initialRetryDelay = 250 * time.Millisecond
+	maxRetryDelay     = 5 * time.Second
+)
+
+// Open connects to the backend selected by cfg.DBDriver. It returns once the
+// database answers a ping or ctx is cancelled.
+func Open(ctx context.Context, cfg config.Config) (*sql.DB, error) {
+	switch cfg.DBDriver {
+	case config.DriverPostgres:
+		return NewPostgresStorage(ctx, cfg)
+	case config.DriverSQLite:
+		return NewSQLiteStorage(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q (want %q or %q)", cfg.DBDriver, config.DriverPostgres, config.DriverSQLite)
+	}
+}
+
+// NewPostgresStorage opens a connection pool sized by cfg and waits up to
+// cfg.DBConnectRetryTimeoutInSeconds for the server to accept connections.
+func NewPostgresStorage(ctx context.Context, cfg config.Config) (*sql.DB, error) {
+	connConfig, err := postgresConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	db := stdlib.OpenDB(*connConfig)
+	db.SetMaxOpenConns(int(cfg.DBMaxOpenConns))
+	db.SetMaxIdleConns(int(cfg.DBMaxIdleConns))
+	db.SetConnMaxLifetime(time.Duration(cfg.DBConnMaxLifetimeInSeconds) * time.Second)
+	db.SetConnMaxIdleTime(time.Duration(cfg.DBConnMaxIdleTimeInSeconds) * time.Second)
 
-	db, err := sql.Open("pgx", dsn)
+	retryFor := time.Duration(cfg.DBConnectRetryTimeoutInSeconds) * time.Second
+	if err := pingWithRetry(ctx, db, retryFor); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// postgresConfig builds the pgx configuration from cfg.DatabaseURL, or from
+// the individual DB_* settings when it is empty. Session parameters already
+// present in DATABASE_URL take precedence over the config defaults.
+func postgresConfig(cfg config.Config) (*pgx.ConnConfig, error) {
+	dsn := cfg.DatabaseURL
+	if dsn == "" {
+		dsn = (&url.URL{
+			Scheme:   "postgres",
+			User:     url.UserPassword(cfg.DBUser, cfg.DBPassword),
+			Host:     cfg.DBHost + ":" + cfg.DBPort,
+			Path:     cfg.DBName,
+			RawQuery: url.Values{"sslmode": {cfg.DBSSLMode}}.Encode(),
+		}).String()
+	}
+
+	connConfig, err := pgx.ParseConfig(dsn)
 	if err != nil {
-		log.Fatal("Failed to open PostgreSQL connection:", err)
+		// pgx redacts the password in its own message but wraps URL parse
+		// errors that quote the raw DSN, so report only the cause for those.
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return nil, fmt.Errorf("invalid database configuration: %w", urlErr.Err)
+		}
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
+	}
+	connConfig.Tracer = tracing.QueryTracer{}
+
+	if _, ok := connConfig.RuntimeParams["application_name"]; !ok && cfg.DBApplicationName != "" {
+		connConfig.RuntimeParams["application_name"] = cfg.DBApplicationName
+	}
+	if _, ok := connConfig.RuntimeParams["statement_timeout"]; !ok && cfg.DBStatementTimeoutInSeconds > 0 {
+		connConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(cfg.DBStatementTimeoutInSeconds*1000, 10)
+	}
+
+	return connConfig, nil
+}
+
+type pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// pingWithRetry pings db until it answers, backing off exponentially between
+// attempts. It gives up with the last error once retryFor has elapsed; a
+// zero retryFor makes a single attempt.
+func pingWithRetry(ctx context.Context, db pinger, retryFor time.Duration) error {
+	deadline := time.Now().Add(retryFor)
+	delay := initialRetryDelay
+
+	for attempt := 1; ; attempt++ {
+		err := db.PingContext(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("database not reachable after %d attempt(s): %w", attempt, err)
+		}
+		wait := min(delay, remaining)
+		slog.Warn("database not reachable, retrying", "attempt", attempt, "retry_in", wait.String(), "error", err)
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		delay = min(delay*2, maxRetryDelay)
+	}
+}
+
+// NewSQLiteStorage opens the SQLite database file at cfg.SQLitePath, creating
+// it if needed, with foreign keys enforced.
+func NewSQLiteStorage(ctx context.Context, cfg config.Config) (*sql.DB, error) {
+	query := url.Values{}
+	query.Add("_pragma", "foreign_keys(1)")
+	query.Add("_pragma", "busy_timeout(5000)")
+	query.Add("_pragma", "journal_mode(WAL)")
+	// Store time.Time arguments in a format SQLite date functions and the
+	// driver can parse back.
+	query.Set("_time_format", "sqlite")
+
+	// SQLite decodes %XX escapes in file: URIs, so characters such as ?, #
+	// and % in the path are escaped rather than ending it.
+	dsn := url.URL{Scheme: "file", Opaque: (&url.URL{Path: cfg.SQLitePath}).EscapedPath(), RawQuery: query.Encode()}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, err
 	}
+	// SQLite allows a single writer; one connection serializes requests
+	// instead of failing them with SQLITE_BUSY.
+	db.SetMaxOpenConns(1)
 
-	if err := db.Ping(); err != nil {
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, err
 	}
 
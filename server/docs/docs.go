@@ -15,29 +15,32 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/goals": {
+        "/admin/export": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all goals with nested tasks for authenticated users",
+                "description": "Stream every user (with password hash), goal and task as a JSON document accepted by POST /admin/import. Requires the caller's email in ADMIN_EMAILS.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "goals"
+                    "admin"
                 ],
-                "summary": "Get goals",
+                "summary": "Export the whole instance",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.GoalWithTasks"
-                            }
+                            "$ref": "#/definitions/types.InstanceSnapshot"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
                     "403": {
@@ -53,14 +56,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/import": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a goal owned by the authenticated user",
+                "description": "Add the users, goals and tasks from a GET /admin/export document in one transaction. Users are matched by email and reused; goals and tasks are always added with new IDs. Requires the caller's email in ADMIN_EMAILS.",
                 "consumes": [
                     "application/json"
                 ],
@@ -68,25 +73,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "goals"
+                    "admin"
                 ],
-                "summary": "Create goal",
+                "summary": "Import an instance export",
                 "parameters": [
                     {
-                        "description": "Goal payload",
+                        "description": "Instance export",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateGoalPayload"
+                            "$ref": "#/definitions/types.InstanceSnapshot"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Goal"
+                            "$ref": "#/definitions/types.ImportSummary"
                         }
                     },
                     "400": {
@@ -95,12 +100,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -110,57 +127,59 @@ const docTemplate = `{
                 }
             }
         },
-        "/goals/{goalID}": {
-            "put": {
+        "/calendar/token": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a goal owned by the authenticated user",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Create a secret token for the caller's iCalendar feed and return the subscription URL. Calling it again replaces the token, so the previous URL stops working. The token is only shown in this response.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "goals"
+                    "calendar"
                 ],
-                "summary": "Update goal",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Goal ID",
-                        "name": "goalID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Goal payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
+                "summary": "Create or regenerate the calendar feed token",
+                "responses": {
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/types.CreateGoalPayload"
+                            "$ref": "#/definitions/types.CalendarTokenResponse"
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/types.Goal"
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the caller's calendar feed token so its URL stops working.",
+                "tags": [
+                    "calendar"
+                ],
+                "summary": "Disable the calendar feed",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -172,33 +191,44 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/digest/preview": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a goal owned by the authenticated user",
+                "description": "Build the caller's digest as it would be sent now, without sending it: as JSON, or rendered as the HTML or plain-text email body. A digest that is off previews as daily.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/html",
+                    "text/plain"
                 ],
                 "tags": [
-                    "goals"
+                    "digest"
                 ],
-                "summary": "Delete goal",
+                "summary": "Preview digest",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Goal ID",
-                        "name": "goalID",
-                        "in": "path",
-                        "required": true
+                        "enum": [
+                            "json",
+                            "html",
+                            "text"
+                        ],
+                        "type": "string",
+                        "description": "json (default), html or text",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Digest"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -206,8 +236,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -221,45 +251,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/goals/{goalID}/tasks": {
+        "/digest/settings": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a single goal with its tasks for authenticated users",
+                "description": "Get when the caller's digest is emailed. Users who have not chosen receive none; turning it on defaults to daily at 8:00 UTC. lastSentOn is the local date of the last digest sent.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
-                ],
-                "summary": "Get tasks by goal",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Goal ID",
-                        "name": "goalID",
-                        "in": "path",
-                        "required": true
-                    }
+                    "digest"
                 ],
+                "summary": "Get digest settings",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.GoalWithTasks"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/types.ErrorResponse"
+                            "$ref": "#/definitions/types.DigestSettings"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -272,13 +287,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a task under a goal. Only the goal owner can create tasks.",
+                "description": "Choose the caller's digest: off, daily or weekly on weekday (0 is Sunday), sent once sendHour (0 to 23) has struck in timeZone, an IANA time zone such as Europe/Berlin.",
                 "consumes": [
                     "application/json"
                 ],
@@ -286,32 +301,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "digest"
                 ],
-                "summary": "Create task",
+                "summary": "Set digest settings",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Goal ID",
-                        "name": "goalID",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Task payload",
+                        "description": "Settings",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.CreateTaskPayload"
+                            "$ref": "#/definitions/types.UpdateDigestSettingsPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Task"
+                            "$ref": "#/definitions/types.DigestSettings"
                         }
                     },
                     "400": {
@@ -320,8 +328,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -335,35 +343,39 @@ const docTemplate = `{
                 }
             }
         },
-        "/login": {
-            "post": {
-                "description": "Authenticate a user and return a JWT token",
-                "consumes": [
-                    "application/json"
+        "/export": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Stream every goal the caller can see with its tasks as JSON (` + "`" + `{\"exportedAt\",\"goals\":[...]}` + "`" + `) or CSV (one row per task; goals without tasks get one row with empty task columns)",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "auth"
+                    "export"
                 ],
-                "summary": "Login",
+                "summary": "Export goals and tasks",
                 "parameters": [
                     {
-                        "description": "Login payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.LoginUserPayload"
-                        }
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.LoginResponse"
+                            "$ref": "#/definitions/types.GoalsExport"
                         }
                     },
                     "400": {
@@ -371,34 +383,49 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/profile": {
+        "/goals": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get authenticated user's profile",
+                "description": "Get all goals with nested tasks for authenticated users",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "goals"
                 ],
-                "summary": "Get profile",
+                "summary": "Get goals",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.UserProfile"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.GoalWithTasks"
+                            }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -411,13 +438,13 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update authenticated user's first and last name",
+                "description": "Create a goal owned by the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -425,25 +452,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "goals"
                 ],
-                "summary": "Update profile",
+                "summary": "Create goal",
                 "parameters": [
                     {
-                        "description": "Profile payload",
+                        "description": "Goal payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateProfilePayload"
+                            "$ref": "#/definitions/types.CreateGoalPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/types.UserProfile"
+                            "$ref": "#/definitions/types.Goal"
                         }
                     },
                     "400": {
@@ -452,8 +479,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -467,14 +494,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/profile/password": {
+        "/goals/{goalID}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Change authenticated user's password",
+                "description": "Update a goal owned by the authenticated user",
                 "consumes": [
                     "application/json"
                 ],
@@ -482,23 +509,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "goals"
                 ],
-                "summary": "Update password",
+                "summary": "Update goal",
                 "parameters": [
                     {
-                        "description": "Password payload",
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "goalID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Goal payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdatePasswordPayload"
+                            "$ref": "#/definitions/types.CreateGoalPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Goal"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -506,12 +543,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -519,72 +568,108 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/register": {
-            "post": {
-                "description": "Create a new user account",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Delete a goal owned by the authenticated user",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "auth"
+                    "goals"
                 ],
-                "summary": "Register",
+                "summary": "Delete goal",
                 "parameters": [
                     {
-                        "description": "Registration payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/types.RegisterUserPayload"
-                        }
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "goalID",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
                     }
                 }
             }
         },
-        "/tasks/assigned": {
+        "/goals/{goalID}/tasks": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get tasks assigned to the authenticated user",
+                "description": "Get a single goal with its tasks for authenticated users, with the total estimated and spent minutes",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Get assigned tasks",
+                "summary": "Get tasks by goal",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "goalID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.Task"
-                            }
+                            "$ref": "#/definitions/types.GoalWithTasks"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
                     "403": {
@@ -593,6 +678,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -600,16 +691,14 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/tasks/{taskID}": {
-            "put": {
+            },
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a task under a goal owned by the authenticated user",
+                "description": "Create a task under a goal. Only the goal owner can create tasks. A task with a recurrence rule (FREQ=DAILY, FREQ=WEEKLY;BYDAY=MO,TH or FREQ=MONTHLY;BYMONTHDAY=15) needs a due date; its next occurrence is created when it is completed or its due date arrives.",
                 "consumes": [
                     "application/json"
                 ],
@@ -619,28 +708,28 @@ const docTemplate = `{
                 "tags": [
                     "tasks"
                 ],
-                "summary": "Update task",
+                "summary": "Create task",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
-                        "name": "taskID",
+                        "description": "Goal ID",
+                        "name": "goalID",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Task update payload",
+                        "description": "Task payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.UpdateTaskPayload"
+                            "$ref": "#/definitions/types.CreateTaskPayload"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
                             "$ref": "#/definitions/types.Task"
                         }
@@ -651,12 +740,24 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -664,33 +765,50 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/goals/{goalID}/time": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a task from a goal owned by the authenticated user",
+                "description": "Total estimated and spent minutes on a goal, per task and per user. Only stopped entries count, by the date they started. from and to are inclusive UTC dates and default to all time.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "time"
                 ],
-                "summary": "Delete task",
+                "summary": "Goal time report",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Task ID",
-                        "name": "taskID",
+                        "description": "Goal ID",
+                        "name": "goalID",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.GoalTimeReport"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -698,8 +816,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -713,39 +837,124 @@ const docTemplate = `{
                 }
             }
         },
-        "/tasks/{taskID}/assign": {
-            "put": {
+        "/imports/{source}": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Assign or unassign a task. Only the goal owner can assign tasks.",
+                "description": "Upload a Trello board JSON export, a Jira CSV export or a JSON array of GitHub issues as the multipart field ` + "`" + `file` + "`" + `. Trello lists, Jira epics and GitHub milestones become goals owned by the caller; cards and issues become their tasks. Assignees are matched to users by email: identities that are not emails are looked up in the optional ` + "`" + `assignees` + "`" + ` field, a JSON object such as ` + "`" + `{\"octocat\":\"octo@example.com\"}` + "`" + `. With dryRun=true nothing is written and the report lists what would be created, the identities without a user and any validation errors. Otherwise everything is created in one transaction, or nothing is when there are validation errors. Unmapped assignees are left unassigned.",
                 "consumes": [
-                    "application/json"
+                    "multipart/form-data"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "tasks"
+                    "import"
                 ],
-                "summary": "Assign task",
+                "summary": "Import goals and tasks from another tool",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Task ID",
-                        "name": "taskID",
+                        "enum": [
+                            "trello",
+                            "jira",
+                            "github"
+                        ],
+                        "type": "string",
+                        "description": "Export format",
+                        "name": "source",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Assignment payload",
+                        "type": "boolean",
+                        "description": "Preview without creating anything",
+                        "name": "dryRun",
+                        "in": "query"
+                    },
+                    {
+                        "type": "file",
+                        "description": "Export file",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JSON object mapping source identities to user emails",
+                        "name": "assignees",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Dry run",
+                        "schema": {
+                            "$ref": "#/definitions/types.ImportReport"
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.ImportReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/login": {
+            "post": {
+                "description": "Authenticate a user and return a JWT token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Login",
+                "parameters": [
+                    {
+                        "description": "Login payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/types.AssignTaskPayload"
+                            "$ref": "#/definitions/types.LoginUserPayload"
                         }
                     }
                 ],
@@ -753,7 +962,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/types.Task"
+                            "$ref": "#/definitions/types.LoginResponse"
                         }
                     },
                     "400": {
@@ -762,12 +971,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/types.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -777,33 +980,56 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/lookup": {
+        "/notifications": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "List users for assignment lookups",
+                "description": "List the caller's in-app notifications, newest first, with the number of unread ones. Pass the id of the last notification as before to get the next page.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "notifications"
+                ],
+                "summary": "List notifications",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only unread notifications",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only notifications with a smaller id",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1 to 200 (default 50)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "List users lookup",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/types.UserLookup"
-                            }
+                            "$ref": "#/definitions/types.NotificationList"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -817,33 +1043,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/users/tasks": {
+        "/notifications/preferences": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all users and their current assigned tasks (not completed)",
+                "description": "Get the caller's delivery channel for every event type: in_app, email or none. Event types default to in_app.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "notifications"
                 ],
-                "summary": "Get users with current tasks",
+                "summary": "Get notification preferences",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/types.UserTasksBoard"
+                                "$ref": "#/definitions/types.NotificationPreference"
                             }
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/types.ErrorResponse"
                         }
@@ -855,29 +1081,2428 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Set the caller's delivery channel for the listed event types; other event types keep theirs. Returns every preference.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Set notification preferences",
+                "parameters": [
+                    {
+                        "description": "Preferences",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateNotificationPreferencesPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.NotificationPreference"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/read-all": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark every unread notification of the caller as read.",
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark all notifications read",
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/notifications/{notificationID}/read": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark one of the caller's notifications as read. Marking a read notification again keeps its read time.",
+                "tags": [
+                    "notifications"
+                ],
+                "summary": "Mark notification read",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Notification ID",
+                        "name": "notificationID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get authenticated user's profile",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UserProfile"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update authenticated user's first and last name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update profile",
+                "parameters": [
+                    {
+                        "description": "Profile payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateProfilePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UserProfile"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/profile/password": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Change authenticated user's password",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update password",
+                "parameters": [
+                    {
+                        "description": "Password payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdatePasswordPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/register": {
+            "post": {
+                "description": "Create a new user account",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "auth"
+                ],
+                "summary": "Register",
+                "parameters": [
+                    {
+                        "description": "Registration payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.RegisterUserPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/completed-per-week": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Completed assigned tasks per assignee and week, by completion time. Weeks start on Monday (UTC). from and to are inclusive UTC dates and default to all time.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Tasks completed per user per week",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.WeeklyCompletion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/cycle-time": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Average time from creation to completion of the tasks completed in the range. from and to are inclusive UTC dates and default to all time.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Average cycle time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.CycleTimeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/goals/{goalID}/burnup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Total and completed tasks of a goal at the end of each day. from and to are inclusive UTC dates; the range defaults to the 30 days ending today and spans at most 366 days. Only the goal's current tasks count.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Goal burn-up",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Goal ID",
+                        "name": "goalID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.BurnUpPoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/open-by-priority": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Number of open tasks per priority, from high to low.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Open tasks by priority",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.PriorityCount"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reports/overloaded": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Users with more open assigned tasks than the threshold, most loaded first. The threshold defaults to REPORT_OVERLOAD_THRESHOLD.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "reports"
+                ],
+                "summary": "Overloaded users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Open task threshold",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv"
+                        ],
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.OverloadReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/assigned": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get tasks assigned to the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Get assigned tasks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Task"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{taskID}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a task under a goal owned by the authenticated user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Update task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Task update payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.UpdateTaskPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a task from a goal owned by the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Delete task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{taskID}/assign": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign or unassign a task. Only the goal owner can assign tasks. The new assignee is notified.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Assign task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Assignment payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.AssignTaskPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{taskID}/move": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a task after afterId, before beforeId, or between the two. On the goal list (the default) only the goal owner can move tasks; on the assigned list the assignee orders their own tasks. The other tasks keep their places.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tasks"
+                ],
+                "summary": "Move task",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Move payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.MoveTaskPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.Task"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{taskID}/time-entries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List a task's time entries from every user, running timers included, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time"
+                ],
+                "summary": "List time entries",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.TimeEntry"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record finished work on a task for the caller. startedAt defaults to the given number of minutes before now, and the entry cannot end in the future.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time"
+                ],
+                "summary": "Log time",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Time entry payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateTimeEntryPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tasks/{taskID}/timer/start": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Start a timer on a task for the caller. Each user has at most one running timer; stop it before starting another. The body may be omitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time"
+                ],
+                "summary": "Start timer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Task ID",
+                        "name": "taskID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Timer payload",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/types.StartTimerPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.TimeEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List the caller's goal templates with their tasks, ordered by name.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "List templates",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.Template"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a goal template, or save an existing goal and its tasks as one with fromGoalId. Titles and descriptions may contain placeholders, a name in double braces; when saving a goal, placeholders maps each name to the text it replaces, such as {\"client\": \"Acme\"}. Due dates are day offsets from the date the template is instantiated; a saved goal's dates count from the day it was created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Create template",
+                "parameters": [
+                    {
+                        "description": "Template payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/types.CreateTemplatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.Template"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{templateID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete one of the caller's templates. Goals created from it are kept.",
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Delete template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "templateID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/templates/{templateID}/instantiate": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a goal owned by the caller, with its tasks, from one of the caller's templates. Placeholders are replaced by values, and every placeholder the template uses needs a value. Due dates are the template's offsets added to startDate, which defaults to today (UTC). The goal starts as todo and its tasks open and unassigned. The body may be omitted for templates without placeholders.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "templates"
+                ],
+                "summary": "Instantiate template",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Template ID",
+                        "name": "templateID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Instantiation payload",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/types.InstantiateTemplatePayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/types.GoalWithTasks"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/time-entries/{entryID}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete one of the caller's time entries. Deleting a running timer discards it.",
+                "tags": [
+                    "time"
+                ],
+                "summary": "Delete time entry",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Time entry ID",
+                        "name": "entryID",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timer": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the caller's running timer.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time"
+                ],
+                "summary": "Get running timer",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.TimeEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/timer/stop": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Stop the caller's running timer and record its duration.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time"
+                ],
+                "summary": "Stop timer",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.TimeEntry"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/lookup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "List users for assignment lookups",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "List users lookup",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.UserLookup"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/tasks": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all users and their current assigned tasks (not completed)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get users with current tasks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/types.UserTasksBoard"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{userID}/time": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Minutes a user spent, in total and per goal. Only stopped entries count, by the date they started. from and to are inclusive UTC dates and default to all time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "time"
+                ],
+                "summary": "User time report",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "User ID",
+                        "name": "userID",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/types.UserTimeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/types.ErrorResponse"
+                        }
+                    }
+                }
+            }
         }
     },
     "definitions": {
         "types.AssignTaskPayload": {
             "type": "object",
             "properties": {
-                "assigneeId": {
+                "assigneeId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.BurnUpPoint": {
+            "type": "object",
+            "properties": {
+                "completedTasks": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "totalTasks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.CalendarTokenResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.CreateGoalPayload": {
+            "type": "object",
+            "required": [
+                "priority",
+                "title"
+            ],
+            "properties": {
+                "autoStatus": {
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "dueDate": {
+                    "description": "DueDate is a calendar date (YYYY-MM-DD); nil or omitted clears it.",
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "high",
+                        "medium",
+                        "low"
+                    ]
+                },
+                "progressWeight": {
+                    "type": "string",
+                    "enum": [
+                        "count",
+                        "priority",
+                        "estimate"
+                    ]
+                },
+                "status": {
+                    "description": "Status is ignored, and may be omitted, when AutoStatus is set.",
+                    "type": "string",
+                    "enum": [
+                        "todo",
+                        "in_progress",
+                        "achieved"
+                    ]
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                }
+            }
+        },
+        "types.CreateTaskPayload": {
+            "type": "object",
+            "required": [
+                "priority",
+                "title"
+            ],
+            "properties": {
+                "assigneeId": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "estimateMinutes": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 0
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "high",
+                        "medium",
+                        "low"
+                    ]
+                },
+                "recurrence": {
+                    "description": "Recurrence is an RRULE such as FREQ=WEEKLY;BYDAY=MO. It needs a due\ndate, which anchors the series.",
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                }
+            }
+        },
+        "types.CreateTemplatePayload": {
+            "type": "object",
+            "required": [
+                "name",
+                "placeholders",
+                "priority",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "dueInDays": {
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 0
+                },
+                "fromGoalId": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                },
+                "placeholders": {
+                    "description": "Placeholders maps placeholder names to the text they stand for in the\nsaved goal, e.g. {\"client\": \"Acme\"}; every occurrence of the text\nbecomes the placeholder.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "high",
+                        "medium",
+                        "low"
+                    ]
+                },
+                "tasks": {
+                    "type": "array",
+                    "maxItems": 200,
+                    "items": {
+                        "$ref": "#/definitions/types.TemplateTask"
+                    }
+                },
+                "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                }
+            }
+        },
+        "types.CreateTimeEntryPayload": {
+            "type": "object",
+            "required": [
+                "minutes"
+            ],
+            "properties": {
+                "minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "startedAt": {
+                    "description": "StartedAt defaults to Minutes before now.",
+                    "type": "string"
+                }
+            }
+        },
+        "types.CycleTimeReport": {
+            "type": "object",
+            "properties": {
+                "averageSeconds": {
+                    "type": "integer"
+                },
+                "completedTasks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.Digest": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DigestCompletion"
+                    }
+                },
+                "date": {
+                    "type": "string"
+                },
+                "frequency": {
+                    "type": "string"
+                },
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DigestGoal"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "newlyAssigned": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DigestTask"
+                    }
+                },
+                "since": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.DigestCompletion": {
+            "type": "object",
+            "properties": {
+                "assigneeName": {
+                    "type": "string"
+                },
+                "completedAt": {
+                    "type": "string"
+                },
+                "goalId": {
+                    "type": "integer"
+                },
+                "goalTitle": {
+                    "type": "string"
+                },
+                "taskId": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.DigestGoal": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.DigestTask"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.DigestSettings": {
+            "type": "object",
+            "properties": {
+                "frequency": {
+                    "type": "string"
+                },
+                "lastSentAt": {
+                    "type": "string"
+                },
+                "lastSentOn": {
+                    "type": "string"
+                },
+                "sendHour": {
+                    "type": "integer"
+                },
+                "timeZone": {
+                    "type": "string"
+                },
+                "weekday": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.DigestTask": {
+            "type": "object",
+            "properties": {
+                "dueDate": {
+                    "type": "string"
+                },
+                "goalId": {
+                    "type": "integer"
+                },
+                "goalTitle": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "overdue": {
+                    "type": "boolean"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "detail": {
+                    "type": "string"
+                },
+                "fieldErrors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.FieldError"
+                    }
+                },
+                "requestId": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.FieldError": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.Goal": {
+            "type": "object",
+            "properties": {
+                "autoStatus": {
+                    "description": "AutoStatus derives Status from the goal's tasks: todo until one is\ncompleted, then in_progress, and achieved once all are.",
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "ownerName": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "progressWeight": {
+                    "description": "ProgressWeight is how tasks count towards Progress: count, priority\nor estimate.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.GoalProgress": {
+            "type": "object",
+            "properties": {
+                "completedTasks": {
+                    "type": "integer"
+                },
+                "percent": {
+                    "type": "integer"
+                },
+                "totalTasks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.GoalTimeItem": {
+            "type": "object",
+            "properties": {
+                "goalId": {
+                    "type": "integer"
+                },
+                "spentMinutes": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.GoalTimeReport": {
+            "type": "object",
+            "properties": {
+                "estimateMinutes": {
+                    "type": "integer"
+                },
+                "goalId": {
+                    "type": "integer"
+                },
+                "spentMinutes": {
+                    "type": "integer"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.TaskTime"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.UserTimeItem"
+                    }
+                }
+            }
+        },
+        "types.GoalWithTasks": {
+            "type": "object",
+            "properties": {
+                "autoStatus": {
+                    "description": "AutoStatus derives Status from the goal's tasks: todo until one is\ncompleted, then in_progress, and achieved once all are.",
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "estimateMinutes": {
+                    "description": "EstimateMinutes and SpentMinutes total the tasks' estimates and the\nfinished time entries on them. Only GetGoalWithTasks reports them.",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "ownerName": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "progress": {
+                    "description": "Progress is set by GetGoalsByOwner and GetGoalWithTasks.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/types.GoalProgress"
+                        }
+                    ]
+                },
+                "progressWeight": {
+                    "description": "ProgressWeight is how tasks count towards Progress: count, priority\nor estimate.",
+                    "type": "string"
+                },
+                "spentMinutes": {
                     "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.Task"
+                    }
+                },
+                "title": {
+                    "type": "string"
                 }
             }
         },
-        "types.CreateGoalPayload": {
+        "types.GoalsExport": {
+            "type": "object",
+            "properties": {
+                "exportedAt": {
+                    "type": "string"
+                },
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.GoalWithTasks"
+                    }
+                }
+            }
+        },
+        "types.ImportReport": {
+            "type": "object",
+            "properties": {
+                "dryRun": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.FieldError"
+                    }
+                },
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.ImportReportGoal"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                },
+                "unmappedUsers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.ImportReportGoal": {
+            "type": "object",
+            "properties": {
+                "assigned": {
+                    "type": "integer"
+                },
+                "completed": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "ID is the created goal's id; zero in a dry run.",
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "ref": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.ImportSummary": {
+            "type": "object",
+            "properties": {
+                "goals": {
+                    "type": "integer"
+                },
+                "tasks": {
+                    "type": "integer"
+                },
+                "timeEntries": {
+                    "type": "integer"
+                },
+                "usersCreated": {
+                    "type": "integer"
+                },
+                "usersMatched": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.InstanceGoal": {
             "type": "object",
             "required": [
+                "id",
+                "ownerId",
                 "priority",
                 "status",
                 "title"
             ],
             "properties": {
+                "autoStatus": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
                 "description": {
-                    "type": "string",
-                    "maxLength": 2000
+                    "type": "string"
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ownerId": {
+                    "type": "integer"
                 },
                 "priority": {
                     "type": "string",
@@ -887,6 +3512,15 @@ const docTemplate = `{
                         "low"
                     ]
                 },
+                "progressWeight": {
+                    "description": "ProgressWeight defaults to count when omitted.",
+                    "type": "string",
+                    "enum": [
+                        "count",
+                        "priority",
+                        "estimate"
+                    ]
+                },
                 "status": {
                     "type": "string",
                     "enum": [
@@ -895,16 +3529,49 @@ const docTemplate = `{
                         "achieved"
                     ]
                 },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.InstanceTask"
+                    }
+                },
                 "title": {
                     "type": "string",
-                    "maxLength": 255,
-                    "minLength": 3
+                    "maxLength": 255
                 }
             }
         },
-        "types.CreateTaskPayload": {
+        "types.InstanceSnapshot": {
+            "type": "object",
+            "required": [
+                "version"
+            ],
+            "properties": {
+                "exportedAt": {
+                    "type": "string"
+                },
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.InstanceGoal"
+                    }
+                },
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.InstanceUser"
+                    }
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.InstanceTask": {
             "type": "object",
             "required": [
+                "createdBy",
+                "id",
                 "priority",
                 "title"
             ],
@@ -912,9 +3579,39 @@ const docTemplate = `{
                 "assigneeId": {
                     "type": "integer"
                 },
+                "assigneePosition": {
+                    "type": "number"
+                },
+                "completedAt": {
+                    "description": "CompletedAt is when the task was completed; nil for open tasks and\ntasks completed before completion times were recorded.",
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "createdBy": {
+                    "type": "integer"
+                },
                 "description": {
-                    "type": "string",
-                    "maxLength": 2000
+                    "type": "string"
+                },
+                "dueDate": {
+                    "type": "string"
+                },
+                "estimateMinutes": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 0
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "isCompleted": {
+                    "type": "boolean"
+                },
+                "position": {
+                    "description": "Position and AssigneePosition order the task on its goal's and its\nassignee's lists.",
+                    "type": "number"
                 },
                 "priority": {
                     "type": "string",
@@ -924,104 +3621,242 @@ const docTemplate = `{
                         "low"
                     ]
                 },
+                "recurred": {
+                    "type": "boolean"
+                },
+                "recurrence": {
+                    "type": "string"
+                },
+                "timeEntries": {
+                    "description": "TimeEntries are the finished entries on the task.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.InstanceTimeEntry"
+                    }
+                },
                 "title": {
                     "type": "string",
-                    "maxLength": 255,
-                    "minLength": 3
+                    "maxLength": 255
                 }
             }
         },
-        "types.ErrorResponse": {
+        "types.InstanceTimeEntry": {
             "type": "object",
+            "required": [
+                "startedAt",
+                "userId"
+            ],
             "properties": {
-                "error": {
+                "durationSeconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "note": {
+                    "type": "string"
+                },
+                "startedAt": {
                     "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
                 }
             }
         },
-        "types.Goal": {
+        "types.InstanceUser": {
             "type": "object",
+            "required": [
+                "email",
+                "firstName",
+                "id",
+                "lastName",
+                "passwordHash"
+            ],
             "properties": {
                 "createdAt": {
                     "type": "string"
                 },
-                "description": {
-                    "type": "string"
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "firstName": {
+                    "type": "string",
+                    "maxLength": 255
                 },
                 "id": {
                     "type": "integer"
                 },
-                "ownerId": {
-                    "type": "integer"
+                "lastName": {
+                    "type": "string",
+                    "maxLength": 255
                 },
-                "ownerName": {
+                "passwordHash": {
+                    "type": "string",
+                    "maxLength": 255
+                }
+            }
+        },
+        "types.InstantiateTemplatePayload": {
+            "type": "object",
+            "properties": {
+                "startDate": {
+                    "description": "StartDate is the date due-date offsets count from (YYYY-MM-DD); it\ndefaults to today in UTC.",
                     "type": "string"
                 },
-                "priority": {
+                "values": {
+                    "description": "Values maps placeholder names to their replacement, e.g.\n{\"client\": \"Acme\"}. Every placeholder the template uses needs one.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "types.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.LoginUserPayload": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
                     "type": "string"
                 },
-                "status": {
+                "password": {
                     "type": "string"
+                }
+            }
+        },
+        "types.MoveTaskPayload": {
+            "type": "object",
+            "properties": {
+                "afterId": {
+                    "type": "integer",
+                    "minimum": 1
                 },
-                "title": {
-                    "type": "string"
+                "beforeId": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "list": {
+                    "type": "string",
+                    "enum": [
+                        "goal",
+                        "assigned"
+                    ]
                 }
             }
         },
-        "types.GoalWithTasks": {
+        "types.Notification": {
             "type": "object",
             "properties": {
+                "actorId": {
+                    "type": "integer"
+                },
+                "actorName": {
+                    "type": "string"
+                },
                 "createdAt": {
                     "type": "string"
                 },
-                "description": {
+                "dueDate": {
                     "type": "string"
                 },
-                "id": {
+                "goalId": {
                     "type": "integer"
                 },
-                "ownerId": {
+                "goalTitle": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "ownerName": {
+                "message": {
                     "type": "string"
                 },
-                "priority": {
+                "readAt": {
                     "type": "string"
                 },
-                "status": {
+                "taskId": {
+                    "type": "integer"
+                },
+                "taskTitle": {
                     "type": "string"
                 },
-                "tasks": {
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.NotificationList": {
+            "type": "object",
+            "properties": {
+                "notifications": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/types.Task"
+                        "$ref": "#/definitions/types.Notification"
                     }
                 },
-                "title": {
-                    "type": "string"
+                "unreadCount": {
+                    "type": "integer"
                 }
             }
         },
-        "types.LoginResponse": {
+        "types.NotificationPreference": {
             "type": "object",
+            "required": [
+                "channel",
+                "type"
+            ],
             "properties": {
-                "token": {
-                    "type": "string"
+                "channel": {
+                    "type": "string",
+                    "enum": [
+                        "in_app",
+                        "email",
+                        "none"
+                    ]
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "assigned",
+                        "mentioned",
+                        "due_soon",
+                        "goal_achieved"
+                    ]
                 }
             }
         },
-        "types.LoginUserPayload": {
+        "types.OverloadReport": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
-                    "type": "string"
+                "threshold": {
+                    "type": "integer"
                 },
-                "password": {
+                "users": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.UserLoad"
+                    }
+                }
+            }
+        },
+        "types.PriorityCount": {
+            "type": "object",
+            "properties": {
+                "openTasks": {
+                    "type": "integer"
+                },
+                "priority": {
                     "type": "string"
                 }
             }
@@ -1051,6 +3886,15 @@ const docTemplate = `{
                 }
             }
         },
+        "types.StartTimerPayload": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
         "types.Task": {
             "type": "object",
             "properties": {
@@ -1072,6 +3916,12 @@ const docTemplate = `{
                 "description": {
                     "type": "string"
                 },
+                "dueDate": {
+                    "type": "string"
+                },
+                "estimateMinutes": {
+                    "type": "integer"
+                },
                 "goalId": {
                     "type": "integer"
                 },
@@ -1087,8 +3937,169 @@ const docTemplate = `{
                 "priority": {
                     "type": "string"
                 },
+                "recurrence": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.TaskTime": {
+            "type": "object",
+            "properties": {
+                "estimateMinutes": {
+                    "type": "integer"
+                },
+                "spentMinutes": {
+                    "type": "integer"
+                },
+                "taskId": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.Template": {
+            "type": "object",
+            "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dueInDays": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "ownerId": {
+                    "type": "integer"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "tasks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.TemplateTask"
+                    }
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.TemplateTask": {
+            "type": "object",
+            "required": [
+                "priority",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "dueInDays": {
+                    "type": "integer",
+                    "maximum": 3650,
+                    "minimum": 0
+                },
+                "priority": {
+                    "type": "string",
+                    "enum": [
+                        "high",
+                        "medium",
+                        "low"
+                    ]
+                },
                 "title": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 3
+                }
+            }
+        },
+        "types.TimeEntry": {
+            "type": "object",
+            "properties": {
+                "durationSeconds": {
+                    "type": "integer"
+                },
+                "endedAt": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "startedAt": {
                     "type": "string"
+                },
+                "taskId": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "userName": {
+                    "type": "string"
+                }
+            }
+        },
+        "types.UpdateDigestSettingsPayload": {
+            "type": "object",
+            "required": [
+                "frequency",
+                "timeZone"
+            ],
+            "properties": {
+                "frequency": {
+                    "type": "string",
+                    "enum": [
+                        "off",
+                        "daily",
+                        "weekly"
+                    ]
+                },
+                "sendHour": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0
+                },
+                "timeZone": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "weekday": {
+                    "type": "integer",
+                    "maximum": 6,
+                    "minimum": 0
+                }
+            }
+        },
+        "types.UpdateNotificationPreferencesPayload": {
+            "type": "object",
+            "required": [
+                "preferences"
+            ],
+            "properties": {
+                "preferences": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/types.NotificationPreference"
+                    }
                 }
             }
         },
@@ -1145,6 +4156,14 @@ const docTemplate = `{
                     "type": "string",
                     "maxLength": 2000
                 },
+                "dueDate": {
+                    "type": "string"
+                },
+                "estimateMinutes": {
+                    "type": "integer",
+                    "maximum": 100000,
+                    "minimum": 0
+                },
                 "goalId": {
                     "type": "integer",
                     "minimum": 1
@@ -1160,6 +4179,9 @@ const docTemplate = `{
                         "low"
                     ]
                 },
+                "recurrence": {
+                    "type": "string"
+                },
                 "title": {
                     "type": "string",
                     "maxLength": 255,
@@ -1167,6 +4189,20 @@ const docTemplate = `{
                 }
             }
         },
+        "types.UserLoad": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "openTasks": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
         "types.UserLookup": {
             "type": "object",
             "properties": {
@@ -1217,6 +4253,57 @@ const docTemplate = `{
                     }
                 }
             }
+        },
+        "types.UserTimeItem": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "spentMinutes": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.UserTimeReport": {
+            "type": "object",
+            "properties": {
+                "goals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/types.GoalTimeItem"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "spentMinutes": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "types.WeeklyCompletion": {
+            "type": "object",
+            "properties": {
+                "completed": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "weekStart": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -0,0 +1,23 @@
+// Package handlertest holds helpers shared by the service handler tests.
+package handlertest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+// Serve sends a request with body to handler as userID, as the JWT
+// middleware would, and returns the recorded response. The request is
+// unauthenticated when userID is 0.
+func Serve(handler http.Handler, method, path string, userID int, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if userID != 0 {
+		req = req.WithContext(context.WithValue(req.Context(), auth.UserKey, userID))
+	}
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
@@ -0,0 +1,89 @@
+// Package logging configures the process-wide slog logger and carries
+// per-request attributes (request ID, authenticated user) through context.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// New builds a logger writing to w. format is "json" or "text"; level is one
+// of debug, info, warn or error.
+func New(w io.Writer, level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "json", "":
+		handler = slog.NewJSONHandler(w, opts)
+	case "text":
+		handler = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+
+	return slog.New(contextHandler{Handler: handler}), nil
+}
+
+type contextKey struct{}
+
+type requestAttrs struct {
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// NewContext returns a context that collects attributes for every log record
+// emitted with it, including attributes added later by inner middleware.
+func NewContext(ctx context.Context, attrs ...slog.Attr) context.Context {
+	return context.WithValue(ctx, contextKey{}, &requestAttrs{attrs: attrs})
+}
+
+// AddAttrs attaches attrs to all subsequent log records of the request. It is
+// a no-op for contexts not created by NewContext.
+func AddAttrs(ctx context.Context, attrs ...slog.Attr) {
+	holder, ok := ctx.Value(contextKey{}).(*requestAttrs)
+	if !ok {
+		return
+	}
+	holder.mu.Lock()
+	holder.attrs = append(holder.attrs, attrs...)
+	holder.mu.Unlock()
+}
+
+func attrsFromContext(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	holder, ok := ctx.Value(contextKey{}).(*requestAttrs)
+	if !ok {
+		return nil
+	}
+	holder.mu.Lock()
+	defer holder.mu.Unlock()
+	return append([]slog.Attr(nil), holder.attrs...)
+}
+
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	record.AddAttrs(attrsFromContext(ctx)...)
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,97 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Run("propagates a valid incoming id", func(t *testing.T) {
+		var seen string
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			seen = RequestIDFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "abc-123")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		if seen != "abc-123" || rr.Header().Get(RequestIDHeader) != "abc-123" {
+			t.Fatalf("expected abc-123 to be propagated, got context %q header %q", seen, rr.Header().Get(RequestIDHeader))
+		}
+	})
+
+	t.Run("replaces an invalid incoming id", func(t *testing.T) {
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(RequestIDHeader, "bad id\nwith newline")
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+
+		id := rr.Header().Get(RequestIDHeader)
+		if id == "" || id == "bad id\nwith newline" {
+			t.Fatalf("expected a generated id, got %q", id)
+		}
+	})
+}
+
+func TestContextAttrsAreLogged(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := NewContext(t.Context(), slog.String("request_id", "req-1"))
+	AddAttrs(ctx, slog.Int("user_id", 7))
+	logger.InfoContext(ctx, "hello")
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["request_id"] != "req-1" || record["user_id"] != float64(7) {
+		t.Fatalf("expected request and user attributes, got %v", record)
+	}
+}
+
+func TestNewRejectsUnknownSettings(t *testing.T) {
+	if _, err := New(&bytes.Buffer{}, "loud", "json"); err == nil {
+		t.Fatal("expected error for unknown level")
+	}
+	if _, err := New(&bytes.Buffer{}, "info", "xml"); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestAccessLogRedactsSecretRouteParams(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := slog.Default()
+	slog.SetDefault(logger)
+	t.Cleanup(func() { slog.SetDefault(previous) })
+
+	r := chi.NewRouter()
+	r.Use(AccessLog)
+	r.Get("/ical/{token}.ics", func(w http.ResponseWriter, r *http.Request) {})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ical/s3cr3t.ics", nil))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatal(err)
+	}
+	if record["path"] != "/ical/REDACTED.ics" || record["route"] != "/ical/{token}.ics" {
+		t.Fatalf("expected the token to be redacted, got %v", record)
+	}
+}
@@ -0,0 +1,53 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/service/calendar"
+	"context"
+)
+
+// SetCalendarToken enforces the same foreign key and unique token hash as
+// the calendar_tokens table.
+func (s *Store) SetCalendarToken(ctx context.Context, userID int, tokenHash string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return errForeignKey
+	}
+	for id, hash := range s.calendarTokens {
+		if hash == tokenHash && id != userID {
+			return errDuplicateToken
+		}
+	}
+	s.calendarTokens[userID] = tokenHash
+	return nil
+}
+
+func (s *Store) DeleteCalendarToken(ctx context.Context, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.calendarTokens, userID)
+	return nil
+}
+
+func (s *Store) GetUserIDByCalendarToken(ctx context.Context, tokenHash string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, hash := range s.calendarTokens {
+		if hash == tokenHash {
+			return id, nil
+		}
+	}
+	return 0, calendar.ErrNotFound
+}
@@ -0,0 +1,200 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/service/digest"
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+func (s *Store) GetDigestSettings(ctx context.Context, userID int) (*types.DigestSettings, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.digestSettingsOf(userID), nil
+}
+
+func (s *Store) SetDigestSettings(ctx context.Context, userID int, settings types.DigestSettings) (*types.DigestSettings, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, errForeignKey
+	}
+	stored, ok := s.digestSettings[userID]
+	if !ok {
+		stored = &types.DigestSettings{}
+		s.digestSettings[userID] = stored
+	}
+	stored.Frequency = settings.Frequency
+	stored.TimeZone = settings.TimeZone
+	stored.SendHour = settings.SendHour
+	stored.Weekday = settings.Weekday
+	return s.digestSettingsOf(userID), nil
+}
+
+func (s *Store) DigestRecipients(ctx context.Context, afterID, limit int) ([]*types.DigestRecipient, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipients := []*types.DigestRecipient{}
+	for userID, settings := range s.digestSettings {
+		if settings.Frequency == digest.Off || userID <= afterID {
+			continue
+		}
+		recipients = append(recipients, &types.DigestRecipient{
+			UserID:         userID,
+			Email:          s.users[userID].Email,
+			Name:           s.userName(userID),
+			DigestSettings: *s.digestSettingsOf(userID),
+		})
+	}
+	slices.SortFunc(recipients, func(a, b *types.DigestRecipient) int { return cmp.Compare(a.UserID, b.UserID) })
+	return recipients[:min(len(recipients), limit)], nil
+}
+
+func (s *Store) BuildDigest(ctx context.Context, userID int, since time.Time, today string) (*types.Digest, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, digest.ErrNotFound
+	}
+	d := &types.Digest{
+		Name:          s.userName(userID),
+		Date:          today,
+		Since:         since.UTC(),
+		Goals:         []*types.DigestGoal{},
+		NewlyAssigned: []*types.DigestTask{},
+		Completed:     []*types.DigestCompletion{},
+	}
+
+	var open []*types.Task
+	for _, task := range s.tasks {
+		if !task.IsCompleted && task.AssigneeID != nil && *task.AssigneeID == userID {
+			open = append(open, task)
+		}
+	}
+	slices.SortFunc(open, func(a, b *types.Task) int {
+		return cmp.Or(
+			cmp.Compare(s.goals[a.GoalID].Title, s.goals[b.GoalID].Title),
+			cmp.Compare(a.GoalID, b.GoalID),
+			compareDueDates(a.DueDate, b.DueDate),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	for _, task := range open {
+		item := &types.DigestTask{
+			ID:        task.ID,
+			GoalID:    task.GoalID,
+			GoalTitle: s.goals[task.GoalID].Title,
+			Title:     task.Title,
+			Priority:  task.Priority,
+			DueDate:   copyDate(task.DueDate),
+			Overdue:   task.DueDate != nil && *task.DueDate < today,
+		}
+		assignedAt, ok := s.assignedAt[task.ID]
+		digest.AddOpenTask(d, item, ok && !assignedAt.Before(d.Since))
+	}
+
+	for taskID, completedAt := range s.completedAt {
+		task := s.tasks[taskID]
+		goal := s.goals[task.GoalID]
+		if goal.OwnerID != userID || completedAt.Before(d.Since) {
+			continue
+		}
+		completion := &types.DigestCompletion{
+			TaskID:      task.ID,
+			Title:       task.Title,
+			GoalID:      goal.ID,
+			GoalTitle:   goal.Title,
+			CompletedAt: completedAt,
+		}
+		if task.AssigneeID != nil {
+			completion.AssigneeName = s.userName(*task.AssigneeID)
+		}
+		d.Completed = append(d.Completed, completion)
+	}
+	slices.SortFunc(d.Completed, func(a, b *types.DigestCompletion) int {
+		return cmp.Or(a.CompletedAt.Compare(b.CompletedAt), cmp.Compare(a.TaskID, b.TaskID))
+	})
+	return d, nil
+}
+
+func (s *Store) ClaimDigest(ctx context.Context, userID int, sentOn string, sentAt time.Time) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings, ok := s.digestSettings[userID]
+	if !ok || settings.Frequency == digest.Off || (settings.LastSentOn != nil && *settings.LastSentOn >= sentOn) {
+		return false, nil
+	}
+	sentAt = sentAt.UTC().Truncate(time.Microsecond)
+	settings.LastSentOn = &sentOn
+	settings.LastSentAt = &sentAt
+	return true, nil
+}
+
+func (s *Store) ReleaseDigest(ctx context.Context, userID int, lastSentOn *string, lastSentAt *time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if settings, ok := s.digestSettings[userID]; ok {
+		settings.LastSentOn = copyDate(lastSentOn)
+		settings.LastSentAt = nil
+		if lastSentAt != nil {
+			prevSentAt := lastSentAt.UTC()
+			settings.LastSentAt = &prevSentAt
+		}
+	}
+	return nil
+}
+
+// digestSettingsOf returns a copy of the user's settings, or the defaults.
+func (s *Store) digestSettingsOf(userID int) *types.DigestSettings {
+	stored, ok := s.digestSettings[userID]
+	if !ok {
+		settings := digest.DefaultSettings()
+		return &settings
+	}
+	settings := *stored
+	settings.LastSentOn = copyDate(stored.LastSentOn)
+	if stored.LastSentAt != nil {
+		lastSentAt := *stored.LastSentAt
+		settings.LastSentAt = &lastSentAt
+	}
+	return &settings
+}
+
+// compareDueDates orders due dates soonest first and missing ones last.
+func compareDueDates(a, b *string) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return cmp.Compare(*a, *b)
+}
@@ -0,0 +1,288 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+// StreamGoals calls fn with every goal in id order, tasks in id order. The
+// goals are copied under the lock and fn runs after it is released.
+func (s *Store) StreamGoals(ctx context.Context, _ int, fn func(*types.GoalWithTasks) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	goals := make([]*types.GoalWithTasks, 0, len(s.goals))
+	for _, goal := range s.sortedGoals() {
+		result := &types.GoalWithTasks{Goal: *copyGoal(goal), Tasks: []*types.Task{}}
+		result.OwnerName = s.userName(goal.OwnerID)
+		for _, task := range s.goalTasksByID(goal.ID) {
+			result.Tasks = append(result.Tasks, s.taskWithLookups(task))
+		}
+		goals = append(goals, result)
+	}
+	s.mu.Unlock()
+
+	for _, goal := range goals {
+		if err := fn(goal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) ExportInstance(ctx context.Context, users func(*types.InstanceUser) error, goals func(*types.InstanceGoal) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	exportedUsers := make([]*types.InstanceUser, 0, len(s.users))
+	for _, u := range s.users {
+		exportedUsers = append(exportedUsers, &types.InstanceUser{
+			ID:           u.ID,
+			FirstName:    u.FirstName,
+			LastName:     u.LastName,
+			Email:        u.Email,
+			PasswordHash: u.Password,
+			CreatedAt:    u.CreatedAt,
+		})
+	}
+	slices.SortFunc(exportedUsers, func(a, b *types.InstanceUser) int { return cmp.Compare(a.ID, b.ID) })
+
+	exportedGoals := make([]*types.InstanceGoal, 0, len(s.goals))
+	for _, goal := range s.sortedGoals() {
+		exported := &types.InstanceGoal{
+			ID:             goal.ID,
+			Title:          goal.Title,
+			Description:    goal.Description,
+			Priority:       goal.Priority,
+			Status:         goal.Status,
+			OwnerID:        goal.OwnerID,
+			DueDate:        copyDate(goal.DueDate),
+			AutoStatus:     goal.AutoStatus,
+			ProgressWeight: goal.ProgressWeight,
+			CreatedAt:      goal.CreatedAt,
+			Tasks:          []types.InstanceTask{},
+		}
+		for _, task := range s.goalTasksByID(goal.ID) {
+			exported.Tasks = append(exported.Tasks, types.InstanceTask{
+				ID:               task.ID,
+				Title:            task.Title,
+				Description:      task.Description,
+				Priority:         task.Priority,
+				IsCompleted:      task.IsCompleted,
+				AssigneeID:       copyID(task.AssigneeID),
+				CreatedBy:        task.CreatedBy,
+				DueDate:          copyDate(task.DueDate),
+				Recurrence:       task.Recurrence,
+				Recurred:         s.recurred[task.ID],
+				EstimateMinutes:  copyID(task.EstimateMinutes),
+				Position:         s.positions[task.ID],
+				AssigneePosition: s.assigneePositionOf(task.ID),
+				TimeEntries:      s.finishedEntries(task.ID),
+				CreatedAt:        task.CreatedAt,
+				CompletedAt:      s.completedAtOf(task.ID),
+			})
+		}
+		exportedGoals = append(exportedGoals, exported)
+	}
+	s.mu.Unlock()
+
+	for _, u := range exportedUsers {
+		if err := users(u); err != nil {
+			return err
+		}
+	}
+	for _, goal := range exportedGoals {
+		if err := goals(goal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportInstance mirrors the SQL store: users are matched by email, goals,
+// tasks and time entries get new IDs. References are checked before
+// anything is written so a failed import leaves the store unchanged.
+func (s *Store) ImportInstance(ctx context.Context, snapshot types.InstanceSnapshot) (*types.ImportSummary, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	known := make(map[int]bool, len(snapshot.Users))
+	for _, u := range snapshot.Users {
+		known[u.ID] = true
+	}
+	for _, goal := range snapshot.Goals {
+		if !known[goal.OwnerID] {
+			return nil, errForeignKey
+		}
+		for _, task := range goal.Tasks {
+			if !known[task.CreatedBy] || (task.AssigneeID != nil && !known[*task.AssigneeID]) {
+				return nil, errForeignKey
+			}
+			for _, entry := range task.TimeEntries {
+				if !known[entry.UserID] {
+					return nil, errForeignKey
+				}
+			}
+		}
+	}
+
+	summary := &types.ImportSummary{}
+	userIDs := make(map[int]int, len(snapshot.Users))
+	for _, imported := range snapshot.Users {
+		if existing := s.userByEmail(imported.Email); existing != nil {
+			userIDs[imported.ID] = existing.ID
+			summary.UsersMatched++
+			continue
+		}
+		s.nextUserID++
+		s.users[s.nextUserID] = &types.User{
+			ID:        s.nextUserID,
+			FirstName: imported.FirstName,
+			LastName:  imported.LastName,
+			Email:     imported.Email,
+			Password:  imported.PasswordHash,
+			CreatedAt: s.timestampOrNow(imported.CreatedAt),
+		}
+		userIDs[imported.ID] = s.nextUserID
+		summary.UsersCreated++
+	}
+
+	for _, imported := range snapshot.Goals {
+		s.nextGoalID++
+		goalID := s.nextGoalID
+		s.goals[goalID] = &types.Goal{
+			ID:             goalID,
+			Title:          imported.Title,
+			Description:    imported.Description,
+			Priority:       imported.Priority,
+			Status:         imported.Status,
+			OwnerID:        userIDs[imported.OwnerID],
+			DueDate:        copyDate(imported.DueDate),
+			AutoStatus:     imported.AutoStatus,
+			ProgressWeight: normalizeProgressWeight(imported.ProgressWeight),
+			CreatedAt:      s.timestampOrNow(imported.CreatedAt),
+		}
+		summary.Goals++
+
+		for _, task := range imported.Tasks {
+			var assigneeID *int
+			if task.AssigneeID != nil {
+				id := userIDs[*task.AssigneeID]
+				assigneeID = &id
+			}
+			s.nextTaskID++
+			s.tasks[s.nextTaskID] = &types.Task{
+				ID:              s.nextTaskID,
+				GoalID:          goalID,
+				Title:           task.Title,
+				Description:     task.Description,
+				Priority:        task.Priority,
+				IsCompleted:     task.IsCompleted,
+				AssigneeID:      assigneeID,
+				CreatedBy:       userIDs[task.CreatedBy],
+				DueDate:         copyDate(task.DueDate),
+				Recurrence:      task.Recurrence,
+				EstimateMinutes: copyID(task.EstimateMinutes),
+				CreatedAt:       s.timestampOrNow(task.CreatedAt),
+			}
+			if task.Recurred {
+				s.recurred[s.nextTaskID] = true
+			}
+			if task.IsCompleted && task.CompletedAt != nil {
+				s.completedAt[s.nextTaskID] = task.CompletedAt.UTC()
+			}
+			s.stampAssigned(s.tasks[s.nextTaskID], nil, s.tasks[s.nextTaskID].CreatedAt)
+			s.positions[s.nextTaskID] = task.Position
+			if assigneeID != nil {
+				s.assigneePositions[s.nextTaskID] = 0
+				if task.AssigneePosition != nil {
+					s.assigneePositions[s.nextTaskID] = *task.AssigneePosition
+				}
+			}
+			summary.Tasks++
+
+			for _, entry := range task.TimeEntries {
+				startedAt := entry.StartedAt.UTC()
+				endedAt := startedAt.Add(time.Duration(entry.DurationSeconds) * time.Second)
+				seconds := entry.DurationSeconds
+				s.nextTimeEntryID++
+				s.timeEntries[s.nextTimeEntryID] = &types.TimeEntry{
+					ID:              s.nextTimeEntryID,
+					TaskID:          s.nextTaskID,
+					UserID:          userIDs[entry.UserID],
+					StartedAt:       startedAt,
+					EndedAt:         &endedAt,
+					DurationSeconds: &seconds,
+					Note:            entry.Note,
+				}
+				summary.TimeEntries++
+			}
+		}
+		s.syncGoalStatus(goalID)
+	}
+	return summary, nil
+}
+
+func (s *Store) sortedGoals() []*types.Goal {
+	goals := make([]*types.Goal, 0, len(s.goals))
+	for _, goal := range s.goals {
+		goals = append(goals, goal)
+	}
+	slices.SortFunc(goals, func(a, b *types.Goal) int { return cmp.Compare(a.ID, b.ID) })
+	return goals
+}
+
+func (s *Store) assigneePositionOf(taskID int) *float64 {
+	position, ok := s.assigneePositions[taskID]
+	if !ok {
+		return nil
+	}
+	return &position
+}
+
+func (s *Store) goalTasksByID(goalID int) []*types.Task {
+	var tasks []*types.Task
+	for _, task := range s.tasks {
+		if task.GoalID == goalID {
+			tasks = append(tasks, task)
+		}
+	}
+	slices.SortFunc(tasks, func(a, b *types.Task) int { return cmp.Compare(a.ID, b.ID) })
+	return tasks
+}
+
+func (s *Store) userByEmail(email string) *types.User {
+	for _, u := range s.users {
+		if u.Email == email {
+			return u
+		}
+	}
+	return nil
+}
+
+// timestampOrNow keeps imported timestamps at PostgreSQL precision and
+// stamps records the snapshot left without one.
+func (s *Store) timestampOrNow(t time.Time) time.Time {
+	if t.IsZero() {
+		return s.now()
+	}
+	return t.UTC().Truncate(time.Microsecond)
+}
+
+func (s *Store) completedAtOf(taskID int) *time.Time {
+	completedAt, ok := s.completedAt[taskID]
+	if !ok {
+		return nil
+	}
+	return &completedAt
+}
@@ -0,0 +1,71 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+)
+
+// CreateGoalsWithTasks checks every foreign key before creating anything,
+// so a failed import leaves the store unchanged like a rolled back
+// transaction.
+func (s *Store) CreateGoalsWithTasks(ctx context.Context, ownerID int, goals []types.ImportedGoal) ([]int, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[ownerID]; !ok {
+		return nil, errForeignKey
+	}
+	for _, g := range goals {
+		for _, t := range g.Tasks {
+			if !s.validAssignee(t.Task.AssigneeID) {
+				return nil, errForeignKey
+			}
+		}
+	}
+
+	ids := make([]int, 0, len(goals))
+	for _, g := range goals {
+		s.nextGoalID++
+		goal := &types.Goal{
+			ID:             s.nextGoalID,
+			Title:          g.Goal.Title,
+			Description:    g.Goal.Description,
+			Priority:       normalizePriority(g.Goal.Priority),
+			Status:         normalizeGoalStatus(g.Goal.Status),
+			OwnerID:        ownerID,
+			DueDate:        copyDate(g.Goal.DueDate),
+			AutoStatus:     g.Goal.AutoStatus,
+			ProgressWeight: normalizeProgressWeight(g.Goal.ProgressWeight),
+			CreatedAt:      s.now(),
+		}
+		s.goals[goal.ID] = goal
+		ids = append(ids, goal.ID)
+
+		for _, t := range g.Tasks {
+			s.nextTaskID++
+			s.tasks[s.nextTaskID] = &types.Task{
+				ID:              s.nextTaskID,
+				GoalID:          goal.ID,
+				Title:           t.Task.Title,
+				Description:     t.Task.Description,
+				Priority:        normalizePriority(t.Task.Priority),
+				IsCompleted:     t.IsCompleted,
+				AssigneeID:      copyID(t.Task.AssigneeID),
+				CreatedBy:       ownerID,
+				DueDate:         copyDate(t.Task.DueDate),
+				EstimateMinutes: copyID(t.Task.EstimateMinutes),
+				CreatedAt:       s.now(),
+			}
+			if t.IsCompleted && t.CompletedAt != nil {
+				s.completedAt[s.nextTaskID] = t.CompletedAt.UTC()
+			}
+			s.placeInGoal(s.tasks[s.nextTaskID])
+			s.stampAssigned(s.tasks[s.nextTaskID], nil, s.now())
+		}
+		s.syncGoalStatus(goal.ID)
+	}
+	return ids, nil
+}
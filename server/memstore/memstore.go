@@ -0,0 +1,745 @@
+// Package memstore implements types.UserStore, types.GoalTaskStore,
+// types.ExportStore, types.ImportStore, types.CalendarStore,
+// types.RecurrenceStore, types.TemplateStore, types.TimeStore,
+// types.ReportStore, types.NotificationStore, types.NotificationOutbox,
+// types.DigestStore and types.DigestOutbox in memory with the same semantics as the PostgreSQL
+// stores: ownership checks, sentinel errors, cascades, foreign keys and
+// result ordering. Handler tests use it instead of hand-written mocks so
+// authorization rules are exercised.
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/service/user"
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errDuplicateEmail = errors.New("memstore: duplicate key value violates unique constraint \"users_email_key\"")
+	errForeignKey     = errors.New("memstore: insert or update violates foreign key constraint")
+	errDuplicateToken = errors.New("memstore: duplicate key value violates unique constraint \"calendar_tokens_token_hash_key\"")
+)
+
+type Store struct {
+	mu     sync.Mutex
+	lastAt time.Time
+
+	users map[int]*types.User
+	goals map[int]*types.Goal
+	tasks map[int]*types.Task
+
+	// calendarTokens maps user ids to feed token hashes.
+	calendarTokens map[int]string
+
+	// recurred holds the recurring tasks whose next occurrence exists, and
+	// recurrenceMu is the scheduler lock.
+	recurred     map[int]bool
+	recurrenceMu sync.Mutex
+
+	templates   map[int]*types.Template
+	timeEntries map[int]*types.TimeEntry
+
+	// completedAt holds the completion times of completed tasks, which
+	// types.Task does not carry.
+	completedAt map[int]time.Time
+	// assignedAt holds when each assigned task was given to its assignee.
+	assignedAt map[int]time.Time
+	// positions and assigneePositions order tasks on their goal's and
+	// their assignee's lists.
+	positions         map[int]float64
+	assigneePositions map[int]float64
+
+	notifications     map[int]*storedNotification
+	notificationPrefs map[int]map[string]string
+	// dueNotified holds the due date each task was last reminded of.
+	dueNotified map[int]string
+
+	digestSettings map[int]*types.DigestSettings
+
+	nextUserID         int
+	nextGoalID         int
+	nextTaskID         int
+	nextTemplateID     int
+	nextTimeEntryID    int
+	nextNotificationID int
+}
+
+func New() *Store {
+	return &Store{
+		users: make(map[int]*types.User),
+		goals: make(map[int]*types.Goal),
+		tasks: make(map[int]*types.Task),
+
+		calendarTokens: make(map[int]string),
+		recurred:       make(map[int]bool),
+		templates:      make(map[int]*types.Template),
+		timeEntries:    make(map[int]*types.TimeEntry),
+		completedAt:    make(map[int]time.Time),
+		assignedAt:     make(map[int]time.Time),
+
+		positions:         make(map[int]float64),
+		assigneePositions: make(map[int]float64),
+
+		notifications:     make(map[int]*storedNotification),
+		notificationPrefs: make(map[int]map[string]string),
+		dueNotified:       make(map[int]string),
+
+		digestSettings: make(map[int]*types.DigestSettings),
+	}
+}
+
+// now returns strictly increasing timestamps with the microsecond precision
+// of a PostgreSQL timestamptz, so created_at orderings are deterministic.
+func (s *Store) now() time.Time {
+	t := time.Now().UTC().Truncate(time.Microsecond)
+	if !t.After(s.lastAt) {
+		t = s.lastAt.Add(time.Microsecond)
+	}
+	s.lastAt = t
+	return t
+}
+
+func (s *Store) GetUserByEmail(ctx context.Context, email string) (*types.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, u := range s.users {
+		if u.Email == email {
+			copied := *u
+			return &copied, nil
+		}
+	}
+	return nil, user.ErrNotFound
+}
+
+func (s *Store) GetUserByID(ctx context.Context, id int) (*types.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[id]
+	if !ok {
+		return nil, user.ErrNotFound
+	}
+	copied := *u
+	return &copied, nil
+}
+
+func (s *Store) CreateUser(ctx context.Context, u types.User) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, existing := range s.users {
+		if existing.Email == u.Email {
+			return errDuplicateEmail
+		}
+	}
+
+	s.nextUserID++
+	u.ID = s.nextUserID
+	u.CreatedAt = s.now()
+	s.users[u.ID] = &u
+	return nil
+}
+
+func (s *Store) UpdateUserProfile(ctx context.Context, userID int, payload types.UpdateProfilePayload) (*types.User, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return nil, user.ErrNotFound
+	}
+	u.FirstName = payload.FirstName
+	u.LastName = payload.LastName
+	copied := *u
+	return &copied, nil
+}
+
+func (s *Store) UpdateUserPassword(ctx context.Context, userID int, hashedPassword string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	u, ok := s.users[userID]
+	if !ok {
+		return user.ErrNotFound
+	}
+	u.Password = hashedPassword
+	return nil
+}
+
+func (s *Store) ListUsers(ctx context.Context) ([]*types.UserLookup, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := make([]*types.UserLookup, 0, len(s.users))
+	for _, u := range s.sortedUsers() {
+		users = append(users, &types.UserLookup{ID: u.ID, Name: s.userName(u.ID)})
+	}
+	return users, nil
+}
+
+func (s *Store) CreateGoal(ctx context.Context, ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[ownerID]; !ok {
+		return nil, errForeignKey
+	}
+
+	s.nextGoalID++
+	goal := &types.Goal{
+		ID:             s.nextGoalID,
+		Title:          payload.Title,
+		Description:    payload.Description,
+		Priority:       normalizePriority(payload.Priority),
+		Status:         normalizeGoalStatus(payload.Status),
+		OwnerID:        ownerID,
+		DueDate:        copyDate(payload.DueDate),
+		AutoStatus:     payload.AutoStatus,
+		ProgressWeight: normalizeProgressWeight(payload.ProgressWeight),
+		CreatedAt:      s.now(),
+	}
+	s.goals[goal.ID] = goal
+	s.syncGoalStatus(goal.ID)
+	return copyGoal(goal), nil
+}
+
+func (s *Store) UpdateGoal(ctx context.Context, goalID, ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[goalID]
+	if !ok {
+		return nil, tracker.ErrNotFound
+	}
+	if goal.OwnerID != ownerID {
+		return nil, tracker.ErrForbidden
+	}
+
+	prevStatus := goal.Status
+	goal.Title = payload.Title
+	goal.Description = payload.Description
+	goal.Priority = normalizePriority(payload.Priority)
+	goal.Status = normalizeGoalStatus(payload.Status)
+	goal.DueDate = copyDate(payload.DueDate)
+	goal.AutoStatus = payload.AutoStatus
+	goal.ProgressWeight = normalizeProgressWeight(payload.ProgressWeight)
+	s.syncGoalStatus(goalID)
+	if goal.Status == "achieved" && prevStatus != "achieved" {
+		s.notifyGoalMembers(notification.Event{Type: notification.GoalAchieved, ActorID: ownerID, GoalID: goalID})
+	}
+	return copyGoal(goal), nil
+}
+
+func (s *Store) DeleteGoal(ctx context.Context, goalID, ownerID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[goalID]
+	if !ok {
+		return tracker.ErrNotFound
+	}
+	if goal.OwnerID != ownerID {
+		return tracker.ErrForbidden
+	}
+
+	delete(s.goals, goalID)
+	for id, task := range s.tasks {
+		if task.GoalID == goalID {
+			s.deleteTask(id)
+		}
+	}
+	s.deleteNotifications(func(n *storedNotification) bool { return n.goalID == goalID })
+	return nil
+}
+
+func (s *Store) GetGoalsByOwner(ctx context.Context, _ int) ([]*types.GoalWithTasks, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goals := make([]*types.Goal, 0, len(s.goals))
+	for _, goal := range s.goals {
+		goals = append(goals, goal)
+	}
+	slices.SortFunc(goals, func(a, b *types.Goal) int {
+		return cmp.Or(
+			cmp.Compare(achievedRank(a.Status), achievedRank(b.Status)),
+			cmp.Compare(priorityRank(a.Priority), priorityRank(b.Priority)),
+			b.CreatedAt.Compare(a.CreatedAt),
+			cmp.Compare(b.ID, a.ID),
+		)
+	})
+
+	result := make([]*types.GoalWithTasks, 0, len(goals))
+	for _, goal := range goals {
+		withTasks := s.goalWithTasks(goal)
+		withTasks.Progress = tracker.Progress(goal.ProgressWeight, withTasks.Tasks)
+		result = append(result, withTasks)
+	}
+	return result, nil
+}
+
+func (s *Store) GetGoalWithTasks(ctx context.Context, goalID, _ int) (*types.GoalWithTasks, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[goalID]
+	if !ok {
+		return nil, tracker.ErrNotFound
+	}
+	result := s.goalWithTasks(goal)
+	estimate, seconds := 0, 0
+	for _, task := range result.Tasks {
+		if task.EstimateMinutes != nil {
+			estimate += *task.EstimateMinutes
+		}
+	}
+	for _, entry := range s.timeEntries {
+		if entry.DurationSeconds != nil && s.tasks[entry.TaskID].GoalID == goalID {
+			seconds += *entry.DurationSeconds
+		}
+	}
+	spent := minutes(seconds)
+	result.Progress = tracker.Progress(goal.ProgressWeight, result.Tasks)
+	result.EstimateMinutes = &estimate
+	result.SpentMinutes = &spent
+	return result, nil
+}
+
+func (s *Store) GetUsersWithCurrentTasks(ctx context.Context) ([]*types.UserTasksBoard, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	boards := make([]*types.UserTasksBoard, 0, len(s.users))
+	for _, u := range s.sortedUsers() {
+		board := &types.UserTasksBoard{
+			ID:    u.ID,
+			Name:  s.userName(u.ID),
+			Email: u.Email,
+			Tasks: []*types.Task{},
+		}
+
+		var tasks []*types.Task
+		for _, task := range s.tasks {
+			if task.AssigneeID != nil && *task.AssigneeID == u.ID && !task.IsCompleted {
+				tasks = append(tasks, task)
+			}
+		}
+		slices.SortFunc(tasks, func(a, b *types.Task) int {
+			return cmp.Or(
+				cmp.Compare(s.assigneePositions[a.ID], s.assigneePositions[b.ID]),
+				cmp.Compare(a.ID, b.ID),
+			)
+		})
+		for _, task := range tasks {
+			board.Tasks = append(board.Tasks, s.taskWithLookups(task))
+		}
+		boards = append(boards, board)
+	}
+	return boards, nil
+}
+
+func (s *Store) CreateTask(ctx context.Context, goalID, creatorID int, payload types.CreateTaskPayload) (*types.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[goalID]
+	if !ok {
+		return nil, tracker.ErrNotFound
+	}
+	if goal.OwnerID != creatorID {
+		return nil, tracker.ErrForbidden
+	}
+	if !s.validAssignee(payload.AssigneeID) {
+		return nil, errForeignKey
+	}
+
+	s.nextTaskID++
+	task := &types.Task{
+		ID:              s.nextTaskID,
+		GoalID:          goalID,
+		Title:           payload.Title,
+		Description:     payload.Description,
+		Priority:        normalizePriority(payload.Priority),
+		AssigneeID:      copyID(payload.AssigneeID),
+		CreatedBy:       creatorID,
+		DueDate:         copyDate(payload.DueDate),
+		Recurrence:      payload.Recurrence,
+		EstimateMinutes: copyID(payload.EstimateMinutes),
+		CreatedAt:       s.now(),
+	}
+	s.tasks[task.ID] = task
+	s.placeInGoal(task)
+	s.stampAssigned(task, nil, task.CreatedAt)
+	s.notifyTask(task, creatorID, nil, nil)
+	s.syncGoalStatusAs(goalID, creatorID)
+	return copyTask(task), nil
+}
+
+func (s *Store) UpdateTask(ctx context.Context, taskID, requesterID int, payload types.UpdateTaskPayload) (*types.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return nil, tracker.ErrNotFound
+	}
+	if _, ok := s.goals[payload.GoalID]; !ok {
+		return nil, tracker.ErrNotFound
+	}
+	if !s.validAssignee(payload.AssigneeID) {
+		return nil, errForeignKey
+	}
+
+	prevGoalID, prevAssigneeID := task.GoalID, task.AssigneeID
+	prevMentions := notification.Mentions(task.Title, task.Description)
+	task.GoalID = payload.GoalID
+	task.Title = payload.Title
+	task.Description = payload.Description
+	task.Priority = normalizePriority(payload.Priority)
+	task.IsCompleted = payload.IsCompleted
+	task.AssigneeID = copyID(payload.AssigneeID)
+	task.DueDate = copyDate(payload.DueDate)
+	task.Recurrence = payload.Recurrence
+	task.EstimateMinutes = copyID(payload.EstimateMinutes)
+	if !task.IsCompleted {
+		delete(s.completedAt, taskID)
+	} else if _, ok := s.completedAt[taskID]; !ok {
+		s.completedAt[taskID] = s.now()
+	}
+	if task.GoalID != prevGoalID {
+		s.placeInGoal(task)
+	}
+	s.stampAssigned(task, prevAssigneeID, s.now())
+	s.notifyTask(task, requesterID, prevAssigneeID, prevMentions)
+	s.syncGoalStatusAs(task.GoalID, requesterID)
+	s.syncGoalStatusAs(prevGoalID, requesterID)
+	return copyTask(task), nil
+}
+
+func (s *Store) DeleteTask(ctx context.Context, taskID, requesterID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkTaskOwner(taskID, requesterID); err != nil {
+		return err
+	}
+	goalID := s.tasks[taskID].GoalID
+	s.deleteTask(taskID)
+	s.syncGoalStatusAs(goalID, requesterID)
+	return nil
+}
+
+// deleteTask removes the task and cascades to the rows that reference it.
+func (s *Store) deleteTask(taskID int) {
+	delete(s.tasks, taskID)
+	delete(s.recurred, taskID)
+	delete(s.completedAt, taskID)
+	delete(s.assignedAt, taskID)
+	delete(s.positions, taskID)
+	delete(s.assigneePositions, taskID)
+	delete(s.dueNotified, taskID)
+	for id, entry := range s.timeEntries {
+		if entry.TaskID == taskID {
+			delete(s.timeEntries, id)
+		}
+	}
+	s.deleteNotifications(func(n *storedNotification) bool { return n.taskID != nil && *n.taskID == taskID })
+}
+
+// stampAssigned mirrors the tracker store's assigned_at and
+// assignee_position: when the assignee changes from prevAssigneeID the
+// task is stamped at and goes last on the new assignee's list. Both are
+// cleared with the assignee.
+func (s *Store) stampAssigned(task *types.Task, prevAssigneeID *int, at time.Time) {
+	switch {
+	case task.AssigneeID == nil:
+		delete(s.assignedAt, task.ID)
+		delete(s.assigneePositions, task.ID)
+	case prevAssigneeID == nil || *prevAssigneeID != *task.AssigneeID:
+		s.assignedAt[task.ID] = at
+		s.assigneePositions[task.ID] = s.assigneeList(*task.AssigneeID).last(task.ID) + 1
+	}
+}
+
+func (s *Store) AssignTask(ctx context.Context, taskID, requesterID int, payload types.AssignTaskPayload) (*types.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkTaskOwner(taskID, requesterID); err != nil {
+		return nil, err
+	}
+	if !s.validAssignee(payload.AssigneeID) {
+		return nil, errForeignKey
+	}
+
+	task := s.tasks[taskID]
+	prevAssigneeID := task.AssigneeID
+	task.AssigneeID = copyID(payload.AssigneeID)
+	s.stampAssigned(task, prevAssigneeID, s.now())
+	mentions := notification.Mentions(task.Title, task.Description)
+	s.notifyTask(task, requesterID, prevAssigneeID, mentions)
+	return copyTask(task), nil
+}
+
+func (s *Store) GetAssignedTasks(ctx context.Context, userID int) ([]*types.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var tasks []*types.Task
+	for _, task := range s.tasks {
+		if task.AssigneeID != nil && *task.AssigneeID == userID {
+			tasks = append(tasks, task)
+		}
+	}
+	slices.SortFunc(tasks, func(a, b *types.Task) int {
+		return cmp.Or(
+			compareCompleted(a, b),
+			cmp.Compare(s.assigneePositions[a.ID], s.assigneePositions[b.ID]),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+
+	result := make([]*types.Task, 0, len(tasks))
+	for _, task := range tasks {
+		result = append(result, s.taskWithLookups(task))
+	}
+	return result, nil
+}
+
+// checkTaskOwner mirrors the Postgres store: only the owner of the task's
+// goal may change or delete it.
+func (s *Store) checkTaskOwner(taskID, requesterID int) error {
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return tracker.ErrNotFound
+	}
+	if s.goals[task.GoalID].OwnerID != requesterID {
+		return tracker.ErrForbidden
+	}
+	return nil
+}
+
+// syncGoalStatus mirrors tracker.SyncGoalStatus and reports whether the
+// goal became achieved.
+func (s *Store) syncGoalStatus(goalID int) bool {
+	goal, ok := s.goals[goalID]
+	if !ok || !goal.AutoStatus {
+		return false
+	}
+	prevStatus := goal.Status
+	goal.Status = tracker.AutoGoalStatus(s.goalTasksByID(goalID))
+	return goal.Status == "achieved" && prevStatus != "achieved"
+}
+
+func (s *Store) validAssignee(assigneeID *int) bool {
+	if assigneeID == nil {
+		return true
+	}
+	_, ok := s.users[*assigneeID]
+	return ok
+}
+
+func (s *Store) goalWithTasks(goal *types.Goal) *types.GoalWithTasks {
+	result := &types.GoalWithTasks{Goal: *copyGoal(goal), Tasks: []*types.Task{}}
+	result.OwnerName = s.userName(goal.OwnerID)
+
+	var tasks []*types.Task
+	for _, task := range s.tasks {
+		if task.GoalID == goal.ID {
+			tasks = append(tasks, task)
+		}
+	}
+	slices.SortFunc(tasks, func(a, b *types.Task) int {
+		return cmp.Or(
+			compareCompleted(a, b),
+			cmp.Compare(s.positions[a.ID], s.positions[b.ID]),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	for _, task := range tasks {
+		result.Tasks = append(result.Tasks, s.taskWithLookups(task))
+	}
+	return result
+}
+
+func (s *Store) taskWithLookups(task *types.Task) *types.Task {
+	copied := copyTask(task)
+	copied.GoalTitle = s.goals[task.GoalID].Title
+	copied.CreatedByName = s.userName(task.CreatedBy)
+	if task.AssigneeID != nil {
+		copied.AssigneeName = s.userName(*task.AssigneeID)
+	}
+	return copied
+}
+
+func (s *Store) sortedUsers() []*types.User {
+	users := make([]*types.User, 0, len(s.users))
+	for _, u := range s.users {
+		users = append(users, u)
+	}
+	slices.SortFunc(users, func(a, b *types.User) int {
+		return cmp.Or(
+			cmp.Compare(a.FirstName, b.FirstName),
+			cmp.Compare(a.LastName, b.LastName),
+			cmp.Compare(a.ID, b.ID),
+		)
+	})
+	return users
+}
+
+// userName matches TRIM(CONCAT(first_name, ' ', last_name)).
+func (s *Store) userName(id int) string {
+	u, ok := s.users[id]
+	if !ok {
+		return ""
+	}
+	return strings.Trim(u.FirstName+" "+u.LastName, " ")
+}
+
+func copyTask(task *types.Task) *types.Task {
+	copied := *task
+	copied.AssigneeID = copyID(task.AssigneeID)
+	copied.DueDate = copyDate(task.DueDate)
+	copied.EstimateMinutes = copyID(task.EstimateMinutes)
+	return &copied
+}
+
+func copyGoal(goal *types.Goal) *types.Goal {
+	copied := *goal
+	copied.DueDate = copyDate(goal.DueDate)
+	return &copied
+}
+
+func copyDate(date *string) *string {
+	if date == nil {
+		return nil
+	}
+	value := *date
+	return &value
+}
+
+func copyID(id *int) *int {
+	if id == nil {
+		return nil
+	}
+	value := *id
+	return &value
+}
+
+func compareCompleted(a, b *types.Task) int {
+	switch {
+	case a.IsCompleted == b.IsCompleted:
+		return 0
+	case a.IsCompleted:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func achievedRank(status string) int {
+	if status == "achieved" {
+		return 1
+	}
+	return 0
+}
+
+func priorityRank(priority string) int {
+	switch priority {
+	case "high":
+		return 0
+	case "medium":
+		return 1
+	case "low":
+		return 2
+	default:
+		return 3
+	}
+}
+
+func normalizePriority(priority string) string {
+	switch priority {
+	case "high", "medium", "low":
+		return priority
+	default:
+		return "medium"
+	}
+}
+
+func normalizeProgressWeight(weight string) string {
+	switch weight {
+	case "count", "priority", "estimate":
+		return weight
+	default:
+		return "count"
+	}
+}
+
+func normalizeGoalStatus(status string) string {
+	switch status {
+	case "todo", "in_progress", "achieved":
+		return status
+	default:
+		return "todo"
+	}
+}
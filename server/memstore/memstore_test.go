@@ -0,0 +1,13 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/storetest"
+	"testing"
+)
+
+func TestConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		store := New()
+		return storetest.Stores{Users: store, Tracker: store, Export: store, Import: store, Calendar: store, Recurrence: store, Templates: store, Time: store, Reports: store, Notifications: store, Outbox: store, Digests: store, DigestOutbox: store}
+	})
+}
@@ -0,0 +1,304 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"time"
+)
+
+// storedNotification is a notifications row. Names, titles and the due
+// date are looked up when it is read, as the SQL stores join them.
+type storedNotification struct {
+	id        int
+	userID    int
+	eventType string
+	channel   string
+	actorID   *int
+	goalID    int
+	taskID    *int
+	readAt    *time.Time
+	sent      bool
+	createdAt time.Time
+	// attempts counts failed sends; the email waits until retryAt.
+	attempts int
+	retryAt  time.Time
+}
+
+func (s *Store) ListNotifications(ctx context.Context, userID int, query types.NotificationQuery) (*types.NotificationList, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := &types.NotificationList{Notifications: []*types.Notification{}}
+	var matched []*storedNotification
+	for _, n := range s.notifications {
+		if n.userID != userID || n.channel != notification.InApp {
+			continue
+		}
+		if n.readAt == nil {
+			list.UnreadCount++
+		}
+		if (query.UnreadOnly && n.readAt != nil) || (query.BeforeID != 0 && n.id >= query.BeforeID) {
+			continue
+		}
+		matched = append(matched, n)
+	}
+	slices.SortFunc(matched, func(a, b *storedNotification) int { return cmp.Compare(b.id, a.id) })
+	for _, n := range matched[:min(len(matched), query.Limit)] {
+		list.Notifications = append(list.Notifications, s.notificationWithLookups(n))
+	}
+	return list, nil
+}
+
+func (s *Store) MarkNotificationRead(ctx context.Context, notificationID, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, ok := s.notifications[notificationID]
+	if !ok || n.channel != notification.InApp {
+		return notification.ErrNotFound
+	}
+	if n.userID != userID {
+		return notification.ErrForbidden
+	}
+	if n.readAt == nil {
+		readAt := s.now()
+		n.readAt = &readAt
+	}
+	return nil
+}
+
+func (s *Store) MarkAllNotificationsRead(ctx context.Context, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	readAt := s.now()
+	for _, n := range s.notifications {
+		if n.userID == userID && n.channel == notification.InApp && n.readAt == nil {
+			n.readAt = &readAt
+		}
+	}
+	return nil
+}
+
+func (s *Store) GetNotificationPreferences(ctx context.Context, userID int) ([]types.NotificationPreference, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return notification.Preferences(s.notificationPrefs[userID]), nil
+}
+
+func (s *Store) SetNotificationPreferences(ctx context.Context, userID int, preferences []types.NotificationPreference) ([]types.NotificationPreference, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, errForeignKey
+	}
+	if s.notificationPrefs[userID] == nil {
+		s.notificationPrefs[userID] = map[string]string{}
+	}
+	for _, preference := range preferences {
+		s.notificationPrefs[userID][preference.Type] = preference.Channel
+	}
+	return notification.Preferences(s.notificationPrefs[userID]), nil
+}
+
+func (s *Store) NotifyDueSoon(ctx context.Context, from, through string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []*types.Task
+	for _, task := range s.tasks {
+		if task.IsCompleted || task.AssigneeID == nil || task.DueDate == nil ||
+			*task.DueDate < from || *task.DueDate > through || s.dueNotified[task.ID] == *task.DueDate {
+			continue
+		}
+		due = append(due, task)
+	}
+	slices.SortFunc(due, func(a, b *types.Task) int { return cmp.Compare(a.ID, b.ID) })
+	for _, task := range due {
+		s.dueNotified[task.ID] = *task.DueDate
+		s.notify(*task.AssigneeID, notification.Event{Type: notification.DueSoon, GoalID: task.GoalID, TaskID: copyID(&task.ID)})
+	}
+	return len(due), nil
+}
+
+func (s *Store) PendingEmails(ctx context.Context, now time.Time, afterID, limit int) ([]*types.NotificationEmail, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var pending []*storedNotification
+	for _, n := range s.notifications {
+		if n.channel == notification.Email && !n.sent && !n.retryAt.After(now) && n.id > afterID {
+			pending = append(pending, n)
+		}
+	}
+	slices.SortFunc(pending, func(a, b *storedNotification) int { return cmp.Compare(a.id, b.id) })
+
+	emails := []*types.NotificationEmail{}
+	for _, n := range pending[:min(len(pending), limit)] {
+		emails = append(emails, &types.NotificationEmail{
+			Notification:  *s.notificationWithLookups(n),
+			To:            s.users[n.userID].Email,
+			RecipientName: s.userName(n.userID),
+			Attempts:      n.attempts,
+		})
+	}
+	return emails, nil
+}
+
+func (s *Store) ClaimEmail(ctx context.Context, notificationID int, _ time.Time) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n, ok := s.notifications[notificationID]
+	if !ok || n.channel != notification.Email || n.sent {
+		return false, nil
+	}
+	n.sent = true
+	return true, nil
+}
+
+func (s *Store) ReleaseEmail(ctx context.Context, notificationID int, retryAt time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if n, ok := s.notifications[notificationID]; ok {
+		n.sent = false
+		n.attempts++
+		n.retryAt = retryAt
+	}
+	return nil
+}
+
+// notify mirrors notification.NotifyUser: actors are not notified of their
+// own events, and users who chose none are skipped.
+func (s *Store) notify(userID int, event notification.Event) {
+	if _, ok := s.users[userID]; !ok || userID == event.ActorID {
+		return
+	}
+	channel := cmp.Or(s.notificationPrefs[userID][event.Type], notification.InApp)
+	if channel == notification.None {
+		return
+	}
+	s.nextNotificationID++
+	n := &storedNotification{
+		id:        s.nextNotificationID,
+		userID:    userID,
+		eventType: event.Type,
+		channel:   channel,
+		goalID:    event.GoalID,
+		taskID:    copyID(event.TaskID),
+		createdAt: s.now(),
+	}
+	if event.ActorID != 0 {
+		n.actorID = copyID(&event.ActorID)
+	}
+	s.notifications[n.id] = n
+}
+
+// notifyTask mirrors the tracker store: the task's assignee is notified
+// when it changed from prevAssigneeID, and the users mentioned in it but
+// not in prevMentions are notified of the mention.
+func (s *Store) notifyTask(task *types.Task, actorID int, prevAssigneeID *int, prevMentions []string) {
+	event := notification.Event{Type: notification.Assigned, ActorID: actorID, GoalID: task.GoalID, TaskID: &task.ID}
+	if task.AssigneeID != nil && (prevAssigneeID == nil || *prevAssigneeID != *task.AssigneeID) {
+		s.notify(*task.AssigneeID, event)
+	}
+	event.Type = notification.Mentioned
+	for _, email := range notification.NewMentions(prevMentions, notification.Mentions(task.Title, task.Description)) {
+		for _, u := range s.sortedUsers() {
+			if strings.ToLower(u.Email) == email {
+				s.notify(u.ID, event)
+			}
+		}
+	}
+}
+
+// notifyGoalMembers mirrors notification.NotifyGoalMembers: the goal's
+// owner and the assignees of its tasks are notified once each.
+func (s *Store) notifyGoalMembers(event notification.Event) {
+	members := []int{s.goals[event.GoalID].OwnerID}
+	for _, task := range s.goalTasksByID(event.GoalID) {
+		if task.AssigneeID != nil && !slices.Contains(members, *task.AssigneeID) {
+			members = append(members, *task.AssigneeID)
+		}
+	}
+	slices.Sort(members)
+	for _, userID := range members {
+		s.notify(userID, event)
+	}
+}
+
+// syncGoalStatusAs syncs the goal's status and, when the goal becomes
+// achieved, notifies its members of actorID's change.
+func (s *Store) syncGoalStatusAs(goalID, actorID int) {
+	if s.syncGoalStatus(goalID) {
+		s.notifyGoalMembers(notification.Event{Type: notification.GoalAchieved, ActorID: actorID, GoalID: goalID})
+	}
+}
+
+// deleteNotifications cascades the deletion of a goal or task.
+func (s *Store) deleteNotifications(match func(n *storedNotification) bool) {
+	for id, n := range s.notifications {
+		if match(n) {
+			delete(s.notifications, id)
+		}
+	}
+}
+
+func (s *Store) notificationWithLookups(n *storedNotification) *types.Notification {
+	result := &types.Notification{
+		ID:        n.id,
+		Type:      n.eventType,
+		ActorID:   copyID(n.actorID),
+		GoalID:    n.goalID,
+		GoalTitle: s.goals[n.goalID].Title,
+		TaskID:    copyID(n.taskID),
+		CreatedAt: n.createdAt,
+	}
+	if n.actorID != nil {
+		result.ActorName = s.userName(*n.actorID)
+	}
+	if n.taskID != nil {
+		task := s.tasks[*n.taskID]
+		result.TaskTitle = task.Title
+		result.DueDate = copyDate(task.DueDate)
+	}
+	if n.readAt != nil {
+		readAt := *n.readAt
+		result.ReadAt = &readAt
+	}
+	return result
+}
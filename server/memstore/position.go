@@ -0,0 +1,150 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"slices"
+)
+
+// taskList is a goal's tasks ordered by position or an assignee's ordered
+// by assignee position.
+type taskList struct {
+	positions map[int]float64
+	ids       []int
+}
+
+func (s *Store) goalList(goalID int) taskList {
+	list := taskList{positions: s.positions}
+	for _, task := range s.tasks {
+		if task.GoalID == goalID {
+			list.ids = append(list.ids, task.ID)
+		}
+	}
+	return list
+}
+
+func (s *Store) assigneeList(userID int) taskList {
+	list := taskList{positions: s.assigneePositions}
+	for _, task := range s.tasks {
+		if task.AssigneeID != nil && *task.AssigneeID == userID {
+			list.ids = append(list.ids, task.ID)
+		}
+	}
+	return list
+}
+
+// last matches COALESCE(MAX(position), 0) over the list without taskID.
+func (l taskList) last(taskID int) float64 {
+	var last float64
+	found := false
+	for _, id := range l.ids {
+		if id != taskID && (!found || l.positions[id] > last) {
+			last, found = l.positions[id], true
+		}
+	}
+	return last
+}
+
+// placeInGoal mirrors tracker.PlaceTask and UpdateTask's goal moves: the
+// task goes last on its goal's list.
+func (s *Store) placeInGoal(task *types.Task) {
+	s.positions[task.ID] = s.goalList(task.GoalID).last(task.ID) + 1
+}
+
+// MoveTask mirrors the tracker store: the task takes the midpoint of its
+// new neighbours' positions, and the list is renumbered when they are
+// closer than tracker.MinPositionGap.
+func (s *Store) MoveTask(ctx context.Context, taskID, requesterID int, payload types.MoveTaskPayload) (*types.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[taskID]
+	if !ok {
+		return nil, tracker.ErrNotFound
+	}
+	list := s.goalList(task.GoalID)
+	if payload.List == "assigned" {
+		if task.AssigneeID == nil || *task.AssigneeID != requesterID {
+			return nil, tracker.ErrForbidden
+		}
+		list = s.assigneeList(requesterID)
+	} else if s.goals[task.GoalID].OwnerID != requesterID {
+		return nil, tracker.ErrForbidden
+	}
+
+	for renumbered := false; ; renumbered = true {
+		low, high, err := list.neighbours(taskID, payload.AfterID, payload.BeforeID)
+		if err != nil {
+			return nil, err
+		}
+		if high-low >= tracker.MinPositionGap {
+			list.positions[taskID] = low + (high-low)/2
+			return copyTask(task), nil
+		}
+		if renumbered {
+			return nil, tracker.ErrInvalidAnchor
+		}
+		list.renumber()
+	}
+}
+
+func (l taskList) neighbours(taskID int, afterID, beforeID *int) (float64, float64, error) {
+	var low, high float64
+	if afterID != nil {
+		position, ok := l.positionOf(*afterID)
+		if !ok {
+			return 0, 0, tracker.ErrInvalidAnchor
+		}
+		low = position
+	}
+	if beforeID != nil {
+		position, ok := l.positionOf(*beforeID)
+		if !ok {
+			return 0, 0, tracker.ErrInvalidAnchor
+		}
+		high = position
+	}
+
+	switch {
+	case beforeID == nil:
+		high = low + 2
+		found := false
+		for _, id := range l.ids {
+			if position := l.positions[id]; id != taskID && id != *afterID && position >= low && (!found || position < high) {
+				high, found = position, true
+			}
+		}
+	case afterID == nil:
+		low = high - 2
+		found := false
+		for _, id := range l.ids {
+			if position := l.positions[id]; id != taskID && id != *beforeID && position <= high && (!found || position > low) {
+				low, found = position, true
+			}
+		}
+	}
+	return low, high, nil
+}
+
+func (l taskList) positionOf(taskID int) (float64, bool) {
+	if !slices.Contains(l.ids, taskID) {
+		return 0, false
+	}
+	return l.positions[taskID], true
+}
+
+// renumber spaces the list's positions 1 apart, keeping its order.
+func (l taskList) renumber() {
+	ids := slices.Clone(l.ids)
+	slices.SortFunc(ids, func(a, b int) int {
+		return cmp.Or(cmp.Compare(l.positions[a], l.positions[b]), cmp.Compare(a, b))
+	})
+	for i, id := range ids {
+		l.positions[id] = float64(i + 1)
+	}
+}
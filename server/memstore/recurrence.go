@@ -0,0 +1,80 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"slices"
+)
+
+func (s *Store) WithRecurrenceLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	if !s.recurrenceMu.TryLock() {
+		return false, nil
+	}
+	defer s.recurrenceMu.Unlock()
+	return true, fn(ctx)
+}
+
+func (s *Store) PendingRecurringTasks(ctx context.Context, today string, afterID, limit int) ([]*types.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tasks := make([]*types.Task, 0)
+	for _, task := range s.tasks {
+		if task.ID <= afterID || task.Recurrence == "" || s.recurred[task.ID] || task.DueDate == nil {
+			continue
+		}
+		if task.IsCompleted || *task.DueDate <= today {
+			tasks = append(tasks, copyTask(task))
+		}
+	}
+	slices.SortFunc(tasks, func(a, b *types.Task) int { return cmp.Compare(a.ID, b.ID) })
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+func (s *Store) CreateNextOccurrence(ctx context.Context, taskID int, dueDate string) (*types.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	task, ok := s.tasks[taskID]
+	if !ok || task.Recurrence == "" || s.recurred[taskID] {
+		return nil, nil
+	}
+	s.recurred[taskID] = true
+
+	s.nextTaskID++
+	next := &types.Task{
+		ID:              s.nextTaskID,
+		GoalID:          task.GoalID,
+		Title:           task.Title,
+		Description:     task.Description,
+		Priority:        task.Priority,
+		AssigneeID:      copyID(task.AssigneeID),
+		CreatedBy:       task.CreatedBy,
+		DueDate:         &dueDate,
+		Recurrence:      task.Recurrence,
+		EstimateMinutes: copyID(task.EstimateMinutes),
+		CreatedAt:       s.now(),
+	}
+	s.tasks[next.ID] = next
+	s.stampAssigned(next, nil, next.CreatedAt)
+	// The occurrence takes the completed task's place on both lists.
+	s.positions[next.ID] = s.positions[task.ID]
+	if next.AssigneeID != nil {
+		s.assigneePositions[next.ID] = s.assigneePositions[task.ID]
+	}
+	s.syncGoalStatus(next.GoalID)
+	return copyTask(next), nil
+}
@@ -0,0 +1,158 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/service/report"
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+func (s *Store) CompletedPerWeek(ctx context.Context, from, to time.Time) ([]*types.WeeklyCompletion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	type key struct {
+		userID    int
+		weekStart string
+	}
+	counts := map[key]int{}
+	for taskID, completedAt := range s.completedAt {
+		task := s.tasks[taskID]
+		if task.AssigneeID == nil || completedAt.Before(from) || !completedAt.Before(to) {
+			continue
+		}
+		day := utcDay(completedAt)
+		monday := day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		counts[key{*task.AssigneeID, monday.Format(time.DateOnly)}]++
+	}
+
+	weeks := make([]*types.WeeklyCompletion, 0, len(counts))
+	for k, count := range counts {
+		weeks = append(weeks, &types.WeeklyCompletion{
+			UserID:    k.userID,
+			Name:      s.userName(k.userID),
+			WeekStart: k.weekStart,
+			Completed: count,
+		})
+	}
+	slices.SortFunc(weeks, func(a, b *types.WeeklyCompletion) int {
+		return cmp.Or(cmp.Compare(a.WeekStart, b.WeekStart), s.compareUsers(a.UserID, b.UserID))
+	})
+	return weeks, nil
+}
+
+func (s *Store) OpenTasksByPriority(ctx context.Context) ([]*types.PriorityCount, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := map[string]int{}
+	for _, task := range s.tasks {
+		if !task.IsCompleted {
+			counts[task.Priority]++
+		}
+	}
+	return report.ByPriority(counts), nil
+}
+
+func (s *Store) CycleTime(ctx context.Context, from, to time.Time) (*types.CycleTimeReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := &types.CycleTimeReport{}
+	var total time.Duration
+	for taskID, completedAt := range s.completedAt {
+		if completedAt.Before(from) || !completedAt.Before(to) {
+			continue
+		}
+		result.CompletedTasks++
+		total += completedAt.Sub(s.tasks[taskID].CreatedAt)
+	}
+	if result.CompletedTasks > 0 {
+		result.AverageSeconds = int((total / time.Duration(result.CompletedTasks)).Round(time.Second) / time.Second)
+	}
+	return result, nil
+}
+
+func (s *Store) GoalBurnUp(ctx context.Context, goalID int, from, to time.Time) ([]*types.BurnUpPoint, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.goals[goalID]; !ok {
+		return nil, report.ErrNotFound
+	}
+	points := []*types.BurnUpPoint{}
+	for day := utcDay(from); !day.After(utcDay(to)); day = day.AddDate(0, 0, 1) {
+		end := day.AddDate(0, 0, 1)
+		point := &types.BurnUpPoint{Date: day.Format(time.DateOnly)}
+		for _, task := range s.tasks {
+			if task.GoalID != goalID || !task.CreatedAt.Before(end) {
+				continue
+			}
+			point.TotalTasks++
+			doneAt, ok := s.completedAt[task.ID]
+			if !ok {
+				doneAt = task.CreatedAt
+			}
+			if task.IsCompleted && doneAt.Before(end) {
+				point.CompletedTasks++
+			}
+		}
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+func (s *Store) OverloadedUsers(ctx context.Context, threshold int) ([]*types.UserLoad, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := map[int]int{}
+	for _, task := range s.tasks {
+		if !task.IsCompleted && task.AssigneeID != nil {
+			counts[*task.AssigneeID]++
+		}
+	}
+	users := []*types.UserLoad{}
+	for userID, count := range counts {
+		if count > threshold {
+			users = append(users, &types.UserLoad{UserID: userID, Name: s.userName(userID), OpenTasks: count})
+		}
+	}
+	slices.SortFunc(users, func(a, b *types.UserLoad) int {
+		return cmp.Or(cmp.Compare(b.OpenTasks, a.OpenTasks), s.compareUsers(a.UserID, b.UserID))
+	})
+	return users, nil
+}
+
+// compareUsers orders users by first name, last name and id.
+func (s *Store) compareUsers(a, b int) int {
+	ua, ub := s.users[a], s.users[b]
+	return cmp.Or(
+		cmp.Compare(ua.FirstName, ub.FirstName),
+		cmp.Compare(ua.LastName, ub.LastName),
+		cmp.Compare(a, b),
+	)
+}
+
+// utcDay truncates t to the start of its UTC day.
+func utcDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
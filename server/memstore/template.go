@@ -0,0 +1,100 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/service/template"
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+)
+
+func (s *Store) ListTemplates(ctx context.Context, ownerID int) ([]*types.Template, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	templates := make([]*types.Template, 0)
+	for _, t := range s.templates {
+		if t.OwnerID == ownerID {
+			templates = append(templates, copyTemplate(t))
+		}
+	}
+	slices.SortFunc(templates, func(a, b *types.Template) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), a.ID-b.ID)
+	})
+	return templates, nil
+}
+
+func (s *Store) GetTemplate(ctx context.Context, templateID, ownerID int) (*types.Template, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.templates[templateID]
+	if !ok || t.OwnerID != ownerID {
+		return nil, template.ErrNotFound
+	}
+	return copyTemplate(t), nil
+}
+
+func (s *Store) CreateTemplate(ctx context.Context, ownerID int, payload types.CreateTemplatePayload) (*types.Template, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[ownerID]; !ok {
+		return nil, errForeignKey
+	}
+
+	s.nextTemplateID++
+	t := &types.Template{
+		ID:          s.nextTemplateID,
+		OwnerID:     ownerID,
+		Name:        payload.Name,
+		Title:       payload.Title,
+		Description: payload.Description,
+		Priority:    payload.Priority,
+		DueInDays:   copyID(payload.DueInDays),
+		Tasks:       make([]types.TemplateTask, 0, len(payload.Tasks)),
+		CreatedAt:   s.now(),
+	}
+	for _, task := range payload.Tasks {
+		task.DueInDays = copyID(task.DueInDays)
+		t.Tasks = append(t.Tasks, task)
+	}
+	s.templates[t.ID] = t
+	return copyTemplate(t), nil
+}
+
+func (s *Store) DeleteTemplate(ctx context.Context, templateID, ownerID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.templates[templateID]
+	if !ok || t.OwnerID != ownerID {
+		return template.ErrNotFound
+	}
+	delete(s.templates, templateID)
+	return nil
+}
+
+func copyTemplate(t *types.Template) *types.Template {
+	c := *t
+	c.DueInDays = copyID(t.DueInDays)
+	c.Tasks = make([]types.TemplateTask, len(t.Tasks))
+	for i, task := range t.Tasks {
+		task.DueInDays = copyID(task.DueInDays)
+		c.Tasks[i] = task
+	}
+	return &c
+}
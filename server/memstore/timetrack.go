@@ -0,0 +1,285 @@
+package memstore
+
+import (
+	"VyacheslavKuchumov/test-backend/service/timetrack"
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"context"
+	"slices"
+	"time"
+)
+
+func (s *Store) StartTimer(ctx context.Context, userID, taskID int, startedAt time.Time, note string) (*types.TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[taskID]; !ok {
+		return nil, timetrack.ErrNotFound
+	}
+	if _, ok := s.users[userID]; !ok {
+		return nil, errForeignKey
+	}
+	if s.runningEntry(userID) != nil {
+		return nil, timetrack.ErrTimerRunning
+	}
+
+	s.nextTimeEntryID++
+	entry := &types.TimeEntry{
+		ID:        s.nextTimeEntryID,
+		TaskID:    taskID,
+		UserID:    userID,
+		StartedAt: startedAt.UTC(),
+		Note:      note,
+	}
+	s.timeEntries[entry.ID] = entry
+	return copyTimeEntry(entry), nil
+}
+
+func (s *Store) StopTimer(ctx context.Context, userID int, endedAt time.Time) (*types.TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.runningEntry(userID)
+	if entry == nil {
+		return nil, timetrack.ErrNoTimer
+	}
+	ended := endedAt.UTC()
+	seconds := max(int(ended.Sub(entry.StartedAt)/time.Second), 0)
+	entry.EndedAt = &ended
+	entry.DurationSeconds = &seconds
+	return copyTimeEntry(entry), nil
+}
+
+func (s *Store) GetRunningTimer(ctx context.Context, userID int) (*types.TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry := s.runningEntry(userID)
+	if entry == nil {
+		return nil, timetrack.ErrNoTimer
+	}
+	return copyTimeEntry(entry), nil
+}
+
+func (s *Store) AddTimeEntry(ctx context.Context, userID, taskID int, startedAt time.Time, seconds int, note string) (*types.TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[taskID]; !ok {
+		return nil, timetrack.ErrNotFound
+	}
+	if _, ok := s.users[userID]; !ok {
+		return nil, errForeignKey
+	}
+
+	started := startedAt.UTC()
+	ended := started.Add(time.Duration(seconds) * time.Second)
+	s.nextTimeEntryID++
+	entry := &types.TimeEntry{
+		ID:              s.nextTimeEntryID,
+		TaskID:          taskID,
+		UserID:          userID,
+		StartedAt:       started,
+		EndedAt:         &ended,
+		DurationSeconds: &seconds,
+		Note:            note,
+	}
+	s.timeEntries[entry.ID] = entry
+	return copyTimeEntry(entry), nil
+}
+
+func (s *Store) ListTimeEntries(ctx context.Context, taskID int) ([]*types.TimeEntry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[taskID]; !ok {
+		return nil, timetrack.ErrNotFound
+	}
+	entries := make([]*types.TimeEntry, 0)
+	for _, entry := range s.timeEntries {
+		if entry.TaskID == taskID {
+			copied := copyTimeEntry(entry)
+			copied.UserName = s.userName(entry.UserID)
+			entries = append(entries, copied)
+		}
+	}
+	slices.SortFunc(entries, func(a, b *types.TimeEntry) int {
+		return cmp.Or(b.StartedAt.Compare(a.StartedAt), cmp.Compare(b.ID, a.ID))
+	})
+	return entries, nil
+}
+
+func (s *Store) DeleteTimeEntry(ctx context.Context, entryID, userID int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.timeEntries[entryID]
+	if !ok {
+		return timetrack.ErrNotFound
+	}
+	if entry.UserID != userID {
+		return timetrack.ErrForbidden
+	}
+	delete(s.timeEntries, entryID)
+	return nil
+}
+
+func (s *Store) GoalTimeReport(ctx context.Context, goalID int, from, to time.Time) (*types.GoalTimeReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	goal, ok := s.goals[goalID]
+	if !ok {
+		return nil, timetrack.ErrNotFound
+	}
+	report := &types.GoalTimeReport{GoalID: goalID, Title: goal.Title, Tasks: []types.TaskTime{}, Users: []types.UserTimeItem{}}
+
+	taskSeconds := make(map[int]int)
+	userSeconds := make(map[int]int)
+	for _, entry := range s.reportEntries(from, to) {
+		if s.tasks[entry.TaskID].GoalID == goalID {
+			taskSeconds[entry.TaskID] += *entry.DurationSeconds
+			userSeconds[entry.UserID] += *entry.DurationSeconds
+		}
+	}
+
+	totalSeconds := 0
+	for _, task := range s.goalTasksByID(goalID) {
+		item := types.TaskTime{
+			TaskID:          task.ID,
+			Title:           task.Title,
+			EstimateMinutes: copyID(task.EstimateMinutes),
+			SpentMinutes:    minutes(taskSeconds[task.ID]),
+		}
+		if task.EstimateMinutes != nil {
+			report.EstimateMinutes += *task.EstimateMinutes
+		}
+		totalSeconds += taskSeconds[task.ID]
+		report.Tasks = append(report.Tasks, item)
+	}
+	report.SpentMinutes = minutes(totalSeconds)
+
+	for userID := range userSeconds {
+		report.Users = append(report.Users, types.UserTimeItem{UserID: userID, Name: s.userName(userID)})
+	}
+	slices.SortFunc(report.Users, func(a, b types.UserTimeItem) int {
+		return cmp.Or(cmp.Compare(userSeconds[b.UserID], userSeconds[a.UserID]), cmp.Compare(a.UserID, b.UserID))
+	})
+	for i := range report.Users {
+		report.Users[i].SpentMinutes = minutes(userSeconds[report.Users[i].UserID])
+	}
+	return report, nil
+}
+
+func (s *Store) UserTimeReport(ctx context.Context, userID int, from, to time.Time) (*types.UserTimeReport, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[userID]; !ok {
+		return nil, timetrack.ErrNotFound
+	}
+	report := &types.UserTimeReport{UserID: userID, Name: s.userName(userID), Goals: []types.GoalTimeItem{}}
+
+	goalSeconds := make(map[int]int)
+	totalSeconds := 0
+	for _, entry := range s.reportEntries(from, to) {
+		if entry.UserID == userID {
+			goalSeconds[s.tasks[entry.TaskID].GoalID] += *entry.DurationSeconds
+			totalSeconds += *entry.DurationSeconds
+		}
+	}
+	for goalID := range goalSeconds {
+		report.Goals = append(report.Goals, types.GoalTimeItem{GoalID: goalID, Title: s.goals[goalID].Title})
+	}
+	slices.SortFunc(report.Goals, func(a, b types.GoalTimeItem) int {
+		return cmp.Or(cmp.Compare(goalSeconds[b.GoalID], goalSeconds[a.GoalID]), cmp.Compare(a.GoalID, b.GoalID))
+	})
+	for i := range report.Goals {
+		report.Goals[i].SpentMinutes = minutes(goalSeconds[report.Goals[i].GoalID])
+	}
+	report.SpentMinutes = minutes(totalSeconds)
+	return report, nil
+}
+
+func (s *Store) runningEntry(userID int) *types.TimeEntry {
+	for _, entry := range s.timeEntries {
+		if entry.UserID == userID && entry.EndedAt == nil {
+			return entry
+		}
+	}
+	return nil
+}
+
+// reportEntries returns the finished entries started in [from, to).
+func (s *Store) reportEntries(from, to time.Time) []*types.TimeEntry {
+	var entries []*types.TimeEntry
+	for _, entry := range s.timeEntries {
+		if entry.EndedAt != nil && !entry.StartedAt.Before(from) && entry.StartedAt.Before(to) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// finishedEntries returns the task's finished entries in id order, as
+// exported by ExportInstance.
+func (s *Store) finishedEntries(taskID int) []types.InstanceTimeEntry {
+	var ids []int
+	for id, entry := range s.timeEntries {
+		if entry.TaskID == taskID && entry.EndedAt != nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+
+	var entries []types.InstanceTimeEntry
+	for _, id := range ids {
+		entry := s.timeEntries[id]
+		entries = append(entries, types.InstanceTimeEntry{
+			UserID:          entry.UserID,
+			StartedAt:       entry.StartedAt,
+			DurationSeconds: *entry.DurationSeconds,
+			Note:            entry.Note,
+		})
+	}
+	return entries
+}
+
+func copyTimeEntry(entry *types.TimeEntry) *types.TimeEntry {
+	copied := *entry
+	if entry.EndedAt != nil {
+		ended := *entry.EndedAt
+		copied.EndedAt = &ended
+	}
+	copied.DurationSeconds = copyID(entry.DurationSeconds)
+	return &copied
+}
+
+// minutes rounds seconds to the nearest minute, like the SQL store.
+func minutes(seconds int) int {
+	return (seconds + 30) / 60
+}
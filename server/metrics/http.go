@@ -0,0 +1,61 @@
+package metrics
+
+import (
+	"VyacheslavKuchumov/test-backend/utils"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Middleware observes request latency labelled with the matched chi route
+// pattern, so IDs in paths do not create new series.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next.ServeHTTP(ww, r)
+
+		route := "unmatched"
+		if rctx := chi.RouteContext(r.Context()); rctx != nil {
+			if pattern := rctx.RoutePattern(); pattern != "" {
+				route = pattern
+			}
+		}
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		HTTPRequestDuration.
+			WithLabelValues(r.Method, route, strconv.Itoa(status)).
+			Observe(time.Since(start).Seconds())
+	})
+}
+
+// Handler serves the registry in the Prometheus text format. When token is
+// non-empty, scrapers must send it as "Authorization: Bearer <token>".
+func Handler(token string) http.Handler {
+	handler := promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+	if token == "" {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		provided, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metrics"`)
+			utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("metrics token required"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
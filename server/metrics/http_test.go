@@ -0,0 +1,64 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddlewareLabelsByRoutePattern(t *testing.T) {
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/goals/{goalID}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	for _, path := range []string{"/goals/1", "/goals/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	count := testutil.CollectAndCount(HTTPRequestDuration, "http_request_duration_seconds")
+	if count != 1 {
+		t.Fatalf("expected a single series for the route pattern, got %d", count)
+	}
+
+	expected := `http_request_duration_seconds_count{method="GET",route="/goals/{goalID}",status="404"} 2`
+	rr := httptest.NewRecorder()
+	Handler("").ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(rr.Body.String(), expected) {
+		t.Fatalf("expected exposition to contain %q", expected)
+	}
+}
+
+func TestHandlerRequiresToken(t *testing.T) {
+	handler := Handler("scrape-secret")
+
+	cases := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{name: "missing token", want: http.StatusUnauthorized},
+		{name: "wrong token", header: "Bearer nope", want: http.StatusUnauthorized},
+		{name: "valid token", header: "Bearer scrape-secret", want: http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+			if tc.header != "" {
+				req.Header.Set("Authorization", tc.header)
+			}
+			rr := httptest.NewRecorder()
+
+			handler.ServeHTTP(rr, req)
+			if rr.Code != tc.want {
+				t.Fatalf("expected %d, got %d", tc.want, rr.Code)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Package metrics holds the Prometheus collectors exposed on /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Registry is served by Handler. A dedicated registry keeps the exposition
+// limited to the collectors registered here.
+var Registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by chi route pattern, method and status code.",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "route", "status"},
+	)
+
+	GoalsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tracker_goals_created_total",
+		Help: "Goals created.",
+	})
+
+	TasksCompleted = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tracker_tasks_completed_total",
+		Help: "Tasks moved from open to completed.",
+	})
+
+	RecurringTasksCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tracker_recurring_tasks_created_total",
+		Help: "Occurrences of recurring tasks created by the scheduler.",
+	})
+
+	NotificationEmailsSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tracker_notification_emails_sent_total",
+		Help: "Notification emails sent by the dispatcher.",
+	})
+
+	DigestsSent = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "tracker_digests_sent_total",
+		Help: "Digest emails sent by the digest scheduler.",
+	})
+
+	Logins = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_logins_total",
+			Help: "Login attempts by result (succeeded or failed).",
+		},
+		[]string{"result"},
+	)
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		GoalsCreated,
+		TasksCompleted,
+		RecurringTasksCreated,
+		NotificationEmailsSent,
+		DigestsSent,
+		Logins,
+	)
+}
+
+// LoginSucceeded and LoginFailed record the outcome of a login attempt.
+func LoginSucceeded() { Logins.WithLabelValues("succeeded").Inc() }
+func LoginFailed()    { Logins.WithLabelValues("failed").Inc() }
+
+// RegisterDBStats exposes sql.DBStats of the pool as gauges. Registering the
+// same pool again is a no-op.
+func RegisterDBStats(db *sql.DB, dbName string) error {
+	err := Registry.Register(collectors.NewDBStatsCollector(db, dbName))
+	var already prometheus.AlreadyRegisteredError
+	if errors.As(err, &already) {
+		return nil
+	}
+	return err
+}
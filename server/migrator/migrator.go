@@ -0,0 +1,246 @@
+// Package migrator runs the embedded SQL migrations against PostgreSQL or
+// SQLite and computes migration plans without applying them.
+package migrator
+
+import (
+	"VyacheslavKuchumov/test-backend/cmd/migrate/migrations"
+	"VyacheslavKuchumov/test-backend/config"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// Migrator wraps migrate.Migrate with the embedded migration source.
+type Migrator struct {
+	*migrate.Migrate
+	Source source.Driver
+}
+
+// New prepares a migrator for db, which must have been opened for driver.
+// Close leaves db open.
+func New(ctx context.Context, db *sql.DB, driver string) (*Migrator, error) {
+	src, err := NewSource(driver)
+	if err != nil {
+		return nil, err
+	}
+
+	var target database.Driver
+	switch driver {
+	case config.DriverPostgres:
+		conn, err := db.Conn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		// Closing the driver releases only this dedicated connection.
+		target, err = postgres.WithConnection(ctx, conn, &postgres.Config{})
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	case config.DriverSQLite:
+		instance, err := sqlite.WithInstance(db, &sqlite.Config{})
+		if err != nil {
+			return nil, err
+		}
+		// The SQLite driver closes the *sql.DB it was given.
+		target = keepOpen{instance}
+	default:
+		return nil, fmt.Errorf("unsupported driver %q", driver)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, driver, target)
+	if err != nil {
+		target.Close()
+		return nil, err
+	}
+
+	// A second source instance for planning, so walking it does not interfere
+	// with the one owned by migrate.Migrate.
+	planSource, err := NewSource(driver)
+	if err != nil {
+		m.Close()
+		return nil, err
+	}
+	return &Migrator{Migrate: m, Source: planSource}, nil
+}
+
+// NewSource returns a source driver over the migrations embedded in the
+// binary for driver.
+func NewSource(driver string) (source.Driver, error) {
+	if driver == config.DriverSQLite {
+		return iofs.New(migrations.SQLiteFS, "sqlite")
+	}
+	return iofs.New(migrations.FS, ".")
+}
+
+type keepOpen struct {
+	database.Driver
+}
+
+func (keepOpen) Close() error {
+	return nil
+}
+
+// CurrentVersion returns the applied version; ok is false on a fresh database.
+func (m *Migrator) CurrentVersion() (version uint, dirty bool, ok bool, err error) {
+	version, dirty, err = m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, false, nil
+	}
+	if err != nil {
+		return 0, false, false, err
+	}
+	return version, dirty, true, nil
+}
+
+// Up applies all pending migrations, treating "nothing to do" as success.
+func Up(ctx context.Context, db *sql.DB, driver string) error {
+	m, err := New(ctx, db, driver)
+	if err != nil {
+		return err
+	}
+	defer m.Close()
+
+	if err := m.Migrate.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return err
+	}
+	return nil
+}
+
+type Direction string
+
+const (
+	DirectionUp   Direction = "up"
+	DirectionDown Direction = "down"
+)
+
+// Step is one migration file that a command would execute.
+type Step struct {
+	Version    uint
+	Identifier string
+	Direction  Direction
+}
+
+func (s Step) String() string {
+	return fmt.Sprintf("%06d_%s.%s.sql", s.Version, s.Identifier, s.Direction)
+}
+
+// PlanUp lists the next n up migrations after current (all pending when n <= 0).
+// hasCurrent is false when no migration has been applied yet.
+func PlanUp(src source.Driver, current uint, hasCurrent bool, n int) ([]Step, error) {
+	var steps []Step
+	next, err := nextVersion(src, current, hasCurrent)
+	for err == nil && (n <= 0 || len(steps) < n) {
+		step, readErr := readStep(src, next, DirectionUp)
+		if readErr != nil {
+			return nil, readErr
+		}
+		steps = append(steps, step)
+		next, err = src.Next(next)
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return steps, nil
+}
+
+// PlanDown lists the down migrations that roll back n versions from current
+// (everything when n <= 0).
+func PlanDown(src source.Driver, current uint, hasCurrent bool, n int) ([]Step, error) {
+	if !hasCurrent {
+		return nil, nil
+	}
+
+	var steps []Step
+	version := current
+	for n <= 0 || len(steps) < n {
+		step, err := readStep(src, version, DirectionDown)
+		if err != nil {
+			return nil, err
+		}
+		steps = append(steps, step)
+
+		prev, err := src.Prev(version)
+		if errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		version = prev
+	}
+	return steps, nil
+}
+
+// PlanGoto lists the migrations that move the schema from current to target.
+func PlanGoto(src source.Driver, current uint, hasCurrent bool, target uint) ([]Step, error) {
+	if _, err := readStep(src, target, DirectionUp); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case !hasCurrent || target > current:
+		all, err := PlanUp(src, current, hasCurrent, 0)
+		if err != nil {
+			return nil, err
+		}
+		var steps []Step
+		for _, step := range all {
+			if step.Version > target {
+				break
+			}
+			steps = append(steps, step)
+		}
+		return steps, nil
+	case target < current:
+		all, err := PlanDown(src, current, hasCurrent, 0)
+		if err != nil {
+			return nil, err
+		}
+		var steps []Step
+		for _, step := range all {
+			if step.Version <= target {
+				break
+			}
+			steps = append(steps, step)
+		}
+		return steps, nil
+	default:
+		return nil, nil
+	}
+}
+
+func nextVersion(src source.Driver, current uint, hasCurrent bool) (uint, error) {
+	if !hasCurrent {
+		return src.First()
+	}
+	return src.Next(current)
+}
+
+func readStep(src source.Driver, version uint, direction Direction) (Step, error) {
+	var (
+		r          io.ReadCloser
+		identifier string
+		err        error
+	)
+	if direction == DirectionUp {
+		r, identifier, err = src.ReadUp(version)
+	} else {
+		r, identifier, err = src.ReadDown(version)
+	}
+	if err != nil {
+		return Step{}, fmt.Errorf("read %s migration %d: %w", direction, version, err)
+	}
+	r.Close()
+	return Step{Version: version, Identifier: identifier, Direction: direction}, nil
+}
@@ -0,0 +1,178 @@
+package migrator
+
+import (
+	"VyacheslavKuchumov/test-backend/cmd/migrate/migrations"
+	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/db"
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func versions(steps []Step) []uint {
+	out := make([]uint, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, step.Version)
+	}
+	return out
+}
+
+func assertVersions(t *testing.T, steps []Step, expected ...uint) {
+	t.Helper()
+	got := versions(steps)
+	if len(got) != len(expected) {
+		t.Fatalf("expected versions %v, got %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("expected versions %v, got %v", expected, got)
+		}
+	}
+}
+
+func TestPlanUp(t *testing.T) {
+	src, err := NewSource(config.DriverPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := PlanUp(src, 0, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18)
+	if steps[0].String() != "000001_add-user-table.up.sql" {
+		t.Fatalf("unexpected step name %q", steps[0])
+	}
+
+	steps, err = PlanUp(src, 3, true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps, 4, 5)
+
+	steps, err = PlanUp(src, 18, true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps)
+}
+
+func TestPlanDown(t *testing.T) {
+	src, err := NewSource(config.DriverPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := PlanDown(src, 6, true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps, 6, 5)
+	if steps[0].Direction != DirectionDown {
+		t.Fatalf("expected down steps, got %s", steps[0].Direction)
+	}
+
+	steps, err = PlanDown(src, 3, true, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps, 3, 2, 1)
+
+	steps, err = PlanDown(src, 0, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps)
+}
+
+func TestPlanGoto(t *testing.T) {
+	src, err := NewSource(config.DriverPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	steps, err := PlanGoto(src, 2, true, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps, 3, 4, 5)
+
+	steps, err = PlanGoto(src, 5, true, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps, 5, 4, 3)
+
+	steps, err = PlanGoto(src, 4, true, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, steps)
+
+	if _, err := PlanGoto(src, 4, true, 99); err == nil {
+		t.Fatal("expected an error for an unknown target version")
+	}
+}
+
+func TestSQLiteMigrationsMatchPostgresVersions(t *testing.T) {
+	postgresSource, err := NewSource(config.DriverPostgres)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqliteSource, err := NewSource(config.DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	postgresSteps, err := PlanUp(postgresSource, 0, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqliteSteps, err := PlanUp(sqliteSource, 0, false, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertVersions(t, sqliteSteps, versions(postgresSteps)...)
+}
+
+func TestSQLiteUpDownRoundTrip(t *testing.T) {
+	database, err := db.NewSQLiteStorage(context.Background(), config.Config{SQLitePath: filepath.Join(t.TempDir(), "tracker.db")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer database.Close()
+
+	ctx := context.Background()
+	if err := Up(ctx, database, config.DriverSQLite); err != nil {
+		t.Fatalf("up: %v", err)
+	}
+
+	m, err := New(ctx, database, config.DriverSQLite)
+	if err != nil {
+		t.Fatal(err)
+	}
+	version, dirty, ok, err := m.CurrentVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest, err := migrations.LatestVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || dirty || version != latest {
+		t.Fatalf("expected clean version %d, got %d (dirty=%v, applied=%v)", latest, version, dirty, ok)
+	}
+
+	if err := m.Down(); err != nil {
+		t.Fatalf("down: %v", err)
+	}
+	if srcErr, dbErr := m.Close(); srcErr != nil || dbErr != nil {
+		t.Fatal(srcErr, dbErr)
+	}
+
+	// Closing the migrator must leave the shared database usable.
+	if err := Up(ctx, database, config.DriverSQLite); err != nil {
+		t.Fatalf("up after down: %v", err)
+	}
+}
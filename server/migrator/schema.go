@@ -0,0 +1,266 @@
+package migrator
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	_ "embed"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// expectedSchema is the snapshot of the schema produced by the embedded
+// migrations. Regenerate it after adding a migration with:
+//
+//	go run ./cmd/migrate snapshot > migrator/schema.snapshot
+//
+//go:embed schema.snapshot
+var expectedSchema string
+
+// ExpectedSchema returns the snapshot lines the live schema is verified against.
+func ExpectedSchema() []string {
+	return ParseSnapshot(expectedSchema)
+}
+
+// ParseSnapshot reads a snapshot, skipping blank lines and # comments.
+func ParseSnapshot(snapshot string) []string {
+	var lines []string
+	scanner := bufio.NewScanner(strings.NewReader(snapshot))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+// Snapshot describes the tables in the current schema as sorted lines, one per
+// column, constraint and index. Keys and indexes are described by the columns
+// they cover; CHECK constraints and partial index predicates by their
+// expressions as the server deparses them, so that a changed list of allowed
+// values or a lost predicate is reported too. The migrate bookkeeping table
+// is excluded.
+func Snapshot(ctx context.Context, db *sql.DB) ([]string, error) {
+	var lines []string
+	for _, query := range []func(context.Context, *sql.DB) ([]string, error){
+		snapshotColumns,
+		snapshotConstraints,
+		snapshotIndexes,
+	} {
+		part, err := query(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, part...)
+	}
+	slices.Sort(lines)
+	return lines, nil
+}
+
+// Drift lists snapshot lines missing from the live schema and lines the
+// live schema has that the snapshot does not.
+type Drift struct {
+	Missing    []string
+	Unexpected []string
+}
+
+func (d Drift) Empty() bool {
+	return len(d.Missing) == 0 && len(d.Unexpected) == 0
+}
+
+// Compare diffs two snapshots.
+func Compare(expected, actual []string) Drift {
+	want := make(map[string]bool, len(expected))
+	for _, line := range expected {
+		want[line] = true
+	}
+	have := make(map[string]bool, len(actual))
+	for _, line := range actual {
+		have[line] = true
+	}
+
+	var drift Drift
+	for _, line := range expected {
+		if !have[line] {
+			drift.Missing = append(drift.Missing, line)
+		}
+	}
+	for _, line := range actual {
+		if !want[line] {
+			drift.Unexpected = append(drift.Unexpected, line)
+		}
+	}
+	return drift
+}
+
+// Verify compares the live schema against the embedded snapshot.
+func Verify(ctx context.Context, db *sql.DB) (Drift, error) {
+	actual, err := Snapshot(ctx, db)
+	if err != nil {
+		return Drift{}, err
+	}
+	return Compare(ExpectedSchema(), actual), nil
+}
+
+const bookkeepingTable = "schema_migrations"
+
+func snapshotColumns(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT cl.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+		        COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+		 FROM pg_attribute a
+		 JOIN pg_class cl ON cl.oid = a.attrelid
+		 JOIN pg_namespace n ON n.oid = cl.relnamespace
+		 LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		 WHERE n.nspname = current_schema()
+		   AND cl.relkind = 'r'
+		   AND cl.relname <> $1
+		   AND a.attnum > 0
+		   AND NOT a.attisdropped`,
+		bookkeepingTable,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var table, column, dataType, defaultExpr string
+		var notNull bool
+		if err := rows.Scan(&table, &column, &dataType, &notNull, &defaultExpr); err != nil {
+			return nil, err
+		}
+
+		line := fmt.Sprintf("column %s.%s %s", table, column, dataType)
+		if notNull {
+			line += " not null"
+		}
+		if defaultExpr != "" {
+			line += " default " + defaultExpr
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+func snapshotConstraints(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT cl.relname, con.conname, con.contype::text,
+		        array_to_string(ARRAY(
+		          SELECT a.attname
+		          FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		          JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		          ORDER BY k.ord
+		        ), ','),
+		        COALESCE(ref.relname, ''),
+		        array_to_string(ARRAY(
+		          SELECT a.attname
+		          FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+		          JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+		          ORDER BY k.ord
+		        ), ','),
+		        con.confdeltype::text,
+		        pg_get_constraintdef(con.oid)
+		 FROM pg_constraint con
+		 JOIN pg_class cl ON cl.oid = con.conrelid
+		 JOIN pg_namespace n ON n.oid = cl.relnamespace
+		 LEFT JOIN pg_class ref ON ref.oid = con.confrelid
+		 WHERE n.nspname = current_schema()
+		   AND cl.relname <> $1
+		   AND con.contype IN ('p', 'u', 'f', 'c')`,
+		bookkeepingTable,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var table, name, kind, columns, refTable, refColumns, onDelete, checkDef string
+		if err := rows.Scan(&table, &name, &kind, &columns, &refTable, &refColumns, &onDelete, &checkDef); err != nil {
+			return nil, err
+		}
+
+		var definition string
+		switch kind {
+		case "p":
+			definition = fmt.Sprintf("primary key (%s)", columns)
+		case "u":
+			definition = fmt.Sprintf("unique (%s)", columns)
+		case "f":
+			definition = fmt.Sprintf("foreign key (%s) references %s (%s) on delete %s", columns, refTable, refColumns, referentialAction(onDelete))
+		case "c":
+			definition = checkDef
+		}
+		lines = append(lines, fmt.Sprintf("constraint %s.%s %s", table, name, definition))
+	}
+	return lines, rows.Err()
+}
+
+func snapshotIndexes(ctx context.Context, db *sql.DB) ([]string, error) {
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT t.relname, i.relname, ix.indisunique,
+		        array_to_string(ARRAY(
+		          SELECT a.attname
+		          FROM unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		          JOIN pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+		          ORDER BY k.ord
+		        ), ','),
+		        COALESCE(pg_get_expr(ix.indpred, ix.indrelid), '')
+		 FROM pg_index ix
+		 JOIN pg_class t ON t.oid = ix.indrelid
+		 JOIN pg_class i ON i.oid = ix.indexrelid
+		 JOIN pg_namespace n ON n.oid = t.relnamespace
+		 WHERE n.nspname = current_schema()
+		   AND t.relname <> $1`,
+		bookkeepingTable,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines []string
+	for rows.Next() {
+		var table, name, columns, predicate string
+		var unique bool
+		if err := rows.Scan(&table, &name, &unique, &columns, &predicate); err != nil {
+			return nil, err
+		}
+
+		kind := "index"
+		if unique {
+			kind = "unique index"
+		}
+		line := fmt.Sprintf("%s %s.%s (%s)", kind, table, name, columns)
+		if predicate != "" {
+			line += " where " + predicate
+		}
+		lines = append(lines, line)
+	}
+	return lines, rows.Err()
+}
+
+func referentialAction(code string) string {
+	switch code {
+	case "r":
+		return "restrict"
+	case "c":
+		return "cascade"
+	case "n":
+		return "set null"
+	case "d":
+		return "set default"
+	default:
+		return "no action"
+	}
+}
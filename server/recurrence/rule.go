@@ -0,0 +1,153 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules
+// that tasks support, and the scheduler that creates the next occurrence of
+// recurring tasks.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Supported frequencies.
+const (
+	Daily   = "DAILY"
+	Weekly  = "WEEKLY"
+	Monthly = "MONTHLY"
+)
+
+var weekdayCodes = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// Rule is a parsed recurrence rule: every day, every week on one or more
+// weekdays, or every month on one day of the month.
+type Rule struct {
+	Freq     string
+	Weekdays []time.Weekday // Weekly only, in calendar order starting Monday.
+	MonthDay int            // Monthly only, 1 to 31.
+}
+
+// Parse parses an RRULE value such as "FREQ=DAILY",
+// "FREQ=WEEKLY;BYDAY=MO,WE,FR" or "FREQ=MONTHLY;BYMONTHDAY=15", in any
+// case. An "RRULE:" prefix is accepted. Weekly rules need BYDAY and monthly rules
+// need BYMONTHDAY; other RRULE parts are not supported.
+func Parse(value string) (Rule, error) {
+	value = strings.TrimSpace(value)
+	if len(value) >= 6 && strings.EqualFold(value[:6], "RRULE:") {
+		value = value[6:]
+	}
+	if value == "" {
+		return Rule{}, errors.New("recurrence rule is empty")
+	}
+
+	parts := make(map[string]string)
+	for _, part := range strings.Split(value, ";") {
+		name, val, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return Rule{}, fmt.Errorf("invalid recurrence rule part %q", part)
+		}
+		if _, dup := parts[name]; dup {
+			return Rule{}, fmt.Errorf("recurrence rule repeats %s", name)
+		}
+		parts[name] = strings.ToUpper(strings.TrimSpace(val))
+	}
+
+	rule := Rule{Freq: parts["FREQ"]}
+	delete(parts, "FREQ")
+	switch rule.Freq {
+	case Daily:
+	case Weekly:
+		days, ok := parts["BYDAY"]
+		if !ok {
+			return Rule{}, errors.New("weekly recurrence needs BYDAY, e.g. BYDAY=MO,TH")
+		}
+		delete(parts, "BYDAY")
+		for _, code := range strings.Split(days, ",") {
+			day := slices.Index(weekdayCodes, strings.TrimSpace(code))
+			if day < 0 {
+				return Rule{}, fmt.Errorf("invalid BYDAY weekday %q", code)
+			}
+			if !slices.Contains(rule.Weekdays, time.Weekday(day)) {
+				rule.Weekdays = append(rule.Weekdays, time.Weekday(day))
+			}
+		}
+		slices.SortFunc(rule.Weekdays, func(a, b time.Weekday) int {
+			return mondayFirst(a) - mondayFirst(b)
+		})
+	case Monthly:
+		day, ok := parts["BYMONTHDAY"]
+		if !ok {
+			return Rule{}, errors.New("monthly recurrence needs BYMONTHDAY, e.g. BYMONTHDAY=1")
+		}
+		delete(parts, "BYMONTHDAY")
+		n, err := strconv.Atoi(day)
+		if err != nil || n < 1 || n > 31 {
+			return Rule{}, fmt.Errorf("BYMONTHDAY must be between 1 and 31, got %q", day)
+		}
+		rule.MonthDay = n
+	case "":
+		return Rule{}, errors.New("recurrence rule needs FREQ")
+	default:
+		return Rule{}, fmt.Errorf("unsupported FREQ %q, use DAILY, WEEKLY or MONTHLY", rule.Freq)
+	}
+
+	for name := range parts {
+		return Rule{}, fmt.Errorf("unsupported recurrence rule part %s", name)
+	}
+	return rule, nil
+}
+
+// String returns the canonical form of the rule, which is what tasks store.
+func (r Rule) String() string {
+	switch r.Freq {
+	case Weekly:
+		codes := make([]string, len(r.Weekdays))
+		for i, day := range r.Weekdays {
+			codes[i] = weekdayCodes[day]
+		}
+		return "FREQ=WEEKLY;BYDAY=" + strings.Join(codes, ",")
+	case Monthly:
+		return "FREQ=MONTHLY;BYMONTHDAY=" + strconv.Itoa(r.MonthDay)
+	default:
+		return "FREQ=" + r.Freq
+	}
+}
+
+// Next returns the first date of the rule strictly after date. Only the
+// calendar date of date is used. Months without the rule's day of the
+// month, such as February for BYMONTHDAY=30, are skipped as RFC 5545
+// requires.
+func (r Rule) Next(date time.Time) time.Time {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+	switch r.Freq {
+	case Weekly:
+		for i := 1; i <= 7; i++ {
+			next := date.AddDate(0, 0, i)
+			if slices.Contains(r.Weekdays, next.Weekday()) {
+				return next
+			}
+		}
+	case Monthly:
+		year, month := date.Year(), date.Month()
+		if date.Day() >= r.MonthDay {
+			month++
+		}
+		for {
+			// time.Date normalizes overflowing days into the next month,
+			// which tells months that are too short apart.
+			next := time.Date(year, month, r.MonthDay, 0, 0, 0, 0, time.UTC)
+			if next.Day() == r.MonthDay {
+				return next
+			}
+			month++
+		}
+	}
+	return date.AddDate(0, 0, 1)
+}
+
+func mondayFirst(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
@@ -0,0 +1,70 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCanonicalizes(t *testing.T) {
+	tests := map[string]string{
+		"FREQ=DAILY":                       "FREQ=DAILY",
+		"rrule:freq=weekly;byday=fr,mo,fr": "FREQ=WEEKLY;BYDAY=MO,FR",
+		"FREQ=WEEKLY;BYDAY=SU,SA":          "FREQ=WEEKLY;BYDAY=SA,SU",
+		" BYMONTHDAY=05;FREQ=MONTHLY ":     "FREQ=MONTHLY;BYMONTHDAY=5",
+	}
+	for value, want := range tests {
+		rule, err := Parse(value)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", value, err)
+		}
+		if got := rule.String(); got != want {
+			t.Fatalf("Parse(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
+
+func TestParseRejectsUnsupportedRules(t *testing.T) {
+	for _, value := range []string{
+		"",
+		"FREQ=YEARLY",
+		"BYDAY=MO",
+		"FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=MONTHLY",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTHDAY=-1",
+		"FREQ=DAILY;INTERVAL=2",
+		"FREQ=DAILY;FREQ=DAILY",
+		"FREQ",
+	} {
+		if _, err := Parse(value); err == nil {
+			t.Fatalf("expected Parse(%q) to fail", value)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		rule, date, want string
+	}{
+		{"FREQ=DAILY", "2026-02-28", "2026-03-01"},
+		{"FREQ=WEEKLY;BYDAY=MO,TH", "2026-03-02", "2026-03-05"}, // Monday
+		{"FREQ=WEEKLY;BYDAY=MO,TH", "2026-03-05", "2026-03-09"},
+		{"FREQ=WEEKLY;BYDAY=MO", "2026-03-02", "2026-03-09"},
+		{"FREQ=MONTHLY;BYMONTHDAY=15", "2026-03-14", "2026-03-15"},
+		{"FREQ=MONTHLY;BYMONTHDAY=15", "2026-03-15", "2026-04-15"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31", "2026-03-31", "2026-05-31"},
+		{"FREQ=MONTHLY;BYMONTHDAY=30", "2026-01-30", "2026-03-30"},
+		{"FREQ=MONTHLY;BYMONTHDAY=1", "2026-12-01", "2027-01-01"},
+	}
+	for _, tt := range tests {
+		rule, err := Parse(tt.rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		date, _ := time.Parse(time.DateOnly, tt.date)
+		if got := rule.Next(date).Format(time.DateOnly); got != tt.want {
+			t.Fatalf("%s after %s = %s, want %s", tt.rule, tt.date, got, tt.want)
+		}
+	}
+}
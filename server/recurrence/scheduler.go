@@ -0,0 +1,111 @@
+package recurrence
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// batchSize bounds how many pending tasks are read per query.
+const batchSize = 100
+
+// Scheduler creates the next occurrence of recurring tasks. An occurrence
+// is created once the current one is completed or its due date arrives.
+// The next due date is the first date of the rule after both the current
+// due date and today, so occurrences missed while the server was down are
+// skipped rather than back-filled. Dates are in UTC.
+//
+// Every server runs a scheduler. The store lock lets one of them work at a
+// time, and the store marks each task as recurred in the transaction that
+// creates its occurrence, so restarts and concurrent runs never create an
+// occurrence twice.
+type Scheduler struct {
+	store    types.RecurrenceStore
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewScheduler(store types.RecurrenceStore, interval time.Duration) *Scheduler {
+	return &Scheduler{store: store, interval: interval, now: time.Now}
+}
+
+// Run runs the scheduler immediately and then every interval until ctx is
+// cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		if created, err := s.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "recurring task scheduler failed", "error", err)
+		} else if created > 0 {
+			slog.InfoContext(ctx, "created recurring task occurrences", "count", created)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce creates every pending occurrence and returns how many it created.
+// It does nothing when another server holds the lock.
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	today := s.now().UTC()
+	created := 0
+	_, err := s.store.WithRecurrenceLock(ctx, func(ctx context.Context) error {
+		afterID := 0
+		for {
+			tasks, err := s.store.PendingRecurringTasks(ctx, today.Format(time.DateOnly), afterID, batchSize)
+			if err != nil {
+				return err
+			}
+			for _, task := range tasks {
+				afterID = task.ID
+				due, err := nextDueDate(task, today)
+				if err != nil {
+					slog.WarnContext(ctx, "skipping recurring task", "task_id", task.ID, "error", err)
+					continue
+				}
+				next, err := s.store.CreateNextOccurrence(ctx, task.ID, due)
+				if err != nil {
+					return fmt.Errorf("create next occurrence of task %d: %w", task.ID, err)
+				}
+				if next != nil {
+					created++
+				}
+			}
+			if len(tasks) < batchSize {
+				return nil
+			}
+		}
+	})
+	return created, err
+}
+
+// nextDueDate returns the due date, as YYYY-MM-DD, of the occurrence that
+// follows task: the first date of its rule after both its due date and
+// today.
+func nextDueDate(task *types.Task, today time.Time) (string, error) {
+	rule, err := Parse(task.Recurrence)
+	if err != nil {
+		return "", err
+	}
+	if task.DueDate == nil {
+		return "", fmt.Errorf("recurring task has no due date")
+	}
+	due, err := time.Parse(time.DateOnly, *task.DueDate)
+	if err != nil {
+		return "", fmt.Errorf("invalid due date %q", *task.DueDate)
+	}
+
+	today = time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	next := rule.Next(due)
+	for !next.After(today) {
+		next = rule.Next(next)
+	}
+	return next.Format(time.DateOnly), nil
+}
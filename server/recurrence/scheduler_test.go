@@ -0,0 +1,94 @@
+package recurrence
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// fakeStore is a RecurrenceStore over a slice of tasks. The memstore
+// package cannot be used here, since it depends on this one.
+type fakeStore struct {
+	tasks    []*types.Task
+	recurred map[int]bool
+	locked   bool
+}
+
+func (f *fakeStore) WithRecurrenceLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	if f.locked {
+		return false, nil
+	}
+	return true, fn(ctx)
+}
+
+func (f *fakeStore) PendingRecurringTasks(_ context.Context, today string, afterID, limit int) ([]*types.Task, error) {
+	var pending []*types.Task
+	for _, task := range f.tasks {
+		if task.ID > afterID && task.Recurrence != "" && !f.recurred[task.ID] && task.DueDate != nil &&
+			(task.IsCompleted || *task.DueDate <= today) && len(pending) < limit {
+			pending = append(pending, task)
+		}
+	}
+	return pending, nil
+}
+
+func (f *fakeStore) CreateNextOccurrence(_ context.Context, taskID int, dueDate string) (*types.Task, error) {
+	if f.recurred[taskID] {
+		return nil, nil
+	}
+	f.recurred[taskID] = true
+	source := f.tasks[slices.IndexFunc(f.tasks, func(t *types.Task) bool { return t.ID == taskID })]
+	next := *source
+	next.ID = len(f.tasks) + 1
+	next.IsCompleted = false
+	next.DueDate = &dueDate
+	f.tasks = append(f.tasks, &next)
+	return &next, nil
+}
+
+func newTask(id int, rule, due string, completed bool) *types.Task {
+	return &types.Task{ID: id, Title: "Task", Recurrence: rule, DueDate: &due, IsCompleted: completed}
+}
+
+func TestRunOnceCreatesEachOccurrenceOnce(t *testing.T) {
+	store := &fakeStore{recurred: map[int]bool{}, tasks: []*types.Task{
+		newTask(1, "FREQ=DAILY", "2026-03-02", false),                // due today
+		newTask(2, "FREQ=WEEKLY;BYDAY=MO", "2026-03-09", true),       // completed early
+		newTask(3, "FREQ=WEEKLY;BYDAY=MO", "2026-03-09", false),      // not due yet
+		newTask(4, "FREQ=MONTHLY;BYMONTHDAY=1", "2025-11-01", false), // missed occurrences
+		newTask(5, "FREQ=HOURLY", "2026-03-01", false),               // invalid rule
+		newTask(6, "", "2026-03-01", true),                           // not recurring
+	}}
+	scheduler := NewScheduler(store, time.Minute)
+	scheduler.now = func() time.Time { return time.Date(2026, 3, 2, 23, 30, 0, 0, time.UTC) }
+
+	created, err := scheduler.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != 3 {
+		t.Fatalf("expected 3 occurrences, got %d", created)
+	}
+	want := map[string]string{"FREQ=DAILY": "2026-03-03", "FREQ=WEEKLY;BYDAY=MO": "2026-03-16", "FREQ=MONTHLY;BYMONTHDAY=1": "2026-04-01"}
+	for _, task := range store.tasks[6:] {
+		if *task.DueDate != want[task.Recurrence] || task.IsCompleted {
+			t.Fatalf("unexpected occurrence %+v due %s", task, *task.DueDate)
+		}
+	}
+
+	if created, err := scheduler.RunOnce(context.Background()); err != nil || created != 0 {
+		t.Fatalf("expected a second run to create nothing, got %d, %v", created, err)
+	}
+}
+
+func TestRunOnceSkipsWhenLocked(t *testing.T) {
+	store := &fakeStore{recurred: map[int]bool{}, locked: true, tasks: []*types.Task{
+		newTask(1, "FREQ=DAILY", "2026-03-01", true),
+	}}
+	created, err := NewScheduler(store, time.Minute).RunOnce(context.Background())
+	if err != nil || created != 0 || len(store.tasks) != 1 {
+		t.Fatalf("expected nothing while another server holds the lock, got %d, %v", created, err)
+	}
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// RequireAdmin rejects requests from users whose email isAdmin does not
+// accept, normally config.Config.IsAdmin. It must run after JWTAuthMiddleware.
+func RequireAdmin(store types.UserStore, isAdmin func(email string) bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID := GetUserIDFromContext(r.Context())
+			if userID <= 0 {
+				unauthorized(w)
+				return
+			}
+
+			u, err := store.GetUserByID(r.Context(), userID)
+			if err != nil {
+				slog.WarnContext(r.Context(), "failed to load user for admin check", "error", err)
+				unauthorized(w)
+				return
+			}
+			if !isAdmin(u.Email) {
+				utils.WriteError(w, http.StatusForbidden, fmt.Errorf("admin access required"))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
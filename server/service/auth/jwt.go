@@ -1,12 +1,13 @@
 package auth
 
 import (
-	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/logging"
+	"VyacheslavKuchumov/test-backend/tracing"
 	"VyacheslavKuchumov/test-backend/types"
 	"VyacheslavKuchumov/test-backend/utils"
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -20,9 +21,7 @@ type contextKey string
 const UserKey contextKey = "userID"
 const AuthCookieName = "task_tracker_token"
 
-func CreateJWT(secret []byte, userID int) (string, error) {
-	expiration := time.Second * time.Duration(config.Envs.JWTExpirationInSeconds)
-
+func CreateJWT(secret []byte, expiration time.Duration, userID int) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"userID":    strconv.Itoa(userID),
 		"expiredAt": time.Now().Add(expiration).Unix(),
@@ -35,44 +34,45 @@ func CreateJWT(secret []byte, userID int) (string, error) {
 	return tokenString, nil
 }
 
-func SetAuthCookie(w http.ResponseWriter, token string) {
-	expiration := time.Second * time.Duration(config.Envs.JWTExpirationInSeconds)
+func SetAuthCookie(w http.ResponseWriter, token string, expiration time.Duration) {
 	expiresAt := time.Now().Add(expiration)
 	http.SetCookie(w, &http.Cookie{
 		Name:     AuthCookieName,
 		Value:    token,
 		Path:     "/",
 		Expires:  expiresAt,
-		MaxAge:   int(config.Envs.JWTExpirationInSeconds),
+		MaxAge:   int(expiration.Seconds()),
 		HttpOnly: true,
 		SameSite: http.SameSiteLaxMode,
 	})
 }
 
-func JWTAuthMiddleware(store types.UserStore) func(http.Handler) http.Handler {
+func JWTAuthMiddleware(store types.UserStore, secret []byte) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			userID, err := getUserIDFromRequest(r, store)
+			userID, err := getUserIDFromRequest(r, store, secret)
 			if err != nil {
-				log.Printf("Failed to authorize request: %v", err)
-				permissionDenied(w)
+				slog.WarnContext(r.Context(), "failed to authorize request", "error", err)
+				unauthorized(w)
 				return
 			}
 
 			ctx := r.Context()
+			logging.AddAttrs(ctx, slog.Int("user_id", userID))
+			tracing.SetUserID(ctx, userID)
 			ctx = context.WithValue(ctx, UserKey, userID)
 			next.ServeHTTP(w, r.WithContext(ctx))
 		})
 	}
 }
 
-func JWTAuthMiddlewareWithExclusions(store types.UserStore, excludedPaths ...string) func(http.Handler) http.Handler {
+func JWTAuthMiddlewareWithExclusions(store types.UserStore, secret []byte, excludedPaths ...string) func(http.Handler) http.Handler {
 	excluded := make(map[string]struct{}, len(excludedPaths))
 	for _, path := range excludedPaths {
 		excluded[normalizePath(path)] = struct{}{}
 	}
 
-	baseMiddleware := JWTAuthMiddleware(store)
+	baseMiddleware := JWTAuthMiddleware(store, secret)
 	return func(next http.Handler) http.Handler {
 		protectedNext := baseMiddleware(next)
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -85,15 +85,15 @@ func JWTAuthMiddlewareWithExclusions(store types.UserStore, excludedPaths ...str
 	}
 }
 
-func WithJWTAuth(handlerFunc http.HandlerFunc, store types.UserStore) http.HandlerFunc {
-	middleware := JWTAuthMiddleware(store)
+func WithJWTAuth(handlerFunc http.HandlerFunc, store types.UserStore, secret []byte) http.HandlerFunc {
+	middleware := JWTAuthMiddleware(store, secret)
 	protectedHandler := middleware(http.HandlerFunc(handlerFunc))
 	return protectedHandler.ServeHTTP
 }
 
-func getUserIDFromRequest(r *http.Request, store types.UserStore) (int, error) {
+func getUserIDFromRequest(r *http.Request, store types.UserStore, secret []byte) (int, error) {
 	tokenString := getTokenFromRequest(r)
-	token, err := validateToken(tokenString)
+	token, err := validateToken(tokenString, secret)
 	if err != nil {
 		return 0, err
 	}
@@ -112,7 +112,7 @@ func getUserIDFromRequest(r *http.Request, store types.UserStore) (int, error) {
 		return 0, err
 	}
 
-	u, err := store.GetUserByID(userID)
+	u, err := store.GetUserByID(r.Context(), userID)
 	if err != nil {
 		return 0, err
 	}
@@ -138,18 +138,19 @@ func getTokenFromRequest(r *http.Request) string {
 	return tokenAuth
 }
 
-func validateToken(t string) (*jwt.Token, error) {
+func validateToken(t string, secret []byte) (*jwt.Token, error) {
 	return jwt.Parse(t, func(t *jwt.Token) (any, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("Unexpected signing method: %v", t.Header["alg"])
 		}
 
-		return []byte(config.Envs.JWTSecret), nil
+		return secret, nil
 	})
 }
 
-func permissionDenied(w http.ResponseWriter) {
-	utils.WriteError(w, http.StatusForbidden, fmt.Errorf("Permission denied"))
+func unauthorized(w http.ResponseWriter) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="api"`)
+	utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 }
 
 func GetUserIDFromContext(ctx context.Context) int {
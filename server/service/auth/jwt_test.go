@@ -4,12 +4,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestCreateJWT(t *testing.T) {
 	secret := []byte("secret")
 
-	token, err := CreateJWT(secret, 1)
+	token, err := CreateJWT(secret, time.Hour, 1)
 	if err != nil {
 		t.Errorf("error creating JWT: %v", err)
 	}
@@ -0,0 +1,157 @@
+package calendar
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+type Handler struct {
+	store        types.CalendarStore
+	trackerStore types.GoalTaskStore
+	publicHost   string
+	now          func() time.Time
+}
+
+func NewHandler(store types.CalendarStore, trackerStore types.GoalTaskStore, publicHost string) *Handler {
+	return &Handler{store: store, trackerStore: trackerStore, publicHost: publicHost, now: time.Now}
+}
+
+// HandleCreateToken godoc
+// @Summary Create or regenerate the calendar feed token
+// @Description Create a secret token for the caller's iCalendar feed and return the subscription URL. Calling it again replaces the token, so the previous URL stops working. The token is only shown in this response.
+// @Tags calendar
+// @Produce json
+// @Security BearerAuth
+// @Success 201 {object} types.CalendarTokenResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /calendar/token [post]
+func (h *Handler) HandleCreateToken(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	if err := h.store.SetCalendarToken(r.Context(), userID, hashToken(token)); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusCreated, types.CalendarTokenResponse{
+		Token: token,
+		URL:   strings.TrimRight(h.publicHost, "/") + "/ical/" + token + ".ics",
+	})
+}
+
+// HandleDeleteToken godoc
+// @Summary Disable the calendar feed
+// @Description Delete the caller's calendar feed token so its URL stops working.
+// @Tags calendar
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /calendar/token [delete]
+func (h *Handler) HandleDeleteToken(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	if err := h.store.DeleteCalendarToken(r.Context(), userID); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleFeed serves the iCalendar feed for the user owning the token in the
+// path. It is mounted outside /api/v1 and needs no JWT, so calendar apps can
+// subscribe to it; the token is the credential. Assigned tasks are VTODOs;
+// with goals=true, goals the user owns that have a due date are added as
+// all-day VEVENTs.
+//
+// The route is left out of the Swagger document, whose paths are relative
+// to /api/v1.
+func (h *Handler) HandleFeed(w http.ResponseWriter, r *http.Request) {
+	includeGoals := false
+	if value := r.URL.Query().Get("goals"); value != "" {
+		var err error
+		if includeGoals, err = strconv.ParseBool(value); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid goals %q", value))
+			return
+		}
+	}
+
+	userID, err := h.store.GetUserIDByCalendarToken(r.Context(), hashToken(chi.URLParam(r, "token")))
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+
+	tasks, err := h.trackerStore.GetAssignedTasks(r.Context(), userID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	var goals []*types.GoalWithTasks
+	if includeGoals {
+		if goals, err = h.trackerStore.GetGoalsByOwner(r.Context(), userID); err != nil {
+			utils.WriteStoreError(w, err, storeErrors)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `inline; filename="task-tracker.ics"`)
+	w.Header().Set("Cache-Control", "private, max-age=300")
+
+	ics := newICSWriter(w, h.now())
+	ics.begin("Task Tracker")
+	for _, task := range tasks {
+		ics.task(task)
+	}
+	for _, goal := range goals {
+		if goal.OwnerID == userID {
+			ics.goal(&goal.Goal)
+		}
+	}
+	if err := ics.end(); err != nil {
+		slog.WarnContext(r.Context(), "failed to write calendar feed", "error", err)
+	}
+}
+
+// hashToken returns the hex SHA-256 of a feed token. Tokens carry 256 bits
+// of randomness, so an unsalted hash is enough to keep a database leak from
+// exposing working feed URLs.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// storeErrors gives the status for an unknown or revoked feed token.
+var storeErrors = map[error]int{
+	ErrNotFound: http.StatusNotFound,
+}
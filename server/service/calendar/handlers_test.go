@@ -0,0 +1,157 @@
+package calendar_test
+
+import (
+	"VyacheslavKuchumov/test-backend/handlertest"
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/calendar"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestFeedRendersAssignedTasksAndOwnedGoals(t *testing.T) {
+	store, owner, other := seed(t)
+	ctx := context.Background()
+	due := "2026-03-31"
+
+	goal, err := store.CreateGoal(ctx, owner, types.CreateGoalPayload{Title: "Launch, phase 1", Priority: "high", Status: "todo", DueDate: &due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherGoal, err := store.CreateGoal(ctx, other, types.CreateGoalPayload{Title: "Someone else's", Priority: "low", Status: "todo", DueDate: &due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := store.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: "Write copy", Description: "Line one\nline two", Priority: "low", AssigneeID: &owner, DueDate: &due})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateTask(ctx, otherGoal.ID, other, types.CreateTaskPayload{Title: "Not mine", Priority: "medium"}); err != nil {
+		t.Fatal(err)
+	}
+
+	router := newRouter(store)
+	token := createToken(t, router, owner)
+
+	rr := handlertest.Serve(router, http.MethodGet, "/ical/"+token.Token+".ics?goals=true", 0, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/calendar; charset=utf-8" {
+		t.Fatalf("unexpected content type %q", ct)
+	}
+	body := strings.ReplaceAll(rr.Body.String(), "\r\n ", "")
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VTODO\r\nUID:task-" + strconv.Itoa(task.ID) + "@task-tracker\r\n",
+		"SUMMARY:Write copy\r\n",
+		"DESCRIPTION:Line one\\nline two\r\n",
+		"CATEGORIES:Launch\\, phase 1\r\n",
+		"PRIORITY:9\r\n",
+		"DUE;VALUE=DATE:20260331\r\n",
+		"STATUS:NEEDS-ACTION\r\n",
+		"BEGIN:VEVENT\r\nUID:goal-" + strconv.Itoa(goal.ID) + "@task-tracker\r\n",
+		"DTSTART;VALUE=DATE:20260331\r\nDTEND;VALUE=DATE:20260401\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected feed to contain %q:\n%s", want, body)
+		}
+	}
+	for _, unwanted := range []string{"Not mine", "Someone else", "goal-" + strconv.Itoa(otherGoal.ID)} {
+		if strings.Contains(body, unwanted) {
+			t.Fatalf("expected feed not to contain %q:\n%s", unwanted, body)
+		}
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/ical/"+token.Token+".ics", 0, "")
+	if strings.Contains(rr.Body.String(), "BEGIN:VEVENT") {
+		t.Fatalf("expected goals only when requested:\n%s", rr.Body.String())
+	}
+}
+
+func TestFeedTokenLifecycle(t *testing.T) {
+	store, owner, _ := seed(t)
+	router := newRouter(store)
+
+	first := createToken(t, router, owner)
+	if first.URL != "https://tracker.example.com/ical/"+first.Token+".ics" {
+		t.Fatalf("unexpected feed URL %q", first.URL)
+	}
+	second := createToken(t, router, owner)
+	if second.Token == first.Token {
+		t.Fatal("expected a new token")
+	}
+	if rr := handlertest.Serve(router, http.MethodGet, "/ical/"+first.Token+".ics", 0, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected the replaced token to 404, got %d", rr.Code)
+	}
+	if rr := handlertest.Serve(router, http.MethodGet, "/ical/"+second.Token+".ics", 0, ""); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if rr := handlertest.Serve(router, http.MethodDelete, "/calendar/token", owner, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := handlertest.Serve(router, http.MethodGet, "/ical/"+second.Token+".ics", 0, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected the deleted token to 404, got %d", rr.Code)
+	}
+}
+
+func TestTokenRoutesRequireAuthentication(t *testing.T) {
+	store, _, _ := seed(t)
+	router := newRouter(store)
+
+	for _, method := range []string{http.MethodPost, http.MethodDelete} {
+		if rr := handlertest.Serve(router, method, "/calendar/token", 0, ""); rr.Code != http.StatusUnauthorized {
+			t.Fatalf("%s: expected 401, got %d", method, rr.Code)
+		}
+	}
+}
+
+func seed(t *testing.T) (*memstore.Store, int, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := memstore.New()
+	ids := make([]int, 0, 2)
+	for _, u := range []types.User{
+		{FirstName: "Owner", LastName: "User", Email: "owner@example.com", Password: "hashed"},
+		{FirstName: "Other", LastName: "User", Email: "other@example.com", Password: "hashed"},
+	} {
+		if err := store.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		created, err := store.GetUserByEmail(ctx, u.Email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+	return store, ids[0], ids[1]
+}
+
+func newRouter(store *memstore.Store) chi.Router {
+	handler := calendar.NewHandler(store, store, "https://tracker.example.com/")
+	router := chi.NewRouter()
+	calendar.RegisterRoutes(router, handler)
+	calendar.RegisterFeedRoutes(router, handler)
+	return router
+}
+
+func createToken(t *testing.T, router chi.Router, userID int) types.CalendarTokenResponse {
+	t.Helper()
+	rr := handlertest.Serve(router, http.MethodPost, "/calendar/token", userID, "")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var token types.CalendarTokenResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &token); err != nil || token.Token == "" {
+		t.Fatalf("unexpected token response %s, %v", rr.Body.String(), err)
+	}
+	return token
+}
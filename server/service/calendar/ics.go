@@ -0,0 +1,160 @@
+package calendar
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// uidDomain qualifies UIDs. It is fixed rather than derived from
+// PUBLIC_HOST so that entries keep their identity if the host changes.
+const uidDomain = "task-tracker"
+
+// maxLineOctets is the longest content line RFC 5545 allows before folding.
+const maxLineOctets = 75
+
+// icsWriter writes an RFC 5545 calendar: CRLF line endings, long lines
+// folded and TEXT values escaped. Write errors are kept and returned by
+// end.
+type icsWriter struct {
+	w     *bufio.Writer
+	stamp string
+	err   error
+}
+
+func newICSWriter(w io.Writer, now time.Time) *icsWriter {
+	return &icsWriter{w: bufio.NewWriter(w), stamp: now.UTC().Format("20060102T150405Z")}
+}
+
+func (c *icsWriter) begin(name string) {
+	c.line("BEGIN:VCALENDAR")
+	c.line("VERSION:2.0")
+	c.line("PRODID:-//Task Tracker//Calendar Feed//EN")
+	c.line("CALSCALE:GREGORIAN")
+	c.line("METHOD:PUBLISH")
+	c.line("X-WR-CALNAME:" + escapeText(name))
+}
+
+// task writes a VTODO. The goal title is its category, and the due date, if
+// any, an all-day DUE.
+func (c *icsWriter) task(task *types.Task) {
+	c.line("BEGIN:VTODO")
+	c.line(fmt.Sprintf("UID:task-%d@%s", task.ID, uidDomain))
+	c.line("DTSTAMP:" + c.stamp)
+	c.line("CREATED:" + task.CreatedAt.UTC().Format("20060102T150405Z"))
+	c.line("SUMMARY:" + escapeText(task.Title))
+	if task.Description != "" {
+		c.line("DESCRIPTION:" + escapeText(task.Description))
+	}
+	if task.GoalTitle != "" {
+		c.line("CATEGORIES:" + escapeText(task.GoalTitle))
+	}
+	c.line(fmt.Sprintf("PRIORITY:%d", icsPriority(task.Priority)))
+	if date, ok := icsDate(task.DueDate); ok {
+		c.line("DUE;VALUE=DATE:" + date)
+	}
+	if task.IsCompleted {
+		c.line("STATUS:COMPLETED")
+		c.line("PERCENT-COMPLETE:100")
+	} else {
+		c.line("STATUS:NEEDS-ACTION")
+	}
+	c.line("END:VTODO")
+}
+
+// goal writes an all-day VEVENT on the goal's due date. Goals without one
+// are skipped, since an event needs a date.
+func (c *icsWriter) goal(goal *types.Goal) {
+	start, ok := icsDate(goal.DueDate)
+	if !ok {
+		return
+	}
+	due, _ := time.Parse(time.DateOnly, *goal.DueDate)
+
+	c.line("BEGIN:VEVENT")
+	c.line(fmt.Sprintf("UID:goal-%d@%s", goal.ID, uidDomain))
+	c.line("DTSTAMP:" + c.stamp)
+	c.line("CREATED:" + goal.CreatedAt.UTC().Format("20060102T150405Z"))
+	c.line("DTSTART;VALUE=DATE:" + start)
+	c.line("DTEND;VALUE=DATE:" + due.AddDate(0, 0, 1).Format("20060102"))
+	c.line("SUMMARY:" + escapeText(goal.Title))
+	if goal.Description != "" {
+		c.line("DESCRIPTION:" + escapeText(goal.Description))
+	}
+	c.line(fmt.Sprintf("PRIORITY:%d", icsPriority(goal.Priority)))
+	c.line("STATUS:CONFIRMED")
+	c.line("TRANSP:TRANSPARENT")
+	c.line("END:VEVENT")
+}
+
+func (c *icsWriter) end() error {
+	c.line("END:VCALENDAR")
+	if c.err != nil {
+		return c.err
+	}
+	return c.w.Flush()
+}
+
+// line writes one content line, folding it into continuation lines that
+// start with a space. Folds never split a UTF-8 sequence.
+func (c *icsWriter) line(s string) {
+	if c.err != nil {
+		return
+	}
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		if _, c.err = c.w.WriteString(s[:cut] + "\r\n "); c.err != nil {
+			return
+		}
+		s = s[cut:]
+		// Continuation lines lose one octet to the leading space.
+		limit = maxLineOctets - 1
+	}
+	_, c.err = c.w.WriteString(s + "\r\n")
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", "",
+)
+
+// escapeText escapes a TEXT property value (RFC 5545 section 3.3.11).
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// icsPriority maps high, medium and low to the iCalendar scale where 1 is
+// the highest priority and 9 the lowest.
+func icsPriority(priority string) int {
+	switch priority {
+	case "high":
+		return 1
+	case "low":
+		return 9
+	default:
+		return 5
+	}
+}
+
+func icsDate(date *string) (string, bool) {
+	if date == nil {
+		return "", false
+	}
+	t, err := time.Parse(time.DateOnly, *date)
+	if err != nil {
+		return "", false
+	}
+	return t.Format("20060102"), true
+}
@@ -0,0 +1,50 @@
+package calendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestLineFoldsAt75OctetsWithoutSplittingRunes(t *testing.T) {
+	var b strings.Builder
+	c := newICSWriter(&b, time.Time{})
+	value := "SUMMARY:" + strings.Repeat("é", 100)
+	c.line(value)
+	if err := c.end(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	lines = lines[:len(lines)-1] // END:VCALENDAR
+	if len(lines) < 3 {
+		t.Fatalf("expected the line to be folded, got %q", lines)
+	}
+	var unfolded strings.Builder
+	for i, line := range lines {
+		if len(line) > 75 {
+			t.Fatalf("line %d has %d octets", i, len(line))
+		}
+		if i > 0 {
+			if !strings.HasPrefix(line, " ") {
+				t.Fatalf("continuation line %d does not start with a space: %q", i, line)
+			}
+			line = line[1:]
+		}
+		if !utf8.ValidString(line) {
+			t.Fatalf("line %d splits a UTF-8 sequence: %q", i, line)
+		}
+		unfolded.WriteString(line)
+	}
+	if unfolded.String() != value {
+		t.Fatalf("unfolded line differs:\n%q\n%q", unfolded.String(), value)
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	got := escapeText("a\\b;c,d\r\ne\nf\rg")
+	if want := `a\\b\;c\,d\ne\nfg`; got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
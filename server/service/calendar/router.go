@@ -0,0 +1,17 @@
+package calendar
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes mounts the token routes under the authenticated API.
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Post("/calendar/token", handler.HandleCreateToken)
+	r.Delete("/calendar/token", handler.HandleDeleteToken)
+}
+
+// RegisterFeedRoutes mounts the public feed, which must sit outside the JWT
+// middleware.
+func RegisterFeedRoutes(r chi.Router, handler *Handler) {
+	r.Get("/ical/{token}.ics", handler.HandleFeed)
+}
@@ -0,0 +1,60 @@
+package calendar
+
+import (
+	"VyacheslavKuchumov/test-backend/tracing"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+var ErrNotFound = errors.New("calendar feed not found")
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// SetCalendarToken stores tokenHash as the user's feed token, replacing any
+// previous one.
+func (s *Store) SetCalendarToken(ctx context.Context, userID int, tokenHash string) error {
+	ctx = tracing.WithStatementName(ctx, "calendar.SetCalendarToken")
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO calendar_tokens (user_id, token_hash)
+		 VALUES ($1, $2)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET token_hash = EXCLUDED.token_hash,
+		     created_at = CURRENT_TIMESTAMP`,
+		userID,
+		tokenHash,
+	)
+	return err
+}
+
+// DeleteCalendarToken disables the user's feed. It succeeds when there is
+// no feed to disable.
+func (s *Store) DeleteCalendarToken(ctx context.Context, userID int) error {
+	ctx = tracing.WithStatementName(ctx, "calendar.DeleteCalendarToken")
+	_, err := s.db.ExecContext(ctx, `DELETE FROM calendar_tokens WHERE user_id = $1`, userID)
+	return err
+}
+
+func (s *Store) GetUserIDByCalendarToken(ctx context.Context, tokenHash string) (int, error) {
+	ctx = tracing.WithStatementName(ctx, "calendar.GetUserIDByCalendarToken")
+	var userID int
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT user_id FROM calendar_tokens WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return userID, nil
+}
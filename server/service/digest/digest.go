@@ -0,0 +1,105 @@
+// Package digest emails each opted-in user a daily or weekly summary of
+// their open assigned tasks, the tasks newly assigned to them and the tasks
+// completed on goals they own.
+package digest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/types"
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+
+	// Time zones are loaded from the embedded database when the host has
+	// none, as in the Alpine image.
+	_ "time/tzdata"
+)
+
+// Digest frequencies.
+const (
+	Off    = "off"
+	Daily  = "daily"
+	Weekly = "weekly"
+)
+
+// DefaultSettings are the settings of users who have not chosen any: no
+// digest, or a daily one at 8:00 UTC once turned on.
+func DefaultSettings() types.DigestSettings {
+	return types.DigestSettings{Frequency: Off, TimeZone: "UTC", SendHour: 8, Weekday: int(time.Monday)}
+}
+
+// Period is how far back a digest of the frequency looks when no earlier
+// digest was sent.
+func Period(frequency string) time.Duration {
+	if frequency == Weekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// Since is the start of the digest built at now: the last digest sent, or
+// one period ago if that was longer ago or never.
+func Since(settings types.DigestSettings, now time.Time) time.Time {
+	since := now.Add(-Period(settings.Frequency)).UTC()
+	if settings.LastSentAt != nil && settings.LastSentAt.After(since) {
+		return settings.LastSentAt.UTC()
+	}
+	return since
+}
+
+// Local returns now in the settings' time zone, or in UTC if it is not
+// known.
+func Local(settings types.DigestSettings, now time.Time) time.Time {
+	location, err := time.LoadLocation(settings.TimeZone)
+	if err != nil {
+		location = time.UTC
+	}
+	return now.In(location)
+}
+
+// AddOpenTask adds an open assigned task to its goal's group, which is the
+// last one when tasks are added in goal order, and to NewlyAssigned when
+// newlyAssigned is set. Stores build digests with it.
+func AddOpenTask(d *types.Digest, task *types.DigestTask, newlyAssigned bool) {
+	if n := len(d.Goals); n == 0 || d.Goals[n-1].ID != task.GoalID {
+		d.Goals = append(d.Goals, &types.DigestGoal{ID: task.GoalID, Title: task.GoalTitle})
+	}
+	group := d.Goals[len(d.Goals)-1]
+	group.Tasks = append(group.Tasks, task)
+	if newlyAssigned {
+		d.NewlyAssigned = append(d.NewlyAssigned, task)
+	}
+}
+
+// Empty reports whether the digest has nothing to tell.
+func Empty(d *types.Digest) bool {
+	return len(d.Goals) == 0 && len(d.Completed) == 0
+}
+
+//go:embed templates
+var templateFiles embed.FS
+
+var (
+	htmlTemplate = htmltemplate.Must(htmltemplate.ParseFS(templateFiles, "templates/digest.html"))
+	textTemplate = texttemplate.Must(texttemplate.ParseFS(templateFiles, "templates/digest.txt"))
+)
+
+// Render renders the digest as an email with plain-text and HTML bodies.
+// The caller sets the recipient.
+func Render(d *types.Digest) (notification.Mail, error) {
+	var text, html bytes.Buffer
+	if err := textTemplate.Execute(&text, d); err != nil {
+		return notification.Mail{}, fmt.Errorf("render text digest: %w", err)
+	}
+	if err := htmlTemplate.Execute(&html, d); err != nil {
+		return notification.Mail{}, fmt.Errorf("render HTML digest: %w", err)
+	}
+	return notification.Mail{
+		Subject: fmt.Sprintf("Your %s digest for %s", d.Frequency, d.Date),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
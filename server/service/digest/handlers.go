@@ -0,0 +1,160 @@
+package digest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+type Handler struct {
+	store types.DigestStore
+	now   func() time.Time
+}
+
+func NewHandler(store types.DigestStore) *Handler {
+	return &Handler{store: store, now: time.Now}
+}
+
+// HandleGetSettings godoc
+// @Summary Get digest settings
+// @Description Get when the caller's digest is emailed. Users who have not chosen receive none; turning it on defaults to daily at 8:00 UTC. lastSentOn is the local date of the last digest sent.
+// @Tags digest
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.DigestSettings
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /digest/settings [get]
+func (h *Handler) HandleGetSettings(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	settings, err := h.store.GetDigestSettings(r.Context(), userID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, settings)
+}
+
+// HandleSetSettings godoc
+// @Summary Set digest settings
+// @Description Choose the caller's digest: off, daily or weekly on weekday (0 is Sunday), sent once sendHour (0 to 23) has struck in timeZone, an IANA time zone such as Europe/Berlin.
+// @Tags digest
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param payload body types.UpdateDigestSettingsPayload true "Settings"
+// @Success 200 {object} types.DigestSettings
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /digest/settings [put]
+func (h *Handler) HandleSetSettings(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	var payload types.UpdateDigestSettingsPayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteJSONError(w, err)
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+	// LoadLocation also accepts "Local", which depends on the host.
+	if _, err := time.LoadLocation(payload.TimeZone); err != nil || payload.TimeZone == "Local" {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("unknown time zone %q", payload.TimeZone))
+		return
+	}
+
+	settings, err := h.store.SetDigestSettings(r.Context(), userID, types.DigestSettings{
+		Frequency: payload.Frequency,
+		TimeZone:  payload.TimeZone,
+		SendHour:  payload.SendHour,
+		Weekday:   payload.Weekday,
+	})
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, settings)
+}
+
+// HandlePreview godoc
+// @Summary Preview digest
+// @Description Build the caller's digest as it would be sent now, without sending it: as JSON, or rendered as the HTML or plain-text email body. A digest that is off previews as daily.
+// @Tags digest
+// @Produce json
+// @Produce text/html
+// @Produce text/plain
+// @Security BearerAuth
+// @Param format query string false "json (default), html or text" Enums(json, html, text)
+// @Success 200 {object} types.Digest
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /digest/preview [get]
+func (h *Handler) HandlePreview(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	switch format {
+	case "", "json", "html", "text":
+	default:
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("unsupported format %q, use json, html or text", format))
+		return
+	}
+
+	settings, err := h.store.GetDigestSettings(r.Context(), userID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	if settings.Frequency == Off {
+		settings.Frequency = Daily
+	}
+	now := h.now()
+	d, err := h.store.BuildDigest(r.Context(), userID, Since(*settings, now), Local(*settings, now).Format(time.DateOnly))
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	d.Frequency = settings.Frequency
+
+	if format == "" || format == "json" {
+		utils.WriteJSON(w, http.StatusOK, d)
+		return
+	}
+	mail, err := Render(d)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	body, contentType := mail.HTML, "text/html; charset=utf-8"
+	if format == "text" {
+		body, contentType = mail.Text, "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(body))
+}
+
+// storeErrors gives the status for a digest of a user that does not exist.
+var storeErrors = map[error]int{
+	ErrNotFound: http.StatusNotFound,
+}
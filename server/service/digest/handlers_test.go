@@ -0,0 +1,128 @@
+package digest_test
+
+import (
+	"VyacheslavKuchumov/test-backend/handlertest"
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/digest"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestDigestSettings(t *testing.T) {
+	store, _, assignee := seed(t)
+	router := newRouter(store)
+
+	rr := handlertest.Serve(router, http.MethodGet, "/digest/settings", assignee, "")
+	var settings types.DigestSettings
+	if err := json.Unmarshal(rr.Body.Bytes(), &settings); err != nil || rr.Code != http.StatusOK || settings != digest.DefaultSettings() {
+		t.Fatalf("expected the default settings, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = handlertest.Serve(router, http.MethodPut, "/digest/settings", assignee, `{"frequency":"weekly","timeZone":"America/New_York","sendHour":7,"weekday":5}`)
+	settings = types.DigestSettings{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &settings); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected settings, got %d: %s", rr.Code, rr.Body.String())
+	}
+	expected := types.DigestSettings{Frequency: "weekly", TimeZone: "America/New_York", SendHour: 7, Weekday: 5}
+	if settings != expected {
+		t.Fatalf("expected %+v, got %+v", expected, settings)
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/digest/settings", assignee, "")
+	settings = types.DigestSettings{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &settings); err != nil || settings != expected {
+		t.Fatalf("expected the stored settings, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestDigestPreview(t *testing.T) {
+	store, owner, assignee := seed(t)
+	router := newRouter(store)
+
+	rr := handlertest.Serve(router, http.MethodGet, "/digest/preview", assignee, "")
+	var d types.Digest
+	if err := json.Unmarshal(rr.Body.Bytes(), &d); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected a digest, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if d.Name != "Assignee User" || d.Frequency != "daily" || len(d.Goals) != 1 || len(d.Goals[0].Tasks) != 2 || len(d.NewlyAssigned) != 2 {
+		t.Fatalf("expected both tasks newly assigned, got %+v", d)
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/digest/preview?format=html", assignee, "")
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "text/html; charset=utf-8" || !strings.Contains(rr.Body.String(), "<h3>Ship it</h3>") {
+		t.Fatalf("expected an HTML digest, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = handlertest.Serve(router, http.MethodGet, "/digest/preview?format=text", owner, "")
+	if rr.Code != http.StatusOK || rr.Header().Get("Content-Type") != "text/plain; charset=utf-8" || !strings.Contains(rr.Body.String(), "You have no open tasks.") {
+		t.Fatalf("expected a text digest, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestDigestErrors(t *testing.T) {
+	store, _, assignee := seed(t)
+	router := newRouter(store)
+
+	for _, tt := range []struct{ method, path, body string }{
+		{http.MethodGet, "/digest/preview?format=pdf", ""},
+		{http.MethodPut, "/digest/settings", `{"frequency":"hourly","timeZone":"UTC","sendHour":8}`},
+		{http.MethodPut, "/digest/settings", `{"frequency":"daily","timeZone":"Mars/Olympus","sendHour":8}`},
+		{http.MethodPut, "/digest/settings", `{"frequency":"daily","timeZone":"Local","sendHour":8}`},
+		{http.MethodPut, "/digest/settings", `{"frequency":"daily","timeZone":"UTC","sendHour":24}`},
+		{http.MethodPut, "/digest/settings", `{"frequency":"weekly","timeZone":"UTC","sendHour":8,"weekday":7}`},
+		{http.MethodPut, "/digest/settings", `{`},
+	} {
+		if rr := handlertest.Serve(router, tt.method, tt.path, assignee, tt.body); rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s %s %s, got %d: %s", tt.method, tt.path, tt.body, rr.Code, rr.Body.String())
+		}
+	}
+	for _, path := range []string{"/digest/settings", "/digest/preview"} {
+		if rr := handlertest.Serve(router, http.MethodGet, path, 0, ""); rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401 for %s without a user, got %d", path, rr.Code)
+		}
+	}
+}
+
+// seed creates a goal whose owner assigns two tasks to another user.
+func seed(t *testing.T) (*memstore.Store, int, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := memstore.New()
+	var ids []int
+	for _, u := range []types.User{
+		{FirstName: "Owner", LastName: "User", Email: "owner@example.com", Password: "hashed"},
+		{FirstName: "Assignee", LastName: "User", Email: "assignee@example.com", Password: "hashed"},
+	} {
+		if err := store.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		created, err := store.GetUserByEmail(ctx, u.Email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+	owner, assignee := ids[0], ids[1]
+
+	goal, err := store.CreateGoal(ctx, owner, types.CreateGoalPayload{Title: "Ship it", Priority: "high", Status: "todo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"First", "Second"} {
+		if _, err := store.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: title, Priority: "high", AssigneeID: &assignee}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, owner, assignee
+}
+
+func newRouter(store *memstore.Store) chi.Router {
+	router := chi.NewRouter()
+	digest.RegisterRoutes(router, digest.NewHandler(store))
+	return router
+}
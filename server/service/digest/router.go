@@ -0,0 +1,13 @@
+package digest
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Route("/digest", func(r chi.Router) {
+		r.Get("/settings", handler.HandleGetSettings)
+		r.Put("/settings", handler.HandleSetSettings)
+		r.Get("/preview", handler.HandlePreview)
+	})
+}
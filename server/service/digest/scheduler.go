@@ -0,0 +1,151 @@
+package digest
+
+import (
+	"VyacheslavKuchumov/test-backend/metrics"
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// batchSize bounds how many recipients are read per query.
+const batchSize = 100
+
+// Scheduler emails digests. A user's digest is due once the send hour has
+// struck on their local date, and on weekly digests their weekday, unless
+// it was already sent that date. A digest missed entirely, say while the
+// server was down all day, is not sent late: the next one covers its
+// period instead.
+//
+// Every server runs a scheduler. Each digest is claimed before it is sent,
+// so concurrent runs do not send it twice, and a digest whose sending fails
+// is released and retried on the next run while the other digests are
+// still sent. A claimed digest with nothing to tell is not sent.
+type Scheduler struct {
+	store    types.DigestOutbox
+	mailer   notification.Mailer
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewScheduler(store types.DigestOutbox, mailer notification.Mailer, interval time.Duration) *Scheduler {
+	return &Scheduler{store: store, mailer: mailer, interval: interval, now: time.Now}
+}
+
+// Run runs the scheduler immediately and then every interval until ctx is
+// cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		sent, err := s.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "digest scheduler failed", "error", err)
+		}
+		if sent > 0 {
+			slog.InfoContext(ctx, "sent digests", "count", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce sends every due digest. It returns how many it sent and the
+// errors of those it could not send, which are released for the next run.
+func (s *Scheduler) RunOnce(ctx context.Context) (int, error) {
+	now := s.now()
+	var sendErrs []error
+	sent, afterID := 0, 0
+	for {
+		recipients, err := s.store.DigestRecipients(ctx, afterID, batchSize)
+		if err != nil {
+			return sent, errors.Join(append(sendErrs, err)...)
+		}
+		for _, recipient := range recipients {
+			afterID = recipient.UserID
+			ok, err := s.send(ctx, recipient, now)
+			if err != nil {
+				sendErrs = append(sendErrs, fmt.Errorf("send digest to user %d: %w", recipient.UserID, err))
+				continue
+			}
+			if ok {
+				sent++
+				metrics.DigestsSent.Inc()
+			}
+		}
+		if len(recipients) < batchSize {
+			return sent, errors.Join(sendErrs...)
+		}
+	}
+}
+
+// send sends the recipient's digest if it is due at now, and reports
+// whether it did.
+func (s *Scheduler) send(ctx context.Context, recipient *types.DigestRecipient, now time.Time) (bool, error) {
+	today, due := Due(recipient.DigestSettings, now)
+	if !due {
+		return false, nil
+	}
+	claimed, err := s.store.ClaimDigest(ctx, recipient.UserID, today, now)
+	if err != nil || !claimed {
+		return false, err
+	}
+
+	mail, err := s.compose(ctx, recipient, now, today)
+	if err == nil && mail != nil {
+		err = s.mailer.Send(ctx, *mail)
+	}
+	if err != nil {
+		// The claim is released with a fresh context so that a cancelled
+		// run does not leave the digest claimed.
+		if releaseErr := s.store.ReleaseDigest(context.WithoutCancel(ctx), recipient.UserID, recipient.LastSentOn, recipient.LastSentAt); releaseErr != nil {
+			slog.ErrorContext(ctx, "failed to release digest", "user_id", recipient.UserID, "error", releaseErr)
+		}
+		return false, err
+	}
+	return mail != nil, nil
+}
+
+// compose builds and renders the recipient's digest, or returns nil when it
+// is empty.
+func (s *Scheduler) compose(ctx context.Context, recipient *types.DigestRecipient, now time.Time, today string) (*notification.Mail, error) {
+	d, err := s.store.BuildDigest(ctx, recipient.UserID, Since(recipient.DigestSettings, now), today)
+	if err != nil {
+		return nil, err
+	}
+	if Empty(d) {
+		return nil, nil
+	}
+	d.Frequency = recipient.Frequency
+	mail, err := Render(d)
+	if err != nil {
+		return nil, err
+	}
+	mail.To = recipient.Email
+	return &mail, nil
+}
+
+// Due reports whether a digest with the settings is due at now and, if so,
+// returns the local date it is for.
+func Due(settings types.DigestSettings, now time.Time) (string, bool) {
+	local := Local(settings, now)
+	today := local.Format(time.DateOnly)
+	switch {
+	case settings.Frequency != Daily && settings.Frequency != Weekly:
+		return "", false
+	case settings.Frequency == Weekly && int(local.Weekday()) != settings.Weekday:
+		return "", false
+	case local.Hour() < settings.SendHour:
+		return "", false
+	case settings.LastSentOn != nil && *settings.LastSentOn >= today:
+		return "", false
+	}
+	return today, true
+}
@@ -0,0 +1,204 @@
+package digest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeOutbox is a DigestOutbox over a slice of recipients, each with one
+// open task. The memstore package cannot be used here, since it depends on
+// this one.
+type fakeOutbox struct {
+	recipients []*types.DigestRecipient
+	idle       map[int]bool
+	since      map[int]time.Time
+}
+
+func (f *fakeOutbox) DigestRecipients(_ context.Context, afterID, limit int) ([]*types.DigestRecipient, error) {
+	var page []*types.DigestRecipient
+	for _, r := range f.recipients {
+		if r.UserID > afterID && len(page) < limit {
+			copied := *r
+			page = append(page, &copied)
+		}
+	}
+	return page, nil
+}
+
+func (f *fakeOutbox) BuildDigest(_ context.Context, userID int, since time.Time, today string) (*types.Digest, error) {
+	f.since[userID] = since
+	d := &types.Digest{Name: "Bob Builder", Date: today, Since: since}
+	if !f.idle[userID] {
+		AddOpenTask(d, &types.DigestTask{ID: 1, GoalID: 1, GoalTitle: "Launch", Title: "Write docs", Priority: "high"}, false)
+	}
+	return d, nil
+}
+
+func (f *fakeOutbox) ClaimDigest(_ context.Context, userID int, sentOn string, sentAt time.Time) (bool, error) {
+	r := f.recipient(userID)
+	if r.LastSentOn != nil && *r.LastSentOn >= sentOn {
+		return false, nil
+	}
+	r.LastSentOn, r.LastSentAt = &sentOn, &sentAt
+	return true, nil
+}
+
+func (f *fakeOutbox) ReleaseDigest(_ context.Context, userID int, lastSentOn *string, lastSentAt *time.Time) error {
+	r := f.recipient(userID)
+	r.LastSentOn, r.LastSentAt = lastSentOn, lastSentAt
+	return nil
+}
+
+func (f *fakeOutbox) recipient(userID int) *types.DigestRecipient {
+	for _, r := range f.recipients {
+		if r.UserID == userID {
+			return r
+		}
+	}
+	return nil
+}
+
+// fakeMailer records the mail it sends and fails for the addresses in
+// failing.
+type fakeMailer struct {
+	sent    []notification.Mail
+	failing map[string]bool
+}
+
+func (f *fakeMailer) Send(_ context.Context, mail notification.Mail) error {
+	if f.failing[mail.To] {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, mail)
+	return nil
+}
+
+func newRecipient(userID int, email string, settings types.DigestSettings) *types.DigestRecipient {
+	return &types.DigestRecipient{UserID: userID, Email: email, Name: "Bob Builder", DigestSettings: settings}
+}
+
+// clock is a fake clock for the scheduler.
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func TestRunOnceSendsAtTheLocalHour(t *testing.T) {
+	store := &fakeOutbox{since: map[int]time.Time{}, recipients: []*types.DigestRecipient{
+		newRecipient(1, "berlin@example.com", types.DigestSettings{Frequency: Daily, TimeZone: "Europe/Berlin", SendHour: 8}),
+		newRecipient(2, "newyork@example.com", types.DigestSettings{Frequency: Daily, TimeZone: "America/New_York", SendHour: 8}),
+	}}
+	mailer := &fakeMailer{}
+	c := &clock{now: time.Date(2026, 3, 27, 6, 30, 0, 0, time.UTC)}
+	scheduler := NewScheduler(store, mailer, time.Minute)
+	scheduler.now = c.Now
+
+	// 7:30 in Berlin and 2:30 in New York: neither is due.
+	if sent, err := scheduler.RunOnce(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("expected nothing to send, got %d, %v", sent, err)
+	}
+
+	c.now = c.now.Add(time.Hour)
+	if sent, err := scheduler.RunOnce(context.Background()); err != nil || sent != 1 {
+		t.Fatalf("expected the Berlin digest, got %d, %v", sent, err)
+	}
+	mail := mailer.sent[0]
+	if mail.To != "berlin@example.com" || mail.Subject != "Your daily digest for 2026-03-27" ||
+		!strings.Contains(mail.Text, "Write docs") || !strings.Contains(mail.HTML, "<li>Write docs (high)</li>") {
+		t.Fatalf("unexpected mail %+v", mail)
+	}
+	// Without an earlier digest, a daily one covers the last day.
+	if since := store.since[1]; !since.Equal(c.now.Add(-24 * time.Hour)) {
+		t.Fatalf("expected the digest to cover one day, got %v", since)
+	}
+
+	// The Berlin digest is sent once a day; New York's at 8:00 there.
+	c.now = time.Date(2026, 3, 27, 12, 0, 0, 0, time.UTC)
+	if sent, err := scheduler.RunOnce(context.Background()); err != nil || sent != 1 || mailer.sent[1].To != "newyork@example.com" {
+		t.Fatalf("expected only the New York digest, got %d, %v", sent, err)
+	}
+
+	// The next day's digest covers the time since the last one.
+	lastSent := time.Date(2026, 3, 27, 7, 30, 0, 0, time.UTC)
+	c.now = time.Date(2026, 3, 28, 7, 0, 0, 0, time.UTC)
+	if sent, err := scheduler.RunOnce(context.Background()); err != nil || sent != 1 {
+		t.Fatalf("expected the next Berlin digest, got %d, %v", sent, err)
+	}
+	if since := store.since[1]; !since.Equal(lastSent) {
+		t.Fatalf("expected the digest to cover the time since %v, got %v", lastSent, since)
+	}
+}
+
+func TestRunOnceSendsWeeklyOnTheWeekday(t *testing.T) {
+	store := &fakeOutbox{since: map[int]time.Time{}, recipients: []*types.DigestRecipient{
+		newRecipient(1, "bob@example.com", types.DigestSettings{Frequency: Weekly, TimeZone: "Asia/Tokyo", SendHour: 9, Weekday: int(time.Monday)}),
+	}}
+	mailer := &fakeMailer{}
+	c := &clock{now: time.Date(2026, 3, 29, 23, 0, 0, 0, time.UTC)}
+	scheduler := NewScheduler(store, mailer, time.Minute)
+	scheduler.now = c.Now
+
+	// Monday 8:00 in Tokyo, then 9:00, then Tuesday.
+	for _, expected := range []int{0, 1, 0} {
+		if sent, err := scheduler.RunOnce(context.Background()); err != nil || sent != expected {
+			t.Fatalf("at %v expected %d digests, got %d, %v", c.now, expected, sent, err)
+		}
+		c.now = c.now.Add(time.Hour)
+		if expected == 1 {
+			c.now = c.now.Add(24 * time.Hour)
+		}
+	}
+	if mailer.sent[0].Subject != "Your weekly digest for 2026-03-30" {
+		t.Fatalf("unexpected subject %q", mailer.sent[0].Subject)
+	}
+	if since := store.since[1]; !since.Equal(time.Date(2026, 3, 23, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the digest to cover one week, got %v", since)
+	}
+}
+
+func TestRunOnceSkipsEmptyAndReleasesFailedDigests(t *testing.T) {
+	daily := types.DigestSettings{Frequency: Daily, TimeZone: "UTC", SendHour: 8}
+	store := &fakeOutbox{since: map[int]time.Time{}, idle: map[int]bool{1: true}, recipients: []*types.DigestRecipient{
+		newRecipient(1, "idle@example.com", daily),
+		newRecipient(2, "gone@example.com", daily),
+		newRecipient(3, "cat@example.com", daily),
+	}}
+	mailer := &fakeMailer{failing: map[string]bool{"gone@example.com": true}}
+	scheduler := NewScheduler(store, mailer, time.Minute)
+	scheduler.now = func() time.Time { return time.Date(2026, 3, 27, 9, 0, 0, 0, time.UTC) }
+
+	sent, err := scheduler.RunOnce(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "user 2") || sent != 1 || len(mailer.sent) != 1 || mailer.sent[0].To != "cat@example.com" {
+		t.Fatalf("expected the digest after the failed one to be sent, got %d, %v", sent, err)
+	}
+	// The empty digest counts as sent for the day; the failed one does not.
+	if store.recipients[0].LastSentOn == nil || store.recipients[1].LastSentOn != nil || store.recipients[2].LastSentOn == nil {
+		t.Fatalf("unexpected claims %+v", store.recipients)
+	}
+
+	delete(mailer.failing, "gone@example.com")
+	if sent, err := scheduler.RunOnce(context.Background()); err != nil || sent != 1 || mailer.sent[1].To != "gone@example.com" {
+		t.Fatalf("expected the failed digest to be retried, got %d, %v", sent, err)
+	}
+}
+
+func TestRenderEscapesHTML(t *testing.T) {
+	d := &types.Digest{Frequency: Daily, Date: "2026-03-27", Completed: []*types.DigestCompletion{
+		{TaskID: 1, Title: "<b>Ship</b>", GoalTitle: "Launch", AssigneeName: "Bob Builder"},
+	}}
+	mail, err := Render(d)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(mail.HTML, "&lt;b&gt;Ship&lt;/b&gt; in Launch by Bob Builder") || strings.Contains(mail.HTML, "<b>Ship") {
+		t.Fatalf("expected the title to be escaped, got %s", mail.HTML)
+	}
+	expected := "Hello,\n\nHere is your daily digest for 2026-03-27.\n\nYour open tasks\nYou have no open tasks.\n\nCompleted on your goals\n  - <b>Ship</b> in Launch by Bob Builder\n\nYou can change when you receive this digest in your digest settings.\n"
+	if mail.Text != expected {
+		t.Fatalf("unexpected text %q", mail.Text)
+	}
+}
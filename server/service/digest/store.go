@@ -0,0 +1,263 @@
+package digest
+
+import (
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when the user of a digest does not exist.
+var ErrNotFound = errors.New("not found")
+
+type Store struct {
+	db     *sql.DB
+	sqlite bool
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// NewSQLiteStore returns a store for a database opened with
+// db.NewSQLiteStorage.
+func NewSQLiteStore(db *sql.DB) *Store {
+	return &Store{db: db, sqlite: true}
+}
+
+// settingsColumns are read by scanSettings, from digest_settings d.
+const settingsColumns = `d.frequency, d.time_zone, d.send_hour, d.weekday, CAST(d.last_sent_on AS TEXT), d.last_sent_at`
+
+func (s *Store) GetDigestSettings(ctx context.Context, userID int) (*types.DigestSettings, error) {
+	ctx = tracing.WithStatementName(ctx, "digest.GetDigestSettings")
+	rows, err := s.db.QueryContext(ctx, `SELECT `+settingsColumns+` FROM digest_settings d WHERE d.user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+		settings := DefaultSettings()
+		return &settings, nil
+	}
+	return scanSettings(rows)
+}
+
+// SetDigestSettings keeps the record of the last digest sent, so changing
+// the settings does not send a second digest the same day.
+func (s *Store) SetDigestSettings(ctx context.Context, userID int, settings types.DigestSettings) (*types.DigestSettings, error) {
+	ctx = tracing.WithStatementName(ctx, "digest.SetDigestSettings")
+	if _, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO digest_settings (user_id, frequency, time_zone, send_hour, weekday)
+		 VALUES ($1, $2, $3, $4, $5)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET frequency = excluded.frequency,
+		     time_zone = excluded.time_zone,
+		     send_hour = excluded.send_hour,
+		     weekday = excluded.weekday`,
+		userID,
+		settings.Frequency,
+		settings.TimeZone,
+		settings.SendHour,
+		settings.Weekday,
+	); err != nil {
+		return nil, err
+	}
+	return s.GetDigestSettings(ctx, userID)
+}
+
+func (s *Store) DigestRecipients(ctx context.Context, afterID, limit int) ([]*types.DigestRecipient, error) {
+	ctx = tracing.WithStatementName(ctx, "digest.DigestRecipients")
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT u.id, u.email, TRIM(CONCAT(u.first_name, ' ', u.last_name)), `+settingsColumns+`
+		 FROM digest_settings d
+		 JOIN users u ON u.id = d.user_id
+		 WHERE d.frequency <> 'off'
+		   AND d.user_id > $1
+		 ORDER BY d.user_id
+		 LIMIT $2`,
+		afterID,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	recipients := []*types.DigestRecipient{}
+	for rows.Next() {
+		recipient := new(types.DigestRecipient)
+		settings, err := scanSettings(rows, &recipient.UserID, &recipient.Email, &recipient.Name)
+		if err != nil {
+			return nil, err
+		}
+		recipient.DigestSettings = *settings
+		recipients = append(recipients, recipient)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return recipients, nil
+}
+
+func (s *Store) BuildDigest(ctx context.Context, userID int, since time.Time, today string) (*types.Digest, error) {
+	ctx = tracing.WithStatementName(ctx, "digest.BuildDigest")
+	d := &types.Digest{
+		Date:          today,
+		Since:         since.UTC(),
+		Goals:         []*types.DigestGoal{},
+		NewlyAssigned: []*types.DigestTask{},
+		Completed:     []*types.DigestCompletion{},
+	}
+	err := s.db.QueryRowContext(ctx, `SELECT TRIM(CONCAT(first_name, ' ', last_name)) FROM users WHERE id = $1`, userID).Scan(&d.Name)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.openTasks(ctx, d, userID); err != nil {
+		return nil, err
+	}
+	if err := s.completions(ctx, d, userID); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// openTasks groups the user's open assigned tasks by goal, soonest due
+// first, and lists those assigned since d.Since.
+func (s *Store) openTasks(ctx context.Context, d *types.Digest, userID int) error {
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT t.id, t.goal_id, g.title, t.title, t.priority, CAST(t.due_date AS TEXT), t.assigned_at
+		 FROM tasks t
+		 JOIN goals g ON g.id = t.goal_id
+		 WHERE t.assignee_id = $1 AND t.is_completed = FALSE
+		 ORDER BY g.title, g.id, t.due_date IS NULL, t.due_date, t.id`,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			task       types.DigestTask
+			dueDate    sql.NullString
+			assignedAt sql.NullTime
+		)
+		if err := rows.Scan(&task.ID, &task.GoalID, &task.GoalTitle, &task.Title, &task.Priority, &dueDate, &assignedAt); err != nil {
+			return err
+		}
+		if dueDate.Valid {
+			task.DueDate = &dueDate.String
+			task.Overdue = dueDate.String < d.Date
+		}
+		AddOpenTask(d, &task, assignedAt.Valid && !assignedAt.Time.Before(d.Since))
+	}
+	return rows.Err()
+}
+
+// completions lists the tasks completed since d.Since on the user's goals.
+func (s *Store) completions(ctx context.Context, d *types.Digest, userID int) error {
+	completedSince := `t.completed_at >= $2`
+	if s.sqlite {
+		completedSince = `julianday(t.completed_at) >= julianday($2)`
+	}
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT t.id, t.title, g.id, g.title, TRIM(CONCAT(a.first_name, ' ', a.last_name)), t.completed_at
+		 FROM tasks t
+		 JOIN goals g ON g.id = t.goal_id
+		 LEFT JOIN users a ON a.id = t.assignee_id
+		 WHERE g.owner_id = $1
+		   AND t.is_completed = TRUE
+		   AND `+completedSince+`
+		 ORDER BY t.completed_at, t.id`,
+		userID,
+		d.Since,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		completion := new(types.DigestCompletion)
+		if err := rows.Scan(&completion.TaskID, &completion.Title, &completion.GoalID, &completion.GoalTitle, &completion.AssigneeName, &completion.CompletedAt); err != nil {
+			return err
+		}
+		d.Completed = append(d.Completed, completion)
+	}
+	return rows.Err()
+}
+
+func (s *Store) ClaimDigest(ctx context.Context, userID int, sentOn string, sentAt time.Time) (bool, error) {
+	ctx = tracing.WithStatementName(ctx, "digest.ClaimDigest")
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE digest_settings
+		 SET last_sent_on = $2, last_sent_at = $3
+		 WHERE user_id = $1
+		   AND frequency <> 'off'
+		   AND (last_sent_on IS NULL OR last_sent_on < $2)`,
+		userID,
+		sentOn,
+		sentAt.UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (s *Store) ReleaseDigest(ctx context.Context, userID int, lastSentOn *string, lastSentAt *time.Time) error {
+	ctx = tracing.WithStatementName(ctx, "digest.ReleaseDigest")
+	var prevSentAt *time.Time
+	if lastSentAt != nil {
+		utc := lastSentAt.UTC()
+		prevSentAt = &utc
+	}
+	_, err := s.db.ExecContext(
+		ctx,
+		`UPDATE digest_settings SET last_sent_on = $2, last_sent_at = $3 WHERE user_id = $1`,
+		userID,
+		lastSentOn,
+		prevSentAt,
+	)
+	return err
+}
+
+// scanSettings scans extra followed by settingsColumns.
+func scanSettings(rows *sql.Rows, extra ...any) (*types.DigestSettings, error) {
+	var (
+		settings   types.DigestSettings
+		lastSentOn sql.NullString
+		lastSentAt sql.NullTime
+	)
+	dest := append(extra, &settings.Frequency, &settings.TimeZone, &settings.SendHour, &settings.Weekday, &lastSentOn, &lastSentAt)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if lastSentOn.Valid {
+		settings.LastSentOn = &lastSentOn.String
+	}
+	if lastSentAt.Valid {
+		settings.LastSentAt = &lastSentAt.Time
+	}
+	return &settings, nil
+}
@@ -0,0 +1,442 @@
+package export
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SnapshotVersion is the version of the admin export document format.
+const SnapshotVersion = 1
+
+// maxImportBytes bounds the admin import body, which is decoded in full so
+// it can be applied in one transaction.
+const maxImportBytes = 256 << 20
+
+type Handler struct {
+	store types.ExportStore
+	// writeTimeout bounds each write of a download rather than the whole
+	// response; zero means no limit.
+	writeTimeout time.Duration
+}
+
+func NewHandler(store types.ExportStore, writeTimeout time.Duration) *Handler {
+	return &Handler{store: store, writeTimeout: writeTimeout}
+}
+
+// HandleExport godoc
+// @Summary Export goals and tasks
+// @Description Stream every goal the caller can see with its tasks as JSON (`{"exportedAt","goals":[...]}`) or CSV (one row per task; goals without tasks get one row with empty task columns)
+// @Tags export
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param format query string false "json (default) or csv" Enums(json, csv)
+// @Success 200 {object} types.GoalsExport
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /export [get]
+func (h *Handler) HandleExport(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	var enc goalEncoder
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		enc = &jsonGoalEncoder{}
+	case "csv":
+		enc = &csvGoalEncoder{}
+	default:
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("unsupported format %q, use json or csv", format))
+		return
+	}
+
+	stream := h.newStream(w, r, enc.contentType(), "goals."+enc.extension())
+	err := h.store.StreamGoals(r.Context(), userID, func(goal *types.GoalWithTasks) error {
+		if err := stream.start(enc.begin); err != nil {
+			return err
+		}
+		return enc.goal(stream.buf, goal)
+	})
+	if err == nil {
+		err = stream.start(enc.begin)
+	}
+	if err == nil {
+		err = enc.end(stream.buf)
+	}
+	stream.finish(err)
+}
+
+// HandleAdminExport godoc
+// @Summary Export the whole instance
+// @Description Stream every user (with password hash), goal and task as a JSON document accepted by POST /admin/import. Requires the caller's email in ADMIN_EMAILS.
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.InstanceSnapshot
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /admin/export [get]
+func (h *Handler) HandleAdminExport(w http.ResponseWriter, r *http.Request) {
+	stream := h.newStream(w, r, "application/json", "instance.json")
+	begin := func(buf *bufio.Writer) error {
+		_, err := fmt.Fprintf(buf, `{"version":%d,"exportedAt":%q,"users":[`, SnapshotVersion, time.Now().UTC().Format(time.RFC3339))
+		return err
+	}
+
+	var users, goals int
+	err := h.store.ExportInstance(
+		r.Context(),
+		func(u *types.InstanceUser) error {
+			if err := stream.start(begin); err != nil {
+				return err
+			}
+			users++
+			return writeJSONElement(stream.buf, users, u)
+		},
+		func(g *types.InstanceGoal) error {
+			if err := stream.start(begin); err != nil {
+				return err
+			}
+			if goals == 0 {
+				if _, err := stream.buf.WriteString(`],"goals":[`); err != nil {
+					return err
+				}
+			}
+			goals++
+			return writeJSONElement(stream.buf, goals, g)
+		},
+	)
+	if err == nil {
+		err = stream.start(begin)
+	}
+	if err == nil && goals == 0 {
+		_, err = stream.buf.WriteString(`],"goals":[`)
+	}
+	if err == nil {
+		_, err = stream.buf.WriteString("]}\n")
+	}
+	stream.finish(err)
+}
+
+// HandleAdminImport godoc
+// @Summary Import an instance export
+// @Description Add the users, goals and tasks from a GET /admin/export document in one transaction. Users are matched by email and reused; goals and tasks are always added with new IDs. Requires the caller's email in ADMIN_EMAILS.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param payload body types.InstanceSnapshot true "Instance export"
+// @Success 200 {object} types.ImportSummary
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Failure 413 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /admin/import [post]
+func (h *Handler) HandleAdminImport(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+
+	var snapshot types.InstanceSnapshot
+	if err := utils.ParseJSON(r, &snapshot); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("import is larger than %d bytes", maxImportBytes))
+			return
+		}
+		utils.WriteJSONError(w, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(snapshot); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+	if err := checkReferences(snapshot); err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	summary, err := h.store.ImportInstance(r.Context(), snapshot)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, summary)
+}
+
+// checkReferences verifies that IDs are unique and that every owner,
+// assignee and creator refers to a user in the snapshot.
+func checkReferences(snapshot types.InstanceSnapshot) error {
+	users := make(map[int]bool, len(snapshot.Users))
+	emails := make(map[string]bool, len(snapshot.Users))
+	for _, u := range snapshot.Users {
+		if users[u.ID] {
+			return fmt.Errorf("duplicate user id %d", u.ID)
+		}
+		if emails[u.Email] {
+			return fmt.Errorf("duplicate user email %s", u.Email)
+		}
+		users[u.ID] = true
+		emails[u.Email] = true
+	}
+
+	goals := make(map[int]bool, len(snapshot.Goals))
+	tasks := make(map[int]bool)
+	for _, g := range snapshot.Goals {
+		if goals[g.ID] {
+			return fmt.Errorf("duplicate goal id %d", g.ID)
+		}
+		goals[g.ID] = true
+		if !users[g.OwnerID] {
+			return fmt.Errorf("goal %d: owner %d is not in users", g.ID, g.OwnerID)
+		}
+		for _, t := range g.Tasks {
+			if tasks[t.ID] {
+				return fmt.Errorf("duplicate task id %d", t.ID)
+			}
+			tasks[t.ID] = true
+			if !users[t.CreatedBy] {
+				return fmt.Errorf("task %d: creator %d is not in users", t.ID, t.CreatedBy)
+			}
+			if t.AssigneeID != nil && !users[*t.AssigneeID] {
+				return fmt.Errorf("task %d: assignee %d is not in users", t.ID, *t.AssigneeID)
+			}
+			for _, e := range t.TimeEntries {
+				if !users[e.UserID] {
+					return fmt.Errorf("task %d: time entry user %d is not in users", t.ID, e.UserID)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// stream writes a download response. Headers are sent with the first
+// record, so a store error before then is still reported as a problem
+// response; later errors abort the connection so clients never mistake a
+// truncated file for a complete one.
+//
+// Exports outlive the server's write timeout on large instances, so the
+// write deadline is pushed back by writeTimeout before every write
+// instead. A client that stops reading fails the export within
+// writeTimeout, which ends the store's query.
+type stream struct {
+	w            http.ResponseWriter
+	r            *http.Request
+	rc           *http.ResponseController
+	writeTimeout time.Duration
+	contentType  string
+	filename     string
+	buf          *bufio.Writer
+	started      bool
+}
+
+func (h *Handler) newStream(w http.ResponseWriter, r *http.Request, contentType, filename string) *stream {
+	return &stream{
+		w:            w,
+		r:            r,
+		rc:           http.NewResponseController(w),
+		writeTimeout: h.writeTimeout,
+		contentType:  contentType,
+		filename:     filename,
+	}
+}
+
+// Write writes p to the client within writeTimeout.
+func (s *stream) Write(p []byte) (int, error) {
+	if err := s.extendDeadline(); err != nil {
+		return 0, err
+	}
+	return s.w.Write(p)
+}
+
+func (s *stream) extendDeadline() error {
+	var deadline time.Time
+	if s.writeTimeout > 0 {
+		deadline = time.Now().Add(s.writeTimeout)
+	}
+	if err := s.rc.SetWriteDeadline(deadline); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	return nil
+}
+
+func (s *stream) start(begin func(*bufio.Writer) error) error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	date := time.Now().UTC().Format("2006-01-02")
+	s.w.Header().Set("Content-Type", s.contentType)
+	s.w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="task-tracker-%s-%s"`, date, s.filename))
+	s.w.WriteHeader(http.StatusOK)
+	s.buf = bufio.NewWriter(s)
+	return begin(s.buf)
+}
+
+func (s *stream) finish(err error) {
+	if err == nil {
+		err = s.buf.Flush()
+	}
+	if err == nil {
+		return
+	}
+	if !s.started {
+		if deadlineErr := s.extendDeadline(); deadlineErr != nil {
+			slog.WarnContext(s.r.Context(), "failed to extend write deadline", "error", deadlineErr)
+		}
+		utils.WriteError(s.w, http.StatusInternalServerError, err)
+		return
+	}
+	slog.ErrorContext(s.r.Context(), "export aborted", "error", err)
+	panic(http.ErrAbortHandler)
+}
+
+func writeJSONElement(buf *bufio.Writer, n int, v any) error {
+	if n > 1 {
+		if err := buf.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = buf.Write(data)
+	return err
+}
+
+type goalEncoder interface {
+	contentType() string
+	extension() string
+	begin(buf *bufio.Writer) error
+	goal(buf *bufio.Writer, goal *types.GoalWithTasks) error
+	end(buf *bufio.Writer) error
+}
+
+type jsonGoalEncoder struct {
+	goals int
+}
+
+func (e *jsonGoalEncoder) contentType() string { return "application/json" }
+func (e *jsonGoalEncoder) extension() string   { return "json" }
+
+func (e *jsonGoalEncoder) begin(buf *bufio.Writer) error {
+	_, err := fmt.Fprintf(buf, `{"exportedAt":%q,"goals":[`, time.Now().UTC().Format(time.RFC3339))
+	return err
+}
+
+func (e *jsonGoalEncoder) goal(buf *bufio.Writer, goal *types.GoalWithTasks) error {
+	e.goals++
+	return writeJSONElement(buf, e.goals, goal)
+}
+
+func (e *jsonGoalEncoder) end(buf *bufio.Writer) error {
+	_, err := buf.WriteString("]}\n")
+	return err
+}
+
+var csvHeader = []string{
+	"goal_id", "goal_title", "goal_description", "goal_priority", "goal_status", "goal_due_date",
+	"goal_owner_id", "goal_owner_name", "goal_created_at",
+	"task_id", "task_title", "task_description", "task_priority", "task_is_completed", "task_due_date", "task_recurrence",
+	"task_estimate_minutes", "task_assignee_id", "task_assignee_name", "task_created_by", "task_created_by_name", "task_created_at",
+}
+
+type csvGoalEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvGoalEncoder) contentType() string { return "text/csv; charset=utf-8" }
+func (e *csvGoalEncoder) extension() string   { return "csv" }
+
+func (e *csvGoalEncoder) begin(buf *bufio.Writer) error {
+	e.w = csv.NewWriter(buf)
+	return e.w.Write(csvHeader)
+}
+
+func (e *csvGoalEncoder) goal(_ *bufio.Writer, goal *types.GoalWithTasks) error {
+	goalColumns := []string{
+		strconv.Itoa(goal.ID),
+		cell(goal.Title),
+		cell(goal.Description),
+		goal.Priority,
+		goal.Status,
+		dateCell(goal.DueDate),
+		strconv.Itoa(goal.OwnerID),
+		cell(goal.OwnerName),
+		goal.CreatedAt.UTC().Format(time.RFC3339),
+	}
+	if len(goal.Tasks) == 0 {
+		return e.w.Write(append(goalColumns, make([]string, len(csvHeader)-len(goalColumns))...))
+	}
+
+	for _, task := range goal.Tasks {
+		assigneeID := ""
+		if task.AssigneeID != nil {
+			assigneeID = strconv.Itoa(*task.AssigneeID)
+		}
+		estimate := ""
+		if task.EstimateMinutes != nil {
+			estimate = strconv.Itoa(*task.EstimateMinutes)
+		}
+		record := append(goalColumns[:len(goalColumns):len(goalColumns)],
+			strconv.Itoa(task.ID),
+			cell(task.Title),
+			cell(task.Description),
+			task.Priority,
+			strconv.FormatBool(task.IsCompleted),
+			dateCell(task.DueDate),
+			task.Recurrence,
+			estimate,
+			assigneeID,
+			cell(task.AssigneeName),
+			strconv.Itoa(task.CreatedBy),
+			cell(task.CreatedByName),
+			task.CreatedAt.UTC().Format(time.RFC3339),
+		)
+		if err := e.w.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *csvGoalEncoder) end(*bufio.Writer) error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func dateCell(date *string) string {
+	if date == nil {
+		return ""
+	}
+	return *date
+}
+
+// cell neutralizes user text that spreadsheet applications would evaluate
+// as a formula when the CSV is opened.
+func cell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
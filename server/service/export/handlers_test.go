@@ -0,0 +1,269 @@
+package export_test
+
+import (
+	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/handlertest"
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/service/export"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestExportJSON(t *testing.T) {
+	store, owner, _ := seed(t)
+
+	rr := serve(t, store, http.MethodGet, "/export", "", owner)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if !strings.HasPrefix(rr.Header().Get("Content-Disposition"), "attachment;") {
+		t.Fatalf("expected an attachment, got %q", rr.Header().Get("Content-Disposition"))
+	}
+
+	var body types.GoalsExport
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rr.Body.String())
+	}
+	if len(body.Goals) != 2 || len(body.Goals[0].Tasks) != 1 || len(body.Goals[1].Tasks) != 0 {
+		t.Fatalf("unexpected export %+v", body)
+	}
+	if body.Goals[0].Tasks[0].AssigneeName != "Other User" {
+		t.Fatalf("expected the assignee name, got %+v", body.Goals[0].Tasks[0])
+	}
+}
+
+func TestExportJSONWithoutGoals(t *testing.T) {
+	store := memstore.New()
+	owner := createUser(t, store, "Solo", "owner@example.com")
+
+	rr := serve(t, store, http.MethodGet, "/export?format=json", "", owner)
+	var body types.GoalsExport
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || len(body.Goals) != 0 {
+		t.Fatalf("expected an empty goal list, got %v: %s", err, rr.Body.String())
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	store, owner, _ := seed(t)
+
+	rr := serve(t, store, http.MethodGet, "/export?format=csv", "", owner)
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected CSV, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected a header and two rows, got %q", records)
+	}
+	header, task, empty := records[0], records[1], records[2]
+	if header[0] != "goal_id" || header[5] != "goal_due_date" || header[9] != "task_id" || header[15] != "task_recurrence" || header[16] != "task_estimate_minutes" {
+		t.Fatalf("unexpected header %q", header)
+	}
+	if task[1] != "'=SUM(A1)" {
+		t.Fatalf("expected the formula-like title to be escaped, got %q", task[1])
+	}
+	if task[5] != "2026-03-31" || task[10] != "Write docs" || task[13] != "true" || task[14] != "" || task[15] != "" || task[16] != "45" || task[18] != "Other User" {
+		t.Fatalf("unexpected task row %q", task)
+	}
+	if empty[1] != "Someday" || empty[5] != "" || empty[9] != "" || len(empty) != len(header) {
+		t.Fatalf("expected an empty task part for a goal without tasks, got %q", empty)
+	}
+}
+
+func TestExportRejectsUnknownFormat(t *testing.T) {
+	store, owner, _ := seed(t)
+
+	rr := serve(t, store, http.MethodGet, "/export?format=xml", "", owner)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rr.Code)
+	}
+}
+
+func TestAdminRoutesRequireAdmin(t *testing.T) {
+	store, owner, other := seed(t)
+
+	if rr := serve(t, store, http.MethodGet, "/admin/export", "", other); rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for export, got %d", rr.Code)
+	}
+	if rr := serve(t, store, http.MethodPost, "/admin/import", `{"version":1}`, other); rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for import, got %d", rr.Code)
+	}
+	if rr := serve(t, store, http.MethodGet, "/admin/export", "", owner); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 for an admin, got %d", rr.Code)
+	}
+}
+
+func TestAdminExportRoundTrip(t *testing.T) {
+	source, owner, _ := seed(t)
+
+	exported := serve(t, source, http.MethodGet, "/admin/export", "", owner)
+	var snapshot types.InstanceSnapshot
+	if err := json.Unmarshal(exported.Body.Bytes(), &snapshot); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, exported.Body.String())
+	}
+	if snapshot.Version != export.SnapshotVersion || len(snapshot.Users) != 2 || len(snapshot.Goals) != 2 {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	target := memstore.New()
+	admin := createUser(t, target, "Admin", "admin@example.com")
+	rr := serve(t, target, http.MethodPost, "/admin/import", exported.Body.String(), admin)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var summary types.ImportSummary
+	if err := json.Unmarshal(rr.Body.Bytes(), &summary); err != nil {
+		t.Fatal(err)
+	}
+	if summary != (types.ImportSummary{UsersCreated: 2, Goals: 2, Tasks: 1}) {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+
+	reexported := serve(t, target, http.MethodGet, "/admin/export", "", admin)
+	var copied types.InstanceSnapshot
+	if err := json.Unmarshal(reexported.Body.Bytes(), &copied); err != nil {
+		t.Fatal(err)
+	}
+	if len(copied.Users) != 3 || len(copied.Goals) != 2 || copied.Goals[0].Tasks[0].Title != "Write docs" {
+		t.Fatalf("unexpected data after import %+v", copied)
+	}
+}
+
+func TestAdminImportValidatesSnapshot(t *testing.T) {
+	store, owner, _ := seed(t)
+
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"wrong version", `{"version":2}`},
+		{"invalid priority", `{"version":1,"users":[{"id":1,"firstName":"A","lastName":"B","email":"a@example.com","passwordHash":"x"}],"goals":[{"id":1,"title":"Goal","priority":"urgent","status":"todo","ownerId":1}]}`},
+		{"unknown owner", `{"version":1,"users":[{"id":1,"firstName":"A","lastName":"B","email":"a@example.com","passwordHash":"x"}],"goals":[{"id":1,"title":"Goal","priority":"low","status":"todo","ownerId":2}]}`},
+		{"duplicate email", `{"version":1,"users":[{"id":1,"firstName":"A","lastName":"B","email":"a@example.com","passwordHash":"x"},{"id":2,"firstName":"C","lastName":"D","email":"a@example.com","passwordHash":"x"}]}`},
+		{"malformed JSON", `{"version":`},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := serve(t, store, http.MethodPost, "/admin/import", tc.body, owner)
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+// seed creates two users and two goals; the first goal has a due date, one
+// completed task assigned to the second user and a title that looks like a
+// formula.
+// endlessStore streams goals until the handler fails to write one.
+type endlessStore struct {
+	types.ExportStore
+	done chan error
+}
+
+func (s *endlessStore) StreamGoals(ctx context.Context, _ int, fn func(*types.GoalWithTasks) error) error {
+	goal := &types.GoalWithTasks{Goal: types.Goal{Title: strings.Repeat("x", 4096)}}
+	for {
+		if err := fn(goal); err != nil {
+			s.done <- err
+			return err
+		}
+	}
+}
+
+func TestExportDropsStalledClient(t *testing.T) {
+	store := &endlessStore{done: make(chan error, 1)}
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), auth.UserKey, 1)))
+		})
+	})
+	export.RegisterRoutes(router, export.NewHandler(store, 100*time.Millisecond), auth.RequireAdmin(memstore.New(), admins.IsAdmin))
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// The client sends its request and never reads the response.
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("GET /export HTTP/1.1\r\nHost: example.com\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-store.done:
+		if err == nil {
+			t.Fatal("expected the write to fail")
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected the export to stop once the client stalled")
+	}
+}
+
+func seed(t *testing.T) (*memstore.Store, int, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := memstore.New()
+	owner := createUser(t, store, "Owner", "owner@example.com")
+	other := createUser(t, store, "Other", "other@example.com")
+
+	dueDate := "2026-03-31"
+	estimate := 45
+	goal, err := store.CreateGoal(ctx, owner, types.CreateGoalPayload{Title: "=SUM(A1)", Priority: "high", Status: "todo", DueDate: &dueDate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := store.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: "Write docs", Priority: "low", AssigneeID: &other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.UpdateTask(ctx, task.ID, owner, types.UpdateTaskPayload{
+		GoalID: goal.ID, Title: task.Title, Priority: task.Priority, IsCompleted: true, AssigneeID: &other, EstimateMinutes: &estimate,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateGoal(ctx, other, types.CreateGoalPayload{Title: "Someday", Priority: "low", Status: "todo"}); err != nil {
+		t.Fatal(err)
+	}
+	return store, owner, other
+}
+
+// admins lists the users the tests treat as administrators.
+var admins = config.Config{AdminEmails: "owner@example.com, admin@example.com"}
+
+func serve(t *testing.T, store *memstore.Store, method, path, body string, userID int) *httptest.ResponseRecorder {
+	t.Helper()
+	router := chi.NewRouter()
+	export.RegisterRoutes(router, export.NewHandler(store, time.Minute), auth.RequireAdmin(store, admins.IsAdmin))
+	return handlertest.Serve(router, method, path, userID, body)
+}
+
+func createUser(t *testing.T, store *memstore.Store, firstName, email string) int {
+	t.Helper()
+	ctx := context.Background()
+	if err := store.CreateUser(ctx, types.User{FirstName: firstName, LastName: "User", Email: email, Password: "hashed"}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := store.GetUserByEmail(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.ID
+}
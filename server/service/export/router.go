@@ -0,0 +1,19 @@
+package export
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes mounts the export routes. requireAdmin guards the
+// full-instance routes.
+func RegisterRoutes(r chi.Router, handler *Handler, requireAdmin func(http.Handler) http.Handler) {
+	r.Get("/export", handler.HandleExport)
+
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(requireAdmin)
+		r.Get("/export", handler.HandleAdminExport)
+		r.Post("/import", handler.HandleAdminImport)
+	})
+}
@@ -0,0 +1,483 @@
+package export
+
+import (
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type Store struct {
+	db     *sql.DB
+	sqlite bool
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// NewSQLiteStore returns a store for a database opened with
+// db.NewSQLiteStorage.
+func NewSQLiteStore(db *sql.DB) *Store {
+	return &Store{db: db, sqlite: true}
+}
+
+// StreamGoals calls fn for every goal userID can see, with its tasks, in id
+// order. Every user sees every goal, matching GET /goals. Rows are read one
+// goal at a time, so memory does not grow with the size of the export.
+func (s *Store) StreamGoals(ctx context.Context, _ int, fn func(*types.GoalWithTasks) error) error {
+	ctx = tracing.WithStatementName(ctx, "export.StreamGoals")
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT
+			g.id,
+			g.title,
+			g.description,
+			g.priority,
+			g.status,
+			g.owner_id,
+			CAST(g.due_date AS TEXT),
+			g.auto_status,
+			g.progress_weight,
+			g.created_at,
+			TRIM(CONCAT(owner_u.first_name, ' ', owner_u.last_name)),
+			t.id,
+			t.title,
+			t.description,
+			t.priority,
+			t.is_completed,
+			t.assignee_id,
+			t.created_by,
+			CAST(t.due_date AS TEXT),
+			t.recurrence,
+			t.estimate_minutes,
+			t.created_at,
+			TRIM(CONCAT(assignee_u.first_name, ' ', assignee_u.last_name)),
+			TRIM(CONCAT(creator_u.first_name, ' ', creator_u.last_name))
+		FROM goals g
+		JOIN users owner_u ON owner_u.id = g.owner_id
+		LEFT JOIN tasks t ON t.goal_id = g.id
+		LEFT JOIN users assignee_u ON assignee_u.id = t.assignee_id
+		LEFT JOIN users creator_u ON creator_u.id = t.created_by
+		ORDER BY g.id, t.id`,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var current *types.GoalWithTasks
+	for rows.Next() {
+		var (
+			goal         types.Goal
+			goalDue      sql.NullString
+			taskID       sql.NullInt64
+			taskTitle    sql.NullString
+			taskDesc     sql.NullString
+			taskPriority sql.NullString
+			taskDone     sql.NullBool
+			assigneeID   sql.NullInt64
+			createdBy    sql.NullInt64
+			taskDue      sql.NullString
+			taskRule     sql.NullString
+			taskEstimate sql.NullInt64
+			taskAt       sql.NullTime
+			assigneeName sql.NullString
+			creatorName  sql.NullString
+		)
+		if err := rows.Scan(
+			&goal.ID,
+			&goal.Title,
+			&goal.Description,
+			&goal.Priority,
+			&goal.Status,
+			&goal.OwnerID,
+			&goalDue,
+			&goal.AutoStatus,
+			&goal.ProgressWeight,
+			&goal.CreatedAt,
+			&goal.OwnerName,
+			&taskID,
+			&taskTitle,
+			&taskDesc,
+			&taskPriority,
+			&taskDone,
+			&assigneeID,
+			&createdBy,
+			&taskDue,
+			&taskRule,
+			&taskEstimate,
+			&taskAt,
+			&assigneeName,
+			&creatorName,
+		); err != nil {
+			return err
+		}
+
+		if current == nil || current.ID != goal.ID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			goal.DueDate = nullableString(goalDue)
+			current = &types.GoalWithTasks{Goal: goal, Tasks: make([]*types.Task, 0)}
+		}
+		if !taskID.Valid {
+			continue
+		}
+
+		task := &types.Task{
+			ID:              int(taskID.Int64),
+			GoalID:          goal.ID,
+			GoalTitle:       goal.Title,
+			Title:           taskTitle.String,
+			Description:     taskDesc.String,
+			Priority:        taskPriority.String,
+			IsCompleted:     taskDone.Bool,
+			CreatedBy:       int(createdBy.Int64),
+			CreatedByName:   creatorName.String,
+			DueDate:         nullableString(taskDue),
+			Recurrence:      taskRule.String,
+			EstimateMinutes: nullableInt(taskEstimate),
+			CreatedAt:       taskAt.Time,
+		}
+		if assigneeID.Valid {
+			id := int(assigneeID.Int64)
+			task.AssigneeID = &id
+			task.AssigneeName = assigneeName.String
+		}
+		current.Tasks = append(current.Tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if current != nil {
+		return fn(current)
+	}
+	return nil
+}
+
+// ExportInstance calls users for every user and then goals for every goal
+// with its tasks, both in id order, from one consistent snapshot.
+func (s *Store) ExportInstance(ctx context.Context, users func(*types.InstanceUser) error, goals func(*types.InstanceGoal) error) error {
+	ctx = tracing.WithStatementName(ctx, "export.ExportInstance")
+	opts := &sql.TxOptions{ReadOnly: true, Isolation: sql.LevelRepeatableRead}
+	if s.sqlite {
+		// A SQLite read transaction always sees a single snapshot.
+		opts = &sql.TxOptions{ReadOnly: true}
+	}
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := exportUsers(ctx, tx, users); err != nil {
+		return err
+	}
+	if err := exportGoals(ctx, tx, goals); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func exportUsers(ctx context.Context, tx *sql.Tx, fn func(*types.InstanceUser) error) error {
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT id, first_name, last_name, email, password, created_at
+		 FROM users
+		 ORDER BY id`,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u types.InstanceUser
+		if err := rows.Scan(&u.ID, &u.FirstName, &u.LastName, &u.Email, &u.PasswordHash, &u.CreatedAt); err != nil {
+			return err
+		}
+		if err := fn(&u); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func exportGoals(ctx context.Context, tx *sql.Tx, fn func(*types.InstanceGoal) error) error {
+	rows, err := tx.QueryContext(
+		ctx,
+		`SELECT
+			g.id, g.title, g.description, g.priority, g.status, g.owner_id, CAST(g.due_date AS TEXT), g.auto_status, g.progress_weight, g.created_at,
+			t.id, t.title, t.description, t.priority, t.is_completed, t.assignee_id, t.created_by, CAST(t.due_date AS TEXT), t.recurrence, t.recurred, t.estimate_minutes, t.created_at, t.completed_at, t.position, t.assignee_position,
+			te.user_id, te.started_at, te.duration_seconds, te.note
+		 FROM goals g
+		 LEFT JOIN tasks t ON t.goal_id = g.id
+		 LEFT JOIN time_entries te ON te.task_id = t.id AND te.ended_at IS NOT NULL
+		 ORDER BY g.id, t.id, te.id`,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var current *types.InstanceGoal
+	for rows.Next() {
+		var (
+			goal         types.InstanceGoal
+			goalDue      sql.NullString
+			taskID       sql.NullInt64
+			taskTitle    sql.NullString
+			taskDesc     sql.NullString
+			taskPriority sql.NullString
+			taskDone     sql.NullBool
+			assigneeID   sql.NullInt64
+			createdBy    sql.NullInt64
+			taskDue      sql.NullString
+			taskRule     sql.NullString
+			taskRecurred sql.NullBool
+			taskEstimate sql.NullInt64
+			taskAt       sql.NullTime
+			taskDoneAt   sql.NullTime
+			taskPosition sql.NullFloat64
+			assigneePos  sql.NullFloat64
+			entryUserID  sql.NullInt64
+			entryStart   sql.NullTime
+			entrySeconds sql.NullInt64
+			entryNote    sql.NullString
+		)
+		if err := rows.Scan(
+			&goal.ID, &goal.Title, &goal.Description, &goal.Priority, &goal.Status, &goal.OwnerID, &goalDue, &goal.AutoStatus, &goal.ProgressWeight, &goal.CreatedAt,
+			&taskID, &taskTitle, &taskDesc, &taskPriority, &taskDone, &assigneeID, &createdBy, &taskDue, &taskRule, &taskRecurred, &taskEstimate, &taskAt, &taskDoneAt, &taskPosition, &assigneePos,
+			&entryUserID, &entryStart, &entrySeconds, &entryNote,
+		); err != nil {
+			return err
+		}
+
+		if current == nil || current.ID != goal.ID {
+			if current != nil {
+				if err := fn(current); err != nil {
+					return err
+				}
+			}
+			goal.DueDate = nullableString(goalDue)
+			goal.Tasks = make([]types.InstanceTask, 0)
+			current = &goal
+		}
+		if !taskID.Valid {
+			continue
+		}
+
+		// A task has one row per finished time entry.
+		last := len(current.Tasks) - 1
+		if last < 0 || current.Tasks[last].ID != int(taskID.Int64) {
+			task := types.InstanceTask{
+				ID:              int(taskID.Int64),
+				Title:           taskTitle.String,
+				Description:     taskDesc.String,
+				Priority:        taskPriority.String,
+				IsCompleted:     taskDone.Bool,
+				CreatedBy:       int(createdBy.Int64),
+				DueDate:         nullableString(taskDue),
+				Recurrence:      taskRule.String,
+				Recurred:        taskRecurred.Bool,
+				EstimateMinutes: nullableInt(taskEstimate),
+				Position:        taskPosition.Float64,
+				CreatedAt:       taskAt.Time,
+			}
+			if assigneeID.Valid {
+				id := int(assigneeID.Int64)
+				task.AssigneeID = &id
+			}
+			if taskDoneAt.Valid {
+				doneAt := taskDoneAt.Time
+				task.CompletedAt = &doneAt
+			}
+			if assigneePos.Valid {
+				position := assigneePos.Float64
+				task.AssigneePosition = &position
+			}
+			current.Tasks = append(current.Tasks, task)
+			last++
+		}
+		if entryUserID.Valid {
+			current.Tasks[last].TimeEntries = append(current.Tasks[last].TimeEntries, types.InstanceTimeEntry{
+				UserID:          int(entryUserID.Int64),
+				StartedAt:       entryStart.Time,
+				DurationSeconds: int(entrySeconds.Int64),
+				Note:            entryNote.String,
+			})
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if current != nil {
+		return fn(current)
+	}
+	return nil
+}
+
+// ImportInstance adds the snapshot's users, goals, tasks and time entries in
+// a single transaction. Users are matched by email: existing accounts are
+// reused and keep their password, the rest are created. Goals, tasks and
+// time entries are always added as new rows. The snapshot must pass
+// checkReferences.
+func (s *Store) ImportInstance(ctx context.Context, snapshot types.InstanceSnapshot) (*types.ImportSummary, error) {
+	ctx = tracing.WithStatementName(ctx, "export.ImportInstance")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	summary := &types.ImportSummary{}
+	now := time.Now().UTC()
+	userIDs := make(map[int]int, len(snapshot.Users))
+	for _, u := range snapshot.Users {
+		var id int
+		err := tx.QueryRowContext(ctx, `SELECT id FROM users WHERE email = $1`, u.Email).Scan(&id)
+		switch {
+		case err == nil:
+			summary.UsersMatched++
+		case err == sql.ErrNoRows:
+			err = tx.QueryRowContext(
+				ctx,
+				`INSERT INTO users (first_name, last_name, email, password, created_at)
+				 VALUES ($1, $2, $3, $4, $5)
+				 RETURNING id`,
+				u.FirstName, u.LastName, u.Email, u.PasswordHash, timestampOr(u.CreatedAt, now),
+			).Scan(&id)
+			if err != nil {
+				return nil, fmt.Errorf("import user %s: %w", u.Email, err)
+			}
+			summary.UsersCreated++
+		default:
+			return nil, err
+		}
+		userIDs[u.ID] = id
+	}
+
+	for _, g := range snapshot.Goals {
+		var goalID int
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO goals (title, description, priority, status, owner_id, due_date, auto_status, progress_weight, created_at)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			 RETURNING id`,
+			g.Title, g.Description, g.Priority, g.Status, userIDs[g.OwnerID], g.DueDate, g.AutoStatus, progressWeightOr(g.ProgressWeight), timestampOr(g.CreatedAt, now),
+		).Scan(&goalID)
+		if err != nil {
+			return nil, fmt.Errorf("import goal %d: %w", g.ID, err)
+		}
+		summary.Goals++
+
+		for _, t := range g.Tasks {
+			var assigneeID *int
+			if t.AssigneeID != nil {
+				id := userIDs[*t.AssigneeID]
+				assigneeID = &id
+			}
+			// The backup does not record assigned_at, so restored
+			// assignments date from the task's creation.
+			var taskID int
+			if err := tx.QueryRowContext(
+				ctx,
+				`INSERT INTO tasks (goal_id, title, description, priority, is_completed, assignee_id, created_by, due_date, recurrence, recurred, estimate_minutes, created_at, completed_at, assigned_at, position, assignee_position)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, CASE WHEN $6 IS NULL THEN NULL ELSE $12 END, $14, $15)
+				 RETURNING id`,
+				goalID, t.Title, t.Description, t.Priority, t.IsCompleted, assigneeID, userIDs[t.CreatedBy], t.DueDate, nullIfEmpty(t.Recurrence), t.Recurred, t.EstimateMinutes, timestampOr(t.CreatedAt, now), completedAtOf(t),
+				t.Position, assigneePositionOf(t),
+			).Scan(&taskID); err != nil {
+				return nil, fmt.Errorf("import task %d: %w", t.ID, err)
+			}
+			summary.Tasks++
+
+			for _, e := range t.TimeEntries {
+				startedAt := e.StartedAt.UTC()
+				if _, err := tx.ExecContext(
+					ctx,
+					`INSERT INTO time_entries (task_id, user_id, started_at, ended_at, duration_seconds, note)
+					 VALUES ($1, $2, $3, $4, $5, $6)`,
+					taskID, userIDs[e.UserID], startedAt, startedAt.Add(time.Duration(e.DurationSeconds)*time.Second), e.DurationSeconds, e.Note,
+				); err != nil {
+					return nil, fmt.Errorf("import time entry on task %d: %w", t.ID, err)
+				}
+				summary.TimeEntries++
+			}
+		}
+		if err := tracker.SyncGoalStatus(ctx, tx, goalID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func nullableString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	result := int(value.Int64)
+	return &result
+}
+
+// progressWeightOr defaults a goal's omitted progress weighting.
+func progressWeightOr(weight string) string {
+	if weight == "" {
+		return "count"
+	}
+	return weight
+}
+
+// nullIfEmpty stores an empty optional text value as NULL.
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// completedAtOf keeps the completion time of completed tasks only.
+func completedAtOf(t types.InstanceTask) *time.Time {
+	if !t.IsCompleted || t.CompletedAt == nil {
+		return nil
+	}
+	completedAt := t.CompletedAt.UTC()
+	return &completedAt
+}
+
+// assigneePositionOf keeps the assignee position of assigned tasks only.
+// Assigned tasks from backups without positions share position 0 and stay
+// in id order.
+func assigneePositionOf(t types.InstanceTask) *float64 {
+	if t.AssigneeID == nil {
+		return nil
+	}
+	if t.AssigneePosition == nil {
+		return new(float64)
+	}
+	return t.AssigneePosition
+}
+
+func timestampOr(t, fallback time.Time) time.Time {
+	if t.IsZero() {
+		return fallback
+	}
+	return t.UTC()
+}
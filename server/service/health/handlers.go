@@ -0,0 +1,69 @@
+package health
+
+import (
+	"VyacheslavKuchumov/test-backend/buildinfo"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const checkTimeout = 2 * time.Second
+
+type Handler struct {
+	store           types.HealthStore
+	expectedVersion uint
+}
+
+// NewHandler returns health handlers that report ready once the database
+// answers and its schema is at least expectedVersion.
+func NewHandler(store types.HealthStore, expectedVersion uint) *Handler {
+	return &Handler{store: store, expectedVersion: expectedVersion}
+}
+
+// HandleLiveness reports that the process is up and serving HTTP.
+func (h *Handler) HandleLiveness(w http.ResponseWriter, r *http.Request) {
+	utils.WriteJSON(w, http.StatusOK, types.HealthResponse{Status: "ok"})
+}
+
+// HandleReadiness reports whether the server can handle API traffic. The
+// probe is public, so failed checks only say "unavailable" or "pending";
+// the underlying errors are logged.
+func (h *Handler) HandleReadiness(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+
+	checks := map[string]string{
+		"database":   "ok",
+		"migrations": "ok",
+	}
+	ready := true
+
+	if err := h.store.Ping(ctx); err != nil {
+		slog.WarnContext(r.Context(), "readiness: database ping failed", "error", err)
+		checks["database"] = "unavailable"
+		checks["migrations"] = "skipped"
+		ready = false
+	} else if version, dirty, err := h.store.MigrationVersion(ctx); err != nil {
+		slog.WarnContext(r.Context(), "readiness: failed to read the migration version", "error", err)
+		checks["migrations"] = "unavailable"
+		ready = false
+	} else if dirty || version < h.expectedVersion {
+		slog.WarnContext(r.Context(), "readiness: migrations pending", "version", version, "dirty", dirty, "expected", h.expectedVersion)
+		checks["migrations"] = "pending"
+		ready = false
+	}
+
+	if !ready {
+		utils.WriteJSON(w, http.StatusServiceUnavailable, types.HealthResponse{Status: "unavailable", Checks: checks})
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, types.HealthResponse{Status: "ok", Checks: checks})
+}
+
+// HandleVersion reports the build metadata of the running binary.
+func (h *Handler) HandleVersion(w http.ResponseWriter, r *http.Request) {
+	utils.WriteJSON(w, http.StatusOK, buildinfo.Get())
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	t.Run("liveness is ok without a database", func(t *testing.T) {
+		handler := NewHandler(&mockHealthStore{pingErr: errors.New("down")}, 6)
+		rr := httptest.NewRecorder()
+
+		handler.HandleLiveness(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+		}
+	})
+
+	cases := []struct {
+		name       string
+		store      *mockHealthStore
+		wantStatus int
+		wantCheck  string
+	}{
+		{name: "ready when migrated", store: &mockHealthStore{version: 6}, wantStatus: http.StatusOK, wantCheck: "ok"},
+		{name: "ready when schema is newer", store: &mockHealthStore{version: 7}, wantStatus: http.StatusOK, wantCheck: "ok"},
+		{name: "not ready when behind", store: &mockHealthStore{version: 5}, wantStatus: http.StatusServiceUnavailable, wantCheck: "pending"},
+		{name: "not ready when dirty", store: &mockHealthStore{version: 6, dirty: true}, wantStatus: http.StatusServiceUnavailable, wantCheck: "pending"},
+		{name: "not ready when the version is unreadable", store: &mockHealthStore{versionErr: errors.New(`relation "schema_migrations" does not exist`)}, wantStatus: http.StatusServiceUnavailable, wantCheck: "unavailable"},
+		{name: "not ready when database is down", store: &mockHealthStore{pingErr: errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")}, wantStatus: http.StatusServiceUnavailable, wantCheck: "skipped"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewHandler(tc.store, 6)
+			rr := httptest.NewRecorder()
+
+			handler.HandleReadiness(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if rr.Code != tc.wantStatus {
+				t.Fatalf("expected %d, got %d", tc.wantStatus, rr.Code)
+			}
+
+			// The probe is public: store errors must not reach the body.
+			if raw := rr.Body.String(); strings.Contains(raw, "10.0.0.5") || strings.Contains(raw, "schema_migrations") {
+				t.Fatalf("expected generic check results, got %s", raw)
+			}
+
+			var body types.HealthResponse
+			if err := json.NewDecoder(rr.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Checks["migrations"] != tc.wantCheck {
+				t.Fatalf("expected migrations check %q, got %q", tc.wantCheck, body.Checks["migrations"])
+			}
+			if tc.store.pingErr != nil && body.Checks["database"] != "unavailable" {
+				t.Fatalf("expected database check %q, got %q", "unavailable", body.Checks["database"])
+			}
+		})
+	}
+
+	t.Run("version reports build info", func(t *testing.T) {
+		handler := NewHandler(&mockHealthStore{}, 6)
+		rr := httptest.NewRecorder()
+
+		handler.HandleVersion(rr, httptest.NewRequest(http.MethodGet, "/version", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("expected %d, got %d", http.StatusOK, rr.Code)
+		}
+	})
+}
+
+type mockHealthStore struct {
+	pingErr    error
+	versionErr error
+	version    uint
+	dirty      bool
+}
+
+func (m *mockHealthStore) Ping(ctx context.Context) error {
+	return m.pingErr
+}
+
+func (m *mockHealthStore) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	return m.version, m.dirty, m.versionErr
+}
@@ -0,0 +1,9 @@
+package health
+
+import "github.com/go-chi/chi/v5"
+
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Get("/healthz", handler.HandleLiveness)
+	r.Get("/readyz", handler.HandleReadiness)
+	r.Get("/version", handler.HandleVersion)
+}
@@ -0,0 +1,37 @@
+package health
+
+import (
+	"VyacheslavKuchumov/test-backend/tracing"
+	"context"
+	"database/sql"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) Ping(ctx context.Context) error {
+	ctx = tracing.WithStatementName(ctx, "health.Ping")
+	return s.db.PingContext(ctx)
+}
+
+// MigrationVersion reads the state golang-migrate records in schema_migrations.
+func (s *Store) MigrationVersion(ctx context.Context) (uint, bool, error) {
+	ctx = tracing.WithStatementName(ctx, "health.MigrationVersion")
+	var (
+		version int64
+		dirty   bool
+	)
+	err := s.db.QueryRowContext(
+		ctx,
+		"SELECT version, dirty FROM schema_migrations LIMIT 1",
+	).Scan(&version, &dirty)
+	if err != nil {
+		return 0, false, err
+	}
+	return uint(version), dirty, nil
+}
@@ -0,0 +1,121 @@
+package importer
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// githubIssue is the subset of an issue the importer reads. It accepts both
+// the REST API shape (GET /repos/{owner}/{repo}/issues) and the output of
+// `gh issue list --json number,title,body,state,labels,assignees,milestone`.
+type githubIssue struct {
+	Number      int              `json:"number"`
+	Title       string           `json:"title"`
+	Body        string           `json:"body"`
+	State       string           `json:"state"`
+	ClosedAt    string           `json:"closed_at"`
+	ClosedAtCLI string           `json:"closedAt"`
+	Labels      []githubLabel    `json:"labels"`
+	Assignee    *githubUser      `json:"assignee"`
+	Assignees   []githubUser     `json:"assignees"`
+	Milestone   *githubMilestone `json:"milestone"`
+	PullRequest json.RawMessage  `json:"pull_request"`
+}
+
+type githubLabel struct {
+	Name string `json:"name"`
+}
+
+type githubUser struct {
+	Login string `json:"login"`
+}
+
+type githubMilestone struct {
+	Number      int    `json:"number"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	State       string `json:"state"`
+	DueOn       string `json:"due_on"`
+	DueOnCLI    string `json:"dueOn"`
+}
+
+// parseGitHub maps milestones to goals, keeping their due dates, and issues
+// to tasks in file order. Pull requests, which the REST API lists alongside
+// issues, are skipped. Issues without a milestone go to a "No milestone"
+// goal. An issue is completed when closed, at its closing time, takes its priority from a label
+// such as "priority: high" or "P1", and is assigned to its first assignee by
+// login.
+func parseGitHub(r io.Reader) (*plan, error) {
+	var issues []githubIssue
+	if err := json.NewDecoder(r).Decode(&issues); err != nil {
+		return nil, fmt.Errorf("invalid GitHub export: %w", err)
+	}
+
+	p := &plan{}
+	goalIndex := make(map[string]int)
+	for _, issue := range issues {
+		if len(issue.PullRequest) > 0 && string(issue.PullRequest) != "null" {
+			continue
+		}
+
+		key := ""
+		if issue.Milestone != nil {
+			key = issue.Milestone.Title
+		}
+		i, ok := goalIndex[key]
+		if !ok {
+			i = len(p.goals)
+			goalIndex[key] = i
+			if issue.Milestone == nil {
+				p.goals = append(p.goals, fallbackGoal("issues without milestone", "No milestone"))
+			} else {
+				p.goals = append(p.goals, githubGoal(issue.Milestone))
+			}
+		}
+
+		task := plannedTask{
+			ref:       fmt.Sprintf("issue #%d", issue.Number),
+			payload:   types.CreateTaskPayload{Title: issue.Title, Description: issue.Body, Priority: "medium"},
+			completed: strings.EqualFold(issue.State, "closed"),
+		}
+		if task.completed {
+			task.completedAt = timestamp(cmp.Or(issue.ClosedAt, issue.ClosedAtCLI), time.RFC3339)
+		}
+		for _, label := range issue.Labels {
+			if priority, ok := priorityFromName(label.Name); ok {
+				task.payload.Priority = priority
+				break
+			}
+		}
+		switch {
+		case len(issue.Assignees) > 0:
+			task.assignee = issue.Assignees[0].Login
+		case issue.Assignee != nil:
+			task.assignee = issue.Assignee.Login
+		}
+		p.goals[i].tasks = append(p.goals[i].tasks, task)
+	}
+	return p, nil
+}
+
+func githubGoal(milestone *githubMilestone) plannedGoal {
+	goal := plannedGoal{
+		ref: fmt.Sprintf("milestone %q", milestone.Title),
+		payload: types.CreateGoalPayload{
+			Title:       milestone.Title,
+			Description: milestone.Description,
+			Priority:    "medium",
+			Status:      "todo",
+			DueDate:     dueDate(cmp.Or(milestone.DueOn, milestone.DueOnCLI), time.RFC3339),
+		},
+	}
+	if strings.EqualFold(milestone.State, "closed") {
+		goal.payload.Status = "achieved"
+	}
+	return goal
+}
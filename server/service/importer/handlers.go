@@ -0,0 +1,219 @@
+package importer
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/service/user"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxUploadBytes bounds the multipart body of an import.
+const maxUploadBytes = 32 << 20
+
+type Handler struct {
+	store     types.ImportStore
+	userStore types.UserStore
+}
+
+func NewHandler(store types.ImportStore, userStore types.UserStore) *Handler {
+	return &Handler{store: store, userStore: userStore}
+}
+
+// HandleImport godoc
+// @Summary Import goals and tasks from another tool
+// @Description Upload a Trello board JSON export, a Jira CSV export or a JSON array of GitHub issues as the multipart field `file`. Trello lists, Jira epics and GitHub milestones become goals owned by the caller; cards and issues become their tasks. Assignees are matched to users by email: identities that are not emails are looked up in the optional `assignees` field, a JSON object such as `{"octocat":"octo@example.com"}`. With dryRun=true nothing is written and the report lists what would be created, the identities without a user and any validation errors. Otherwise everything is created in one transaction, or nothing is when there are validation errors. Unmapped assignees are left unassigned.
+// @Tags import
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param source path string true "Export format" Enums(trello, jira, github)
+// @Param dryRun query bool false "Preview without creating anything"
+// @Param file formData file true "Export file"
+// @Param assignees formData string false "JSON object mapping source identities to user emails"
+// @Success 200 {object} types.ImportReport "Dry run"
+// @Success 201 {object} types.ImportReport
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 413 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /imports/{source} [post]
+func (h *Handler) HandleImport(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	source := chi.URLParam(r, "source")
+	parse, ok := parsers[source]
+	if !ok {
+		utils.WriteError(w, http.StatusNotFound, fmt.Errorf("unknown import source %q, use trello, jira or github", source))
+		return
+	}
+
+	dryRun := false
+	if value := r.URL.Query().Get("dryRun"); value != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid dryRun %q", value))
+			return
+		}
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadBytes)
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			utils.WriteError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("upload is larger than %d bytes", maxUploadBytes))
+			return
+		}
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("missing multipart file field \"file\": %w", err))
+		return
+	}
+	defer file.Close()
+
+	emails := map[string]string{}
+	if value := r.FormValue("assignees"); value != "" {
+		if err := json.Unmarshal([]byte(value), &emails); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid assignees mapping: %w", err))
+			return
+		}
+	}
+
+	p, err := parse(file)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report := &types.ImportReport{
+		Source:        source,
+		DryRun:        dryRun,
+		Goals:         []types.ImportReportGoal{},
+		UnmappedUsers: []string{},
+		Errors:        validatePlan(p),
+	}
+	goals, err := h.resolve(r, p, emails, report)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if dryRun {
+		utils.WriteJSON(w, http.StatusOK, report)
+		return
+	}
+	if len(report.Errors) > 0 {
+		utils.WriteProblem(w, http.StatusBadRequest, utils.CodeValidationFailed, "import has invalid records", report.Errors)
+		return
+	}
+
+	ids, err := h.store.CreateGoalsWithTasks(r.Context(), userID, goals)
+	if err != nil {
+		utils.WriteError(w, http.StatusInternalServerError, err)
+		return
+	}
+	for i, id := range ids {
+		report.Goals[i].ID = id
+	}
+	utils.WriteJSON(w, http.StatusCreated, report)
+}
+
+// resolve matches assignees to users and fills in the report's goals and
+// unmapped users. Identities containing "@" are treated as emails unless
+// emails maps them elsewhere.
+func (h *Handler) resolve(r *http.Request, p *plan, emails map[string]string, report *types.ImportReport) ([]types.ImportedGoal, error) {
+	userIDs := make(map[string]*int)
+	lookup := func(identity string) (*int, error) {
+		if id, ok := userIDs[identity]; ok {
+			return id, nil
+		}
+		email, ok := emails[identity]
+		if !ok && strings.Contains(identity, "@") {
+			email = identity
+		}
+		var id *int
+		if email = strings.TrimSpace(email); email != "" {
+			u, err := h.userStore.GetUserByEmail(r.Context(), email)
+			switch {
+			case err == nil:
+				id = &u.ID
+			case !errors.Is(err, user.ErrNotFound):
+				return nil, err
+			}
+		}
+		if id == nil {
+			report.UnmappedUsers = append(report.UnmappedUsers, identity)
+		}
+		userIDs[identity] = id
+		return id, nil
+	}
+
+	goals := make([]types.ImportedGoal, 0, len(p.goals))
+	for _, g := range p.goals {
+		goal := types.ImportedGoal{Goal: g.payload, Tasks: make([]types.ImportedTask, 0, len(g.tasks))}
+		summary := types.ImportReportGoal{
+			Ref:      g.ref,
+			Title:    g.payload.Title,
+			Status:   g.payload.Status,
+			Priority: g.payload.Priority,
+			Tasks:    len(g.tasks),
+		}
+		for _, t := range g.tasks {
+			task := types.ImportedTask{Task: t.payload, IsCompleted: t.completed, CompletedAt: t.completedAt}
+			if t.assignee != "" {
+				id, err := lookup(t.assignee)
+				if err != nil {
+					return nil, err
+				}
+				if id != nil {
+					task.Task.AssigneeID = id
+					summary.Assigned++
+				}
+			}
+			if t.completed {
+				summary.Completed++
+			}
+			goal.Tasks = append(goal.Tasks, task)
+		}
+		goals = append(goals, goal)
+		report.Goals = append(report.Goals, summary)
+	}
+	slices.Sort(report.UnmappedUsers)
+	return goals, nil
+}
+
+// validatePlan checks every goal and task against the same rules as the
+// create endpoints. Field names are prefixed with the record's ref, e.g.
+// "card 12.title".
+func validatePlan(p *plan) []types.FieldError {
+	fieldErrors := []types.FieldError{}
+	check := func(ref string, payload any) {
+		var validationErrors validator.ValidationErrors
+		if err := utils.Validate.Struct(payload); errors.As(err, &validationErrors) {
+			for _, fe := range utils.FieldErrors(validationErrors) {
+				fe.Field = ref + "." + fe.Field
+				fieldErrors = append(fieldErrors, fe)
+			}
+		}
+	}
+	for _, g := range p.goals {
+		check(g.ref, g.payload)
+		for _, t := range g.tasks {
+			check(t.ref, t.payload)
+		}
+	}
+	return fieldErrors
+}
@@ -0,0 +1,174 @@
+package importer_test
+
+import (
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/service/importer"
+	"VyacheslavKuchumov/test-backend/types"
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const githubIssues = `[
+	{"number": 1, "title": "Fix login", "state": "closed", "milestone": {"title": "v1.0"}, "assignee": {"login": "octocat"}},
+	{"number": 2, "title": "Write docs", "state": "open", "milestone": {"title": "v1.0"}, "assignee": {"login": "ada@example.com"}},
+	{"number": 3, "title": "Tidy up", "state": "open", "assignee": {"login": "hubot"}}
+]`
+
+func TestImportDryRunCreatesNothing(t *testing.T) {
+	store, owner := seed(t)
+
+	rr := upload(t, store, "/imports/github?dryRun=true", githubIssues, "", owner)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	report := decodeReport(t, rr)
+	if !report.DryRun || len(report.Goals) != 2 || report.Goals[0].ID != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if g := report.Goals[0]; g.Title != "v1.0" || g.Tasks != 2 || g.Assigned != 1 || g.Completed != 1 {
+		t.Fatalf("unexpected goal summary %+v", g)
+	}
+	if !slices.Equal(report.UnmappedUsers, []string{"hubot", "octocat"}) {
+		t.Fatalf("expected unmapped users listed once in order, got %q", report.UnmappedUsers)
+	}
+
+	goals, err := store.GetGoalsByOwner(context.Background(), owner)
+	if err != nil || len(goals) != 0 {
+		t.Fatalf("expected a dry run to create nothing, got %d goals, %v", len(goals), err)
+	}
+}
+
+func TestImportCommitsWithAssigneeMapping(t *testing.T) {
+	store, owner := seed(t)
+
+	rr := upload(t, store, "/imports/github", githubIssues, `{"octocat":"ada@example.com"}`, owner)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	report := decodeReport(t, rr)
+	if report.DryRun || report.Goals[0].ID == 0 || report.Goals[0].Assigned != 2 || !slices.Equal(report.UnmappedUsers, []string{"hubot"}) {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	goal, err := store.GetGoalWithTasks(context.Background(), report.Goals[0].ID, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goal.OwnerID != owner || len(goal.Tasks) != 2 {
+		t.Fatalf("unexpected imported goal %+v", goal)
+	}
+	i := slices.IndexFunc(goal.Tasks, func(task *types.Task) bool { return task.Title == "Fix login" })
+	if i < 0 || goal.Tasks[i].AssigneeName != "Ada Lovelace" || !goal.Tasks[i].IsCompleted || goal.Tasks[i].CreatedBy != owner {
+		t.Fatalf("expected the mapped, completed issue, got %+v", goal.Tasks)
+	}
+}
+
+func TestImportReportsValidationErrors(t *testing.T) {
+	store, owner := seed(t)
+	issues := `[{"number": 7, "title": "No", "state": "open"}]`
+
+	rr := upload(t, store, "/imports/github?dryRun=1", issues, "", owner)
+	report := decodeReport(t, rr)
+	if len(report.Errors) != 1 || report.Errors[0].Field != "issue #7.title" || report.Errors[0].Code != "min" {
+		t.Fatalf("expected the short title reported, got %+v", report.Errors)
+	}
+
+	rr = upload(t, store, "/imports/github", issues, "", owner)
+	if rr.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var problem types.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil || problem.Code != "validation_failed" || len(problem.FieldErrors) != 1 {
+		t.Fatalf("unexpected problem %+v, %v", problem, err)
+	}
+	goals, _ := store.GetGoalsByOwner(context.Background(), owner)
+	if len(goals) != 0 {
+		t.Fatalf("expected nothing created, got %d goals", len(goals))
+	}
+}
+
+func TestImportRejectsBadRequests(t *testing.T) {
+	store, owner := seed(t)
+
+	cases := []struct {
+		name      string
+		path      string
+		file      string
+		assignees string
+		status    int
+	}{
+		{"unknown source", "/imports/asana", "[]", "", http.StatusNotFound},
+		{"malformed file", "/imports/trello", "{", "", http.StatusBadRequest},
+		{"malformed mapping", "/imports/github", "[]", "{", http.StatusBadRequest},
+		{"invalid dryRun", "/imports/github?dryRun=maybe", "[]", "", http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if rr := upload(t, store, tc.path, tc.file, tc.assignees, owner); rr.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func seed(t *testing.T) (*memstore.Store, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := memstore.New()
+	for _, u := range []types.User{
+		{FirstName: "Owner", LastName: "User", Email: "owner@example.com", Password: "hashed"},
+		{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com", Password: "hashed"},
+	} {
+		if err := store.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	owner, err := store.GetUserByEmail(ctx, "owner@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, owner.ID
+}
+
+func upload(t *testing.T, store *memstore.Store, path, file, assignees string, userID int) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", "export")
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write([]byte(file))
+	if assignees != "" {
+		form.WriteField("assignees", assignees)
+	}
+	form.Close()
+
+	router := chi.NewRouter()
+	importer.RegisterRoutes(router, importer.NewHandler(store, store))
+
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req = req.WithContext(context.WithValue(req.Context(), auth.UserKey, userID))
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	return rr
+}
+
+func decodeReport(t *testing.T, rr *httptest.ResponseRecorder) types.ImportReport {
+	t.Helper()
+	var report types.ImportReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, rr.Body.String())
+	}
+	return report
+}
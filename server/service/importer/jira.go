@@ -0,0 +1,166 @@
+package importer
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// jiraDateLayouts are the "Due Date" formats of Jira's CSV export, which
+// follow the site's date settings, tried in order.
+var jiraDateLayouts = []string{
+	time.DateOnly,
+	"02/Jan/06 3:04 PM",
+	"02/Jan/06",
+	"2006-01-02 15:04",
+	time.RFC3339,
+}
+
+// jiraDoneStatuses are the status names treated as done when the export
+// has no "Status Category" column.
+var jiraDoneStatuses = []string{"done", "closed", "resolved"}
+
+// parseJira reads a Jira CSV export (Filters → Export → CSV). Epics become
+// goals and other issues become tasks of the epic named in their "Epic
+// Link" or "Parent" column, matched by issue key or id. Issues outside an
+// epic go to a "No epic" goal. Assignees are taken from the "Assignee"
+// column as exported (display name or email, depending on the site), and
+// completed issues keep their "Resolved" time.
+func parseJira(r io.Reader) (*plan, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid Jira export: %w", err)
+	}
+	// Jira repeats some columns (Labels, Sprint); the first one is used.
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; !ok {
+			columns[name] = i
+		}
+	}
+	for _, required := range []string{"summary", "issue key", "issue type"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("invalid Jira export: missing %q column", required)
+		}
+	}
+
+	type issue struct {
+		line   int
+		fields func(string) string
+	}
+	var epics, issues []issue
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid Jira export: %w", err)
+		}
+		fields := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		if strings.EqualFold(fields("issue type"), "epic") {
+			epics = append(epics, issue{line, fields})
+		} else {
+			issues = append(issues, issue{line, fields})
+		}
+	}
+
+	p := &plan{}
+	goalIndex := make(map[string]int, 2*len(epics))
+	for _, epic := range epics {
+		f := epic.fields
+		goal := plannedGoal{
+			ref: f("issue key"),
+			payload: types.CreateGoalPayload{
+				Title:       f("summary"),
+				Description: f("description"),
+				Priority:    jiraPriority(f("priority")),
+				Status:      "todo",
+				DueDate:     dueDate(f("due date"), jiraDateLayouts...),
+			},
+		}
+		switch {
+		case jiraDone(f):
+			goal.payload.Status = "achieved"
+		case strings.EqualFold(f("status category"), "in progress") || strings.EqualFold(f("status"), "in progress"):
+			goal.payload.Status = "in_progress"
+		}
+
+		goalIndex[f("issue key")] = len(p.goals)
+		if id := f("issue id"); id != "" {
+			goalIndex[id] = len(p.goals)
+		}
+		p.goals = append(p.goals, goal)
+	}
+
+	noEpic := -1
+	for _, issue := range issues {
+		f := issue.fields
+		task := plannedTask{
+			ref: f("issue key"),
+			payload: types.CreateTaskPayload{
+				Title:       f("summary"),
+				Description: f("description"),
+				Priority:    jiraPriority(f("priority")),
+				DueDate:     dueDate(f("due date"), jiraDateLayouts...),
+			},
+			completed: jiraDone(f),
+			assignee:  f("assignee"),
+		}
+		if task.completed {
+			task.completedAt = timestamp(f("resolved"), jiraDateLayouts...)
+		}
+		if task.ref == "" {
+			task.ref = fmt.Sprintf("line %d", issue.line)
+		}
+
+		i, ok := goalIndex[f("epic link")]
+		if !ok {
+			i, ok = goalIndex[f("parent")]
+		}
+		if !ok {
+			if noEpic < 0 {
+				noEpic = len(p.goals)
+				p.goals = append(p.goals, fallbackGoal("issues without epic", "No epic"))
+			}
+			i = noEpic
+		}
+		p.goals[i].tasks = append(p.goals[i].tasks, task)
+	}
+	return p, nil
+}
+
+func jiraDone(fields func(string) string) bool {
+	if category := fields("status category"); category != "" {
+		return strings.EqualFold(category, "done")
+	}
+	status := strings.ToLower(fields("status"))
+	for _, done := range jiraDoneStatuses {
+		if status == done {
+			return true
+		}
+	}
+	return false
+}
+
+func jiraPriority(name string) string {
+	if priority, ok := priorityFromName(name); ok {
+		return priority
+	}
+	return "medium"
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTrello(t *testing.T) {
+	board := `{
+		"lists": [
+			{"id": "l2", "name": "Doing", "pos": 2},
+			{"id": "l1", "name": "Backlog", "pos": 1},
+			{"id": "l3", "name": "Old", "pos": 3, "closed": true}
+		],
+		"members": [{"id": "m1", "username": "ada"}],
+		"cards": [
+			{"idShort": 2, "name": "Second card", "idList": "l1", "pos": 20},
+			{"idShort": 1, "name": "First card", "desc": "Details", "idList": "l1", "pos": 10,
+			 "idMembers": ["m1"], "due": "2026-03-31T12:00:00.000Z", "dueComplete": true, "labels": [{"name": "Priority: High"}]},
+			{"idShort": 3, "name": "Archived", "idList": "l2", "closed": true},
+			{"idShort": 4, "name": "In closed list", "idList": "l3"}
+		]
+	}`
+	p, err := parseTrello(strings.NewReader(board))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.goals) != 2 || p.goals[0].payload.Title != "Backlog" || p.goals[1].payload.Title != "Doing" {
+		t.Fatalf("expected open lists in board order, got %+v", p.goals)
+	}
+	tasks := p.goals[0].tasks
+	if len(tasks) != 2 || tasks[0].payload.Title != "First card" || len(p.goals[1].tasks) != 0 {
+		t.Fatalf("expected open cards in board order, got %+v", p.goals)
+	}
+	first := tasks[0]
+	if first.payload.DueDate == nil || *first.payload.DueDate != "2026-03-31" {
+		t.Fatalf("expected the card due date, got %v", first.payload.DueDate)
+	}
+	if first.ref != "card 1" || first.assignee != "ada" || !first.completed || first.payload.Priority != "high" || first.payload.Description != "Details" {
+		t.Fatalf("unexpected card %+v", first)
+	}
+	if tasks[1].payload.Priority != "medium" || tasks[1].assignee != "" {
+		t.Fatalf("expected defaults for a bare card, got %+v", tasks[1])
+	}
+}
+
+func TestParseJira(t *testing.T) {
+	export := "\ufeffIssue key,Issue id,Issue Type,Summary,Priority,Status,Assignee,Custom field (Epic Link),Parent,Epic Link,Due Date,Resolved\n" +
+		"PROJ-1,1001,Epic,Launch,Highest,In Progress,,,,,31/Mar/26 12:00 AM,\n" +
+		"PROJ-2,1002,Story,Write copy,Low,Done,ada@example.com,,,PROJ-1,,02/Mar/26 4:30 PM\n" +
+		"PROJ-3,1003,Sub-task,Proofread,Medium,To Do,Ada Lovelace,,1001,,next week,\n" +
+		"PROJ-4,1004,Bug,Stray bug,Blocker,Closed,,,,,,\n"
+	p, err := parseJira(strings.NewReader(export))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.goals) != 2 {
+		t.Fatalf("expected the epic and a fallback goal, got %+v", p.goals)
+	}
+	epic := p.goals[0]
+	if epic.ref != "PROJ-1" || epic.payload.Status != "in_progress" || epic.payload.Priority != "high" || len(epic.tasks) != 2 {
+		t.Fatalf("unexpected epic %+v", epic)
+	}
+	if story := epic.tasks[0]; !story.completed || story.assignee != "ada@example.com" || story.payload.Priority != "low" {
+		t.Fatalf("unexpected story %+v", story)
+	}
+	if resolved := epic.tasks[0].completedAt; resolved == nil || !resolved.Equal(time.Date(2026, 3, 2, 16, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected the story's resolution time, got %v", resolved)
+	}
+	if subtask := epic.tasks[1]; subtask.completed || subtask.ref != "PROJ-3" {
+		t.Fatalf("expected the parent id to link the sub-task, got %+v", subtask)
+	}
+	if *epic.payload.DueDate != "2026-03-31" || *epic.tasks[1].payload.DueDate != "next week" || epic.tasks[0].payload.DueDate != nil {
+		t.Fatalf("expected parsed, passed-through and empty due dates, got %v, %v, %v",
+			*epic.payload.DueDate, *epic.tasks[1].payload.DueDate, epic.tasks[0].payload.DueDate)
+	}
+	orphan := p.goals[1]
+	if orphan.payload.Title != "No epic" || len(orphan.tasks) != 1 || !orphan.tasks[0].completed || orphan.tasks[0].completedAt != nil || orphan.tasks[0].payload.Priority != "high" {
+		t.Fatalf("unexpected fallback goal %+v", orphan)
+	}
+}
+
+func TestParseJiraRequiresColumns(t *testing.T) {
+	if _, err := parseJira(strings.NewReader("Key,Title\nA-1,Thing\n")); err == nil {
+		t.Fatal("expected an error for a CSV without Jira columns")
+	}
+}
+
+func TestParseGitHub(t *testing.T) {
+	issues := `[
+		{"number": 1, "title": "Fix login", "state": "closed", "closed_at": "2026-03-30T18:45:00Z",
+		 "milestone": {"title": "v1.0", "description": "First release", "state": "closed", "due_on": "2026-03-31T07:00:00Z"},
+		 "assignee": {"login": "octocat"}, "labels": [{"name": "bug"}, {"name": "P1"}]},
+		{"number": 2, "title": "Open PR", "pull_request": {"url": "https://example.com"}},
+		{"number": 3, "title": "Write docs", "state": "OPEN",
+		 "assignees": [{"login": "hubot"}, {"login": "octocat"}]},
+		{"number": 4, "title": "Polish", "state": "open", "milestone": {"title": "v1.0"}, "pull_request": null}
+	]`
+	p, err := parseGitHub(strings.NewReader(issues))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.goals) != 2 {
+		t.Fatalf("expected a milestone and a fallback goal, got %+v", p.goals)
+	}
+	milestone := p.goals[0]
+	if milestone.payload.DueDate == nil || *milestone.payload.DueDate != "2026-03-31" {
+		t.Fatalf("expected the milestone due date, got %v", milestone.payload.DueDate)
+	}
+	if milestone.payload.Title != "v1.0" || milestone.payload.Status != "achieved" || milestone.payload.Description != "First release" || len(milestone.tasks) != 2 {
+		t.Fatalf("unexpected milestone %+v", milestone)
+	}
+	if fix := milestone.tasks[0]; !fix.completed || fix.assignee != "octocat" || fix.payload.Priority != "high" || fix.ref != "issue #1" {
+		t.Fatalf("unexpected issue %+v", fix)
+	}
+	if closed := milestone.tasks[0].completedAt; closed == nil || !closed.Equal(time.Date(2026, 3, 30, 18, 45, 0, 0, time.UTC)) {
+		t.Fatalf("expected the issue's closing time, got %v", closed)
+	}
+	docs := p.goals[1]
+	if docs.payload.Title != "No milestone" || len(docs.tasks) != 1 || docs.tasks[0].assignee != "hubot" || docs.tasks[0].completed {
+		t.Fatalf("unexpected fallback goal %+v", docs)
+	}
+}
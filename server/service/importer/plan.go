@@ -0,0 +1,97 @@
+package importer
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"io"
+	"strings"
+	"time"
+)
+
+// plan is a parsed export file: goals with their tasks, ready to be
+// validated and resolved against the user store.
+type plan struct {
+	goals []plannedGoal
+}
+
+type plannedGoal struct {
+	// ref names the source record in reports, e.g. `list "Doing"` or PROJ-1.
+	ref     string
+	payload types.CreateGoalPayload
+	tasks   []plannedTask
+}
+
+type plannedTask struct {
+	ref       string
+	payload   types.CreateTaskPayload
+	completed bool
+	// completedAt is the source tool's completion time, if it has one.
+	completedAt *time.Time
+	// assignee is the source tool's identity for the assignee (username,
+	// login, display name or email); empty when unassigned.
+	assignee string
+}
+
+// parsers maps the {source} path parameter to its parser.
+var parsers = map[string]func(io.Reader) (*plan, error){
+	"trello": parseTrello,
+	"jira":   parseJira,
+	"github": parseGitHub,
+}
+
+// fallbackGoal collects tasks that are not under a list, epic or milestone
+// in the source tool.
+func fallbackGoal(ref, title string) plannedGoal {
+	return plannedGoal{
+		ref:     ref,
+		payload: types.CreateGoalPayload{Title: title, Priority: "medium", Status: "todo"},
+	}
+}
+
+// dueDate converts a source tool's date or timestamp to YYYY-MM-DD using
+// the first layout that parses. Values no layout accepts are passed through
+// unchanged so validation reports them against the record.
+func dueDate(value string, layouts ...string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			date := t.Format(time.DateOnly)
+			return &date
+		}
+	}
+	return &value
+}
+
+// timestamp parses a source tool's completion time using the first layout
+// that parses. Unlike due dates, values no layout accepts are dropped: the
+// task is still imported, without a completion time.
+func timestamp(value string, layouts ...string) *time.Time {
+	value = strings.TrimSpace(value)
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			t = t.UTC()
+			return &t
+		}
+	}
+	return nil
+}
+
+// priorityFromName maps the priority names used by the supported tools to
+// high, medium or low. ok is false for names that carry no priority.
+func priorityFromName(name string) (priority string, ok bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, prefix := range []string{"priority:", "priority/", "priority-", "priority "} {
+		name = strings.TrimSpace(strings.TrimPrefix(name, prefix))
+	}
+	switch name {
+	case "highest", "blocker", "critical", "urgent", "high", "p0", "p1":
+		return "high", true
+	case "medium", "normal", "major", "p2":
+		return "medium", true
+	case "low", "lowest", "minor", "trivial", "p3", "p4":
+		return "low", true
+	}
+	return "", false
+}
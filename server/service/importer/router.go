@@ -0,0 +1,9 @@
+package importer
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Post("/imports/{source}", handler.HandleImport)
+}
@@ -0,0 +1,100 @@
+package importer
+
+import (
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// CreateGoalsWithTasks creates goals owned by ownerID, each with its tasks
+// created by ownerID, in one transaction, and returns the new goal ids in
+// order. Nothing is created if any insert fails.
+func (s *Store) CreateGoalsWithTasks(ctx context.Context, ownerID int, goals []types.ImportedGoal) ([]int, error) {
+	ctx = tracing.WithStatementName(ctx, "importer.CreateGoalsWithTasks")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now().UTC()
+	ids := make([]int, 0, len(goals))
+	for i, g := range goals {
+		var goalID int
+		err := tx.QueryRowContext(
+			ctx,
+			`INSERT INTO goals (title, description, priority, status, owner_id, due_date, auto_status, progress_weight)
+			 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			 RETURNING id`,
+			g.Goal.Title, g.Goal.Description, g.Goal.Priority, g.Goal.Status, ownerID, g.Goal.DueDate, g.Goal.AutoStatus, progressWeight(g.Goal.ProgressWeight),
+		).Scan(&goalID)
+		if err != nil {
+			return nil, fmt.Errorf("import goal %d: %w", i+1, err)
+		}
+		ids = append(ids, goalID)
+
+		for j, t := range g.Tasks {
+			var taskID int
+			err := tx.QueryRowContext(
+				ctx,
+				`INSERT INTO tasks (goal_id, title, description, priority, is_completed, assignee_id, created_by, due_date, estimate_minutes, completed_at, assigned_at)
+				 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+				 RETURNING id`,
+				goalID, t.Task.Title, t.Task.Description, t.Task.Priority, t.IsCompleted, t.Task.AssigneeID, ownerID, t.Task.DueDate, t.Task.EstimateMinutes, completedAt(t), assignedAt(t.Task.AssigneeID, now),
+			).Scan(&taskID)
+			if err == nil {
+				err = tracker.PlaceTask(ctx, tx, taskID)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("import goal %d task %d: %w", i+1, j+1, err)
+			}
+		}
+		if err := tracker.SyncGoalStatus(ctx, tx, goalID); err != nil {
+			return nil, fmt.Errorf("import goal %d: %w", i+1, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// completedAt is the completion time recorded for an imported task: the
+// other tool's when it has one, otherwise unknown like tasks completed
+// before completion times were recorded.
+func completedAt(t types.ImportedTask) *time.Time {
+	if !t.IsCompleted {
+		return nil
+	}
+	return t.CompletedAt
+}
+
+// assignedAt is the assigned_at of an imported task: assigned tasks count
+// as assigned now.
+func assignedAt(assigneeID *int, now time.Time) *time.Time {
+	if assigneeID == nil {
+		return nil
+	}
+	return &now
+}
+
+// progressWeight defaults a goal's omitted progress weighting.
+func progressWeight(weight string) string {
+	if weight == "" {
+		return "count"
+	}
+	return weight
+}
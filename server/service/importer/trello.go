@@ -0,0 +1,108 @@
+package importer
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// trelloBoard is the subset of a Trello board JSON export (board menu →
+// Print, export and share → Export as JSON) that the importer reads.
+type trelloBoard struct {
+	Lists   []trelloList   `json:"lists"`
+	Cards   []trelloCard   `json:"cards"`
+	Members []trelloMember `json:"members"`
+}
+
+type trelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type trelloCard struct {
+	IDShort     int      `json:"idShort"`
+	Name        string   `json:"name"`
+	Desc        string   `json:"desc"`
+	IDList      string   `json:"idList"`
+	Closed      bool     `json:"closed"`
+	Due         string   `json:"due"`
+	DueComplete bool     `json:"dueComplete"`
+	Pos         float64  `json:"pos"`
+	IDMembers   []string `json:"idMembers"`
+	Labels      []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+type trelloMember struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+// parseTrello maps open lists to goals and their open cards to tasks, both
+// in board order. Archived lists and cards are skipped. A card keeps its due
+// date, is completed when the due date is marked complete, takes its priority from a label
+// named like high, medium or low, and is assigned to its first member by
+// Trello username.
+func parseTrello(r io.Reader) (*plan, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, fmt.Errorf("invalid Trello export: %w", err)
+	}
+	if board.Lists == nil {
+		return nil, fmt.Errorf("invalid Trello export: no lists")
+	}
+
+	usernames := make(map[string]string, len(board.Members))
+	for _, member := range board.Members {
+		usernames[member.ID] = member.Username
+	}
+
+	lists := slices.Clone(board.Lists)
+	slices.SortStableFunc(lists, func(a, b trelloList) int { return cmp.Compare(a.Pos, b.Pos) })
+	cards := slices.Clone(board.Cards)
+	slices.SortStableFunc(cards, func(a, b trelloCard) int { return cmp.Compare(a.Pos, b.Pos) })
+
+	p := &plan{}
+	goalIndex := make(map[string]int, len(lists))
+	for _, list := range lists {
+		if list.Closed {
+			continue
+		}
+		goalIndex[list.ID] = len(p.goals)
+		p.goals = append(p.goals, plannedGoal{
+			ref:     fmt.Sprintf("list %q", list.Name),
+			payload: types.CreateGoalPayload{Title: list.Name, Priority: "medium", Status: "todo"},
+		})
+	}
+
+	for _, card := range cards {
+		i, ok := goalIndex[card.IDList]
+		if card.Closed || !ok {
+			continue
+		}
+
+		task := plannedTask{
+			ref:       fmt.Sprintf("card %d", card.IDShort),
+			payload:   types.CreateTaskPayload{Title: card.Name, Description: card.Desc, Priority: "medium", DueDate: dueDate(card.Due, time.RFC3339)},
+			completed: card.DueComplete,
+		}
+		for _, label := range card.Labels {
+			if priority, ok := priorityFromName(label.Name); ok {
+				task.payload.Priority = priority
+				break
+			}
+		}
+		if len(card.IDMembers) > 0 {
+			task.assignee = usernames[card.IDMembers[0]]
+		}
+		p.goals[i].tasks = append(p.goals[i].tasks, task)
+	}
+	return p, nil
+}
@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"VyacheslavKuchumov/test-backend/metrics"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// batchSize bounds how many pending emails are read per query.
+const batchSize = 100
+
+// An email whose send fails waits firstRetryDelay, twice as long after
+// each further failure, and at most maxRetryDelay.
+const (
+	firstRetryDelay = time.Minute
+	maxRetryDelay   = 24 * time.Hour
+)
+
+// Dispatcher creates due soon reminders and sends email notifications.
+// Tasks are due soon from today through dueSoonDays days ahead, in UTC.
+//
+// Every server runs a dispatcher. The store remembers which due date a
+// task was reminded of, and each email is claimed before it is sent, so
+// concurrent runs neither remind nor email twice. An email whose sending
+// fails is released and held back for retryDelay, while the emails after
+// it are still sent.
+type Dispatcher struct {
+	store       types.NotificationOutbox
+	mailer      Mailer
+	interval    time.Duration
+	dueSoonDays int
+	now         func() time.Time
+}
+
+func NewDispatcher(store types.NotificationOutbox, mailer Mailer, interval time.Duration, dueSoonDays int) *Dispatcher {
+	return &Dispatcher{store: store, mailer: mailer, interval: interval, dueSoonDays: dueSoonDays, now: time.Now}
+}
+
+// Run runs the dispatcher immediately and then every interval until ctx is
+// cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		sent, err := d.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			slog.ErrorContext(ctx, "notification dispatcher failed", "error", err)
+		}
+		if sent > 0 {
+			slog.InfoContext(ctx, "sent notification emails", "count", sent)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce creates the due soon reminders and sends every pending email. It
+// returns how many emails it sent and the errors of those it could not
+// send, which are released for a later retry.
+func (d *Dispatcher) RunOnce(ctx context.Context) (int, error) {
+	today := d.now().UTC()
+	through := today.AddDate(0, 0, d.dueSoonDays)
+	if _, err := d.store.NotifyDueSoon(ctx, today.Format(time.DateOnly), through.Format(time.DateOnly)); err != nil {
+		return 0, fmt.Errorf("notify due soon tasks: %w", err)
+	}
+
+	var sendErrs []error
+	sent, afterID := 0, 0
+	for {
+		emails, err := d.store.PendingEmails(ctx, d.now(), afterID, batchSize)
+		if err != nil {
+			return sent, errors.Join(append(sendErrs, err)...)
+		}
+		for _, email := range emails {
+			afterID = email.ID
+			claimed, err := d.store.ClaimEmail(ctx, email.ID, d.now())
+			if err != nil {
+				return sent, errors.Join(append(sendErrs, err)...)
+			}
+			if !claimed {
+				continue
+			}
+			if err := d.mailer.Send(ctx, Compose(email)); err != nil {
+				sendErrs = append(sendErrs, fmt.Errorf("send notification %d: %w", email.ID, err))
+				// The claim is released with a fresh context so that a
+				// cancelled run does not leave the email claimed.
+				retryAt := d.now().Add(retryDelay(email.Attempts))
+				if releaseErr := d.store.ReleaseEmail(context.WithoutCancel(ctx), email.ID, retryAt); releaseErr != nil {
+					slog.ErrorContext(ctx, "failed to release notification email", "notification_id", email.ID, "error", releaseErr)
+				}
+				continue
+			}
+			sent++
+			metrics.NotificationEmailsSent.Inc()
+		}
+		if len(emails) < batchSize {
+			return sent, errors.Join(sendErrs...)
+		}
+	}
+}
+
+// retryDelay is how long an email waits after its attempts+1st failed send.
+func retryDelay(attempts int) time.Duration {
+	if attempts >= 20 {
+		return maxRetryDelay
+	}
+	return min(firstRetryDelay<<attempts, maxRetryDelay)
+}
+
+// Compose renders the email for a notification.
+func Compose(email *types.NotificationEmail) Mail {
+	message := Describe(&email.Notification)
+	greeting := "Hello,"
+	if email.RecipientName != "" {
+		greeting = fmt.Sprintf("Hello %s,", email.RecipientName)
+	}
+	return Mail{
+		To:      email.To,
+		Subject: message,
+		Text:    greeting + "\n\n" + message + ".\n\nYou can change which notifications you receive by email in your notification preferences.\n",
+	}
+}
@@ -0,0 +1,156 @@
+package notification
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeOutbox is a NotificationOutbox over a slice of emails. The memstore
+// package cannot be used here, since it depends on this one.
+type fakeOutbox struct {
+	emails  []*types.NotificationEmail
+	sent    map[int]bool
+	retryAt map[int]time.Time
+	dueFrom string
+	dueTo   string
+}
+
+func (f *fakeOutbox) NotifyDueSoon(_ context.Context, from, through string) (int, error) {
+	f.dueFrom, f.dueTo = from, through
+	return 0, nil
+}
+
+func (f *fakeOutbox) PendingEmails(_ context.Context, now time.Time, afterID, limit int) ([]*types.NotificationEmail, error) {
+	var pending []*types.NotificationEmail
+	for _, email := range f.emails {
+		if email.ID > afterID && !f.sent[email.ID] && !f.retryAt[email.ID].After(now) && len(pending) < limit {
+			pending = append(pending, email)
+		}
+	}
+	return pending, nil
+}
+
+func (f *fakeOutbox) ClaimEmail(_ context.Context, notificationID int, _ time.Time) (bool, error) {
+	if f.sent[notificationID] {
+		return false, nil
+	}
+	f.sent[notificationID] = true
+	return true, nil
+}
+
+func (f *fakeOutbox) ReleaseEmail(_ context.Context, notificationID int, retryAt time.Time) error {
+	delete(f.sent, notificationID)
+	for _, email := range f.emails {
+		if email.ID == notificationID {
+			email.Attempts++
+		}
+	}
+	f.retryAt[notificationID] = retryAt
+	return nil
+}
+
+// fakeMailer records the mail it sends and fails for the addresses in
+// failing.
+type fakeMailer struct {
+	sent    []Mail
+	failing map[string]bool
+}
+
+func (f *fakeMailer) Send(_ context.Context, mail Mail) error {
+	if f.failing[mail.To] {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, mail)
+	return nil
+}
+
+func newEmail(id int, to string) *types.NotificationEmail {
+	return &types.NotificationEmail{
+		Notification:  types.Notification{ID: id, Type: Assigned, ActorName: "Ada Lovelace", GoalTitle: "Launch", TaskTitle: "Write docs"},
+		To:            to,
+		RecipientName: "Bob Builder",
+	}
+}
+
+func TestRunOnceSendsEachEmailOnce(t *testing.T) {
+	store := &fakeOutbox{sent: map[int]bool{}, retryAt: map[int]time.Time{}}
+	for id := 1; id <= batchSize+2; id++ {
+		store.emails = append(store.emails, newEmail(id, "bob@example.com"))
+	}
+	mailer := &fakeMailer{}
+	dispatcher := NewDispatcher(store, mailer, time.Minute, 2)
+	dispatcher.now = func() time.Time { return time.Date(2026, 3, 30, 23, 0, 0, 0, time.FixedZone("UTC-2", -2*3600)) }
+
+	sent, err := dispatcher.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != batchSize+2 || len(mailer.sent) != batchSize+2 {
+		t.Fatalf("expected every email to be sent, got %d and %d", sent, len(mailer.sent))
+	}
+	// Due dates are in UTC, where it is already March 31.
+	if store.dueFrom != "2026-03-31" || store.dueTo != "2026-04-02" {
+		t.Fatalf("expected the due soon window in UTC, got %s to %s", store.dueFrom, store.dueTo)
+	}
+	if mail := mailer.sent[0]; mail.To != "bob@example.com" || mail.Subject != `Ada Lovelace assigned you "Write docs" in "Launch"` {
+		t.Fatalf("unexpected mail %+v", mail)
+	}
+
+	if sent, err := dispatcher.RunOnce(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("expected nothing to send on the second run, got %d, %v", sent, err)
+	}
+}
+
+func TestRunOnceSendsPastFailedEmails(t *testing.T) {
+	store := &fakeOutbox{sent: map[int]bool{}, retryAt: map[int]time.Time{}, emails: []*types.NotificationEmail{
+		newEmail(1, "gone@example.com"),
+		newEmail(2, "bob@example.com"),
+		newEmail(3, "cat@example.com"),
+	}}
+	mailer := &fakeMailer{failing: map[string]bool{"gone@example.com": true}}
+	dispatcher := NewDispatcher(store, mailer, time.Minute, 1)
+	now := time.Date(2026, 3, 30, 12, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+
+	sent, err := dispatcher.RunOnce(context.Background())
+	if err == nil || sent != 2 || len(mailer.sent) != 2 {
+		t.Fatalf("expected the emails after the failed one to be sent, got %d, %v", sent, err)
+	}
+	if store.sent[1] || !store.retryAt[1].Equal(now.Add(time.Minute)) {
+		t.Fatalf("expected the failed email to be released for a minute, got %v, %v", store.sent, store.retryAt)
+	}
+
+	// The failed email waits out its delay, which doubles with each failure.
+	if sent, err := dispatcher.RunOnce(context.Background()); err != nil || sent != 0 {
+		t.Fatalf("expected nothing to send before the retry, got %d, %v", sent, err)
+	}
+	now = now.Add(time.Minute)
+	if sent, err := dispatcher.RunOnce(context.Background()); err == nil || sent != 0 {
+		t.Fatalf("expected the retry to fail, got %d, %v", sent, err)
+	}
+	if !store.retryAt[1].Equal(now.Add(2 * time.Minute)) {
+		t.Fatalf("expected the second delay to be two minutes, got %v", store.retryAt[1])
+	}
+
+	delete(mailer.failing, "gone@example.com")
+	now = now.Add(2 * time.Minute)
+	if sent, err := dispatcher.RunOnce(context.Background()); err != nil || sent != 1 {
+		t.Fatalf("expected the failed email to be retried, got %d, %v", sent, err)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	for attempts, expected := range map[int]time.Duration{
+		0:  time.Minute,
+		3:  8 * time.Minute,
+		11: maxRetryDelay,
+		63: maxRetryDelay,
+	} {
+		if delay := retryDelay(attempts); delay != expected {
+			t.Errorf("retryDelay(%d) = %v, expected %v", attempts, delay, expected)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package notification
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Event types.
+const (
+	Assigned     = "assigned"
+	Mentioned    = "mentioned"
+	DueSoon      = "due_soon"
+	GoalAchieved = "goal_achieved"
+)
+
+// Delivery channels. None is only a preference: nothing is stored.
+const (
+	InApp = "in_app"
+	Email = "email"
+	None  = "none"
+)
+
+// Types lists every event type.
+var Types = []string{Assigned, Mentioned, DueSoon, GoalAchieved}
+
+// Event is something that happened to a goal or one of its tasks.
+type Event struct {
+	Type string
+	// ActorID is the user who caused the event, or 0 for reminders. Actors
+	// are never notified of their own events.
+	ActorID int
+	GoalID  int
+	TaskID  *int
+}
+
+// Execer is a *sql.DB or *sql.Tx.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// insertNotifications notifies the users matching a condition appended to
+// it, skipping the actor and users whose preference for the type is none.
+const insertNotifications = `INSERT INTO notifications (user_id, type, channel, actor_id, goal_id, task_id)
+	 SELECT u.id, CAST($1 AS VARCHAR(20)), COALESCE(p.channel, 'in_app'), NULLIF(CAST($2 AS BIGINT), 0), CAST($3 AS BIGINT), CAST($4 AS BIGINT)
+	 FROM users u
+	 LEFT JOIN notification_preferences p ON p.user_id = u.id AND p.type = $1
+	 WHERE u.id <> $2
+	   AND COALESCE(p.channel, 'in_app') <> 'none'
+	   AND `
+
+// NotifyUser notifies one user. Stores call it in the transaction that
+// causes the event.
+func NotifyUser(ctx context.Context, db Execer, userID int, event Event) error {
+	_, err := db.ExecContext(ctx, insertNotifications+`u.id = $5`, event.Type, event.ActorID, event.GoalID, event.TaskID, userID)
+	return err
+}
+
+// NotifyMentions notifies the users with the given emails, matched
+// case-insensitively. Unknown emails are ignored.
+func NotifyMentions(ctx context.Context, db Execer, emails []string, event Event) error {
+	for _, email := range emails {
+		if _, err := db.ExecContext(ctx, insertNotifications+`LOWER(u.email) = $5`, event.Type, event.ActorID, event.GoalID, event.TaskID, email); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NotifyGoalMembers notifies the owner of the event's goal and the
+// assignees of its tasks.
+func NotifyGoalMembers(ctx context.Context, db Execer, event Event) error {
+	_, err := db.ExecContext(
+		ctx,
+		insertNotifications+`u.id IN (
+		     SELECT owner_id FROM goals WHERE id = $3
+		     UNION
+		     SELECT assignee_id FROM tasks WHERE goal_id = $3
+		 )`,
+		event.Type, event.ActorID, event.GoalID, event.TaskID,
+	)
+	return err
+}
+
+// mentionPattern matches "@" followed by an email address, at the start of
+// the text or after a character that cannot be part of an address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^A-Za-z0-9._%+@-])@([A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,})`)
+
+// Mentions returns the lower-cased emails mentioned in the texts, in order
+// and without duplicates.
+func Mentions(texts ...string) []string {
+	var emails []string
+	for _, text := range texts {
+		for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+			email := strings.ToLower(match[1])
+			if !slices.Contains(emails, email) {
+				emails = append(emails, email)
+			}
+		}
+	}
+	return emails
+}
+
+// NewMentions returns the emails mentioned in after but not in before.
+func NewMentions(before, after []string) []string {
+	var emails []string
+	for _, email := range after {
+		if !slices.Contains(before, email) {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+// Describe renders the message of a notification.
+func Describe(n *types.Notification) string {
+	actor := n.ActorName
+	if actor == "" {
+		actor = "Someone"
+	}
+	switch n.Type {
+	case Assigned:
+		return fmt.Sprintf(`%s assigned you "%s" in "%s"`, actor, n.TaskTitle, n.GoalTitle)
+	case Mentioned:
+		return fmt.Sprintf(`%s mentioned you in "%s"`, actor, n.TaskTitle)
+	case DueSoon:
+		if n.DueDate != nil {
+			return fmt.Sprintf(`"%s" is due on %s`, n.TaskTitle, *n.DueDate)
+		}
+		return fmt.Sprintf(`"%s" is due soon`, n.TaskTitle)
+	case GoalAchieved:
+		return fmt.Sprintf(`Goal "%s" was achieved`, n.GoalTitle)
+	}
+	return n.Type
+}
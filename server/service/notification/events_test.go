@@ -0,0 +1,46 @@
+package notification
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"slices"
+	"testing"
+)
+
+func TestMentions(t *testing.T) {
+	tests := []struct {
+		texts []string
+		want  []string
+	}{
+		{[]string{"ask @Bob@Example.com"}, []string{"bob@example.com"}},
+		{[]string{"@ada@example.com, then @bob@example.com.", "cc @ADA@example.com"}, []string{"ada@example.com", "bob@example.com"}},
+		{[]string{"mail ada@example.com", "a@@b.example.com", "x@ada@example.com"}, nil},
+		{[]string{"(@cat+tasks@mail.example.org)"}, []string{"cat+tasks@mail.example.org"}},
+	}
+	for _, tt := range tests {
+		if got := Mentions(tt.texts...); !slices.Equal(got, tt.want) {
+			t.Errorf("Mentions(%q) = %q, want %q", tt.texts, got, tt.want)
+		}
+	}
+
+	if got := NewMentions([]string{"ada@example.com"}, []string{"ada@example.com", "bob@example.com"}); !slices.Equal(got, []string{"bob@example.com"}) {
+		t.Errorf("NewMentions = %q, want only the new mention", got)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	due := "2026-03-31"
+	tests := []struct {
+		n    types.Notification
+		want string
+	}{
+		{types.Notification{Type: Assigned, ActorName: "Ada Lovelace", TaskTitle: "Write docs", GoalTitle: "Launch"}, `Ada Lovelace assigned you "Write docs" in "Launch"`},
+		{types.Notification{Type: Mentioned, TaskTitle: "Write docs"}, `Someone mentioned you in "Write docs"`},
+		{types.Notification{Type: DueSoon, TaskTitle: "Write docs", DueDate: &due}, `"Write docs" is due on 2026-03-31`},
+		{types.Notification{Type: GoalAchieved, GoalTitle: "Launch"}, `Goal "Launch" was achieved`},
+	}
+	for _, tt := range tests {
+		if got := Describe(&tt.n); got != tt.want {
+			t.Errorf("Describe(%s) = %q, want %q", tt.n.Type, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package notification
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Page sizes of GET /notifications.
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+type Handler struct {
+	store types.NotificationStore
+}
+
+func NewHandler(store types.NotificationStore) *Handler {
+	return &Handler{store: store}
+}
+
+// HandleListNotifications godoc
+// @Summary List notifications
+// @Description List the caller's in-app notifications, newest first, with the number of unread ones. Pass the id of the last notification as before to get the next page.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Param unread query bool false "Only unread notifications"
+// @Param before query int false "Only notifications with a smaller id"
+// @Param limit query int false "Page size, 1 to 200 (default 50)"
+// @Success 200 {object} types.NotificationList
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /notifications [get]
+func (h *Handler) HandleListNotifications(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	query := types.NotificationQuery{Limit: defaultLimit}
+	values := r.URL.Query()
+	if value := values.Get("unread"); value != "" {
+		var err error
+		if query.UnreadOnly, err = strconv.ParseBool(value); err != nil {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid unread %q", value))
+			return
+		}
+	}
+	if value := values.Get("before"); value != "" {
+		before, err := strconv.Atoi(value)
+		if err != nil || before <= 0 {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid before, expected a notification id"))
+			return
+		}
+		query.BeforeID = before
+	}
+	if value := values.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 || limit > maxLimit {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid limit, expected 1 to %d", maxLimit))
+			return
+		}
+		query.Limit = limit
+	}
+
+	list, err := h.store.ListNotifications(r.Context(), userID, query)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	for _, n := range list.Notifications {
+		n.Message = Describe(n)
+	}
+	utils.WriteJSON(w, http.StatusOK, list)
+}
+
+// HandleMarkRead godoc
+// @Summary Mark notification read
+// @Description Mark one of the caller's notifications as read. Marking a read notification again keeps its read time.
+// @Tags notifications
+// @Security BearerAuth
+// @Param notificationID path int true "Notification ID"
+// @Success 204
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /notifications/{notificationID}/read [post]
+func (h *Handler) HandleMarkRead(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	notificationID, err := parsePathID(r, "notificationID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid notification id"))
+		return
+	}
+
+	if err := h.store.MarkNotificationRead(r.Context(), notificationID, userID); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleMarkAllRead godoc
+// @Summary Mark all notifications read
+// @Description Mark every unread notification of the caller as read.
+// @Tags notifications
+// @Security BearerAuth
+// @Success 204
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /notifications/read-all [post]
+func (h *Handler) HandleMarkAllRead(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	if err := h.store.MarkAllNotificationsRead(r.Context(), userID); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGetPreferences godoc
+// @Summary Get notification preferences
+// @Description Get the caller's delivery channel for every event type: in_app, email or none. Event types default to in_app.
+// @Tags notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} types.NotificationPreference
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /notifications/preferences [get]
+func (h *Handler) HandleGetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	preferences, err := h.store.GetNotificationPreferences(r.Context(), userID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, preferences)
+}
+
+// HandleSetPreferences godoc
+// @Summary Set notification preferences
+// @Description Set the caller's delivery channel for the listed event types; other event types keep theirs. Returns every preference.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param payload body types.UpdateNotificationPreferencesPayload true "Preferences"
+// @Success 200 {array} types.NotificationPreference
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /notifications/preferences [put]
+func (h *Handler) HandleSetPreferences(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	var payload types.UpdateNotificationPreferencesPayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteJSONError(w, err)
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+
+	preferences, err := h.store.SetNotificationPreferences(r.Context(), userID, payload.Preferences)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, preferences)
+}
+
+// storeErrors maps notification store errors, including reading another
+// user's notification, to HTTP statuses.
+var storeErrors = map[error]int{
+	ErrNotFound:  http.StatusNotFound,
+	ErrForbidden: http.StatusForbidden,
+}
+
+func parsePathID(r *http.Request, key string) (int, error) {
+	value := chi.URLParam(r, key)
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return id, nil
+}
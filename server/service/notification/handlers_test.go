@@ -0,0 +1,161 @@
+package notification_test
+
+import (
+	"VyacheslavKuchumov/test-backend/handlertest"
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestNotificationInbox(t *testing.T) {
+	store, owner, assignee := seed(t)
+	router := newRouter(store)
+
+	rr := handlertest.Serve(router, http.MethodGet, "/notifications", assignee, "")
+	var list types.NotificationList
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected a list, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if list.UnreadCount != 2 || len(list.Notifications) != 2 {
+		t.Fatalf("expected two unread notifications, got %+v", list)
+	}
+	newest := list.Notifications[0]
+	if newest.Message != `Owner User assigned you "Second" in "Ship it"` {
+		t.Fatalf("unexpected message %q", newest.Message)
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/notifications?limit=1&before="+strconv.Itoa(newest.ID), assignee, "")
+	list = types.NotificationList{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil || len(list.Notifications) != 1 || list.Notifications[0].ID >= newest.ID {
+		t.Fatalf("expected the older notification, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	readPath := "/notifications/" + strconv.Itoa(newest.ID) + "/read"
+	if rr := handlertest.Serve(router, http.MethodPost, readPath, owner, ""); rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for another user's notification, got %d", rr.Code)
+	}
+	if rr := handlertest.Serve(router, http.MethodPost, "/notifications/999/read", assignee, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing notification, got %d", rr.Code)
+	}
+	if rr := handlertest.Serve(router, http.MethodPost, readPath, assignee, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = handlertest.Serve(router, http.MethodGet, "/notifications?unread=true", assignee, "")
+	list = types.NotificationList{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil || list.UnreadCount != 1 || len(list.Notifications) != 1 {
+		t.Fatalf("expected one unread notification, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	if rr := handlertest.Serve(router, http.MethodPost, "/notifications/read-all", assignee, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = handlertest.Serve(router, http.MethodGet, "/notifications", assignee, "")
+	list = types.NotificationList{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &list); err != nil || list.UnreadCount != 0 || len(list.Notifications) != 2 {
+		t.Fatalf("expected every notification read, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	store, _, assignee := seed(t)
+	router := newRouter(store)
+
+	rr := handlertest.Serve(router, http.MethodPut, "/notifications/preferences", assignee, `{"preferences":[{"type":"due_soon","channel":"email"},{"type":"assigned","channel":"none"}]}`)
+	var preferences []types.NotificationPreference
+	if err := json.Unmarshal(rr.Body.Bytes(), &preferences); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected preferences, got %d: %s", rr.Code, rr.Body.String())
+	}
+	expected := []types.NotificationPreference{
+		{Type: "assigned", Channel: "none"},
+		{Type: "mentioned", Channel: "in_app"},
+		{Type: "due_soon", Channel: "email"},
+		{Type: "goal_achieved", Channel: "in_app"},
+	}
+	if len(preferences) != len(expected) {
+		t.Fatalf("expected %+v, got %+v", expected, preferences)
+	}
+	for i := range expected {
+		if preferences[i] != expected[i] {
+			t.Fatalf("expected %+v, got %+v", expected[i], preferences[i])
+		}
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/notifications/preferences", assignee, "")
+	preferences = nil
+	if err := json.Unmarshal(rr.Body.Bytes(), &preferences); err != nil || len(preferences) != len(expected) || preferences[0].Channel != "none" {
+		t.Fatalf("expected the stored preferences, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestNotificationErrors(t *testing.T) {
+	store, _, assignee := seed(t)
+	router := newRouter(store)
+
+	for _, tt := range []struct{ method, path, body string }{
+		{http.MethodGet, "/notifications?unread=maybe", ""},
+		{http.MethodGet, "/notifications?limit=0", ""},
+		{http.MethodGet, "/notifications?limit=201", ""},
+		{http.MethodGet, "/notifications?before=abc", ""},
+		{http.MethodPost, "/notifications/abc/read", ""},
+		{http.MethodPut, "/notifications/preferences", `{"preferences":[]}`},
+		{http.MethodPut, "/notifications/preferences", `{"preferences":[{"type":"assigned","channel":"sms"}]}`},
+		{http.MethodPut, "/notifications/preferences", `{"preferences":[{"type":"liked","channel":"email"}]}`},
+		{http.MethodPut, "/notifications/preferences", `{"preferences":[{"type":"commented","channel":"email"}]}`},
+		{http.MethodPut, "/notifications/preferences", `{`},
+	} {
+		if rr := handlertest.Serve(router, tt.method, tt.path, assignee, tt.body); rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s %s %s, got %d: %s", tt.method, tt.path, tt.body, rr.Code, rr.Body.String())
+		}
+	}
+	for _, path := range []string{"/notifications", "/notifications/preferences"} {
+		if rr := handlertest.Serve(router, http.MethodGet, path, 0, ""); rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected 401 for %s without a user, got %d", path, rr.Code)
+		}
+	}
+}
+
+// seed creates a goal whose owner assigns two tasks to another user.
+func seed(t *testing.T) (*memstore.Store, int, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := memstore.New()
+	var ids []int
+	for _, u := range []types.User{
+		{FirstName: "Owner", LastName: "User", Email: "owner@example.com", Password: "hashed"},
+		{FirstName: "Assignee", LastName: "User", Email: "assignee@example.com", Password: "hashed"},
+	} {
+		if err := store.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		created, err := store.GetUserByEmail(ctx, u.Email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+	owner, assignee := ids[0], ids[1]
+
+	goal, err := store.CreateGoal(ctx, owner, types.CreateGoalPayload{Title: "Ship it", Priority: "high", Status: "todo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, title := range []string{"First", "Second"} {
+		if _, err := store.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: title, Priority: "high", AssigneeID: &assignee}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, owner, assignee
+}
+
+func newRouter(store *memstore.Store) chi.Router {
+	router := chi.NewRouter()
+	notification.RegisterRoutes(router, notification.NewHandler(store))
+	return router
+}
@@ -0,0 +1,167 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+)
+
+// Mail is a plain-text email, with an HTML alternative when HTML is set.
+type Mail struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends email.
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// LogMailer logs email instead of sending it, for development and for
+// servers without SMTP.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, mail Mail) error {
+	slog.InfoContext(ctx, "email not sent: SMTP is not configured", "to", mail.To, "subject", mail.Subject)
+	return nil
+}
+
+// SMTPTimeout bounds each SMTPMailer send, from dialling the server to
+// its reply to QUIT.
+const SMTPTimeout = 30 * time.Second
+
+// SMTPMailer sends email through an SMTP server, authenticating with PLAIN
+// when a username is set and upgrading to TLS when the server supports
+// STARTTLS, as smtp.SendMail does. A send is aborted when ctx is done or
+// after SMTPTimeout, so a hung server cannot block its caller.
+type SMTPMailer struct {
+	// Addr is the server's host:port.
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+func (m SMTPMailer) Send(ctx context.Context, mail Mail) error {
+	host, _, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return fmt.Errorf("invalid SMTP address %q: %w", m.Addr, err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, SMTPTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+	// Cancelling ctx unblocks a read or write in progress.
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	err = m.send(conn, host, mail)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		// The connection's deadline is ctx's, so ctx is done or about to be.
+		<-ctx.Done()
+		return fmt.Errorf("%w: %w", ctx.Err(), err)
+	}
+	return err
+}
+
+// send runs the SMTP conversation over conn.
+func (m SMTPMailer) send(conn net.Conn, host string, mail Mail) error {
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			return err
+		}
+	}
+	if m.Username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.From); err != nil {
+		return err
+	}
+	if err := c.Rcpt(mail.To); err != nil {
+		return err
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(message(m.From, mail)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// message renders mail as an RFC 5322 message, multipart/alternative when
+// it has an HTML body.
+func message(from string, mail Mail) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", mail.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", mail.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if mail.HTML == "" {
+		b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+		b.WriteString("\r\n")
+		b.WriteString(crlf(mail.Text))
+		return []byte(b.String())
+	}
+
+	parts := multipart.NewWriter(&b)
+	fmt.Fprintf(&b, "Content-Type: multipart/alternative; boundary=%s\r\n", parts.Boundary())
+	b.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", mail.Text},
+		{"text/html; charset=utf-8", mail.HTML},
+	} {
+		// Writes to a strings.Builder do not fail.
+		w, _ := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		qp := quotedprintable.NewWriter(w)
+		qp.Write([]byte(crlf(part.body)))
+		qp.Close()
+	}
+	parts.Close()
+	return []byte(b.String())
+}
+
+// crlf normalizes line endings to CRLF.
+func crlf(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", "\r\n")
+}
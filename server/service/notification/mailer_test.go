@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMessageWithHTMLIsMultipart(t *testing.T) {
+	msg, err := mail.ReadMessage(bytes.NewReader(message("tracker@example.com", Mail{
+		To:      "bob@example.com",
+		Subject: "Your daily digest",
+		Text:    "Hello Bob,\nnothing to do.",
+		HTML:    "<p>Hello Bob,</p>",
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("expected multipart/alternative, got %q, %v", mediaType, err)
+	}
+
+	parts := multipart.NewReader(msg.Body, params["boundary"])
+	for _, expected := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", "Hello Bob,\r\nnothing to do."},
+		{"text/html; charset=utf-8", "<p>Hello Bob,</p>"},
+	} {
+		part, err := parts.NextPart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(part)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if part.Header.Get("Content-Type") != expected.contentType || string(body) != expected.body {
+			t.Fatalf("expected %s part %q, got %s %q", expected.contentType, expected.body, part.Header.Get("Content-Type"), body)
+		}
+	}
+	if _, err := parts.NextPart(); err != io.EOF {
+		t.Fatalf("expected two parts, got %v", err)
+	}
+}
+
+func TestMessageWithoutHTMLIsPlainText(t *testing.T) {
+	msg := string(message("tracker@example.com", Mail{To: "bob@example.com", Subject: "Hi", Text: "one\ntwo"}))
+	if !strings.Contains(msg, "Content-Type: text/plain; charset=utf-8\r\n\r\none\r\ntwo") {
+		t.Fatalf("expected a plain-text body, got %q", msg)
+	}
+}
+
+// fakeSMTPServer accepts one connection and hands it to serve.
+func fakeSMTPServer(t *testing.T, serve func(*textproto.Conn)) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		serve(textproto.NewConn(conn))
+	}()
+	return listener.Addr().String()
+}
+
+func TestSMTPMailerSends(t *testing.T) {
+	received := make(chan string, 1)
+	addr := fakeSMTPServer(t, func(c *textproto.Conn) {
+		c.PrintfLine("220 localhost ready")
+		var lines []string
+		for {
+			line, err := c.ReadLine()
+			if err != nil {
+				return
+			}
+			lines = append(lines, line)
+			switch {
+			case strings.HasPrefix(line, "EHLO"):
+				c.PrintfLine("250 localhost")
+			case line == "DATA":
+				c.PrintfLine("354 go ahead")
+				body, err := c.ReadDotLines()
+				if err != nil {
+					return
+				}
+				lines = append(lines, body...)
+				c.PrintfLine("250 queued")
+			case line == "QUIT":
+				c.PrintfLine("221 bye")
+				received <- strings.Join(lines, "\n")
+				return
+			default:
+				c.PrintfLine("250 ok")
+			}
+		}
+	})
+
+	mailer := SMTPMailer{Addr: addr, From: "tracker@example.com"}
+	if err := mailer.Send(context.Background(), Mail{To: "bob@example.com", Subject: "Hi", Text: "Hello"}); err != nil {
+		t.Fatal(err)
+	}
+	conversation := <-received
+	for _, expected := range []string{"MAIL FROM:<tracker@example.com>", "RCPT TO:<bob@example.com>", "Subject: Hi", "Hello"} {
+		if !strings.Contains(conversation, expected) {
+			t.Fatalf("expected %q in the conversation, got %q", expected, conversation)
+		}
+	}
+}
+
+func TestSMTPMailerStopsWhenCancelled(t *testing.T) {
+	// The server accepts the connection but never greets.
+	hung := make(chan struct{})
+	t.Cleanup(func() { close(hung) })
+	addr := fakeSMTPServer(t, func(*textproto.Conn) { <-hung })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- SMTPMailer{Addr: addr, From: "tracker@example.com"}.Send(ctx, Mail{To: "bob@example.com"})
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Send to return once its context expired")
+	}
+}
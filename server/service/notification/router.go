@@ -0,0 +1,15 @@
+package notification
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Route("/notifications", func(r chi.Router) {
+		r.Get("/", handler.HandleListNotifications)
+		r.Post("/read-all", handler.HandleMarkAllRead)
+		r.Post("/{notificationID}/read", handler.HandleMarkRead)
+		r.Get("/preferences", handler.HandleGetPreferences)
+		r.Put("/preferences", handler.HandleSetPreferences)
+	})
+}
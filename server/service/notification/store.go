@@ -0,0 +1,370 @@
+package notification
+
+import (
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned when a notification does not exist or is not
+	// an in-app notification.
+	ErrNotFound = errors.New("not found")
+	// ErrForbidden is returned when the notification belongs to another
+	// user.
+	ErrForbidden = errors.New("forbidden")
+)
+
+type Store struct {
+	db     *sql.DB
+	sqlite bool
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// NewSQLiteStore returns a store for a database opened with
+// db.NewSQLiteStorage, which compares timestamps with julianday.
+func NewSQLiteStore(db *sql.DB) *Store {
+	return &Store{db: db, sqlite: true}
+}
+
+// notificationColumns are read by scanNotification, from notifications n
+// joined with goals g, tasks t and the actor a.
+const notificationColumns = `n.id, n.type, n.actor_id, TRIM(CONCAT(a.first_name, ' ', a.last_name)), n.goal_id, g.title, n.task_id, COALESCE(t.title, ''), CAST(t.due_date AS TEXT), n.read_at, n.created_at`
+
+const notificationJoins = `JOIN goals g ON g.id = n.goal_id
+		 LEFT JOIN tasks t ON t.id = n.task_id
+		 LEFT JOIN users a ON a.id = n.actor_id`
+
+func (s *Store) ListNotifications(ctx context.Context, userID int, query types.NotificationQuery) (*types.NotificationList, error) {
+	ctx = tracing.WithStatementName(ctx, "notification.ListNotifications")
+	list := &types.NotificationList{Notifications: []*types.Notification{}}
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND channel = 'in_app' AND read_at IS NULL`,
+		userID,
+	).Scan(&list.UnreadCount)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT `+notificationColumns+`
+		 FROM notifications n
+		 `+notificationJoins+`
+		 WHERE n.user_id = $1
+		   AND n.channel = 'in_app'
+		   AND ($2 = FALSE OR n.read_at IS NULL)
+		   AND ($3 = 0 OR n.id < $3)
+		 ORDER BY n.id DESC
+		 LIMIT $4`,
+		userID,
+		query.UnreadOnly,
+		query.BeforeID,
+		query.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		list.Notifications = append(list.Notifications, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *Store) MarkNotificationRead(ctx context.Context, notificationID, userID int) error {
+	ctx = tracing.WithStatementName(ctx, "notification.MarkNotificationRead")
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE notifications
+		 SET read_at = COALESCE(read_at, $3)
+		 WHERE id = $1 AND user_id = $2 AND channel = 'in_app'`,
+		notificationID,
+		userID,
+		time.Now().UTC(),
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var exists int
+	err = s.db.QueryRowContext(ctx, `SELECT 1 FROM notifications WHERE id = $1 AND channel = 'in_app'`, notificationID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return ErrForbidden
+}
+
+func (s *Store) MarkAllNotificationsRead(ctx context.Context, userID int) error {
+	ctx = tracing.WithStatementName(ctx, "notification.MarkAllNotificationsRead")
+	_, err := s.db.ExecContext(
+		ctx,
+		`UPDATE notifications
+		 SET read_at = $2
+		 WHERE user_id = $1 AND channel = 'in_app' AND read_at IS NULL`,
+		userID,
+		time.Now().UTC(),
+	)
+	return err
+}
+
+func (s *Store) GetNotificationPreferences(ctx context.Context, userID int) ([]types.NotificationPreference, error) {
+	ctx = tracing.WithStatementName(ctx, "notification.GetNotificationPreferences")
+	rows, err := s.db.QueryContext(ctx, `SELECT type, channel FROM notification_preferences WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	channels := map[string]string{}
+	for rows.Next() {
+		var eventType, channel string
+		if err := rows.Scan(&eventType, &channel); err != nil {
+			return nil, err
+		}
+		channels[eventType] = channel
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return Preferences(channels), nil
+}
+
+// Preferences lists the channel of every event type, in the order of
+// Types, defaulting to in-app.
+func Preferences(channels map[string]string) []types.NotificationPreference {
+	preferences := make([]types.NotificationPreference, 0, len(Types))
+	for _, eventType := range Types {
+		channel := channels[eventType]
+		if channel == "" {
+			channel = InApp
+		}
+		preferences = append(preferences, types.NotificationPreference{Type: eventType, Channel: channel})
+	}
+	return preferences
+}
+
+// SetNotificationPreferences changes the listed event types and returns
+// every preference.
+func (s *Store) SetNotificationPreferences(ctx context.Context, userID int, preferences []types.NotificationPreference) ([]types.NotificationPreference, error) {
+	ctx = tracing.WithStatementName(ctx, "notification.SetNotificationPreferences")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, preference := range preferences {
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO notification_preferences (user_id, type, channel)
+			 VALUES ($1, $2, $3)
+			 ON CONFLICT (user_id, type) DO UPDATE SET channel = excluded.channel`,
+			userID,
+			preference.Type,
+			preference.Channel,
+		); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return s.GetNotificationPreferences(ctx, userID)
+}
+
+// NotifyDueSoon records the reminded due date on each task in the
+// transaction that notifies its assignee, so a task is reminded of once per
+// due date however many servers run the dispatcher.
+func (s *Store) NotifyDueSoon(ctx context.Context, from, through string) (int, error) {
+	ctx = tracing.WithStatementName(ctx, "notification.NotifyDueSoon")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(
+		ctx,
+		`UPDATE tasks
+		 SET due_notified_on = due_date
+		 WHERE is_completed = FALSE
+		   AND assignee_id IS NOT NULL
+		   AND due_date >= $1
+		   AND due_date <= $2
+		   AND (due_notified_on IS NULL OR due_notified_on <> due_date)
+		 RETURNING id, goal_id, assignee_id`,
+		from,
+		through,
+	)
+	if err != nil {
+		return 0, err
+	}
+	type dueTask struct{ id, goalID, assigneeID int }
+	var due []dueTask
+	for rows.Next() {
+		var task dueTask
+		if err := rows.Scan(&task.id, &task.goalID, &task.assigneeID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		due = append(due, task)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, task := range due {
+		if err := NotifyUser(ctx, tx, task.assigneeID, Event{Type: DueSoon, GoalID: task.goalID, TaskID: &task.id}); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(due), nil
+}
+
+func (s *Store) PendingEmails(ctx context.Context, now time.Time, afterID, limit int) ([]*types.NotificationEmail, error) {
+	ctx = tracing.WithStatementName(ctx, "notification.PendingEmails")
+	retryDue := `n.retry_at <= $3`
+	if s.sqlite {
+		retryDue = `julianday(n.retry_at) <= julianday($3)`
+	}
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT `+notificationColumns+`, u.email, TRIM(CONCAT(u.first_name, ' ', u.last_name)), n.send_attempts
+		 FROM notifications n
+		 JOIN users u ON u.id = n.user_id
+		 `+notificationJoins+`
+		 WHERE n.channel = 'email'
+		   AND n.sent_at IS NULL
+		   AND (n.retry_at IS NULL OR `+retryDue+`)
+		   AND n.id > $1
+		 ORDER BY n.id
+		 LIMIT $2`,
+		afterID,
+		limit,
+		now.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	emails := []*types.NotificationEmail{}
+	for rows.Next() {
+		email := new(types.NotificationEmail)
+		n, err := scanNotification(rows, &email.To, &email.RecipientName, &email.Attempts)
+		if err != nil {
+			return nil, err
+		}
+		email.Notification = *n
+		emails = append(emails, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+func (s *Store) ClaimEmail(ctx context.Context, notificationID int, sentAt time.Time) (bool, error) {
+	ctx = tracing.WithStatementName(ctx, "notification.ClaimEmail")
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE notifications
+		 SET sent_at = $2
+		 WHERE id = $1 AND channel = 'email' AND sent_at IS NULL`,
+		notificationID,
+		sentAt.UTC(),
+	)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected > 0, nil
+}
+
+func (s *Store) ReleaseEmail(ctx context.Context, notificationID int, retryAt time.Time) error {
+	ctx = tracing.WithStatementName(ctx, "notification.ReleaseEmail")
+	_, err := s.db.ExecContext(
+		ctx,
+		`UPDATE notifications
+		 SET sent_at = NULL, send_attempts = send_attempts + 1, retry_at = $2
+		 WHERE id = $1`,
+		notificationID,
+		retryAt.UTC(),
+	)
+	return err
+}
+
+// scanNotification scans notificationColumns followed by extra.
+func scanNotification(rows *sql.Rows, extra ...any) (*types.Notification, error) {
+	var (
+		n       types.Notification
+		actorID sql.NullInt64
+		taskID  sql.NullInt64
+		dueDate sql.NullString
+		readAt  sql.NullTime
+	)
+	dest := append([]any{
+		&n.ID,
+		&n.Type,
+		&actorID,
+		&n.ActorName,
+		&n.GoalID,
+		&n.GoalTitle,
+		&taskID,
+		&n.TaskTitle,
+		&dueDate,
+		&readAt,
+		&n.CreatedAt,
+	}, extra...)
+	if err := rows.Scan(dest...); err != nil {
+		return nil, err
+	}
+	if actorID.Valid {
+		id := int(actorID.Int64)
+		n.ActorID = &id
+	}
+	if taskID.Valid {
+		id := int(taskID.Int64)
+		n.TaskID = &id
+	}
+	if dueDate.Valid {
+		n.DueDate = &dueDate.String
+	}
+	if readAt.Valid {
+		n.ReadAt = &readAt.Time
+	}
+	return &n, nil
+}
@@ -0,0 +1,312 @@
+package report
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+const (
+	// defaultBurnUpDays is the length of a burn-up without from.
+	defaultBurnUpDays = 30
+	// maxBurnUpDays bounds the points of one burn-up.
+	maxBurnUpDays = 366
+)
+
+// Report ranges without from or to are open on that side.
+var (
+	minReportTime = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+	maxReportTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+type Handler struct {
+	store             types.ReportStore
+	overloadThreshold int
+	now               func() time.Time
+}
+
+// NewHandler returns a handler that reports users with more than
+// overloadThreshold open tasks unless the request sets its own threshold.
+func NewHandler(store types.ReportStore, overloadThreshold int) *Handler {
+	return &Handler{store: store, overloadThreshold: overloadThreshold, now: time.Now}
+}
+
+// HandleCompletedPerWeek godoc
+// @Summary Tasks completed per user per week
+// @Description Completed assigned tasks per assignee and week, by completion time. Weeks start on Monday (UTC). from and to are inclusive UTC dates and default to all time.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param format query string false "json (default) or csv" Enums(json, csv)
+// @Success 200 {array} types.WeeklyCompletion
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /reports/completed-per-week [get]
+func (h *Handler) HandleCompletedPerWeek(w http.ResponseWriter, r *http.Request) {
+	format, ok := h.begin(w, r)
+	if !ok {
+		return
+	}
+	from, to, err := parseRange(r, minReportTime, maxReportTime)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	weeks, err := h.store.CompletedPerWeek(r.Context(), from, to)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	records := [][]string{{"weekStart", "userId", "name", "completed"}}
+	for _, week := range weeks {
+		records = append(records, []string{week.WeekStart, strconv.Itoa(week.UserID), week.Name, strconv.Itoa(week.Completed)})
+	}
+	h.write(w, format, "completed-per-week", weeks, records)
+}
+
+// HandleOpenByPriority godoc
+// @Summary Open tasks by priority
+// @Description Number of open tasks per priority, from high to low.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param format query string false "json (default) or csv" Enums(json, csv)
+// @Success 200 {array} types.PriorityCount
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /reports/open-by-priority [get]
+func (h *Handler) HandleOpenByPriority(w http.ResponseWriter, r *http.Request) {
+	format, ok := h.begin(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := h.store.OpenTasksByPriority(r.Context())
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	records := [][]string{{"priority", "openTasks"}}
+	for _, count := range counts {
+		records = append(records, []string{count.Priority, strconv.Itoa(count.OpenTasks)})
+	}
+	h.write(w, format, "open-by-priority", counts, records)
+}
+
+// HandleCycleTime godoc
+// @Summary Average cycle time
+// @Description Average time from creation to completion of the tasks completed in the range. from and to are inclusive UTC dates and default to all time.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param format query string false "json (default) or csv" Enums(json, csv)
+// @Success 200 {object} types.CycleTimeReport
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /reports/cycle-time [get]
+func (h *Handler) HandleCycleTime(w http.ResponseWriter, r *http.Request) {
+	format, ok := h.begin(w, r)
+	if !ok {
+		return
+	}
+	from, to, err := parseRange(r, minReportTime, maxReportTime)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := h.store.CycleTime(r.Context(), from, to)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	records := [][]string{
+		{"completedTasks", "averageSeconds"},
+		{strconv.Itoa(report.CompletedTasks), strconv.Itoa(report.AverageSeconds)},
+	}
+	h.write(w, format, "cycle-time", report, records)
+}
+
+// HandleGoalBurnUp godoc
+// @Summary Goal burn-up
+// @Description Total and completed tasks of a goal at the end of each day. from and to are inclusive UTC dates; the range defaults to the 30 days ending today and spans at most 366 days. Only the goal's current tasks count.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param goalID path int true "Goal ID"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param format query string false "json (default) or csv" Enums(json, csv)
+// @Success 200 {array} types.BurnUpPoint
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /reports/goals/{goalID}/burnup [get]
+func (h *Handler) HandleGoalBurnUp(w http.ResponseWriter, r *http.Request) {
+	format, ok := h.begin(w, r)
+	if !ok {
+		return
+	}
+	goalID, err := parsePathID(r, "goalID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid goal id"))
+		return
+	}
+
+	from, to, err := parseRange(r, minReportTime, utcDay(h.now()).AddDate(0, 0, 1))
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+	if r.URL.Query().Get("from") == "" {
+		from = to.AddDate(0, 0, -defaultBurnUpDays)
+	}
+	if to.After(from.AddDate(0, 0, maxBurnUpDays)) {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("a burn-up spans at most %d days", maxBurnUpDays))
+		return
+	}
+
+	points, err := h.store.GoalBurnUp(r.Context(), goalID, from, to.AddDate(0, 0, -1))
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	records := [][]string{{"date", "totalTasks", "completedTasks"}}
+	for _, point := range points {
+		records = append(records, []string{point.Date, strconv.Itoa(point.TotalTasks), strconv.Itoa(point.CompletedTasks)})
+	}
+	h.write(w, format, fmt.Sprintf("goal-%d-burnup", goalID), points, records)
+}
+
+// HandleOverloaded godoc
+// @Summary Overloaded users
+// @Description Users with more open assigned tasks than the threshold, most loaded first. The threshold defaults to REPORT_OVERLOAD_THRESHOLD.
+// @Tags reports
+// @Produce json
+// @Produce text/csv
+// @Security BearerAuth
+// @Param threshold query int false "Open task threshold"
+// @Param format query string false "json (default) or csv" Enums(json, csv)
+// @Success 200 {object} types.OverloadReport
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /reports/overloaded [get]
+func (h *Handler) HandleOverloaded(w http.ResponseWriter, r *http.Request) {
+	format, ok := h.begin(w, r)
+	if !ok {
+		return
+	}
+	threshold := h.overloadThreshold
+	if value := r.URL.Query().Get("threshold"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid threshold, expected a non-negative integer"))
+			return
+		}
+		threshold = parsed
+	}
+
+	users, err := h.store.OverloadedUsers(r.Context(), threshold)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	records := [][]string{{"userId", "name", "openTasks"}}
+	for _, user := range users {
+		records = append(records, []string{strconv.Itoa(user.UserID), user.Name, strconv.Itoa(user.OpenTasks)})
+	}
+	h.write(w, format, "overloaded", &types.OverloadReport{Threshold: threshold, Users: users}, records)
+}
+
+// begin authenticates the request and parses its format, writing the error
+// response when either fails.
+func (h *Handler) begin(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if auth.GetUserIDFromContext(r.Context()) <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return "", false
+	}
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		return "json", true
+	case "csv":
+		return format, true
+	default:
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("unsupported format %q, use json or csv", format))
+		return "", false
+	}
+}
+
+// write sends body as JSON, or records, the first being the header, as a
+// CSV attachment.
+func (h *Handler) write(w http.ResponseWriter, format, name string, body any, records [][]string) {
+	if format != "csv" {
+		utils.WriteJSON(w, http.StatusOK, body)
+		return
+	}
+	date := h.now().UTC().Format(time.DateOnly)
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="task-tracker-%s-%s.csv"`, name, date))
+	w.WriteHeader(http.StatusOK)
+	// The status is sent; a failed write can only be a gone client.
+	_ = csv.NewWriter(w).WriteAll(records)
+}
+
+// parseRange turns the inclusive from and to dates into the half-open range
+// [from, to+1 day), using the defaults for missing dates.
+func parseRange(r *http.Request, from, to time.Time) (time.Time, time.Time, error) {
+	if value := r.URL.Query().Get("from"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+		}
+		if parsed.Before(maxReportTime) {
+			to = parsed.AddDate(0, 0, 1)
+		}
+	}
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from must not be after to")
+	}
+	return from, to, nil
+}
+
+// storeErrors gives the status for a burn-up of a goal that does not exist.
+var storeErrors = map[error]int{
+	ErrNotFound: http.StatusNotFound,
+}
+
+func parsePathID(r *http.Request, key string) (int, error) {
+	value := chi.URLParam(r, key)
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return id, nil
+}
@@ -0,0 +1,188 @@
+package report_test
+
+import (
+	"VyacheslavKuchumov/test-backend/handlertest"
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/report"
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestReportsJSON(t *testing.T) {
+	store, owner, goalID := seed(t)
+	router := newRouter(store, 1)
+
+	rr := handlertest.Serve(router, http.MethodGet, "/reports/completed-per-week", owner, "")
+	var weeks []types.WeeklyCompletion
+	if err := json.Unmarshal(rr.Body.Bytes(), &weeks); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected weeks, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(weeks) != 1 || weeks[0].UserID != owner || weeks[0].Name != "Owner User" || weeks[0].Completed != 1 {
+		t.Fatalf("unexpected weeks %+v", weeks)
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/reports/open-by-priority", owner, "")
+	var counts []types.PriorityCount
+	if err := json.Unmarshal(rr.Body.Bytes(), &counts); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected counts, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if len(counts) != 3 || counts[0] != (types.PriorityCount{Priority: "high", OpenTasks: 2}) || counts[2].OpenTasks != 0 {
+		t.Fatalf("unexpected counts %+v", counts)
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/reports/cycle-time?from=2026-01-01", owner, "")
+	var cycle types.CycleTimeReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &cycle); err != nil || rr.Code != http.StatusOK || cycle.CompletedTasks != 1 {
+		t.Fatalf("expected one completed task, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	// The configured threshold applies unless the request sets one.
+	rr = handlertest.Serve(router, http.MethodGet, "/reports/overloaded", owner, "")
+	var overloaded types.OverloadReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &overloaded); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected a report, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if overloaded.Threshold != 1 || len(overloaded.Users) != 1 || overloaded.Users[0].OpenTasks != 2 {
+		t.Fatalf("unexpected report %+v", overloaded)
+	}
+	rr = handlertest.Serve(router, http.MethodGet, "/reports/overloaded?threshold=2", owner, "")
+	overloaded = types.OverloadReport{}
+	if err := json.Unmarshal(rr.Body.Bytes(), &overloaded); err != nil || overloaded.Threshold != 2 || overloaded.Users == nil || len(overloaded.Users) != 0 {
+		t.Fatalf("expected no overloaded users, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	goalPath := "/reports/goals/" + strconv.Itoa(goalID) + "/burnup"
+	rr = handlertest.Serve(router, http.MethodGet, goalPath, owner, "")
+	var points []types.BurnUpPoint
+	if err := json.Unmarshal(rr.Body.Bytes(), &points); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected points, got %d: %s", rr.Code, rr.Body.String())
+	}
+	today := time.Now().UTC().Format(time.DateOnly)
+	if len(points) != 30 || points[29].Date != today || points[29].TotalTasks != 3 || points[29].CompletedTasks != 1 {
+		t.Fatalf("expected 30 days ending today, got %+v", points)
+	}
+	rr = handlertest.Serve(router, http.MethodGet, goalPath+"?to=2026-03-10", owner, "")
+	points = nil
+	if err := json.Unmarshal(rr.Body.Bytes(), &points); err != nil || len(points) != 30 ||
+		points[0].Date != "2026-02-09" || points[29].Date != "2026-03-10" {
+		t.Fatalf("expected the 30 days ending to, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestReportsCSV(t *testing.T) {
+	store, owner, goalID := seed(t)
+	router := newRouter(store, 10)
+
+	rr := handlertest.Serve(router, http.MethodGet, "/reports/open-by-priority?format=csv", owner, "")
+	if rr.Code != http.StatusOK || !strings.HasPrefix(rr.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("expected CSV, got %d %q", rr.Code, rr.Header().Get("Content-Type"))
+	}
+	if disposition := rr.Header().Get("Content-Disposition"); !strings.Contains(disposition, "task-tracker-open-by-priority-") {
+		t.Fatalf("unexpected Content-Disposition %q", disposition)
+	}
+	records, err := csv.NewReader(rr.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{"priority", "openTasks"}, {"high", "2"}, {"medium", "0"}, {"low", "0"}}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, records)
+	}
+	for i := range expected {
+		if strings.Join(records[i], ",") != strings.Join(expected[i], ",") {
+			t.Fatalf("row %d: expected %v, got %v", i, expected[i], records[i])
+		}
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/reports/goals/"+strconv.Itoa(goalID)+"/burnup?from=2026-03-01&to=2026-03-03&format=csv", owner, "")
+	records, err = csv.NewReader(rr.Body).ReadAll()
+	if err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected CSV, got %d: %v", rr.Code, err)
+	}
+	if len(records) != 4 || records[0][0] != "date" || records[1][0] != "2026-03-01" || records[3][0] != "2026-03-03" {
+		t.Fatalf("unexpected burn-up %v", records)
+	}
+}
+
+func TestReportErrors(t *testing.T) {
+	store, owner, goalID := seed(t)
+	router := newRouter(store, 10)
+	goalPath := "/reports/goals/" + strconv.Itoa(goalID) + "/burnup"
+
+	for _, path := range []string{
+		"/reports/open-by-priority?format=xml",
+		"/reports/completed-per-week?from=March",
+		"/reports/cycle-time?from=2026-04-02&to=2026-04-01",
+		"/reports/overloaded?threshold=-1",
+		"/reports/overloaded?threshold=many",
+		goalPath + "?from=2025-01-01&to=2026-03-01",
+		"/reports/goals/abc/burnup",
+	} {
+		if rr := handlertest.Serve(router, http.MethodGet, path, owner, ""); rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d: %s", path, rr.Code, rr.Body.String())
+		}
+	}
+	if rr := handlertest.Serve(router, http.MethodGet, "/reports/goals/999/burnup", owner, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing goal, got %d", rr.Code)
+	}
+	if rr := handlertest.Serve(router, http.MethodGet, "/reports/open-by-priority", 0, ""); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a user, got %d", rr.Code)
+	}
+	// A year, leap day included, is one burn-up.
+	if rr := handlertest.Serve(router, http.MethodGet, goalPath+"?from=2024-01-01&to=2024-12-31", owner, ""); rr.Code != http.StatusOK {
+		t.Fatalf("expected 200 for 366 days, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+// seed creates a goal with three tasks assigned to its owner, one of them
+// completed and two open and high priority.
+func seed(t *testing.T) (*memstore.Store, int, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := memstore.New()
+	if err := store.CreateUser(ctx, types.User{FirstName: "Owner", LastName: "User", Email: "owner@example.com", Password: "hashed"}); err != nil {
+		t.Fatal(err)
+	}
+	owner, err := store.GetUserByEmail(ctx, "owner@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goal, err := store.CreateGoal(ctx, owner.ID, types.CreateGoalPayload{Title: "Ship it", Priority: "high", Status: "todo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, priority := range []string{"low", "high", "high"} {
+		task, err := store.CreateTask(ctx, goal.ID, owner.ID, types.CreateTaskPayload{Title: "Task", Priority: priority, AssigneeID: &owner.ID})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			if _, err := store.UpdateTask(ctx, task.ID, owner.ID, types.UpdateTaskPayload{
+				GoalID: goal.ID, Title: "Task", Priority: priority, AssigneeID: &owner.ID, IsCompleted: true,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return store, owner.ID, goal.ID
+}
+
+// newRouter mounts the reports next to the tracker, whose /goals subrouter
+// must not shadow them.
+func newRouter(store *memstore.Store, overloadThreshold int) chi.Router {
+	router := chi.NewRouter()
+	tracker.RegisterRoutes(router, tracker.NewHandler(store))
+	report.RegisterRoutes(router, report.NewHandler(store, overloadThreshold))
+	return router
+}
@@ -0,0 +1,15 @@
+package report
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Route("/reports", func(r chi.Router) {
+		r.Get("/completed-per-week", handler.HandleCompletedPerWeek)
+		r.Get("/open-by-priority", handler.HandleOpenByPriority)
+		r.Get("/cycle-time", handler.HandleCycleTime)
+		r.Get("/goals/{goalID}/burnup", handler.HandleGoalBurnUp)
+		r.Get("/overloaded", handler.HandleOverloaded)
+	})
+}
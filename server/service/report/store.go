@@ -0,0 +1,233 @@
+package report
+
+import (
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+// ErrNotFound is returned when the goal of a burn-up does not exist.
+var ErrNotFound = errors.New("not found")
+
+// Priorities lists task priorities from most to least urgent, the order of
+// OpenTasksByPriority.
+var Priorities = []string{"high", "medium", "low"}
+
+// Store computes every report in SQL. Tasks completed before completed_at
+// was recorded are missing from the weekly and cycle time reports and count
+// as done from their creation in burn-ups.
+type Store struct {
+	db     *sql.DB
+	sqlite bool
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// NewSQLiteStore returns a store for a database opened with
+// db.NewSQLiteStorage.
+func NewSQLiteStore(db *sql.DB) *Store {
+	return &Store{db: db, sqlite: true}
+}
+
+func (s *Store) CompletedPerWeek(ctx context.Context, from, to time.Time) ([]*types.WeeklyCompletion, error) {
+	ctx = tracing.WithStatementName(ctx, "report.CompletedPerWeek")
+	query := `SELECT u.id, TRIM(CONCAT(u.first_name, ' ', u.last_name)),
+		        TO_CHAR(DATE_TRUNC('week', t.completed_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD') AS week_start,
+		        COUNT(*)
+		 FROM tasks t
+		 JOIN users u ON u.id = t.assignee_id
+		 WHERE t.completed_at >= $1 AND t.completed_at < $2
+		 GROUP BY u.id, u.first_name, u.last_name, week_start
+		 ORDER BY week_start, u.first_name, u.last_name, u.id`
+	if s.sqlite {
+		// Sunday closes the week, so the Monday before it opens it.
+		query = `SELECT u.id, TRIM(CONCAT(u.first_name, ' ', u.last_name)),
+		        date(t.completed_at, 'weekday 0', '-6 days') AS week_start,
+		        COUNT(*)
+		 FROM tasks t
+		 JOIN users u ON u.id = t.assignee_id
+		 WHERE julianday(t.completed_at) >= julianday($1) AND julianday(t.completed_at) < julianday($2)
+		 GROUP BY u.id, u.first_name, u.last_name, week_start
+		 ORDER BY week_start, u.first_name, u.last_name, u.id`
+	}
+	rows, err := s.db.QueryContext(ctx, query, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	weeks := []*types.WeeklyCompletion{}
+	for rows.Next() {
+		week := new(types.WeeklyCompletion)
+		if err := rows.Scan(&week.UserID, &week.Name, &week.WeekStart, &week.Completed); err != nil {
+			return nil, err
+		}
+		weeks = append(weeks, week)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return weeks, nil
+}
+
+func (s *Store) OpenTasksByPriority(ctx context.Context) ([]*types.PriorityCount, error) {
+	ctx = tracing.WithStatementName(ctx, "report.OpenTasksByPriority")
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT priority, COUNT(*)
+		 FROM tasks
+		 WHERE is_completed = FALSE
+		 GROUP BY priority`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := map[string]int{}
+	for rows.Next() {
+		var (
+			priority string
+			count    int
+		)
+		if err := rows.Scan(&priority, &count); err != nil {
+			return nil, err
+		}
+		counts[priority] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return ByPriority(counts), nil
+}
+
+// ByPriority lists the counts in priority order, including zeros.
+func ByPriority(counts map[string]int) []*types.PriorityCount {
+	result := make([]*types.PriorityCount, 0, len(Priorities))
+	for _, priority := range Priorities {
+		result = append(result, &types.PriorityCount{Priority: priority, OpenTasks: counts[priority]})
+	}
+	return result
+}
+
+func (s *Store) CycleTime(ctx context.Context, from, to time.Time) (*types.CycleTimeReport, error) {
+	ctx = tracing.WithStatementName(ctx, "report.CycleTime")
+	query := `SELECT COUNT(*),
+		        CAST(COALESCE(ROUND(AVG(EXTRACT(EPOCH FROM (completed_at - created_at)))), 0) AS BIGINT)
+		 FROM tasks
+		 WHERE completed_at >= $1 AND completed_at < $2`
+	if s.sqlite {
+		query = `SELECT COUNT(*),
+		        CAST(COALESCE(ROUND(AVG((julianday(completed_at) - julianday(created_at)) * 86400)), 0) AS INTEGER)
+		 FROM tasks
+		 WHERE julianday(completed_at) >= julianday($1) AND julianday(completed_at) < julianday($2)`
+	}
+	report := &types.CycleTimeReport{}
+	err := s.db.QueryRowContext(ctx, query, from.UTC(), to.UTC()).Scan(&report.CompletedTasks, &report.AverageSeconds)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// GoalBurnUp counts the goal's current tasks as they stood at the end of
+// each day; deleted tasks are not remembered.
+func (s *Store) GoalBurnUp(ctx context.Context, goalID int, from, to time.Time) ([]*types.BurnUpPoint, error) {
+	ctx = tracing.WithStatementName(ctx, "report.GoalBurnUp")
+	var exists int
+	err := s.db.QueryRowContext(ctx, `SELECT 1 FROM goals WHERE id = $1`, goalID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Days are 24 hours rather than INTERVAL '1 day', which follows the
+	// session time zone across daylight saving changes.
+	first, last := utcDay(from), utcDay(to)
+	query := `SELECT TO_CHAR(d.day AT TIME ZONE 'UTC', 'YYYY-MM-DD'),
+		        COUNT(t.id),
+		        COUNT(t.id) FILTER (WHERE t.is_completed AND COALESCE(t.completed_at, t.created_at) < d.day + INTERVAL '24 hours')
+		 FROM generate_series($2::timestamptz, $3::timestamptz, INTERVAL '24 hours') AS d(day)
+		 LEFT JOIN tasks t ON t.goal_id = $1 AND t.created_at < d.day + INTERVAL '24 hours'
+		 GROUP BY d.day
+		 ORDER BY d.day`
+	args := []any{goalID, first, last}
+	if s.sqlite {
+		query = `WITH RECURSIVE days(day) AS (
+		     SELECT date($2)
+		     UNION ALL
+		     SELECT date(day, '+1 day') FROM days WHERE day < date($3)
+		 )
+		 SELECT d.day,
+		        COUNT(t.id),
+		        COALESCE(SUM(CASE WHEN t.is_completed AND julianday(COALESCE(t.completed_at, t.created_at)) < julianday(d.day, '+1 day') THEN 1 ELSE 0 END), 0)
+		 FROM days d
+		 LEFT JOIN tasks t ON t.goal_id = $1 AND julianday(t.created_at) < julianday(d.day, '+1 day')
+		 GROUP BY d.day
+		 ORDER BY d.day`
+		args = []any{goalID, first.Format(time.DateOnly), last.Format(time.DateOnly)}
+	}
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	points := []*types.BurnUpPoint{}
+	for rows.Next() {
+		point := new(types.BurnUpPoint)
+		if err := rows.Scan(&point.Date, &point.TotalTasks, &point.CompletedTasks); err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+func (s *Store) OverloadedUsers(ctx context.Context, threshold int) ([]*types.UserLoad, error) {
+	ctx = tracing.WithStatementName(ctx, "report.OverloadedUsers")
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT u.id, TRIM(CONCAT(u.first_name, ' ', u.last_name)), COUNT(*)
+		 FROM tasks t
+		 JOIN users u ON u.id = t.assignee_id
+		 WHERE t.is_completed = FALSE
+		 GROUP BY u.id, u.first_name, u.last_name
+		 HAVING COUNT(*) > $1
+		 ORDER BY COUNT(*) DESC, u.first_name, u.last_name, u.id`,
+		threshold,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*types.UserLoad{}
+	for rows.Next() {
+		user := new(types.UserLoad)
+		if err := rows.Scan(&user.UserID, &user.Name, &user.OpenTasks); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// utcDay truncates t to the start of its UTC day.
+func utcDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,259 @@
+package template
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-playground/validator/v10"
+)
+
+type Handler struct {
+	store        types.TemplateStore
+	trackerStore types.GoalTaskStore
+	importStore  types.ImportStore
+	now          func() time.Time
+}
+
+// NewHandler returns the template handler. Instantiating a template creates
+// its goal and tasks through importStore, in one transaction.
+func NewHandler(store types.TemplateStore, trackerStore types.GoalTaskStore, importStore types.ImportStore) *Handler {
+	return &Handler{store: store, trackerStore: trackerStore, importStore: importStore, now: time.Now}
+}
+
+// HandleListTemplates godoc
+// @Summary List templates
+// @Description List the caller's goal templates with their tasks, ordered by name.
+// @Tags templates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} types.Template
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /templates [get]
+func (h *Handler) HandleListTemplates(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	templates, err := h.store.ListTemplates(r.Context(), userID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, templates)
+}
+
+// HandleCreateTemplate godoc
+// @Summary Create template
+// @Description Define a goal template, or save an existing goal and its tasks as one with fromGoalId. Titles and descriptions may contain placeholders, a name in double braces; when saving a goal, placeholders maps each name to the text it replaces, such as {"client": "Acme"}. Due dates are day offsets from the date the template is instantiated; a saved goal's dates count from the day it was created.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param payload body types.CreateTemplatePayload true "Template payload"
+// @Success 201 {object} types.Template
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /templates [post]
+func (h *Handler) HandleCreateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	var payload types.CreateTemplatePayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteJSONError(w, err)
+		return
+	}
+
+	if payload.FromGoalID != nil {
+		if payload.Title != "" || payload.Description != "" || payload.Priority != "" || payload.DueInDays != nil || payload.Tasks != nil {
+			utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("fromGoalId cannot be combined with title, description, priority, dueInDays or tasks"))
+			return
+		}
+		goal, err := h.trackerStore.GetGoalWithTasks(r.Context(), *payload.FromGoalID, userID)
+		if err != nil {
+			utils.WriteStoreError(w, err, storeErrors)
+			return
+		}
+		fromGoal(&payload, goal)
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+
+	template, err := h.store.CreateTemplate(r.Context(), userID, payload)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, template)
+}
+
+// HandleDeleteTemplate godoc
+// @Summary Delete template
+// @Description Delete one of the caller's templates. Goals created from it are kept.
+// @Tags templates
+// @Security BearerAuth
+// @Param templateID path int true "Template ID"
+// @Success 204
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /templates/{templateID} [delete]
+func (h *Handler) HandleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	templateID, err := parsePathID(r, "templateID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid template id"))
+		return
+	}
+
+	if err := h.store.DeleteTemplate(r.Context(), templateID, userID); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleInstantiateTemplate godoc
+// @Summary Instantiate template
+// @Description Create a goal owned by the caller, with its tasks, from one of the caller's templates. Placeholders are replaced by values, and every placeholder the template uses needs a value. Due dates are the template's offsets added to startDate, which defaults to today (UTC). The goal starts as todo and its tasks open and unassigned. The body may be omitted for templates without placeholders.
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param templateID path int true "Template ID"
+// @Param payload body types.InstantiateTemplatePayload false "Instantiation payload"
+// @Success 201 {object} types.GoalWithTasks
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /templates/{templateID}/instantiate [post]
+func (h *Handler) HandleInstantiateTemplate(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	templateID, err := parsePathID(r, "templateID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid template id"))
+		return
+	}
+
+	var payload types.InstantiateTemplatePayload
+	if err := utils.ParseJSON(r, &payload); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteJSONError(w, err)
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+
+	template, err := h.store.GetTemplate(r.Context(), templateID, userID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+
+	var missing []types.FieldError
+	for _, name := range placeholders(template) {
+		if _, ok := payload.Values[name]; !ok {
+			missing = append(missing, types.FieldError{
+				Field:   "values." + name,
+				Code:    "required",
+				Message: "is required by the template's {{" + name + "}} placeholder",
+			})
+		}
+	}
+	if len(missing) > 0 {
+		utils.WriteProblem(w, http.StatusBadRequest, utils.CodeValidationFailed, "missing placeholder values", missing)
+		return
+	}
+
+	start := h.now().UTC()
+	if payload.StartDate != nil {
+		start, _ = time.Parse(time.DateOnly, *payload.StartDate)
+	}
+	goal := instantiate(template, start, payload.Values)
+	if fieldErrors := validateGoal(goal); len(fieldErrors) > 0 {
+		utils.WriteProblem(w, http.StatusBadRequest, utils.CodeValidationFailed, "the filled-in template is invalid", fieldErrors)
+		return
+	}
+
+	ids, err := h.importStore.CreateGoalsWithTasks(r.Context(), userID, []types.ImportedGoal{goal})
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	created, err := h.trackerStore.GetGoalWithTasks(r.Context(), ids[0], userID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, created)
+}
+
+// validateGoal checks an instantiated goal, since filled-in placeholders can
+// push titles past their maximum length. Fields are reported as title or
+// tasks[i].title.
+func validateGoal(goal types.ImportedGoal) []types.FieldError {
+	var fieldErrors []types.FieldError
+	check := func(prefix string, payload any) {
+		var validationErrors validator.ValidationErrors
+		if errors.As(utils.Validate.Struct(payload), &validationErrors) {
+			for _, fe := range utils.FieldErrors(validationErrors) {
+				fe.Field = prefix + fe.Field
+				fieldErrors = append(fieldErrors, fe)
+			}
+		}
+	}
+	check("", goal.Goal)
+	for i, task := range goal.Tasks {
+		check("tasks["+strconv.Itoa(i)+"].", task.Task)
+	}
+	return fieldErrors
+}
+
+// storeErrors covers the template store and the tracker store the
+// template handlers read goals from.
+var storeErrors = map[error]int{
+	ErrNotFound:         http.StatusNotFound,
+	tracker.ErrNotFound: http.StatusNotFound,
+}
+
+func parsePathID(r *http.Request, key string) (int, error) {
+	value := chi.URLParam(r, key)
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return id, nil
+}
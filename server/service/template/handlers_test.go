@@ -0,0 +1,196 @@
+package template_test
+
+import (
+	"VyacheslavKuchumov/test-backend/handlertest"
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/template"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestSaveGoalAsTemplateAndInstantiate(t *testing.T) {
+	store, owner, _ := seed(t)
+	ctx := context.Background()
+
+	goal, err := store.CreateGoal(ctx, owner, types.CreateGoalPayload{Title: "Onboard Acme", Description: "New client Acme", Priority: "high", Status: "in_progress"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := goal.CreatedAt.UTC()
+	goalDue := created.AddDate(0, 0, 14).Format(time.DateOnly)
+	taskDue := created.AddDate(0, 0, 3).Format(time.DateOnly)
+	if _, err := store.UpdateGoal(ctx, goal.ID, owner, types.CreateGoalPayload{Title: goal.Title, Description: goal.Description, Priority: "high", Status: "in_progress", DueDate: &goalDue}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: "Sign contract with Acme", Priority: "high", AssigneeID: &owner, DueDate: &taskDue}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: "Create workspace", Priority: "low"}); err != nil {
+		t.Fatal(err)
+	}
+
+	router := newRouter(store)
+	rr := handlertest.Serve(router, http.MethodPost, "/templates", owner, `{"name":"Client onboarding","fromGoalId":`+strconv.Itoa(goal.ID)+`,"placeholders":{"client":"Acme"}}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var saved types.Template
+	if err := json.Unmarshal(rr.Body.Bytes(), &saved); err != nil {
+		t.Fatal(err)
+	}
+	if saved.Title != "Onboard {{client}}" || saved.Description != "New client {{client}}" || saved.Priority != "high" ||
+		saved.DueInDays == nil || *saved.DueInDays != 14 || len(saved.Tasks) != 2 ||
+		saved.Tasks[0].Title != "Sign contract with {{client}}" || *saved.Tasks[0].DueInDays != 3 || saved.Tasks[1].DueInDays != nil {
+		t.Fatalf("unexpected template %+v", saved)
+	}
+
+	rr = handlertest.Serve(router, http.MethodPost, "/templates/"+strconv.Itoa(saved.ID)+"/instantiate", owner, `{"startDate":"2026-04-01","values":{"client":"Globex"}}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var instance types.GoalWithTasks
+	if err := json.Unmarshal(rr.Body.Bytes(), &instance); err != nil {
+		t.Fatal(err)
+	}
+	if instance.ID == goal.ID || instance.Title != "Onboard Globex" || instance.Status != "todo" || instance.OwnerID != owner ||
+		instance.DueDate == nil || *instance.DueDate != "2026-04-15" || len(instance.Tasks) != 2 {
+		t.Fatalf("unexpected goal %+v", instance)
+	}
+	for _, task := range instance.Tasks {
+		if task.IsCompleted || task.AssigneeID != nil || task.CreatedBy != owner {
+			t.Fatalf("expected an open, unassigned task, got %+v", task)
+		}
+		if task.Title == "Sign contract with Globex" && (task.DueDate == nil || *task.DueDate != "2026-04-04") {
+			t.Fatalf("expected the task due three days after the start, got %+v", task)
+		}
+	}
+}
+
+func TestInstantiateChecksPlaceholderValues(t *testing.T) {
+	store, owner, _ := seed(t)
+	router := newRouter(store)
+	withPlaceholder := createTemplate(t, router, owner, `{"name":"Onboarding","title":"Onboard {{client}}","priority":"medium","tasks":[{"title":"Call {{contact}}","priority":"low"}]}`)
+	plain := createTemplate(t, router, owner, `{"name":"Audit","title":"Yearly audit","priority":"low"}`)
+
+	rr := handlertest.Serve(router, http.MethodPost, "/templates/"+strconv.Itoa(withPlaceholder.ID)+"/instantiate", owner, "")
+	problem := decodeProblem(t, rr)
+	if rr.Code != http.StatusBadRequest || len(problem.FieldErrors) != 2 ||
+		problem.FieldErrors[0].Field != "values.client" || problem.FieldErrors[1].Field != "values.contact" {
+		t.Fatalf("expected missing values to be reported, got %d: %+v", rr.Code, problem)
+	}
+
+	rr = handlertest.Serve(router, http.MethodPost, "/templates/"+strconv.Itoa(withPlaceholder.ID)+"/instantiate", owner, `{"values":{"client":"`+strings.Repeat("x", 250)+`","contact":"Ann"}}`)
+	problem = decodeProblem(t, rr)
+	if rr.Code != http.StatusBadRequest || len(problem.FieldErrors) != 1 || problem.FieldErrors[0].Field != "title" || problem.FieldErrors[0].Code != "max" {
+		t.Fatalf("expected the filled-in title to be validated, got %d: %+v", rr.Code, problem)
+	}
+
+	rr = handlertest.Serve(router, http.MethodPost, "/templates/"+strconv.Itoa(plain.ID)+"/instantiate", owner, "")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected a template without placeholders to need no body, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestCreateTemplateValidation(t *testing.T) {
+	store, owner, _ := seed(t)
+	router := newRouter(store)
+
+	for body, want := range map[string]int{
+		`{"name":"Mixed","fromGoalId":1,"title":"Also a title"}`:                                              http.StatusBadRequest,
+		`{"name":"Missing","fromGoalId":999}`:                                                                 http.StatusNotFound,
+		`{"title":"No name","priority":"low"}`:                                                                http.StatusBadRequest,
+		`{"name":"Bad key","title":"Title","priority":"low","placeholders":{"1x":"a"}}`:                       http.StatusBadRequest,
+		`{"name":"Bad task","title":"Title","priority":"low","tasks":[{"title":"Task","priority":"urgent"}]}`: http.StatusBadRequest,
+		`{"name":"Bad offset","title":"Title","priority":"low","dueInDays":-1}`:                               http.StatusBadRequest,
+	} {
+		if rr := handlertest.Serve(router, http.MethodPost, "/templates", owner, body); rr.Code != want {
+			t.Fatalf("%s: expected %d, got %d: %s", body, want, rr.Code, rr.Body.String())
+		}
+	}
+}
+
+func TestTemplatesArePrivate(t *testing.T) {
+	store, owner, other := seed(t)
+	router := newRouter(store)
+	created := createTemplate(t, router, owner, `{"name":"Onboarding","title":"Onboard","priority":"medium"}`)
+	path := "/templates/" + strconv.Itoa(created.ID)
+
+	rr := handlertest.Serve(router, http.MethodGet, "/templates", other, "")
+	if rr.Code != http.StatusOK || strings.TrimSpace(rr.Body.String()) != "[]" {
+		t.Fatalf("expected no templates for another user, got %d: %s", rr.Code, rr.Body.String())
+	}
+	for _, req := range []struct{ method, path string }{{http.MethodDelete, path}, {http.MethodPost, path + "/instantiate"}} {
+		if rr := handlertest.Serve(router, req.method, req.path, other, ""); rr.Code != http.StatusNotFound {
+			t.Fatalf("%s %s: expected 404, got %d", req.method, req.path, rr.Code)
+		}
+	}
+	if rr := handlertest.Serve(router, http.MethodGet, "/templates", 0, ""); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401, got %d", rr.Code)
+	}
+
+	if rr := handlertest.Serve(router, http.MethodDelete, path, owner, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = handlertest.Serve(router, http.MethodGet, "/templates", owner, "")
+	if strings.TrimSpace(rr.Body.String()) != "[]" {
+		t.Fatalf("expected the template to be deleted, got %s", rr.Body.String())
+	}
+}
+
+func seed(t *testing.T) (*memstore.Store, int, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := memstore.New()
+	ids := make([]int, 0, 2)
+	for _, u := range []types.User{
+		{FirstName: "Owner", LastName: "User", Email: "owner@example.com", Password: "hashed"},
+		{FirstName: "Other", LastName: "User", Email: "other@example.com", Password: "hashed"},
+	} {
+		if err := store.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		created, err := store.GetUserByEmail(ctx, u.Email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+	return store, ids[0], ids[1]
+}
+
+func newRouter(store *memstore.Store) chi.Router {
+	router := chi.NewRouter()
+	template.RegisterRoutes(router, template.NewHandler(store, store, store))
+	return router
+}
+
+func createTemplate(t *testing.T, router chi.Router, userID int, body string) types.Template {
+	t.Helper()
+	rr := handlertest.Serve(router, http.MethodPost, "/templates", userID, body)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	var created types.Template
+	if err := json.Unmarshal(rr.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+func decodeProblem(t *testing.T, rr *httptest.ResponseRecorder) types.ErrorResponse {
+	t.Helper()
+	var problem types.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("expected a problem response, got %s", rr.Body.String())
+	}
+	return problem
+}
@@ -0,0 +1,136 @@
+package template
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxDueInDays matches the validation of types.TemplateTask.DueInDays.
+const maxDueInDays = 3650
+
+// placeholderPattern matches a placeholder such as {{client}}. Names follow
+// the "placeholder" validation rule.
+var placeholderPattern = regexp.MustCompile(`\{\{([A-Za-z][A-Za-z0-9_]{0,63})\}\}`)
+
+// placeholders returns the sorted names of the placeholders the template
+// uses.
+func placeholders(t *types.Template) []string {
+	var names []string
+	collect := func(s string) {
+		for _, match := range placeholderPattern.FindAllStringSubmatch(s, -1) {
+			if !slices.Contains(names, match[1]) {
+				names = append(names, match[1])
+			}
+		}
+	}
+	collect(t.Title)
+	collect(t.Description)
+	for _, task := range t.Tasks {
+		collect(task.Title)
+		collect(task.Description)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// fill replaces the placeholders in s that have a value.
+func fill(s string, values map[string]string) string {
+	return placeholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		if value, ok := values[match[2:len(match)-2]]; ok {
+			return value
+		}
+		return match
+	})
+}
+
+// parameterize is the inverse of fill: it turns each text in placeholders
+// back into its {{name}}. Longer texts are replaced first, so "Acme Corp"
+// wins over "Acme".
+func parameterize(s string, placeholders map[string]string) string {
+	if len(placeholders) == 0 {
+		return s
+	}
+	names := make([]string, 0, len(placeholders))
+	for name := range placeholders {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return cmp.Or(len(placeholders[b])-len(placeholders[a]), strings.Compare(a, b))
+	})
+	pairs := make([]string, 0, 2*len(names))
+	for _, name := range names {
+		pairs = append(pairs, placeholders[name], "{{"+name+"}}")
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// instantiate builds the goal and tasks created from t, with placeholders
+// filled from values and due dates counted from start. The goal starts as
+// todo and the tasks open and unassigned.
+func instantiate(t *types.Template, start time.Time, values map[string]string) types.ImportedGoal {
+	goal := types.ImportedGoal{
+		Goal: types.CreateGoalPayload{
+			Title:       fill(t.Title, values),
+			Description: fill(t.Description, values),
+			Priority:    t.Priority,
+			Status:      "todo",
+			DueDate:     dueDate(start, t.DueInDays),
+		},
+		Tasks: make([]types.ImportedTask, 0, len(t.Tasks)),
+	}
+	for _, task := range t.Tasks {
+		goal.Tasks = append(goal.Tasks, types.ImportedTask{Task: types.CreateTaskPayload{
+			Title:       fill(task.Title, values),
+			Description: fill(task.Description, values),
+			Priority:    task.Priority,
+			DueDate:     dueDate(start, task.DueInDays),
+		}})
+	}
+	return goal
+}
+
+// fromGoal fills the goal fields and tasks of payload from goal. Due dates
+// become offsets from the day the goal was created; dates before it become
+// zero.
+func fromGoal(payload *types.CreateTemplatePayload, goal *types.GoalWithTasks) {
+	created := goal.CreatedAt.UTC()
+	start := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)
+
+	payload.Title = parameterize(goal.Title, payload.Placeholders)
+	payload.Description = parameterize(goal.Description, payload.Placeholders)
+	payload.Priority = goal.Priority
+	payload.DueInDays = dueInDays(start, goal.DueDate)
+	payload.Tasks = make([]types.TemplateTask, 0, len(goal.Tasks))
+	for _, task := range goal.Tasks {
+		payload.Tasks = append(payload.Tasks, types.TemplateTask{
+			Title:       parameterize(task.Title, payload.Placeholders),
+			Description: parameterize(task.Description, payload.Placeholders),
+			Priority:    task.Priority,
+			DueInDays:   dueInDays(start, task.DueDate),
+		})
+	}
+}
+
+func dueDate(start time.Time, days *int) *string {
+	if days == nil {
+		return nil
+	}
+	date := start.AddDate(0, 0, *days).Format(time.DateOnly)
+	return &date
+}
+
+func dueInDays(start time.Time, date *string) *int {
+	if date == nil {
+		return nil
+	}
+	due, err := time.Parse(time.DateOnly, *date)
+	if err != nil {
+		return nil
+	}
+	days := min(max(int(due.Sub(start).Hours()/24), 0), maxDueInDays)
+	return &days
+}
@@ -0,0 +1,41 @@
+package template
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParameterizePrefersLongerText(t *testing.T) {
+	got := parameterize("Acme Corp onboarding, ask Acme", map[string]string{"client": "Acme Corp", "short": "Acme"})
+	if want := "{{client}} onboarding, ask {{short}}"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestFillLeavesUnknownPlaceholders(t *testing.T) {
+	got := fill("{{client}} / {{ client }} / {{other}}", map[string]string{"client": "Acme"})
+	if want := "Acme / {{ client }} / {{other}}"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+func TestPlaceholdersAreSortedAndUnique(t *testing.T) {
+	got := placeholders(&types.Template{
+		Title: "{{b}} {{a}}",
+		Tasks: []types.TemplateTask{{Title: "{{a}}", Description: "{{c}} {{not valid}}"}},
+	})
+	if want := []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestDueInDaysClampsToRange(t *testing.T) {
+	start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	for date, want := range map[string]int{"2026-03-10": 0, "2026-03-01": 0, "2026-04-09": 30, "2099-01-01": maxDueInDays} {
+		if got := dueInDays(start, &date); got == nil || *got != want {
+			t.Fatalf("%s: got %v, want %d", date, got, want)
+		}
+	}
+}
@@ -0,0 +1,14 @@
+package template
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Route("/templates", func(r chi.Router) {
+		r.Get("/", handler.HandleListTemplates)
+		r.Post("/", handler.HandleCreateTemplate)
+		r.Delete("/{templateID}", handler.HandleDeleteTemplate)
+		r.Post("/{templateID}/instantiate", handler.HandleInstantiateTemplate)
+	})
+}
@@ -0,0 +1,230 @@
+package template
+
+import (
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"errors"
+)
+
+// ErrNotFound is returned for templates that do not exist and for those of
+// other users, which are private.
+var ErrNotFound = errors.New("template not found")
+
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+// ListTemplates returns the owner's templates ordered by name.
+func (s *Store) ListTemplates(ctx context.Context, ownerID int) ([]*types.Template, error) {
+	ctx = tracing.WithStatementName(ctx, "template.ListTemplates")
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, owner_id, name, title, description, priority, due_in_days, created_at
+		 FROM templates
+		 WHERE owner_id = $1
+		 ORDER BY name, id`,
+		ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := make([]*types.Template, 0)
+	byID := make(map[int]*types.Template)
+	for rows.Next() {
+		template, err := scanRowIntoTemplate(rows)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, template)
+		byID[template.ID] = template
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = s.scanTasks(
+		ctx,
+		byID,
+		`SELECT tt.template_id, tt.title, tt.description, tt.priority, tt.due_in_days
+		 FROM template_tasks tt
+		 JOIN templates t ON t.id = tt.template_id
+		 WHERE t.owner_id = $1
+		 ORDER BY tt.template_id, tt.position`,
+		ownerID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (s *Store) GetTemplate(ctx context.Context, templateID, ownerID int) (*types.Template, error) {
+	ctx = tracing.WithStatementName(ctx, "template.GetTemplate")
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, owner_id, name, title, description, priority, due_in_days, created_at
+		 FROM templates
+		 WHERE id = $1 AND owner_id = $2`,
+		templateID,
+		ownerID,
+	)
+	template, err := scanRowIntoTemplate(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.scanTasks(
+		ctx,
+		map[int]*types.Template{template.ID: template},
+		`SELECT template_id, title, description, priority, due_in_days
+		 FROM template_tasks
+		 WHERE template_id = $1
+		 ORDER BY position`,
+		templateID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// CreateTemplate stores the template and its tasks in one transaction.
+func (s *Store) CreateTemplate(ctx context.Context, ownerID int, payload types.CreateTemplatePayload) (*types.Template, error) {
+	ctx = tracing.WithStatementName(ctx, "template.CreateTemplate")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO templates (owner_id, name, title, description, priority, due_in_days)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, owner_id, name, title, description, priority, due_in_days, created_at`,
+		ownerID,
+		payload.Name,
+		payload.Title,
+		payload.Description,
+		payload.Priority,
+		payload.DueInDays,
+	)
+	template, err := scanRowIntoTemplate(row)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, task := range payload.Tasks {
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO template_tasks (template_id, position, title, description, priority, due_in_days)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			template.ID,
+			i,
+			task.Title,
+			task.Description,
+			task.Priority,
+			task.DueInDays,
+		); err != nil {
+			return nil, err
+		}
+		template.Tasks = append(template.Tasks, task)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// DeleteTemplate deletes the template and its tasks. Goals created from it
+// are kept.
+func (s *Store) DeleteTemplate(ctx context.Context, templateID, ownerID int) error {
+	ctx = tracing.WithStatementName(ctx, "template.DeleteTemplate")
+	result, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM templates WHERE id = $1 AND owner_id = $2`,
+		templateID,
+		ownerID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// scanTasks appends the tasks query returns to the templates in byID, in
+// row order.
+func (s *Store) scanTasks(ctx context.Context, byID map[int]*types.Template, query string, args ...any) error {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			templateID int
+			task       types.TemplateTask
+			dueInDays  sql.NullInt64
+		)
+		if err := rows.Scan(&templateID, &task.Title, &task.Description, &task.Priority, &dueInDays); err != nil {
+			return err
+		}
+		task.DueInDays = intPtr(dueInDays)
+		if template, ok := byID[templateID]; ok {
+			template.Tasks = append(template.Tasks, task)
+		}
+	}
+	return rows.Err()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRowIntoTemplate(row rowScanner) (*types.Template, error) {
+	template := &types.Template{Tasks: make([]types.TemplateTask, 0)}
+	var dueInDays sql.NullInt64
+	err := row.Scan(
+		&template.ID,
+		&template.OwnerID,
+		&template.Name,
+		&template.Title,
+		&template.Description,
+		&template.Priority,
+		&dueInDays,
+		&template.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	template.DueInDays = intPtr(dueInDays)
+	return template, nil
+}
+
+func intPtr(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
+}
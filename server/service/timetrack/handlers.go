@@ -0,0 +1,388 @@
+package timetrack
+
+import (
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/types"
+	"VyacheslavKuchumov/test-backend/utils"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Report ranges without from or to are open on that side.
+var (
+	minReportTime = time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC)
+	maxReportTime = time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)
+)
+
+type Handler struct {
+	store types.TimeStore
+	now   func() time.Time
+}
+
+func NewHandler(store types.TimeStore) *Handler {
+	return &Handler{store: store, now: time.Now}
+}
+
+// HandleStartTimer godoc
+// @Summary Start timer
+// @Description Start a timer on a task for the caller. Each user has at most one running timer; stop it before starting another. The body may be omitted.
+// @Tags time
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path int true "Task ID"
+// @Param payload body types.StartTimerPayload false "Timer payload"
+// @Success 201 {object} types.TimeEntry
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /tasks/{taskID}/timer/start [post]
+func (h *Handler) HandleStartTimer(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	taskID, err := parsePathID(r, "taskID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid task id"))
+		return
+	}
+
+	var payload types.StartTimerPayload
+	if err := utils.ParseJSON(r, &payload); err != nil && !errors.Is(err, io.EOF) {
+		utils.WriteJSONError(w, err)
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+
+	entry, err := h.store.StartTimer(r.Context(), userID, taskID, h.clock(), payload.Note)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, entry)
+}
+
+// HandleStopTimer godoc
+// @Summary Stop timer
+// @Description Stop the caller's running timer and record its duration.
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.TimeEntry
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /timer/stop [post]
+func (h *Handler) HandleStopTimer(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	entry, err := h.store.StopTimer(r.Context(), userID, h.clock())
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, entry)
+}
+
+// HandleGetTimer godoc
+// @Summary Get running timer
+// @Description Get the caller's running timer.
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} types.TimeEntry
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /timer [get]
+func (h *Handler) HandleGetTimer(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	entry, err := h.store.GetRunningTimer(r.Context(), userID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, entry)
+}
+
+// HandleListTimeEntries godoc
+// @Summary List time entries
+// @Description List a task's time entries from every user, running timers included, newest first.
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path int true "Task ID"
+// @Success 200 {array} types.TimeEntry
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /tasks/{taskID}/time-entries [get]
+func (h *Handler) HandleListTimeEntries(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	taskID, err := parsePathID(r, "taskID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid task id"))
+		return
+	}
+
+	entries, err := h.store.ListTimeEntries(r.Context(), taskID)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, entries)
+}
+
+// HandleCreateTimeEntry godoc
+// @Summary Log time
+// @Description Record finished work on a task for the caller. startedAt defaults to the given number of minutes before now, and the entry cannot end in the future.
+// @Tags time
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path int true "Task ID"
+// @Param payload body types.CreateTimeEntryPayload true "Time entry payload"
+// @Success 201 {object} types.TimeEntry
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /tasks/{taskID}/time-entries [post]
+func (h *Handler) HandleCreateTimeEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	taskID, err := parsePathID(r, "taskID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid task id"))
+		return
+	}
+
+	var payload types.CreateTimeEntryPayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteJSONError(w, err)
+		return
+	}
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+
+	now := h.clock()
+	duration := time.Duration(payload.Minutes) * time.Minute
+	startedAt := now.Add(-duration)
+	if payload.StartedAt != nil {
+		startedAt = payload.StartedAt.UTC().Truncate(time.Second)
+	}
+	if startedAt.Add(duration).After(now) {
+		utils.WriteProblem(w, http.StatusBadRequest, utils.CodeValidationFailed, "invalid payload", []types.FieldError{{
+			Field:   "startedAt",
+			Code:    "future",
+			Message: "must leave the entry ending no later than now",
+		}})
+		return
+	}
+
+	entry, err := h.store.AddTimeEntry(r.Context(), userID, taskID, startedAt, int(duration/time.Second), payload.Note)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusCreated, entry)
+}
+
+// HandleDeleteTimeEntry godoc
+// @Summary Delete time entry
+// @Description Delete one of the caller's time entries. Deleting a running timer discards it.
+// @Tags time
+// @Security BearerAuth
+// @Param entryID path int true "Time entry ID"
+// @Success 204
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /time-entries/{entryID} [delete]
+func (h *Handler) HandleDeleteTimeEntry(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	entryID, err := parsePathID(r, "entryID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid time entry id"))
+		return
+	}
+
+	if err := h.store.DeleteTimeEntry(r.Context(), entryID, userID); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleGoalTime godoc
+// @Summary Goal time report
+// @Description Total estimated and spent minutes on a goal, per task and per user. Only stopped entries count, by the date they started. from and to are inclusive UTC dates and default to all time.
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Param goalID path int true "Goal ID"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} types.GoalTimeReport
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /goals/{goalID}/time [get]
+func (h *Handler) HandleGoalTime(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	goalID, err := parsePathID(r, "goalID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid goal id"))
+		return
+	}
+	from, to, err := parseRange(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := h.store.GoalTimeReport(r.Context(), goalID, from, to)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, report)
+}
+
+// HandleUserTime godoc
+// @Summary User time report
+// @Description Minutes a user spent, in total and per goal. Only stopped entries count, by the date they started. from and to are inclusive UTC dates and default to all time.
+// @Tags time
+// @Produce json
+// @Security BearerAuth
+// @Param userID path int true "User ID"
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Success 200 {object} types.UserTimeReport
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /users/{userID}/time [get]
+func (h *Handler) HandleUserTime(w http.ResponseWriter, r *http.Request) {
+	userID := auth.GetUserIDFromContext(r.Context())
+	if userID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	reportUserID, err := parsePathID(r, "userID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid user id"))
+		return
+	}
+	from, to, err := parseRange(r)
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := h.store.UserTimeReport(r.Context(), reportUserID, from, to)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+	utils.WriteJSON(w, http.StatusOK, report)
+}
+
+// clock returns the current time in whole seconds, the precision of
+// recorded durations.
+func (h *Handler) clock() time.Time {
+	return h.now().UTC().Truncate(time.Second)
+}
+
+// parseRange turns the inclusive from and to dates into the half-open range
+// [from, to+1 day).
+func parseRange(r *http.Request) (time.Time, time.Time, error) {
+	from, to := minReportTime, maxReportTime
+	if value := r.URL.Query().Get("from"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid from date, expected YYYY-MM-DD")
+		}
+		from = parsed
+	}
+	if value := r.URL.Query().Get("to"); value != "" {
+		parsed, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return from, to, fmt.Errorf("invalid to date, expected YYYY-MM-DD")
+		}
+		if parsed.Before(maxReportTime) {
+			to = parsed.AddDate(0, 0, 1)
+		}
+	}
+	if !from.Before(to) {
+		return from, to, fmt.Errorf("from must not be after to")
+	}
+	return from, to, nil
+}
+
+// storeErrors maps time entry and timer errors to HTTP statuses.
+var storeErrors = map[error]int{
+	ErrNotFound:     http.StatusNotFound,
+	ErrNoTimer:      http.StatusNotFound,
+	ErrForbidden:    http.StatusForbidden,
+	ErrTimerRunning: http.StatusConflict,
+}
+
+func parsePathID(r *http.Request, key string) (int, error) {
+	value := chi.URLParam(r, key)
+	id, err := strconv.Atoi(value)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid %s", key)
+	}
+	return id, nil
+}
@@ -0,0 +1,242 @@
+package timetrack_test
+
+import (
+	"VyacheslavKuchumov/test-backend/handlertest"
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/timetrack"
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestTimerLifecycle(t *testing.T) {
+	store, owner, _, taskID := seed(t)
+	router := newRouter(store)
+	taskPath := "/tasks/" + strconv.Itoa(taskID)
+
+	if rr := handlertest.Serve(router, http.MethodGet, "/timer", owner, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 without a running timer, got %d", rr.Code)
+	}
+	rr := handlertest.Serve(router, http.MethodPost, taskPath+"/timer/start", owner, "")
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	started := decodeEntry(t, rr)
+	if started.TaskID != taskID || started.EndedAt != nil || started.DurationSeconds != nil {
+		t.Fatalf("unexpected running timer %+v", started)
+	}
+
+	rr = handlertest.Serve(router, http.MethodPost, taskPath+"/timer/start", owner, `{"note":"again"}`)
+	if rr.Code != http.StatusConflict || decodeProblem(t, rr).Code != "conflict" {
+		t.Fatalf("expected 409 for a second timer, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := handlertest.Serve(router, http.MethodGet, "/timer", owner, ""); rr.Code != http.StatusOK || decodeEntry(t, rr).ID != started.ID {
+		t.Fatalf("expected the running timer, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = handlertest.Serve(router, http.MethodPost, "/timer/stop", owner, "")
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if stopped := decodeEntry(t, rr); stopped.ID != started.ID || stopped.EndedAt == nil || stopped.DurationSeconds == nil {
+		t.Fatalf("unexpected stopped timer %+v", stopped)
+	}
+	if rr := handlertest.Serve(router, http.MethodPost, "/timer/stop", owner, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 when no timer is running, got %d", rr.Code)
+	}
+	if rr := handlertest.Serve(router, http.MethodPost, "/tasks/999/timer/start", owner, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing task, got %d", rr.Code)
+	}
+	if rr := handlertest.Serve(router, http.MethodPost, "/timer/stop", 0, ""); rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without a user, got %d", rr.Code)
+	}
+}
+
+func TestLogTime(t *testing.T) {
+	store, owner, other, taskID := seed(t)
+	router := newRouter(store)
+	path := "/tasks/" + strconv.Itoa(taskID) + "/time-entries"
+
+	rr := handlertest.Serve(router, http.MethodPost, path, owner, `{"minutes":45,"startedAt":"2026-03-02T09:00:00Z","note":"review"}`)
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", rr.Code, rr.Body.String())
+	}
+	entry := decodeEntry(t, rr)
+	if entry.DurationSeconds == nil || *entry.DurationSeconds != 2700 || entry.EndedAt == nil ||
+		!entry.EndedAt.Equal(time.Date(2026, 3, 2, 9, 45, 0, 0, time.UTC)) {
+		t.Fatalf("unexpected entry %+v", entry)
+	}
+	if rr := handlertest.Serve(router, http.MethodPost, path, other, `{"minutes":15}`); rr.Code != http.StatusCreated {
+		t.Fatalf("expected 201 without startedAt, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	future := time.Now().UTC().Add(time.Hour).Format(time.RFC3339)
+	rr = handlertest.Serve(router, http.MethodPost, path, owner, `{"minutes":30,"startedAt":"`+future+`"}`)
+	if problem := decodeProblem(t, rr); rr.Code != http.StatusBadRequest || len(problem.FieldErrors) != 1 || problem.FieldErrors[0].Field != "startedAt" {
+		t.Fatalf("expected a startedAt field error, got %d: %s", rr.Code, rr.Body.String())
+	}
+	rr = handlertest.Serve(router, http.MethodPost, path, owner, `{"minutes":0}`)
+	if problem := decodeProblem(t, rr); rr.Code != http.StatusBadRequest || len(problem.FieldErrors) != 1 || problem.FieldErrors[0].Field != "minutes" {
+		t.Fatalf("expected a minutes field error, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, path, owner, "")
+	var entries []types.TimeEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &entries); err != nil || len(entries) != 2 {
+		t.Fatalf("expected two entries, got %v: %s", err, rr.Body.String())
+	}
+	if entries[1].ID != entry.ID || entries[1].UserName != "Owner User" {
+		t.Fatalf("expected the entries newest first with names, got %+v", entries)
+	}
+
+	entryPath := "/time-entries/" + strconv.Itoa(entry.ID)
+	if rr := handlertest.Serve(router, http.MethodDelete, entryPath, other, ""); rr.Code != http.StatusForbidden {
+		t.Fatalf("expected 403 for another user's entry, got %d", rr.Code)
+	}
+	if rr := handlertest.Serve(router, http.MethodDelete, entryPath, owner, ""); rr.Code != http.StatusNoContent {
+		t.Fatalf("expected 204, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if rr := handlertest.Serve(router, http.MethodDelete, entryPath, owner, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 once deleted, got %d", rr.Code)
+	}
+}
+
+func TestTimeReports(t *testing.T) {
+	store, owner, other, taskID := seed(t)
+	router := newRouter(store)
+	ctx := context.Background()
+	task, err := store.GetAssignedTasks(ctx, owner)
+	if err != nil || len(task) != 1 {
+		t.Fatalf("expected the seeded task, got %v, %v", task, err)
+	}
+	goalID := task[0].GoalID
+
+	march := time.Date(2026, 3, 31, 23, 30, 0, 0, time.UTC)
+	if _, err := store.AddTimeEntry(ctx, owner, taskID, march, 1800, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.AddTimeEntry(ctx, other, taskID, march.Add(time.Hour), 600, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	rr := handlertest.Serve(router, http.MethodGet, "/goals/"+strconv.Itoa(goalID)+"/time?from=2026-03-01&to=2026-03-31", owner, "")
+	var report types.GoalTimeReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &report); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected a report, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if report.EstimateMinutes != 60 || report.SpentMinutes != 30 || len(report.Users) != 1 || report.Users[0].UserID != owner {
+		t.Fatalf("expected the inclusive to date to end the range, got %+v", report)
+	}
+
+	rr = handlertest.Serve(router, http.MethodGet, "/users/"+strconv.Itoa(other)+"/time", owner, "")
+	var userReport types.UserTimeReport
+	if err := json.Unmarshal(rr.Body.Bytes(), &userReport); err != nil || rr.Code != http.StatusOK {
+		t.Fatalf("expected a report, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if userReport.SpentMinutes != 10 || len(userReport.Goals) != 1 || userReport.Goals[0].GoalID != goalID {
+		t.Fatalf("unexpected user report %+v", userReport)
+	}
+
+	for _, query := range []string{"?from=March", "?to=2026-13-01", "?from=2026-04-02&to=2026-04-01"} {
+		if rr := handlertest.Serve(router, http.MethodGet, "/users/"+strconv.Itoa(other)+"/time"+query, owner, ""); rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400 for %q, got %d", query, rr.Code)
+		}
+	}
+	if rr := handlertest.Serve(router, http.MethodGet, "/goals/999/time", owner, ""); rr.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for a missing goal, got %d", rr.Code)
+	}
+}
+
+// TestRoutesCoexistWithTracker guards the full-path routes against the
+// /goals and /tasks subrouters the tracker mounts on the same router.
+func TestRoutesCoexistWithTracker(t *testing.T) {
+	store, owner, _, taskID := seed(t)
+	router := chi.NewRouter()
+	tracker.RegisterRoutes(router, tracker.NewHandler(store))
+	timetrack.RegisterRoutes(router, timetrack.NewHandler(store))
+
+	assigned, err := store.GetAssignedTasks(context.Background(), owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goalPath := "/goals/" + strconv.Itoa(assigned[0].GoalID)
+	for _, path := range []string{goalPath + "/tasks", goalPath + "/time", "/tasks/assigned", "/tasks/" + strconv.Itoa(taskID) + "/time-entries", "/users/tasks", "/users/" + strconv.Itoa(owner) + "/time"} {
+		if rr := handlertest.Serve(router, http.MethodGet, path, owner, ""); rr.Code != http.StatusOK {
+			t.Fatalf("expected 200 for %s, got %d: %s", path, rr.Code, rr.Body.String())
+		}
+	}
+
+	rr := handlertest.Serve(router, http.MethodGet, goalPath+"/tasks", owner, "")
+	var goal types.GoalWithTasks
+	if err := json.Unmarshal(rr.Body.Bytes(), &goal); err != nil {
+		t.Fatal(err)
+	}
+	if goal.EstimateMinutes == nil || *goal.EstimateMinutes != 60 || goal.SpentMinutes == nil || *goal.SpentMinutes != 0 {
+		t.Fatalf("expected goal time totals, got %s", rr.Body.String())
+	}
+}
+
+// seed creates two users and a goal owned by the first with one task
+// assigned to them, estimated at an hour.
+func seed(t *testing.T) (*memstore.Store, int, int, int) {
+	t.Helper()
+	ctx := context.Background()
+	store := memstore.New()
+	ids := make([]int, 0, 2)
+	for _, u := range []types.User{
+		{FirstName: "Owner", LastName: "User", Email: "owner@example.com", Password: "hashed"},
+		{FirstName: "Other", LastName: "User", Email: "other@example.com", Password: "hashed"},
+	} {
+		if err := store.CreateUser(ctx, u); err != nil {
+			t.Fatal(err)
+		}
+		created, err := store.GetUserByEmail(ctx, u.Email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, created.ID)
+	}
+
+	goal, err := store.CreateGoal(ctx, ids[0], types.CreateGoalPayload{Title: "Ship it", Priority: "high", Status: "todo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	estimate := 60
+	task, err := store.CreateTask(ctx, goal.ID, ids[0], types.CreateTaskPayload{Title: "Write docs", Priority: "low", AssigneeID: &ids[0], EstimateMinutes: &estimate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, ids[0], ids[1], task.ID
+}
+
+func newRouter(store *memstore.Store) chi.Router {
+	router := chi.NewRouter()
+	timetrack.RegisterRoutes(router, timetrack.NewHandler(store))
+	return router
+}
+
+func decodeEntry(t *testing.T, rr *httptest.ResponseRecorder) types.TimeEntry {
+	t.Helper()
+	var entry types.TimeEntry
+	if err := json.Unmarshal(rr.Body.Bytes(), &entry); err != nil {
+		t.Fatalf("expected a time entry, got %s", rr.Body.String())
+	}
+	return entry
+}
+
+func decodeProblem(t *testing.T, rr *httptest.ResponseRecorder) types.ErrorResponse {
+	t.Helper()
+	var problem types.ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("expected a problem response, got %s", rr.Body.String())
+	}
+	return problem
+}
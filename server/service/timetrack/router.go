@@ -0,0 +1,18 @@
+package timetrack
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes uses full paths because /tasks, /goals and /users are
+// mounted by other services.
+func RegisterRoutes(r chi.Router, handler *Handler) {
+	r.Post("/tasks/{taskID}/timer/start", handler.HandleStartTimer)
+	r.Get("/tasks/{taskID}/time-entries", handler.HandleListTimeEntries)
+	r.Post("/tasks/{taskID}/time-entries", handler.HandleCreateTimeEntry)
+	r.Get("/timer", handler.HandleGetTimer)
+	r.Post("/timer/stop", handler.HandleStopTimer)
+	r.Delete("/time-entries/{entryID}", handler.HandleDeleteTimeEntry)
+	r.Get("/goals/{goalID}/time", handler.HandleGoalTime)
+	r.Get("/users/{userID}/time", handler.HandleUserTime)
+}
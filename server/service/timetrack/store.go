@@ -0,0 +1,435 @@
+package timetrack
+
+import (
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNotFound is returned for tasks, goals, users and time entries that
+	// do not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrForbidden is returned when deleting another user's time entry.
+	ErrForbidden = errors.New("forbidden")
+	// ErrTimerRunning is returned when starting a timer while another one
+	// is running.
+	ErrTimerRunning = errors.New("a timer is already running")
+	// ErrNoTimer is returned when the user has no running timer.
+	ErrNoTimer = errors.New("no timer is running")
+)
+
+// Store works on both PostgreSQL and SQLite. The partial unique index on
+// time_entries(user_id) WHERE ended_at IS NULL allows one running timer per
+// user.
+type Store struct {
+	db *sql.DB
+}
+
+func NewStore(db *sql.DB) *Store {
+	return &Store{db: db}
+}
+
+func (s *Store) StartTimer(ctx context.Context, userID, taskID int, startedAt time.Time, note string) (*types.TimeEntry, error) {
+	ctx = tracing.WithStatementName(ctx, "timetrack.StartTimer")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := taskExists(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+	row := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO time_entries (task_id, user_id, started_at, note)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id) WHERE ended_at IS NULL DO NOTHING
+		 RETURNING id, task_id, user_id, started_at, ended_at, duration_seconds, note`,
+		taskID,
+		userID,
+		startedAt.UTC(),
+		note,
+	)
+	entry, err := scanRowIntoTimeEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrTimerRunning
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, tx.Commit()
+}
+
+// StopTimer ends the user's running timer at endedAt. A timer stopped
+// before it started, after a clock change, records zero seconds.
+func (s *Store) StopTimer(ctx context.Context, userID int, endedAt time.Time) (*types.TimeEntry, error) {
+	ctx = tracing.WithStatementName(ctx, "timetrack.StopTimer")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var (
+		entryID   int
+		startedAt time.Time
+	)
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT id, started_at FROM time_entries WHERE user_id = $1 AND ended_at IS NULL`,
+		userID,
+	).Scan(&entryID, &startedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoTimer
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	row := tx.QueryRowContext(
+		ctx,
+		`UPDATE time_entries
+		 SET ended_at = $2, duration_seconds = $3
+		 WHERE id = $1 AND ended_at IS NULL
+		 RETURNING id, task_id, user_id, started_at, ended_at, duration_seconds, note`,
+		entryID,
+		endedAt.UTC(),
+		max(int(endedAt.Sub(startedAt)/time.Second), 0),
+	)
+	entry, err := scanRowIntoTimeEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoTimer
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, tx.Commit()
+}
+
+func (s *Store) GetRunningTimer(ctx context.Context, userID int) (*types.TimeEntry, error) {
+	ctx = tracing.WithStatementName(ctx, "timetrack.GetRunningTimer")
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT id, task_id, user_id, started_at, ended_at, duration_seconds, note
+		 FROM time_entries
+		 WHERE user_id = $1 AND ended_at IS NULL`,
+		userID,
+	)
+	entry, err := scanRowIntoTimeEntry(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoTimer
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// AddTimeEntry records seconds of finished work starting at startedAt.
+func (s *Store) AddTimeEntry(ctx context.Context, userID, taskID int, startedAt time.Time, seconds int, note string) (*types.TimeEntry, error) {
+	ctx = tracing.WithStatementName(ctx, "timetrack.AddTimeEntry")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := taskExists(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+	startedAt = startedAt.UTC()
+	row := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO time_entries (task_id, user_id, started_at, ended_at, duration_seconds, note)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 RETURNING id, task_id, user_id, started_at, ended_at, duration_seconds, note`,
+		taskID,
+		userID,
+		startedAt,
+		startedAt.Add(time.Duration(seconds)*time.Second),
+		seconds,
+		note,
+	)
+	entry, err := scanRowIntoTimeEntry(row)
+	if err != nil {
+		return nil, err
+	}
+	return entry, tx.Commit()
+}
+
+// ListTimeEntries returns the task's entries, running ones included, newest
+// first.
+func (s *Store) ListTimeEntries(ctx context.Context, taskID int) ([]*types.TimeEntry, error) {
+	ctx = tracing.WithStatementName(ctx, "timetrack.ListTimeEntries")
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT te.id, te.task_id, te.user_id, te.started_at, te.ended_at, te.duration_seconds, te.note,
+			TRIM(CONCAT(u.first_name, ' ', u.last_name))
+		 FROM time_entries te
+		 JOIN users u ON u.id = te.user_id
+		 WHERE te.task_id = $1
+		 ORDER BY te.started_at DESC, te.id DESC`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := make([]*types.TimeEntry, 0)
+	for rows.Next() {
+		var userName string
+		entry, err := scanRowIntoTimeEntry(withUserName{rows, &userName})
+		if err != nil {
+			return nil, err
+		}
+		entry.UserName = userName
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		if err := taskExists(ctx, s.db, taskID); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+// DeleteTimeEntry deletes one of the user's entries, running or finished.
+func (s *Store) DeleteTimeEntry(ctx context.Context, entryID, userID int) error {
+	ctx = tracing.WithStatementName(ctx, "timetrack.DeleteTimeEntry")
+	result, err := s.db.ExecContext(
+		ctx,
+		`DELETE FROM time_entries WHERE id = $1 AND user_id = $2`,
+		entryID,
+		userID,
+	)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	var exists int
+	err = s.db.QueryRowContext(ctx, `SELECT 1 FROM time_entries WHERE id = $1`, entryID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return ErrForbidden
+}
+
+func (s *Store) GoalTimeReport(ctx context.Context, goalID int, from, to time.Time) (*types.GoalTimeReport, error) {
+	ctx = tracing.WithStatementName(ctx, "timetrack.GoalTimeReport")
+	report := &types.GoalTimeReport{GoalID: goalID, Tasks: []types.TaskTime{}, Users: []types.UserTimeItem{}}
+	err := s.db.QueryRowContext(ctx, `SELECT title FROM goals WHERE id = $1`, goalID).Scan(&report.Title)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT t.id, t.title, t.estimate_minutes, COALESCE(SUM(te.duration_seconds), 0)
+		 FROM tasks t
+		 LEFT JOIN time_entries te
+		   ON te.task_id = t.id
+		  AND te.ended_at IS NOT NULL
+		  AND te.started_at >= $2
+		  AND te.started_at < $3
+		 WHERE t.goal_id = $1
+		 GROUP BY t.id, t.title, t.estimate_minutes
+		 ORDER BY t.id`,
+		goalID,
+		from.UTC(),
+		to.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totalSeconds := 0
+	for rows.Next() {
+		var (
+			item     types.TaskTime
+			estimate sql.NullInt64
+			seconds  int
+		)
+		if err := rows.Scan(&item.TaskID, &item.Title, &estimate, &seconds); err != nil {
+			return nil, err
+		}
+		if estimate.Valid {
+			value := int(estimate.Int64)
+			item.EstimateMinutes = &value
+			report.EstimateMinutes += value
+		}
+		item.SpentMinutes = minutes(seconds)
+		totalSeconds += seconds
+		report.Tasks = append(report.Tasks, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	report.SpentMinutes = minutes(totalSeconds)
+
+	rows, err = s.db.QueryContext(
+		ctx,
+		`SELECT u.id, TRIM(CONCAT(u.first_name, ' ', u.last_name)), SUM(te.duration_seconds)
+		 FROM time_entries te
+		 JOIN tasks t ON t.id = te.task_id
+		 JOIN users u ON u.id = te.user_id
+		 WHERE t.goal_id = $1
+		   AND te.ended_at IS NOT NULL
+		   AND te.started_at >= $2
+		   AND te.started_at < $3
+		 GROUP BY u.id, u.first_name, u.last_name
+		 ORDER BY SUM(te.duration_seconds) DESC, u.id`,
+		goalID,
+		from.UTC(),
+		to.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			item    types.UserTimeItem
+			seconds int
+		)
+		if err := rows.Scan(&item.UserID, &item.Name, &seconds); err != nil {
+			return nil, err
+		}
+		item.SpentMinutes = minutes(seconds)
+		report.Users = append(report.Users, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+func (s *Store) UserTimeReport(ctx context.Context, userID int, from, to time.Time) (*types.UserTimeReport, error) {
+	ctx = tracing.WithStatementName(ctx, "timetrack.UserTimeReport")
+	report := &types.UserTimeReport{UserID: userID, Goals: []types.GoalTimeItem{}}
+	err := s.db.QueryRowContext(
+		ctx,
+		`SELECT TRIM(CONCAT(first_name, ' ', last_name)) FROM users WHERE id = $1`,
+		userID,
+	).Scan(&report.Name)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT g.id, g.title, SUM(te.duration_seconds)
+		 FROM time_entries te
+		 JOIN tasks t ON t.id = te.task_id
+		 JOIN goals g ON g.id = t.goal_id
+		 WHERE te.user_id = $1
+		   AND te.ended_at IS NOT NULL
+		   AND te.started_at >= $2
+		   AND te.started_at < $3
+		 GROUP BY g.id, g.title
+		 ORDER BY SUM(te.duration_seconds) DESC, g.id`,
+		userID,
+		from.UTC(),
+		to.UTC(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totalSeconds := 0
+	for rows.Next() {
+		var (
+			item    types.GoalTimeItem
+			seconds int
+		)
+		if err := rows.Scan(&item.GoalID, &item.Title, &seconds); err != nil {
+			return nil, err
+		}
+		item.SpentMinutes = minutes(seconds)
+		totalSeconds += seconds
+		report.Goals = append(report.Goals, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	report.SpentMinutes = minutes(totalSeconds)
+	return report, nil
+}
+
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+func taskExists(ctx context.Context, db queryRower, taskID int) error {
+	var exists int
+	err := db.QueryRowContext(ctx, `SELECT 1 FROM tasks WHERE id = $1`, taskID).Scan(&exists)
+	if err == sql.ErrNoRows {
+		return ErrNotFound
+	}
+	return err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// withUserName reads a trailing user name column after the entry columns.
+type withUserName struct {
+	row  rowScanner
+	name *string
+}
+
+func (w withUserName) Scan(dest ...any) error {
+	return w.row.Scan(append(dest, w.name)...)
+}
+
+func scanRowIntoTimeEntry(row rowScanner) (*types.TimeEntry, error) {
+	entry := new(types.TimeEntry)
+	var endedAt sql.NullTime
+	var seconds sql.NullInt64
+	if err := row.Scan(&entry.ID, &entry.TaskID, &entry.UserID, &entry.StartedAt, &endedAt, &seconds, &entry.Note); err != nil {
+		return nil, err
+	}
+	entry.StartedAt = entry.StartedAt.UTC()
+	if endedAt.Valid {
+		value := endedAt.Time.UTC()
+		entry.EndedAt = &value
+	}
+	if seconds.Valid {
+		value := int(seconds.Int64)
+		entry.DurationSeconds = &value
+	}
+	return entry, nil
+}
+
+// minutes rounds seconds to the nearest minute.
+func minutes(seconds int) int {
+	return (seconds + 30) / 60
+}
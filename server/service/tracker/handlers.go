@@ -1,6 +1,8 @@
 package tracker
 
 import (
+	"VyacheslavKuchumov/test-backend/metrics"
+	"VyacheslavKuchumov/test-backend/recurrence"
 	"VyacheslavKuchumov/test-backend/service/auth"
 	"VyacheslavKuchumov/test-backend/types"
 	"VyacheslavKuchumov/test-backend/utils"
@@ -9,7 +11,6 @@ import (
 	"strconv"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-playground/validator/v10"
 )
 
 type Handler struct {
@@ -30,33 +31,33 @@ func NewHandler(store types.GoalTaskStore) *Handler {
 // @Param payload body types.CreateGoalPayload true "Goal payload"
 // @Success 201 {object} types.Goal
 // @Failure 400 {object} types.ErrorResponse
-// @Failure 403 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /goals [post]
 func (h *Handler) HandleCreateGoal(w http.ResponseWriter, r *http.Request) {
 	ownerID := auth.GetUserIDFromContext(r.Context())
 	if ownerID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
 	var payload types.CreateGoalPayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
 		return
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid payload %v", errors))
+		utils.WriteValidationError(w, err)
 		return
 	}
 
-	goal, err := h.store.CreateGoal(ownerID, payload)
+	goal, err := h.store.CreateGoal(r.Context(), ownerID, payload)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
+	metrics.GoalsCreated.Inc()
 
 	utils.WriteJSON(w, http.StatusCreated, goal)
 }
@@ -72,13 +73,15 @@ func (h *Handler) HandleCreateGoal(w http.ResponseWriter, r *http.Request) {
 // @Param payload body types.CreateGoalPayload true "Goal payload"
 // @Success 200 {object} types.Goal
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /goals/{goalID} [put]
 func (h *Handler) HandleUpdateGoal(w http.ResponseWriter, r *http.Request) {
 	ownerID := auth.GetUserIDFromContext(r.Context())
 	if ownerID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
@@ -90,23 +93,18 @@ func (h *Handler) HandleUpdateGoal(w http.ResponseWriter, r *http.Request) {
 
 	var payload types.CreateGoalPayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
 		return
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid payload %v", errors))
+		utils.WriteValidationError(w, err)
 		return
 	}
 
-	goal, err := h.store.UpdateGoal(goalID, ownerID, payload)
+	goal, err := h.store.UpdateGoal(r.Context(), goalID, ownerID, payload)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrForbidden {
-			status = http.StatusForbidden
-		}
-		utils.WriteError(w, status, err)
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -122,13 +120,15 @@ func (h *Handler) HandleUpdateGoal(w http.ResponseWriter, r *http.Request) {
 // @Param goalID path int true "Goal ID"
 // @Success 204 {object} nil
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /goals/{goalID} [delete]
 func (h *Handler) HandleDeleteGoal(w http.ResponseWriter, r *http.Request) {
 	ownerID := auth.GetUserIDFromContext(r.Context())
 	if ownerID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
@@ -138,12 +138,8 @@ func (h *Handler) HandleDeleteGoal(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.DeleteGoal(goalID, ownerID); err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrForbidden {
-			status = http.StatusForbidden
-		}
-		utils.WriteError(w, status, err)
+	if err := h.store.DeleteGoal(r.Context(), goalID, ownerID); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -157,17 +153,17 @@ func (h *Handler) HandleDeleteGoal(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {array} types.GoalWithTasks
-// @Failure 403 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /goals [get]
 func (h *Handler) HandleGetGoals(w http.ResponseWriter, r *http.Request) {
 	ownerID := auth.GetUserIDFromContext(r.Context())
 	if ownerID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
-	goals, err := h.store.GetGoalsByOwner(ownerID)
+	goals, err := h.store.GetGoalsByOwner(r.Context(), ownerID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -178,7 +174,7 @@ func (h *Handler) HandleGetGoals(w http.ResponseWriter, r *http.Request) {
 
 // HandleCreateTask godoc
 // @Summary Create task
-// @Description Create a task under a goal. Only the goal owner can create tasks.
+// @Description Create a task under a goal. Only the goal owner can create tasks. A task with a recurrence rule (FREQ=DAILY, FREQ=WEEKLY;BYDAY=MO,TH or FREQ=MONTHLY;BYMONTHDAY=15) needs a due date; its next occurrence is created when it is completed or its due date arrives.
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -187,13 +183,15 @@ func (h *Handler) HandleGetGoals(w http.ResponseWriter, r *http.Request) {
 // @Param payload body types.CreateTaskPayload true "Task payload"
 // @Success 201 {object} types.Task
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /goals/{goalID}/tasks [post]
 func (h *Handler) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
 	creatorID := auth.GetUserIDFromContext(r.Context())
 	if creatorID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
@@ -205,23 +203,19 @@ func (h *Handler) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
 
 	var payload types.CreateTaskPayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
 		return
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid payload %v", errors))
+		utils.WriteValidationError(w, err)
 		return
 	}
 
-	task, err := h.store.CreateTask(goalID, creatorID, payload)
+	payload.Recurrence = canonicalRecurrence(payload.Recurrence)
+	task, err := h.store.CreateTask(r.Context(), goalID, creatorID, payload)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrForbidden {
-			status = http.StatusForbidden
-		}
-		utils.WriteError(w, status, err)
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -230,20 +224,22 @@ func (h *Handler) HandleCreateTask(w http.ResponseWriter, r *http.Request) {
 
 // HandleGetGoalTasks godoc
 // @Summary Get tasks by goal
-// @Description Get a single goal with its tasks for authenticated users
+// @Description Get a single goal with its tasks for authenticated users, with the total estimated and spent minutes
 // @Tags tasks
 // @Produce json
 // @Security BearerAuth
 // @Param goalID path int true "Goal ID"
 // @Success 200 {object} types.GoalWithTasks
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /goals/{goalID}/tasks [get]
 func (h *Handler) HandleGetGoalTasks(w http.ResponseWriter, r *http.Request) {
 	ownerID := auth.GetUserIDFromContext(r.Context())
 	if ownerID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
@@ -253,13 +249,9 @@ func (h *Handler) HandleGetGoalTasks(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	goalWithTasks, err := h.store.GetGoalWithTasks(goalID, ownerID)
+	goalWithTasks, err := h.store.GetGoalWithTasks(r.Context(), goalID, ownerID)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrForbidden {
-			status = http.StatusForbidden
-		}
-		utils.WriteError(w, status, err)
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -268,7 +260,7 @@ func (h *Handler) HandleGetGoalTasks(w http.ResponseWriter, r *http.Request) {
 
 // HandleAssignTask godoc
 // @Summary Assign task
-// @Description Assign or unassign a task. Only the goal owner can assign tasks.
+// @Description Assign or unassign a task. Only the goal owner can assign tasks. The new assignee is notified.
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -277,13 +269,15 @@ func (h *Handler) HandleGetGoalTasks(w http.ResponseWriter, r *http.Request) {
 // @Param payload body types.AssignTaskPayload true "Assignment payload"
 // @Success 200 {object} types.Task
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /tasks/{taskID}/assign [put]
 func (h *Handler) HandleAssignTask(w http.ResponseWriter, r *http.Request) {
 	requesterID := auth.GetUserIDFromContext(r.Context())
 	if requesterID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
@@ -295,17 +289,67 @@ func (h *Handler) HandleAssignTask(w http.ResponseWriter, r *http.Request) {
 
 	var payload types.AssignTaskPayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
 		return
 	}
 
-	task, err := h.store.AssignTask(taskID, requesterID, payload)
+	task, err := h.store.AssignTask(r.Context(), taskID, requesterID, payload)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrForbidden {
-			status = http.StatusForbidden
-		}
-		utils.WriteError(w, status, err)
+		utils.WriteStoreError(w, err, storeErrors)
+		return
+	}
+
+	utils.WriteJSON(w, http.StatusOK, task)
+}
+
+// HandleMoveTask godoc
+// @Summary Move task
+// @Description Move a task after afterId, before beforeId, or between the two. On the goal list (the default) only the goal owner can move tasks; on the assigned list the assignee orders their own tasks. The other tasks keep their places.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param taskID path int true "Task ID"
+// @Param payload body types.MoveTaskPayload true "Move payload"
+// @Success 200 {object} types.Task
+// @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
+// @Router /tasks/{taskID}/move [post]
+func (h *Handler) HandleMoveTask(w http.ResponseWriter, r *http.Request) {
+	requesterID := auth.GetUserIDFromContext(r.Context())
+	if requesterID <= 0 {
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
+		return
+	}
+
+	taskID, err := parsePathID(r, "taskID")
+	if err != nil {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid task id"))
+		return
+	}
+
+	var payload types.MoveTaskPayload
+	if err := utils.ParseJSON(r, &payload); err != nil {
+		utils.WriteJSONError(w, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+	if isTask(payload.AfterID, taskID) || isTask(payload.BeforeID, taskID) || (payload.AfterID != nil && isTask(payload.BeforeID, *payload.AfterID)) {
+		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("anchors must be two other tasks"))
+		return
+	}
+
+	task, err := h.store.MoveTask(r.Context(), taskID, requesterID, payload)
+	if err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -323,13 +367,15 @@ func (h *Handler) HandleAssignTask(w http.ResponseWriter, r *http.Request) {
 // @Param payload body types.UpdateTaskPayload true "Task update payload"
 // @Success 200 {object} types.Task
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /tasks/{taskID} [put]
 func (h *Handler) HandleUpdateTask(w http.ResponseWriter, r *http.Request) {
 	requesterID := auth.GetUserIDFromContext(r.Context())
 	if requesterID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
@@ -341,23 +387,19 @@ func (h *Handler) HandleUpdateTask(w http.ResponseWriter, r *http.Request) {
 
 	var payload types.UpdateTaskPayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
 		return
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid payload %v", errors))
+		utils.WriteValidationError(w, err)
 		return
 	}
 
-	task, err := h.store.UpdateTask(taskID, requesterID, payload)
+	payload.Recurrence = canonicalRecurrence(payload.Recurrence)
+	task, err := h.store.UpdateTask(r.Context(), taskID, requesterID, payload)
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrForbidden {
-			status = http.StatusForbidden
-		}
-		utils.WriteError(w, status, err)
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -373,13 +415,15 @@ func (h *Handler) HandleUpdateTask(w http.ResponseWriter, r *http.Request) {
 // @Param taskID path int true "Task ID"
 // @Success 204 {object} nil
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 403 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /tasks/{taskID} [delete]
 func (h *Handler) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
 	requesterID := auth.GetUserIDFromContext(r.Context())
 	if requesterID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
@@ -389,12 +433,8 @@ func (h *Handler) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.DeleteTask(taskID, requesterID); err != nil {
-		status := http.StatusInternalServerError
-		if err == ErrForbidden {
-			status = http.StatusForbidden
-		}
-		utils.WriteError(w, status, err)
+	if err := h.store.DeleteTask(r.Context(), taskID, requesterID); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -408,17 +448,17 @@ func (h *Handler) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {array} types.Task
-// @Failure 403 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /tasks/assigned [get]
 func (h *Handler) HandleGetAssignedTasks(w http.ResponseWriter, r *http.Request) {
 	userID := auth.GetUserIDFromContext(r.Context())
 	if userID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
-	tasks, err := h.store.GetAssignedTasks(userID)
+	tasks, err := h.store.GetAssignedTasks(r.Context(), userID)
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -434,17 +474,17 @@ func (h *Handler) HandleGetAssignedTasks(w http.ResponseWriter, r *http.Request)
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {array} types.UserTasksBoard
-// @Failure 403 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /users/tasks [get]
 func (h *Handler) HandleGetUsersWithCurrentTasks(w http.ResponseWriter, r *http.Request) {
 	userID := auth.GetUserIDFromContext(r.Context())
 	if userID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
-	usersTasks, err := h.store.GetUsersWithCurrentTasks()
+	usersTasks, err := h.store.GetUsersWithCurrentTasks(r.Context())
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -453,6 +493,18 @@ func (h *Handler) HandleGetUsersWithCurrentTasks(w http.ResponseWriter, r *http.
 	utils.WriteJSON(w, http.StatusOK, usersTasks)
 }
 
+// storeErrors maps goal and task store errors to HTTP statuses.
+var storeErrors = map[error]int{
+	ErrNotFound:      http.StatusNotFound,
+	ErrForbidden:     http.StatusForbidden,
+	ErrInvalidAnchor: http.StatusConflict,
+}
+
+// isTask reports whether the optional anchor id is taskID.
+func isTask(id *int, taskID int) bool {
+	return id != nil && *id == taskID
+}
+
 func parsePathID(r *http.Request, key string) (int, error) {
 	value := chi.URLParam(r, key)
 	id, err := strconv.Atoi(value)
@@ -461,3 +513,13 @@ func parsePathID(r *http.Request, key string) (int, error) {
 	}
 	return id, nil
 }
+
+// canonicalRecurrence rewrites a validated recurrence rule in the form
+// tasks store, e.g. "freq=weekly;byday=fr,mo" as "FREQ=WEEKLY;BYDAY=MO,FR".
+func canonicalRecurrence(value string) string {
+	rule, err := recurrence.Parse(value)
+	if err != nil {
+		return value
+	}
+	return rule.String()
+}
@@ -35,6 +35,61 @@ func TestTrackerHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("create goal reports field errors", func(t *testing.T) {
+		body := []byte(`{"title":"x","priority":"urgent","status":"todo"}`)
+		req := newRequestWithUser(http.MethodPost, "/api/v1/goals", body, 1)
+		rr := httptest.NewRecorder()
+
+		handler.HandleCreateGoal(rr, req)
+		if rr.Code != http.StatusBadRequest {
+			t.Fatalf("expected %d, got %d", http.StatusBadRequest, rr.Code)
+		}
+		if ct := rr.Header().Get("Content-Type"); ct != "application/problem+json" {
+			t.Fatalf("expected problem+json content type, got %q", ct)
+		}
+
+		var problem types.ErrorResponse
+		if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+			t.Fatal(err)
+		}
+		if problem.Code != "validation_failed" || problem.Status != http.StatusBadRequest {
+			t.Fatalf("unexpected problem: %+v", problem)
+		}
+		fields := map[string]string{}
+		for _, fe := range problem.FieldErrors {
+			fields[fe.Field] = fe.Code
+		}
+		if fields["title"] != "min" || fields["priority"] != "oneof" || len(fields) != 2 {
+			t.Fatalf("unexpected field errors: %+v", problem.FieldErrors)
+		}
+	})
+
+	t.Run("create goal rejects a malformed due date", func(t *testing.T) {
+		body := []byte(`{"title":"Launch","priority":"high","status":"todo","dueDate":"31/03/2026"}`)
+		req := newRequestWithUser(http.MethodPost, "/api/v1/goals", body, 1)
+		rr := httptest.NewRecorder()
+
+		handler.HandleCreateGoal(rr, req)
+		var problem types.ErrorResponse
+		if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+			t.Fatal(err)
+		}
+		if rr.Code != http.StatusBadRequest || len(problem.FieldErrors) != 1 ||
+			problem.FieldErrors[0].Field != "dueDate" || problem.FieldErrors[0].Code != "datetime" {
+			t.Fatalf("unexpected response %d: %+v", rr.Code, problem)
+		}
+	})
+
+	t.Run("create goal requires authentication", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/goals", bytes.NewBufferString(`{}`))
+		rr := httptest.NewRecorder()
+
+		handler.HandleCreateGoal(rr, req)
+		if rr.Code != http.StatusUnauthorized {
+			t.Fatalf("expected %d, got %d", http.StatusUnauthorized, rr.Code)
+		}
+	})
+
 	t.Run("create goal returns created goal", func(t *testing.T) {
 		payload := types.CreateGoalPayload{
 			Title:       "Launch MVP",
@@ -69,6 +124,35 @@ func TestTrackerHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("create goal requires a status unless it is automatic", func(t *testing.T) {
+		body := []byte(`{"title":"Launch","priority":"high","progressWeight":"effort"}`)
+		req := newRequestWithUser(http.MethodPost, "/api/v1/goals", body, 1)
+		rr := httptest.NewRecorder()
+
+		handler.HandleCreateGoal(rr, req)
+		var problem types.ErrorResponse
+		if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+			t.Fatal(err)
+		}
+		fields := map[string]string{}
+		for _, fe := range problem.FieldErrors {
+			fields[fe.Field] = fe.Message
+		}
+		if rr.Code != http.StatusBadRequest || fields["status"] != "is required unless autoStatus is true" ||
+			fields["progressWeight"] != "must be one of: count, priority, estimate" || len(fields) != 2 {
+			t.Fatalf("unexpected response %d: %+v", rr.Code, problem)
+		}
+
+		body = []byte(`{"title":"Launch","priority":"high","autoStatus":true,"progressWeight":"estimate"}`)
+		req = newRequestWithUser(http.MethodPost, "/api/v1/goals", body, 1)
+		rr = httptest.NewRecorder()
+
+		handler.HandleCreateGoal(rr, req)
+		if rr.Code != http.StatusCreated {
+			t.Fatalf("expected %d, got %d: %s", http.StatusCreated, rr.Code, rr.Body)
+		}
+	})
+
 	t.Run("create task validates goal path param", func(t *testing.T) {
 		payload := types.CreateTaskPayload{
 			Title:       "Break down tasks",
@@ -107,6 +191,49 @@ func TestTrackerHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("create task validates recurrence and estimate", func(t *testing.T) {
+		tests := map[string]string{
+			`{"title":"Standup","priority":"low","recurrence":"FREQ=DAILY"}`:                         "dueDate:required_with",
+			`{"title":"Standup","priority":"low","dueDate":"2026-03-02","recurrence":"FREQ=WEEKLY"}`: "recurrence:rrule",
+			`{"title":"Standup","priority":"low","estimateMinutes":-5}`:                              "estimateMinutes:min",
+		}
+		for body, want := range tests {
+			req := newRequestWithUser(http.MethodPost, "/api/v1/goals/1/tasks", []byte(body), 2)
+			rr := httptest.NewRecorder()
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("goalID", "1")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			handler.HandleCreateTask(rr, req)
+			var problem types.ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			if rr.Code != http.StatusBadRequest || len(problem.FieldErrors) != 1 ||
+				problem.FieldErrors[0].Field+":"+problem.FieldErrors[0].Code != want {
+				t.Fatalf("%s: unexpected response %d: %+v", body, rr.Code, problem)
+			}
+		}
+	})
+
+	t.Run("create task stores the canonical recurrence", func(t *testing.T) {
+		body := []byte(`{"title":"Standup","priority":"low","dueDate":"2026-03-02","recurrence":"rrule:freq=weekly;byday=fr,mo"}`)
+		req := newRequestWithUser(http.MethodPost, "/api/v1/goals/1/tasks", body, 2)
+		rr := httptest.NewRecorder()
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("goalID", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.HandleCreateTask(rr, req)
+		var task types.Task
+		if err := json.NewDecoder(rr.Body).Decode(&task); err != nil {
+			t.Fatal(err)
+		}
+		if rr.Code != http.StatusCreated || task.Recurrence != "FREQ=WEEKLY;BYDAY=MO,FR" {
+			t.Fatalf("unexpected response %d: %+v", rr.Code, task)
+		}
+	})
+
 	t.Run("get goal tasks validates goal path param", func(t *testing.T) {
 		req := newRequestWithUser(http.MethodGet, "/api/v1/goals/wrong/tasks", nil, 2)
 		rr := httptest.NewRecorder()
@@ -120,6 +247,38 @@ func TestTrackerHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("get goal tasks maps not found error", func(t *testing.T) {
+		store.getGoalErr = ErrNotFound
+		defer func() { store.getGoalErr = nil }()
+
+		req := newRequestWithUser(http.MethodGet, "/api/v1/goals/99/tasks", nil, 2)
+		rr := httptest.NewRecorder()
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("goalID", "99")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.HandleGetGoalTasks(rr, req)
+		if rr.Code != http.StatusNotFound {
+			t.Fatalf("expected %d, got %d", http.StatusNotFound, rr.Code)
+		}
+	})
+
+	t.Run("get goal tasks maps deadline to gateway timeout", func(t *testing.T) {
+		store.getGoalErr = context.DeadlineExceeded
+		defer func() { store.getGoalErr = nil }()
+
+		req := newRequestWithUser(http.MethodGet, "/api/v1/goals/1/tasks", nil, 2)
+		rr := httptest.NewRecorder()
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("goalID", "1")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.HandleGetGoalTasks(rr, req)
+		if rr.Code != http.StatusGatewayTimeout {
+			t.Fatalf("expected %d, got %d", http.StatusGatewayTimeout, rr.Code)
+		}
+	})
+
 	t.Run("update goal validates goal path param", func(t *testing.T) {
 		payload := types.CreateGoalPayload{
 			Title:       "Valid title",
@@ -158,6 +317,37 @@ func TestTrackerHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("move task rejects invalid anchors", func(t *testing.T) {
+		for _, body := range []string{`{}`, `{"list":"board","afterId":3}`, `{"afterId":10}`, `{"afterId":3,"beforeId":3}`} {
+			req := newRequestWithUser(http.MethodPost, "/api/v1/tasks/10/move", []byte(body), 2)
+			rr := httptest.NewRecorder()
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("taskID", "10")
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+			handler.HandleMoveTask(rr, req)
+			if rr.Code != http.StatusBadRequest {
+				t.Fatalf("expected %d for %s, got %d", http.StatusBadRequest, body, rr.Code)
+			}
+		}
+	})
+
+	t.Run("move task maps invalid anchor to conflict", func(t *testing.T) {
+		store.moveErr = ErrInvalidAnchor
+		defer func() { store.moveErr = nil }()
+
+		req := newRequestWithUser(http.MethodPost, "/api/v1/tasks/10/move", []byte(`{"beforeId":3}`), 2)
+		rr := httptest.NewRecorder()
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("taskID", "10")
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+
+		handler.HandleMoveTask(rr, req)
+		if rr.Code != http.StatusConflict {
+			t.Fatalf("expected %d, got %d", http.StatusConflict, rr.Code)
+		}
+	})
+
 	t.Run("delete task maps forbidden error", func(t *testing.T) {
 		store.deleteErr = ErrForbidden
 		defer func() { store.deleteErr = nil }()
@@ -196,11 +386,13 @@ func TestTrackerHandlers(t *testing.T) {
 }
 
 type mockGoalTaskStore struct {
-	assignErr error
-	deleteErr error
+	assignErr  error
+	deleteErr  error
+	getGoalErr error
+	moveErr    error
 }
 
-func (m *mockGoalTaskStore) CreateGoal(ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
+func (m *mockGoalTaskStore) CreateGoal(ctx context.Context, ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
 	return &types.Goal{
 		ID:          1,
 		Title:       payload.Title,
@@ -212,7 +404,7 @@ func (m *mockGoalTaskStore) CreateGoal(ownerID int, payload types.CreateGoalPayl
 	}, nil
 }
 
-func (m *mockGoalTaskStore) GetGoalsByOwner(ownerID int) ([]*types.GoalWithTasks, error) {
+func (m *mockGoalTaskStore) GetGoalsByOwner(ctx context.Context, ownerID int) ([]*types.GoalWithTasks, error) {
 	return []*types.GoalWithTasks{
 		{
 			Goal: types.Goal{
@@ -229,7 +421,10 @@ func (m *mockGoalTaskStore) GetGoalsByOwner(ownerID int) ([]*types.GoalWithTasks
 	}, nil
 }
 
-func (m *mockGoalTaskStore) GetGoalWithTasks(goalID, ownerID int) (*types.GoalWithTasks, error) {
+func (m *mockGoalTaskStore) GetGoalWithTasks(ctx context.Context, goalID, ownerID int) (*types.GoalWithTasks, error) {
+	if m.getGoalErr != nil {
+		return nil, m.getGoalErr
+	}
 	return &types.GoalWithTasks{
 		Goal: types.Goal{
 			ID:          goalID,
@@ -244,7 +439,7 @@ func (m *mockGoalTaskStore) GetGoalWithTasks(goalID, ownerID int) (*types.GoalWi
 	}, nil
 }
 
-func (m *mockGoalTaskStore) UpdateGoal(goalID, ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
+func (m *mockGoalTaskStore) UpdateGoal(ctx context.Context, goalID, ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
 	return &types.Goal{
 		ID:          goalID,
 		Title:       payload.Title,
@@ -256,11 +451,11 @@ func (m *mockGoalTaskStore) UpdateGoal(goalID, ownerID int, payload types.Create
 	}, nil
 }
 
-func (m *mockGoalTaskStore) DeleteGoal(goalID, ownerID int) error {
+func (m *mockGoalTaskStore) DeleteGoal(ctx context.Context, goalID, ownerID int) error {
 	return m.deleteErr
 }
 
-func (m *mockGoalTaskStore) CreateTask(goalID, creatorID int, payload types.CreateTaskPayload) (*types.Task, error) {
+func (m *mockGoalTaskStore) CreateTask(ctx context.Context, goalID, creatorID int, payload types.CreateTaskPayload) (*types.Task, error) {
 	return &types.Task{
 		ID:          1,
 		GoalID:      goalID,
@@ -270,11 +465,12 @@ func (m *mockGoalTaskStore) CreateTask(goalID, creatorID int, payload types.Crea
 		IsCompleted: false,
 		AssigneeID:  payload.AssigneeID,
 		CreatedBy:   creatorID,
+		Recurrence:  payload.Recurrence,
 		CreatedAt:   time.Now(),
 	}, nil
 }
 
-func (m *mockGoalTaskStore) UpdateTask(taskID, requesterID int, payload types.UpdateTaskPayload) (*types.Task, error) {
+func (m *mockGoalTaskStore) UpdateTask(ctx context.Context, taskID, requesterID int, payload types.UpdateTaskPayload) (*types.Task, error) {
 	return &types.Task{
 		ID:          taskID,
 		GoalID:      payload.GoalID,
@@ -288,11 +484,11 @@ func (m *mockGoalTaskStore) UpdateTask(taskID, requesterID int, payload types.Up
 	}, nil
 }
 
-func (m *mockGoalTaskStore) DeleteTask(taskID, requesterID int) error {
+func (m *mockGoalTaskStore) DeleteTask(ctx context.Context, taskID, requesterID int) error {
 	return m.deleteErr
 }
 
-func (m *mockGoalTaskStore) AssignTask(taskID, requesterID int, payload types.AssignTaskPayload) (*types.Task, error) {
+func (m *mockGoalTaskStore) AssignTask(ctx context.Context, taskID, requesterID int, payload types.AssignTaskPayload) (*types.Task, error) {
 	if m.assignErr != nil {
 		return nil, m.assignErr
 	}
@@ -309,7 +505,15 @@ func (m *mockGoalTaskStore) AssignTask(taskID, requesterID int, payload types.As
 	}, nil
 }
 
-func (m *mockGoalTaskStore) GetAssignedTasks(userID int) ([]*types.Task, error) {
+func (m *mockGoalTaskStore) MoveTask(ctx context.Context, taskID, requesterID int, payload types.MoveTaskPayload) (*types.Task, error) {
+	if m.moveErr != nil {
+		return nil, m.moveErr
+	}
+
+	return &types.Task{ID: taskID, GoalID: 1, Title: "task", Priority: "medium", CreatedBy: requesterID}, nil
+}
+
+func (m *mockGoalTaskStore) GetAssignedTasks(ctx context.Context, userID int) ([]*types.Task, error) {
 	return []*types.Task{
 		{
 			ID:          1,
@@ -324,7 +528,7 @@ func (m *mockGoalTaskStore) GetAssignedTasks(userID int) ([]*types.Task, error)
 	}, nil
 }
 
-func (m *mockGoalTaskStore) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard, error) {
+func (m *mockGoalTaskStore) GetUsersWithCurrentTasks(ctx context.Context) ([]*types.UserTasksBoard, error) {
 	return []*types.UserTasksBoard{
 		{
 			ID:    1,
@@ -349,7 +553,7 @@ func (m *mockGoalTaskStore) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard,
 	}, nil
 }
 
-func (m *mockGoalTaskStore) ListUsers() ([]*types.UserLookup, error) {
+func (m *mockGoalTaskStore) ListUsers(ctx context.Context) ([]*types.UserLookup, error) {
 	return []*types.UserLookup{
 		{ID: 1, Name: "Alice Doe"},
 		{ID: 2, Name: "Bob Doe"},
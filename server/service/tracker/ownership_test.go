@@ -0,0 +1,90 @@
+package tracker_test
+
+import (
+	"VyacheslavKuchumov/test-backend/memstore"
+	"VyacheslavKuchumov/test-backend/service/auth"
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/types"
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// TestOwnershipRules drives the real routes against the in-memory store, so
+// a handler or store that skips an owner check fails here.
+func TestOwnershipRules(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	owner := createUser(t, store, "owner@example.com")
+	other := createUser(t, store, "other@example.com")
+
+	goal, err := store.CreateGoal(ctx, owner, types.CreateGoalPayload{Title: "Ship it", Priority: "medium", Status: "todo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := store.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: "Write docs", Priority: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	anchor, err := store.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: "Review docs", Priority: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := chi.NewRouter()
+	tracker.RegisterRoutes(router, tracker.NewHandler(store))
+
+	goalBody := `{"title":"Renamed","description":"","priority":"high","status":"todo"}`
+	moveBody := fmt.Sprintf(`{"afterId":%d}`, anchor.ID)
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		userID int
+		status int
+	}{
+		{"other user cannot update goal", http.MethodPut, fmt.Sprintf("/goals/%d", goal.ID), goalBody, other, http.StatusForbidden},
+		{"other user cannot delete goal", http.MethodDelete, fmt.Sprintf("/goals/%d", goal.ID), "", other, http.StatusForbidden},
+		{"other user cannot add task", http.MethodPost, fmt.Sprintf("/goals/%d/tasks", goal.ID), `{"title":"Sneaky","priority":"low"}`, other, http.StatusForbidden},
+		{"other user cannot assign task", http.MethodPut, fmt.Sprintf("/tasks/%d/assign", task.ID), fmt.Sprintf(`{"assigneeId":%d}`, other), other, http.StatusForbidden},
+		{"other user cannot delete task", http.MethodDelete, fmt.Sprintf("/tasks/%d", task.ID), "", other, http.StatusForbidden},
+		{"other user cannot move task", http.MethodPost, fmt.Sprintf("/tasks/%d/move", task.ID), moveBody, other, http.StatusForbidden},
+		{"only the assignee moves task on assigned list", http.MethodPost, fmt.Sprintf("/tasks/%d/move", task.ID), fmt.Sprintf(`{"list":"assigned","afterId":%d}`, anchor.ID), owner, http.StatusForbidden},
+		{"missing goal is not found", http.MethodPut, "/goals/999", goalBody, owner, http.StatusNotFound},
+		{"owner updates goal", http.MethodPut, fmt.Sprintf("/goals/%d", goal.ID), goalBody, owner, http.StatusOK},
+		{"owner moves task", http.MethodPost, fmt.Sprintf("/tasks/%d/move", task.ID), moveBody, owner, http.StatusOK},
+		{"owner deletes task", http.MethodDelete, fmt.Sprintf("/tasks/%d", task.ID), "", owner, http.StatusNoContent},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.path, bytes.NewBufferString(tc.body))
+			req = req.WithContext(context.WithValue(req.Context(), auth.UserKey, tc.userID))
+			rr := httptest.NewRecorder()
+
+			router.ServeHTTP(rr, req)
+			if rr.Code != tc.status {
+				t.Fatalf("expected %d, got %d: %s", tc.status, rr.Code, rr.Body.String())
+			}
+		})
+	}
+}
+
+func createUser(t *testing.T, store *memstore.Store, email string) int {
+	t.Helper()
+	ctx := context.Background()
+	if err := store.CreateUser(ctx, types.User{FirstName: "Test", LastName: "User", Email: email, Password: "hashed"}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := store.GetUserByEmail(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.ID
+}
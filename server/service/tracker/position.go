@@ -0,0 +1,221 @@
+package tracker
+
+import (
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+)
+
+// MinPositionGap is how close two positions may get before a move
+// renumbers the list. float64 still has room to spare below it for lists
+// of millions of tasks.
+const MinPositionGap = 1e-6
+
+// assignedListLockKey namespaces the PostgreSQL advisory locks on assigned
+// lists, which are keyed by (assignedListLockKey, user ID): the ASCII bytes
+// of "asgn". Two-key advisory locks never conflict with one-key ones such
+// as recurrenceLockKey.
+const assignedListLockKey int32 = 0x6173676e
+
+// PlaceTask puts a new task last on its goal's list and, when it is
+// assigned, last on its assignee's list. Stores call it after inserting
+// the task, in the same transaction.
+func PlaceTask(ctx context.Context, db Execer, taskID int) error {
+	_, err := db.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET position = (SELECT COALESCE(MAX(p.position), 0) + 1 FROM tasks p WHERE p.goal_id = tasks.goal_id AND p.id <> tasks.id),
+		     assignee_position = CASE
+		       WHEN assignee_id IS NULL THEN NULL
+		       ELSE (SELECT COALESCE(MAX(p.assignee_position), 0) + 1 FROM tasks p WHERE p.assignee_id = tasks.assignee_id AND p.id <> tasks.id)
+		     END
+		 WHERE id = $1`,
+		taskID,
+	)
+	return err
+}
+
+// taskList is an ordered list of tasks: a goal's, ordered by position, or
+// an assignee's, ordered by assignee_position.
+type taskList struct {
+	column string
+	key    string
+	id     int
+}
+
+// MoveTask puts the task between the anchors on its goal's list, which
+// only the goal owner can reorder, or on the requester's assigned list.
+// The task takes the midpoint of its new neighbours' positions, so the
+// other tasks keep theirs unless the gap has become too small; then the
+// list is renumbered first.
+func (s *Store) MoveTask(ctx context.Context, taskID, requesterID int, payload types.MoveTaskPayload) (*types.Task, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.MoveTask")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if payload.List == "assigned" {
+		if err := s.lockAssignedList(ctx, tx, requesterID); err != nil {
+			return nil, err
+		}
+	}
+	// Locking the goal as well serialises moves within it.
+	var goalID, ownerID int
+	var assigneeID sql.NullInt64
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT t.goal_id, g.owner_id, t.assignee_id
+		 FROM tasks t
+		 JOIN goals g ON g.id = t.goal_id
+		 WHERE t.id = $1`+s.forUpdate(),
+		taskID,
+	).Scan(&goalID, &ownerID, &assigneeID)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	list := taskList{column: "position", key: "goal_id", id: goalID}
+	if payload.List == "assigned" {
+		if !assigneeID.Valid || int(assigneeID.Int64) != requesterID {
+			return nil, ErrForbidden
+		}
+		list = taskList{column: "assignee_position", key: "assignee_id", id: requesterID}
+	} else if ownerID != requesterID {
+		return nil, ErrForbidden
+	}
+
+	position, err := list.movePosition(ctx, tx, taskID, payload.AfterID, payload.BeforeID)
+	if err != nil {
+		return nil, err
+	}
+	row := tx.QueryRowContext(
+		ctx,
+		`UPDATE tasks
+		 SET `+list.column+` = $1
+		 WHERE id = $2
+		 RETURNING id, goal_id, title, description, priority, is_completed, assignee_id, created_by, CAST(due_date AS TEXT), recurrence, estimate_minutes, created_at`,
+		position,
+		taskID,
+	)
+	task, err := scanRowIntoTask(row)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// lockAssignedList serialises moves on a user's assigned list, whose tasks
+// span goals that no goal lock covers. It is taken before the task is
+// locked, so that two moves on the list never hold each other's rows. SQLite
+// serializes its writers anyway.
+func (s *Store) lockAssignedList(ctx context.Context, tx *sql.Tx, userID int) error {
+	if s.sqlite {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1, $2)`, assignedListLockKey, userID)
+	return err
+}
+
+// movePosition returns the position halfway between the anchors. With one
+// anchor the task goes next to it, before the task that follows afterID or
+// after the one that precedes beforeID.
+func (l taskList) movePosition(ctx context.Context, tx *sql.Tx, taskID int, afterID, beforeID *int) (float64, error) {
+	for renumbered := false; ; renumbered = true {
+		low, high, err := l.neighbours(ctx, tx, taskID, afterID, beforeID)
+		if err != nil {
+			return 0, err
+		}
+		if high-low >= MinPositionGap {
+			return low + (high-low)/2, nil
+		}
+		if renumbered {
+			// Every gap is 1 now, so afterID does not come before beforeID.
+			return 0, ErrInvalidAnchor
+		}
+		if err := l.renumber(ctx, tx); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// neighbours returns the positions the task goes between.
+func (l taskList) neighbours(ctx context.Context, tx *sql.Tx, taskID int, afterID, beforeID *int) (float64, float64, error) {
+	var low, high float64
+	var err error
+	if afterID != nil {
+		if low, err = l.positionOf(ctx, tx, *afterID); err != nil {
+			return 0, 0, err
+		}
+	}
+	if beforeID != nil {
+		if high, err = l.positionOf(ctx, tx, *beforeID); err != nil {
+			return 0, 0, err
+		}
+	}
+
+	switch {
+	case beforeID == nil:
+		var next sql.NullFloat64
+		err = tx.QueryRowContext(
+			ctx,
+			"SELECT MIN("+l.column+") FROM tasks WHERE "+l.key+" = $1 AND "+l.column+" >= $2 AND id <> $3 AND id <> $4",
+			l.id, low, taskID, *afterID,
+		).Scan(&next)
+		high = low + 2
+		if next.Valid {
+			high = next.Float64
+		}
+	case afterID == nil:
+		var previous sql.NullFloat64
+		err = tx.QueryRowContext(
+			ctx,
+			"SELECT MAX("+l.column+") FROM tasks WHERE "+l.key+" = $1 AND "+l.column+" <= $2 AND id <> $3 AND id <> $4",
+			l.id, high, taskID, *beforeID,
+		).Scan(&previous)
+		low = high - 2
+		if previous.Valid {
+			low = previous.Float64
+		}
+	}
+	return low, high, err
+}
+
+// positionOf returns the position of an anchor, which must be on the list.
+func (l taskList) positionOf(ctx context.Context, tx *sql.Tx, taskID int) (float64, error) {
+	var position float64
+	err := tx.QueryRowContext(
+		ctx,
+		"SELECT "+l.column+" FROM tasks WHERE id = $1 AND "+l.key+" = $2",
+		taskID, l.id,
+	).Scan(&position)
+	if err == sql.ErrNoRows {
+		return 0, ErrInvalidAnchor
+	}
+	return position, err
+}
+
+// renumber spaces the list's positions 1 apart, keeping its order.
+func (l taskList) renumber(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(
+		ctx,
+		`UPDATE tasks
+		 SET `+l.column+` = ranked.new_position
+		 FROM (
+		   SELECT id, ROW_NUMBER() OVER (ORDER BY `+l.column+`, id) AS new_position
+		   FROM tasks
+		   WHERE `+l.key+` = $1
+		 ) ranked
+		 WHERE tasks.id = ranked.id`,
+		l.id,
+	)
+	return err
+}
@@ -0,0 +1,149 @@
+package tracker
+
+import (
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+)
+
+// priorityWeights are the task weights under the priority weighting.
+var priorityWeights = map[string]int{"high": 3, "medium": 2, "low": 1}
+
+// Progress rolls a goal's tasks up under its progress weighting: count
+// weighs every task equally, priority weighs high, medium and low tasks
+// 3, 2 and 1, and estimate weighs tasks by their estimated minutes.
+func Progress(weighting string, tasks []*types.Task) *types.GoalProgress {
+	progress := &types.GoalProgress{TotalTasks: len(tasks)}
+	weights := taskWeights(weighting, tasks)
+
+	total, done := 0, 0
+	for i, task := range tasks {
+		total += weights[i]
+		if task.IsCompleted {
+			progress.CompletedTasks++
+			done += weights[i]
+		}
+	}
+	if total > 0 {
+		progress.Percent = done * 100 / total
+	}
+	// Tasks estimated at zero minutes weigh nothing, but the goal is not
+	// done until they are.
+	if progress.Percent == 100 && progress.CompletedTasks < progress.TotalTasks {
+		progress.Percent = 99
+	}
+	return progress
+}
+
+// taskWeights returns the weight of each task. Under the estimate weighting
+// an unestimated task weighs the mean of the estimated ones; when no task
+// has a positive estimate every task weighs the same.
+func taskWeights(weighting string, tasks []*types.Task) []int {
+	weights := make([]int, len(tasks))
+	switch weighting {
+	case "priority":
+		for i, task := range tasks {
+			weights[i] = priorityWeights[normalizePriority(task.Priority)]
+		}
+		return weights
+	case "estimate":
+		sum, estimated := 0, 0
+		for _, task := range tasks {
+			if task.EstimateMinutes != nil {
+				sum += *task.EstimateMinutes
+				estimated++
+			}
+		}
+		if sum > 0 {
+			mean := max(sum/estimated, 1)
+			for i, task := range tasks {
+				weights[i] = mean
+				if task.EstimateMinutes != nil {
+					weights[i] = *task.EstimateMinutes
+				}
+			}
+			return weights
+		}
+	}
+	for i := range weights {
+		weights[i] = 1
+	}
+	return weights
+}
+
+// AutoGoalStatus is the status of an auto-status goal with the given tasks.
+func AutoGoalStatus(tasks []*types.Task) string {
+	completed := 0
+	for _, task := range tasks {
+		if task.IsCompleted {
+			completed++
+		}
+	}
+	switch {
+	case completed == 0:
+		return "todo"
+	case completed == len(tasks):
+		return "achieved"
+	default:
+		return "in_progress"
+	}
+}
+
+// Execer is a *sql.DB or *sql.Tx.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// autoStatusExpr is AutoGoalStatus in SQL, for a statement on goals.
+const autoStatusExpr = `CASE
+		     WHEN NOT EXISTS (SELECT 1 FROM tasks WHERE goal_id = goals.id AND is_completed) THEN 'todo'
+		     WHEN NOT EXISTS (SELECT 1 FROM tasks WHERE goal_id = goals.id AND NOT is_completed) THEN 'achieved'
+		     ELSE 'in_progress'
+		 END`
+
+// SyncGoalStatus recomputes the status of the goal if it is an
+// auto-status goal. Stores call it after changing the goal's tasks, in the
+// same transaction.
+func SyncGoalStatus(ctx context.Context, db Execer, goalID int) error {
+	_, err := db.ExecContext(
+		ctx,
+		`UPDATE goals
+		 SET status = `+autoStatusExpr+`
+		 WHERE id = $1 AND auto_status`,
+		goalID,
+	)
+	return err
+}
+
+// syncGoalStatus is SyncGoalStatus for the tracker's own writes: when the
+// goal becomes achieved its members are notified, except the actor.
+func syncGoalStatus(ctx context.Context, tx *sql.Tx, goalID, actorID int) error {
+	var status string
+	err := tx.QueryRowContext(
+		ctx,
+		`UPDATE goals
+		 SET status = `+autoStatusExpr+`
+		 WHERE id = $1 AND auto_status AND status <> `+autoStatusExpr+`
+		 RETURNING status`,
+		goalID,
+	).Scan(&status)
+	if err == sql.ErrNoRows || (err == nil && status != "achieved") {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return notification.NotifyGoalMembers(ctx, tx, notification.Event{Type: notification.GoalAchieved, ActorID: actorID, GoalID: goalID})
+}
+
+// syncGoalStatuses syncs the goal a task moved to and the one it left.
+func syncGoalStatuses(ctx context.Context, tx *sql.Tx, goalID, prevGoalID, actorID int) error {
+	if err := syncGoalStatus(ctx, tx, goalID, actorID); err != nil {
+		return err
+	}
+	if prevGoalID != goalID {
+		return syncGoalStatus(ctx, tx, prevGoalID, actorID)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package tracker
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	estimate := func(minutes int) *int { return &minutes }
+	tests := []struct {
+		name      string
+		weighting string
+		tasks     []*types.Task
+		expected  types.GoalProgress
+	}{
+		{"no tasks", "count", nil, types.GoalProgress{}},
+		{
+			"count", "count",
+			[]*types.Task{{IsCompleted: true}, {}, {}},
+			types.GoalProgress{CompletedTasks: 1, TotalTasks: 3, Percent: 33},
+		},
+		{
+			"unknown priority weighs as medium", "priority",
+			[]*types.Task{{Priority: "high", IsCompleted: true}, {Priority: ""}},
+			types.GoalProgress{CompletedTasks: 1, TotalTasks: 2, Percent: 60},
+		},
+		{
+			"no estimates fall back to count", "estimate",
+			[]*types.Task{{IsCompleted: true}, {}},
+			types.GoalProgress{CompletedTasks: 1, TotalTasks: 2, Percent: 50},
+		},
+		{
+			"zero estimates keep the goal below 100", "estimate",
+			[]*types.Task{{EstimateMinutes: estimate(30), IsCompleted: true}, {EstimateMinutes: estimate(0)}},
+			types.GoalProgress{CompletedTasks: 1, TotalTasks: 2, Percent: 99},
+		},
+		{
+			"all done", "estimate",
+			[]*types.Task{{EstimateMinutes: estimate(30), IsCompleted: true}, {IsCompleted: true}},
+			types.GoalProgress{CompletedTasks: 2, TotalTasks: 2, Percent: 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Progress(tt.weighting, tt.tasks); *got != tt.expected {
+				t.Fatalf("expected %+v, got %+v", tt.expected, *got)
+			}
+		})
+	}
+}
+
+func TestAutoGoalStatus(t *testing.T) {
+	done, open := &types.Task{IsCompleted: true}, &types.Task{}
+	for expected, tasks := range map[string][]*types.Task{
+		"todo":        {open, open},
+		"in_progress": {done, open},
+		"achieved":    {done, done},
+	} {
+		if got := AutoGoalStatus(tasks); got != expected {
+			t.Fatalf("expected %q, got %q", expected, got)
+		}
+	}
+	if got := AutoGoalStatus(nil); got != "todo" {
+		t.Fatalf("expected a goal without tasks to be todo, got %q", got)
+	}
+}
@@ -0,0 +1,157 @@
+package tracker
+
+import (
+	"VyacheslavKuchumov/test-backend/metrics"
+	"VyacheslavKuchumov/test-backend/tracing"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"database/sql"
+	"time"
+)
+
+// recurrenceLockKey is the PostgreSQL advisory lock taken by the recurrence
+// scheduler: the ASCII bytes of "taskrecu".
+const recurrenceLockKey int64 = 0x7461736b72656375
+
+// WithRecurrenceLock runs fn while holding a transaction-scoped advisory
+// lock, so only one server creates occurrences at a time and a crashed
+// server cannot leave the lock behind. The transaction keeps one pooled
+// connection busy while fn runs.
+func (s *Store) WithRecurrenceLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error) {
+	if s.sqlite {
+		if !s.recurrenceMu.TryLock() {
+			return false, nil
+		}
+		defer s.recurrenceMu.Unlock()
+		return true, fn(ctx)
+	}
+
+	lockCtx := tracing.WithStatementName(ctx, "tracker.WithRecurrenceLock")
+	tx, err := s.db.BeginTx(lockCtx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var locked bool
+	if err := tx.QueryRowContext(lockCtx, `SELECT pg_try_advisory_xact_lock($1)`, recurrenceLockKey).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+	if err := fn(ctx); err != nil {
+		return true, err
+	}
+	return true, tx.Commit()
+}
+
+func (s *Store) PendingRecurringTasks(ctx context.Context, today string, afterID, limit int) ([]*types.Task, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.PendingRecurringTasks")
+	rows, err := s.db.QueryContext(
+		ctx,
+		`SELECT id, goal_id, title, description, priority, is_completed, assignee_id, created_by, CAST(due_date AS TEXT), recurrence, estimate_minutes, created_at
+		 FROM tasks
+		 WHERE recurrence IS NOT NULL
+		   AND recurred = FALSE
+		   AND due_date IS NOT NULL
+		   AND (is_completed = TRUE OR due_date <= $1)
+		   AND id > $2
+		 ORDER BY id
+		 LIMIT $3`,
+		today,
+		afterID,
+		limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := make([]*types.Task, 0)
+	for rows.Next() {
+		task, err := scanRowIntoTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// CreateNextOccurrence marks the task as recurred before copying it, so of
+// two concurrent calls for the same task only one creates an occurrence.
+func (s *Store) CreateNextOccurrence(ctx context.Context, taskID int, dueDate string) (*types.Task, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.CreateNextOccurrence")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(
+		ctx,
+		`UPDATE tasks SET recurred = TRUE
+		 WHERE id = $1 AND recurred = FALSE AND recurrence IS NOT NULL`,
+		taskID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	if rowsAffected == 0 {
+		return nil, nil
+	}
+
+	var source types.Task
+	var assigneeID, estimate sql.NullInt64
+	var position float64
+	var assigneePosition sql.NullFloat64
+	err = tx.QueryRowContext(
+		ctx,
+		`SELECT goal_id, title, description, priority, assignee_id, created_by, recurrence, estimate_minutes, position, assignee_position
+		 FROM tasks
+		 WHERE id = $1`,
+		taskID,
+	).Scan(&source.GoalID, &source.Title, &source.Description, &source.Priority, &assigneeID, &source.CreatedBy, &source.Recurrence, &estimate, &position, &assigneePosition)
+	if err != nil {
+		return nil, err
+	}
+
+	// The occurrence takes the completed task's place on both lists.
+	row := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO tasks (goal_id, title, description, priority, assignee_id, created_by, due_date, recurrence, estimate_minutes, assigned_at, position, assignee_position)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		 RETURNING id, goal_id, title, description, priority, is_completed, assignee_id, created_by, CAST(due_date AS TEXT), recurrence, estimate_minutes, created_at`,
+		source.GoalID,
+		source.Title,
+		source.Description,
+		source.Priority,
+		assigneeID,
+		source.CreatedBy,
+		dueDate,
+		source.Recurrence,
+		estimate,
+		assignedAt(nullableInt(assigneeID), time.Now().UTC()),
+		position,
+		assigneePosition,
+	)
+	task, err := scanRowIntoTask(row)
+	if err != nil {
+		return nil, err
+	}
+	// The open occurrence takes an achieved auto-status goal back to
+	// in_progress.
+	if err := SyncGoalStatus(ctx, tx, task.GoalID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	metrics.RecurringTasksCreated.Inc()
+	return task, nil
+}
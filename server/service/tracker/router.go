@@ -21,5 +21,6 @@ func RegisterRoutes(r chi.Router, handler *Handler) {
 		r.Put("/{taskID}", handler.HandleUpdateTask)
 		r.Delete("/{taskID}", handler.HandleDeleteTask)
 		r.Put("/{taskID}/assign", handler.HandleAssignTask)
+		r.Post("/{taskID}/move", handler.HandleMoveTask)
 	})
 }
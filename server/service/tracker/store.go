@@ -1,68 +1,134 @@
 package tracker
 
 import (
+	"VyacheslavKuchumov/test-backend/metrics"
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/tracing"
 	"VyacheslavKuchumov/test-backend/types"
+	"context"
 	"database/sql"
 	"errors"
+	"sync"
+	"time"
 )
 
 var (
 	ErrNotFound  = errors.New("resource not found")
 	ErrForbidden = errors.New("forbidden")
+	// ErrInvalidAnchor is returned by MoveTask when an anchor is not on the
+	// list or the anchors are out of order.
+	ErrInvalidAnchor = errors.New("anchor task is not on the list or the anchors are out of order")
 )
 
 type Store struct {
-	db *sql.DB
+	db     *sql.DB
+	sqlite bool
+
+	// recurrenceMu is the recurrence scheduler lock on SQLite, whose
+	// databases are not shared between servers.
+	recurrenceMu sync.Mutex
 }
 
 func NewStore(db *sql.DB) *Store {
 	return &Store{db: db}
 }
 
-func (s *Store) CreateGoal(ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
-	row := s.db.QueryRow(
-		`INSERT INTO goals (title, description, priority, status, owner_id)
-		 VALUES ($1, $2, $3, $4, $5)
-		 RETURNING id, title, description, priority, status, owner_id, created_at`,
+// NewSQLiteStore returns a store for a database opened with
+// db.NewSQLiteStorage. Queries are shared with PostgreSQL except where
+// SQLite lacks the syntax.
+func NewSQLiteStore(db *sql.DB) *Store {
+	return &Store{db: db, sqlite: true}
+}
+
+func (s *Store) CreateGoal(ctx context.Context, ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.CreateGoal")
+	status := normalizeGoalStatus(payload.Status)
+	if payload.AutoStatus {
+		// A new goal has no completed tasks.
+		status = "todo"
+	}
+	row := s.db.QueryRowContext(
+		ctx,
+		`INSERT INTO goals (title, description, priority, status, owner_id, due_date, auto_status, progress_weight)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		 RETURNING id, title, description, priority, status, owner_id, CAST(due_date AS TEXT), auto_status, progress_weight, created_at`,
 		payload.Title,
 		payload.Description,
 		normalizePriority(payload.Priority),
-		normalizeGoalStatus(payload.Status),
+		status,
 		ownerID,
+		payload.DueDate,
+		payload.AutoStatus,
+		normalizeProgressWeight(payload.ProgressWeight),
 	)
 	return scanRowIntoGoal(row)
 }
 
-func (s *Store) UpdateGoal(goalID, ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
-	_ = ownerID
+// UpdateGoal notifies the goal's members when it becomes achieved.
+func (s *Store) UpdateGoal(ctx context.Context, goalID, ownerID int, payload types.CreateGoalPayload) (*types.Goal, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.UpdateGoal")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	row := s.db.QueryRow(
+	var prevStatus string
+	err = tx.QueryRowContext(ctx, "SELECT status FROM goals WHERE id = $1 AND owner_id = $2"+s.forUpdate(), goalID, ownerID).Scan(&prevStatus)
+	if err == sql.ErrNoRows {
+		// Release the connection first: SQLite has only one.
+		tx.Rollback()
+		return nil, s.missingOrForbidden(ctx, "goals", goalID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	row := tx.QueryRowContext(
+		ctx,
 		`UPDATE goals
 		 SET title = $1,
 		     description = $2,
 		     priority = $3,
-		     status = $4
-		 WHERE id = $5
-		 RETURNING id, title, description, priority, status, owner_id, created_at`,
+		     status = CASE WHEN $8 THEN `+autoStatusExpr+` ELSE $4 END,
+		     due_date = $7,
+		     auto_status = $8,
+		     progress_weight = $9
+		 WHERE id = $5 AND owner_id = $6
+		 RETURNING id, title, description, priority, status, owner_id, CAST(due_date AS TEXT), auto_status, progress_weight, created_at`,
 		payload.Title,
 		payload.Description,
 		normalizePriority(payload.Priority),
 		normalizeGoalStatus(payload.Status),
 		goalID,
+		ownerID,
+		payload.DueDate,
+		payload.AutoStatus,
+		normalizeProgressWeight(payload.ProgressWeight),
 	)
 
 	goal, err := scanRowIntoGoal(row)
 	if err == sql.ErrNoRows {
-		return nil, ErrForbidden
+		return nil, ErrNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
+	if goal.Status == "achieved" && prevStatus != "achieved" {
+		if err := notification.NotifyGoalMembers(ctx, tx, notification.Event{Type: notification.GoalAchieved, ActorID: ownerID, GoalID: goalID}); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return goal, nil
 }
 
-func (s *Store) DeleteGoal(goalID, ownerID int) error {
-	result, err := s.db.Exec(
+func (s *Store) DeleteGoal(ctx context.Context, goalID, ownerID int) error {
+	ctx = tracing.WithStatementName(ctx, "tracker.DeleteGoal")
+	result, err := s.db.ExecContext(
+		ctx,
 		`DELETE FROM goals
 		 WHERE id = $1 AND owner_id = $2`,
 		goalID,
@@ -77,13 +143,15 @@ func (s *Store) DeleteGoal(goalID, ownerID int) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return ErrForbidden
+		return s.missingOrForbidden(ctx, "goals", goalID)
 	}
 	return nil
 }
 
-func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
-	rows, err := s.db.Query(
+func (s *Store) GetGoalsByOwner(ctx context.Context, _ int) ([]*types.GoalWithTasks, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.GetGoalsByOwner")
+	rows, err := s.db.QueryContext(
+		ctx,
 		`SELECT
 			g.id,
 			g.title,
@@ -91,6 +159,9 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 			g.priority,
 			g.status,
 			g.owner_id,
+			CAST(g.due_date AS TEXT),
+			g.auto_status,
+			g.progress_weight,
 			g.created_at,
 			TRIM(CONCAT(owner_u.first_name, ' ', owner_u.last_name)) AS owner_name,
 			t.id,
@@ -101,6 +172,9 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 			t.is_completed,
 			t.assignee_id,
 			t.created_by,
+			CAST(t.due_date AS TEXT),
+			t.recurrence,
+			t.estimate_minutes,
 			t.created_at,
 			TRIM(CONCAT(assignee_u.first_name, ' ', assignee_u.last_name)) AS assignee_name,
 			TRIM(CONCAT(creator_u.first_name, ' ', creator_u.last_name)) AS creator_name
@@ -113,9 +187,10 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 			CASE WHEN g.status = 'achieved' THEN 1 ELSE 0 END,
 			CASE g.priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 ELSE 2 END,
 			g.created_at DESC,
+			g.id DESC,
 			CASE WHEN t.is_completed THEN 1 ELSE 0 END,
-			CASE t.priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 WHEN 'low' THEN 2 ELSE 3 END,
-			t.created_at ASC`,
+			t.position,
+			t.id`,
 	)
 	if err != nil {
 		return nil, err
@@ -128,6 +203,7 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 	for rows.Next() {
 		var (
 			goal             types.Goal
+			goalDueDate      sql.NullString
 			goalOwnerName    string
 			taskID           sql.NullInt64
 			taskGoalID       sql.NullInt64
@@ -137,6 +213,9 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 			taskIsCompleted  sql.NullBool
 			assigneeID       sql.NullInt64
 			createdBy        sql.NullInt64
+			taskDueDate      sql.NullString
+			taskRecurrence   sql.NullString
+			taskEstimate     sql.NullInt64
 			taskAt           sql.NullTime
 			taskAssigneeName sql.NullString
 			taskCreatorName  sql.NullString
@@ -149,6 +228,9 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 			&goal.Priority,
 			&goal.Status,
 			&goal.OwnerID,
+			&goalDueDate,
+			&goal.AutoStatus,
+			&goal.ProgressWeight,
 			&goal.CreatedAt,
 			&goalOwnerName,
 			&taskID,
@@ -159,6 +241,9 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 			&taskIsCompleted,
 			&assigneeID,
 			&createdBy,
+			&taskDueDate,
+			&taskRecurrence,
+			&taskEstimate,
 			&taskAt,
 			&taskAssigneeName,
 			&taskCreatorName,
@@ -167,6 +252,7 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 		}
 
 		goal.OwnerName = goalOwnerName
+		goal.DueDate = nullableString(goalDueDate)
 
 		current, exists := goalByID[goal.ID]
 		if !exists {
@@ -180,16 +266,19 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 
 		if taskID.Valid {
 			task := &types.Task{
-				ID:            int(taskID.Int64),
-				GoalID:        int(taskGoalID.Int64),
-				GoalTitle:     current.Title,
-				Title:         taskTitle.String,
-				Description:   taskDesc.String,
-				Priority:      normalizePriority(taskPriority.String),
-				IsCompleted:   taskIsCompleted.Valid && taskIsCompleted.Bool,
-				CreatedBy:     int(createdBy.Int64),
-				CreatedByName: taskCreatorName.String,
-				CreatedAt:     taskAt.Time,
+				ID:              int(taskID.Int64),
+				GoalID:          int(taskGoalID.Int64),
+				GoalTitle:       current.Title,
+				Title:           taskTitle.String,
+				Description:     taskDesc.String,
+				Priority:        normalizePriority(taskPriority.String),
+				IsCompleted:     taskIsCompleted.Valid && taskIsCompleted.Bool,
+				CreatedBy:       int(createdBy.Int64),
+				CreatedByName:   taskCreatorName.String,
+				DueDate:         nullableString(taskDueDate),
+				Recurrence:      taskRecurrence.String,
+				EstimateMinutes: nullableInt(taskEstimate),
+				CreatedAt:       taskAt.Time,
 			}
 			if assigneeID.Valid {
 				value := int(assigneeID.Int64)
@@ -201,12 +290,20 @@ func (s *Store) GetGoalsByOwner(_ int) ([]*types.GoalWithTasks, error) {
 			current.Tasks = append(current.Tasks, task)
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
-	return goals, rows.Err()
+	for _, goal := range goals {
+		goal.Progress = Progress(goal.ProgressWeight, goal.Tasks)
+	}
+	return goals, nil
 }
 
-func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
-	rows, err := s.db.Query(
+func (s *Store) GetGoalWithTasks(ctx context.Context, goalID, _ int) (*types.GoalWithTasks, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.GetGoalWithTasks")
+	rows, err := s.db.QueryContext(
+		ctx,
 		`SELECT
 			g.id,
 			g.title,
@@ -214,6 +311,9 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 			g.priority,
 			g.status,
 			g.owner_id,
+			CAST(g.due_date AS TEXT),
+			g.auto_status,
+			g.progress_weight,
 			g.created_at,
 			TRIM(CONCAT(owner_u.first_name, ' ', owner_u.last_name)) AS owner_name,
 			t.id,
@@ -224,6 +324,9 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 			t.is_completed,
 			t.assignee_id,
 			t.created_by,
+			CAST(t.due_date AS TEXT),
+			t.recurrence,
+			t.estimate_minutes,
 			t.created_at,
 			TRIM(CONCAT(assignee_u.first_name, ' ', assignee_u.last_name)) AS assignee_name,
 			TRIM(CONCAT(creator_u.first_name, ' ', creator_u.last_name)) AS creator_name
@@ -235,8 +338,8 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 		WHERE g.id = $1
 		ORDER BY
 			CASE WHEN t.is_completed THEN 1 ELSE 0 END,
-			CASE t.priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 WHEN 'low' THEN 2 ELSE 3 END,
-			t.created_at ASC`,
+			t.position,
+			t.id`,
 		goalID,
 	)
 	if err != nil {
@@ -253,6 +356,7 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 	for rows.Next() {
 		var (
 			currentGoal      types.Goal
+			goalDueDate      sql.NullString
 			goalOwnerName    string
 			taskID           sql.NullInt64
 			taskGoalID       sql.NullInt64
@@ -262,6 +366,9 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 			taskIsCompleted  sql.NullBool
 			assigneeID       sql.NullInt64
 			createdBy        sql.NullInt64
+			taskDueDate      sql.NullString
+			taskRecurrence   sql.NullString
+			taskEstimate     sql.NullInt64
 			taskAt           sql.NullTime
 			taskAssigneeName sql.NullString
 			taskCreatorName  sql.NullString
@@ -274,6 +381,9 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 			&currentGoal.Priority,
 			&currentGoal.Status,
 			&currentGoal.OwnerID,
+			&goalDueDate,
+			&currentGoal.AutoStatus,
+			&currentGoal.ProgressWeight,
 			&currentGoal.CreatedAt,
 			&goalOwnerName,
 			&taskID,
@@ -284,6 +394,9 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 			&taskIsCompleted,
 			&assigneeID,
 			&createdBy,
+			&taskDueDate,
+			&taskRecurrence,
+			&taskEstimate,
 			&taskAt,
 			&taskAssigneeName,
 			&taskCreatorName,
@@ -293,6 +406,7 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 
 		if !goalFound {
 			currentGoal.OwnerName = goalOwnerName
+			currentGoal.DueDate = nullableString(goalDueDate)
 			goal = currentGoal
 			goalModel = &types.GoalWithTasks{
 				Goal:  goal,
@@ -303,16 +417,19 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 
 		if taskID.Valid {
 			task := &types.Task{
-				ID:            int(taskID.Int64),
-				GoalID:        int(taskGoalID.Int64),
-				GoalTitle:     goal.Title,
-				Title:         taskTitle.String,
-				Description:   taskDesc.String,
-				Priority:      normalizePriority(taskPriority.String),
-				IsCompleted:   taskIsCompleted.Valid && taskIsCompleted.Bool,
-				CreatedBy:     int(createdBy.Int64),
-				CreatedByName: taskCreatorName.String,
-				CreatedAt:     taskAt.Time,
+				ID:              int(taskID.Int64),
+				GoalID:          int(taskGoalID.Int64),
+				GoalTitle:       goal.Title,
+				Title:           taskTitle.String,
+				Description:     taskDesc.String,
+				Priority:        normalizePriority(taskPriority.String),
+				IsCompleted:     taskIsCompleted.Valid && taskIsCompleted.Bool,
+				CreatedBy:       int(createdBy.Int64),
+				CreatedByName:   taskCreatorName.String,
+				DueDate:         nullableString(taskDueDate),
+				Recurrence:      taskRecurrence.String,
+				EstimateMinutes: nullableInt(taskEstimate),
+				CreatedAt:       taskAt.Time,
 			}
 			if assigneeID.Valid {
 				value := int(assigneeID.Int64)
@@ -329,13 +446,37 @@ func (s *Store) GetGoalWithTasks(goalID, _ int) (*types.GoalWithTasks, error) {
 		return nil, err
 	}
 	if !goalFound {
-		return nil, ErrForbidden
+		return nil, ErrNotFound
 	}
+
+	estimate := 0
+	for _, task := range goalModel.Tasks {
+		if task.EstimateMinutes != nil {
+			estimate += *task.EstimateMinutes
+		}
+	}
+	var spentSeconds int
+	if err := s.db.QueryRowContext(
+		ctx,
+		`SELECT COALESCE(SUM(te.duration_seconds), 0)
+		 FROM time_entries te
+		 JOIN tasks t ON t.id = te.task_id
+		 WHERE t.goal_id = $1 AND te.ended_at IS NOT NULL`,
+		goalID,
+	).Scan(&spentSeconds); err != nil {
+		return nil, err
+	}
+	spent := (spentSeconds + 30) / 60
+	goalModel.Progress = Progress(goalModel.ProgressWeight, goalModel.Tasks)
+	goalModel.EstimateMinutes = &estimate
+	goalModel.SpentMinutes = &spent
 	return goalModel, nil
 }
 
-func (s *Store) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard, error) {
-	rows, err := s.db.Query(
+func (s *Store) GetUsersWithCurrentTasks(ctx context.Context) ([]*types.UserTasksBoard, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.GetUsersWithCurrentTasks")
+	rows, err := s.db.QueryContext(
+		ctx,
 		`SELECT
 			u.id,
 			TRIM(CONCAT(u.first_name, ' ', u.last_name)) AS user_name,
@@ -348,6 +489,9 @@ func (s *Store) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard, error) {
 			t.is_completed,
 			t.assignee_id,
 			t.created_by,
+			CAST(t.due_date AS TEXT),
+			t.recurrence,
+			t.estimate_minutes,
 			t.created_at,
 			g.title AS goal_title,
 			TRIM(CONCAT(assignee_u.first_name, ' ', assignee_u.last_name)) AS assignee_name,
@@ -363,8 +507,8 @@ func (s *Store) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard, error) {
 			u.first_name,
 			u.last_name,
 			u.id,
-			CASE t.priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 WHEN 'low' THEN 2 ELSE 3 END,
-			t.created_at DESC`,
+			t.assignee_position,
+			t.id`,
 	)
 	if err != nil {
 		return nil, err
@@ -388,6 +532,9 @@ func (s *Store) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard, error) {
 			taskIsCompleted  sql.NullBool
 			assigneeID       sql.NullInt64
 			createdBy        sql.NullInt64
+			taskDueDate      sql.NullString
+			taskRecurrence   sql.NullString
+			taskEstimate     sql.NullInt64
 			taskAt           sql.NullTime
 			taskGoalTitle    sql.NullString
 			taskAssigneeName sql.NullString
@@ -406,6 +553,9 @@ func (s *Store) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard, error) {
 			&taskIsCompleted,
 			&assigneeID,
 			&createdBy,
+			&taskDueDate,
+			&taskRecurrence,
+			&taskEstimate,
 			&taskAt,
 			&taskGoalTitle,
 			&taskAssigneeName,
@@ -428,15 +578,18 @@ func (s *Store) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard, error) {
 
 		if taskID.Valid {
 			task := &types.Task{
-				ID:          int(taskID.Int64),
-				GoalID:      int(taskGoalID.Int64),
-				GoalTitle:   taskGoalTitle.String,
-				Title:       taskTitle.String,
-				Description: taskDesc.String,
-				Priority:    normalizePriority(taskPriority.String),
-				IsCompleted: taskIsCompleted.Valid && taskIsCompleted.Bool,
-				CreatedBy:   int(createdBy.Int64),
-				CreatedAt:   taskAt.Time,
+				ID:              int(taskID.Int64),
+				GoalID:          int(taskGoalID.Int64),
+				GoalTitle:       taskGoalTitle.String,
+				Title:           taskTitle.String,
+				Description:     taskDesc.String,
+				Priority:        normalizePriority(taskPriority.String),
+				IsCompleted:     taskIsCompleted.Valid && taskIsCompleted.Bool,
+				CreatedBy:       int(createdBy.Int64),
+				DueDate:         nullableString(taskDueDate),
+				Recurrence:      taskRecurrence.String,
+				EstimateMinutes: nullableInt(taskEstimate),
+				CreatedAt:       taskAt.Time,
 			}
 			if assigneeID.Valid {
 				value := int(assigneeID.Int64)
@@ -455,45 +608,93 @@ func (s *Store) GetUsersWithCurrentTasks() ([]*types.UserTasksBoard, error) {
 	return boards, rows.Err()
 }
 
-func (s *Store) CreateTask(goalID, creatorID int, payload types.CreateTaskPayload) (*types.Task, error) {
-	row := s.db.QueryRow(
-		`INSERT INTO tasks (goal_id, title, description, priority, assignee_id, created_by)
-		 SELECT g.id, $2, $3, $4, $5, $6
+func (s *Store) CreateTask(ctx context.Context, goalID, creatorID int, payload types.CreateTaskPayload) (*types.Task, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.CreateTask")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(
+		ctx,
+		`INSERT INTO tasks (goal_id, title, description, priority, assignee_id, created_by, due_date, recurrence, estimate_minutes, assigned_at)
+		 SELECT g.id, $2, $3, $4, $5, $6, $7, $8, $9, $10
 		 FROM goals g
 		 WHERE g.id = $1 AND g.owner_id = $6
-		 RETURNING id, goal_id, title, description, priority, is_completed, assignee_id, created_by, created_at`,
+		 RETURNING id, goal_id, title, description, priority, is_completed, assignee_id, created_by, CAST(due_date AS TEXT), recurrence, estimate_minutes, created_at`,
 		goalID,
 		payload.Title,
 		payload.Description,
 		normalizePriority(payload.Priority),
 		payload.AssigneeID,
 		creatorID,
+		payload.DueDate,
+		nullIfEmpty(payload.Recurrence),
+		payload.EstimateMinutes,
+		assignedAt(payload.AssigneeID, time.Now().UTC()),
 	)
 	task, err := scanRowIntoTask(row)
 	if err == sql.ErrNoRows {
-		return nil, ErrForbidden
+		// Release the connection first: SQLite has only one.
+		tx.Rollback()
+		return nil, s.missingOrForbidden(ctx, "goals", goalID)
 	}
 	if err != nil {
 		return nil, err
 	}
+	if err := PlaceTask(ctx, tx, task.ID); err != nil {
+		return nil, err
+	}
+	if err := notifyTask(ctx, tx, task, creatorID, nil, nil); err != nil {
+		return nil, err
+	}
+	if err := syncGoalStatus(ctx, tx, goalID, creatorID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return task, nil
 }
 
-func (s *Store) UpdateTask(taskID, requesterID int, payload types.UpdateTaskPayload) (*types.Task, error) {
-	_ = requesterID
+// UpdateTask stamps completed_at when the task is completed and clears it
+// when the task is reopened, and stamps assigned_at when the assignee
+// changes. A task moved to another goal or assignee goes last on the new
+// list.
+func (s *Store) UpdateTask(ctx context.Context, taskID, requesterID int, payload types.UpdateTaskPayload) (*types.Task, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.UpdateTask")
+	if s.sqlite {
+		return s.updateTaskSQLite(ctx, taskID, requesterID, payload)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	row := s.db.QueryRow(
+	row := tx.QueryRowContext(
+		ctx,
 		`UPDATE tasks t
 		 SET goal_id = $1,
 		     title = $2,
 		     description = $3,
 		     priority = $4,
 		     is_completed = $5,
-		     assignee_id = $6
-		 FROM goals new_goal
+		     assignee_id = $6,
+		     due_date = $8,
+		     recurrence = $9,
+		     estimate_minutes = $10,
+		     completed_at = CASE WHEN $5 THEN COALESCE(t.completed_at, $11) ELSE NULL END,
+		     assigned_at = CASE WHEN $6 IS NULL THEN NULL WHEN t.assignee_id = $6 THEN t.assigned_at ELSE $11 END,
+		     position = CASE WHEN t.goal_id = $1 THEN t.position ELSE (SELECT COALESCE(MAX(p.position), 0) + 1 FROM tasks p WHERE p.goal_id = $1) END,
+		     assignee_position = CASE WHEN $6 IS NULL THEN NULL WHEN t.assignee_id = $6 THEN t.assignee_position ELSE (SELECT COALESCE(MAX(p.assignee_position), 0) + 1 FROM tasks p WHERE p.assignee_id = $6) END
+		 FROM goals new_goal, tasks prev
 		 WHERE t.id = $7
+		   AND prev.id = t.id
 		   AND new_goal.id = $1
-		 RETURNING t.id, t.goal_id, t.title, t.description, t.priority, t.is_completed, t.assignee_id, t.created_by, t.created_at`,
+		 RETURNING t.id, t.goal_id, t.title, t.description, t.priority, t.is_completed, t.assignee_id, t.created_by, CAST(t.due_date AS TEXT), t.recurrence, t.estimate_minutes, t.created_at, prev.is_completed, prev.goal_id, prev.assignee_id, prev.title, prev.description`,
 		payload.GoalID,
 		payload.Title,
 		payload.Description,
@@ -501,66 +702,202 @@ func (s *Store) UpdateTask(taskID, requesterID int, payload types.UpdateTaskPayl
 		payload.IsCompleted,
 		payload.AssigneeID,
 		taskID,
+		payload.DueDate,
+		nullIfEmpty(payload.Recurrence),
+		payload.EstimateMinutes,
+		time.Now().UTC(),
 	)
 
-	task, err := scanRowIntoTask(row)
+	var wasCompleted bool
+	var prevGoalID int
+	var prevAssigneeID sql.NullInt64
+	var prevTitle, prevDescription string
+	task, err := scanRowIntoTask(withExtraColumns(row, &wasCompleted, &prevGoalID, &prevAssigneeID, &prevTitle, &prevDescription))
 	if err == sql.ErrNoRows {
-		return nil, ErrForbidden
+		return nil, ErrNotFound
 	}
 	if err != nil {
 		return nil, err
 	}
+	if err := notifyTask(ctx, tx, task, requesterID, nullableInt(prevAssigneeID), notification.Mentions(prevTitle, prevDescription)); err != nil {
+		return nil, err
+	}
+	if err := syncGoalStatuses(ctx, tx, task.GoalID, prevGoalID, requesterID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if task.IsCompleted && !wasCompleted {
+		metrics.TasksCompleted.Inc()
+	}
 	return task, nil
 }
 
-func (s *Store) DeleteTask(taskID, requesterID int) error {
-	result, err := s.db.Exec(
-		`DELETE FROM tasks t
-		 USING goals g
-		 WHERE t.goal_id = g.id
-		   AND t.id = $1
-		   AND g.owner_id = $2`,
+// updateTaskSQLite reads the previous completion state and goal in the
+// same transaction, since SQLite's RETURNING cannot see the joined previous
+// row.
+func (s *Store) updateTaskSQLite(ctx context.Context, taskID, requesterID int, payload types.UpdateTaskPayload) (*types.Task, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var wasCompleted bool
+	var prevGoalID int
+	var prevAssigneeID sql.NullInt64
+	var prevTitle, prevDescription string
+	err = tx.QueryRowContext(
+		ctx,
+		"SELECT is_completed, goal_id, assignee_id, title, description FROM tasks WHERE id = $1",
 		taskID,
-		requesterID,
+	).Scan(&wasCompleted, &prevGoalID, &prevAssigneeID, &prevTitle, &prevDescription)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	row := tx.QueryRowContext(
+		ctx,
+		`UPDATE tasks
+		 SET goal_id = $1,
+		     title = $2,
+		     description = $3,
+		     priority = $4,
+		     is_completed = $5,
+		     assignee_id = $6,
+		     due_date = $8,
+		     recurrence = $9,
+		     estimate_minutes = $10,
+		     completed_at = CASE WHEN $5 THEN COALESCE(completed_at, $11) ELSE NULL END,
+		     assigned_at = CASE WHEN $6 IS NULL THEN NULL WHEN assignee_id = $6 THEN assigned_at ELSE $11 END,
+		     position = CASE WHEN goal_id = $1 THEN position ELSE (SELECT COALESCE(MAX(p.position), 0) + 1 FROM tasks p WHERE p.goal_id = $1) END,
+		     assignee_position = CASE WHEN $6 IS NULL THEN NULL WHEN assignee_id = $6 THEN assignee_position ELSE (SELECT COALESCE(MAX(p.assignee_position), 0) + 1 FROM tasks p WHERE p.assignee_id = $6) END
+		 WHERE id = $7
+		   AND EXISTS (SELECT 1 FROM goals WHERE id = $1)
+		 RETURNING id, goal_id, title, description, priority, is_completed, assignee_id, created_by, CAST(due_date AS TEXT), recurrence, estimate_minutes, created_at`,
+		payload.GoalID,
+		payload.Title,
+		payload.Description,
+		normalizePriority(payload.Priority),
+		payload.IsCompleted,
+		payload.AssigneeID,
+		taskID,
+		payload.DueDate,
+		nullIfEmpty(payload.Recurrence),
+		payload.EstimateMinutes,
+		time.Now().UTC(),
 	)
+	task, err := scanRowIntoTask(row)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := notifyTask(ctx, tx, task, requesterID, nullableInt(prevAssigneeID), notification.Mentions(prevTitle, prevDescription)); err != nil {
+		return nil, err
+	}
+	if err := syncGoalStatuses(ctx, tx, task.GoalID, prevGoalID, requesterID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	if task.IsCompleted && !wasCompleted {
+		metrics.TasksCompleted.Inc()
+	}
+	return task, nil
+}
+
+func (s *Store) DeleteTask(ctx context.Context, taskID, requesterID int) error {
+	ctx = tracing.WithStatementName(ctx, "tracker.DeleteTask")
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
 
-	rowsAffected, err := result.RowsAffected()
+	var goalID int
+	err = tx.QueryRowContext(
+		ctx,
+		`DELETE FROM tasks
+		 WHERE id = $1
+		   AND goal_id IN (SELECT id FROM goals WHERE owner_id = $2)
+		 RETURNING goal_id`,
+		taskID,
+		requesterID,
+	).Scan(&goalID)
+	if err == sql.ErrNoRows {
+		// Release the connection first: SQLite has only one.
+		tx.Rollback()
+		return s.missingOrForbidden(ctx, "tasks", taskID)
+	}
 	if err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
-		return ErrForbidden
+	if err := syncGoalStatus(ctx, tx, goalID, requesterID); err != nil {
+		return err
 	}
-	return nil
+	return tx.Commit()
 }
 
-func (s *Store) AssignTask(taskID, requesterID int, payload types.AssignTaskPayload) (*types.Task, error) {
-	row := s.db.QueryRow(
-		`UPDATE tasks t
-		 SET assignee_id = $1
-		 FROM goals g
-		 WHERE t.goal_id = g.id AND t.id = $2 AND g.owner_id = $3
-		 RETURNING t.id, t.goal_id, t.title, t.description, t.priority, t.is_completed, t.assignee_id, t.created_by, t.created_at`,
+// AssignTask stamps assigned_at and notifies the new assignee.
+func (s *Store) AssignTask(ctx context.Context, taskID, requesterID int, payload types.AssignTaskPayload) (*types.Task, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.AssignTask")
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var prevAssigneeID sql.NullInt64
+	err = tx.QueryRowContext(ctx, "SELECT assignee_id FROM tasks WHERE id = $1"+s.forUpdate(), taskID).Scan(&prevAssigneeID)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, err
+	}
+
+	row := tx.QueryRowContext(
+		ctx,
+		`UPDATE tasks
+		 SET assignee_id = $1,
+		     assigned_at = CASE WHEN $1 IS NULL THEN NULL WHEN assignee_id = $1 THEN assigned_at ELSE $4 END,
+		     assignee_position = CASE WHEN $1 IS NULL THEN NULL WHEN assignee_id = $1 THEN assignee_position ELSE (SELECT COALESCE(MAX(p.assignee_position), 0) + 1 FROM tasks p WHERE p.assignee_id = $1) END
+		 WHERE id = $2
+		   AND goal_id IN (SELECT id FROM goals WHERE owner_id = $3)
+		 RETURNING id, goal_id, title, description, priority, is_completed, assignee_id, created_by, CAST(due_date AS TEXT), recurrence, estimate_minutes, created_at`,
 		payload.AssigneeID,
 		taskID,
 		requesterID,
+		time.Now().UTC(),
 	)
 
 	task, err := scanRowIntoTask(row)
 	if err == sql.ErrNoRows {
-		return nil, ErrForbidden
+		// Release the connection first: SQLite has only one.
+		tx.Rollback()
+		return nil, s.missingOrForbidden(ctx, "tasks", taskID)
 	}
 	if err != nil {
 		return nil, err
 	}
+	if err := notifyTask(ctx, tx, task, requesterID, nullableInt(prevAssigneeID), notification.Mentions(task.Title, task.Description)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return task, nil
 }
 
-func (s *Store) GetAssignedTasks(userID int) ([]*types.Task, error) {
-	rows, err := s.db.Query(
+func (s *Store) GetAssignedTasks(ctx context.Context, userID int) ([]*types.Task, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.GetAssignedTasks")
+	rows, err := s.db.QueryContext(
+		ctx,
 		`SELECT
 			t.id,
 			t.goal_id,
@@ -570,6 +907,9 @@ func (s *Store) GetAssignedTasks(userID int) ([]*types.Task, error) {
 			t.is_completed,
 			t.assignee_id,
 			t.created_by,
+			CAST(t.due_date AS TEXT),
+			t.recurrence,
+			t.estimate_minutes,
 			t.created_at,
 			g.title AS goal_title,
 			TRIM(CONCAT(assignee_u.first_name, ' ', assignee_u.last_name)) AS assignee_name,
@@ -581,8 +921,8 @@ func (s *Store) GetAssignedTasks(userID int) ([]*types.Task, error) {
 		 WHERE t.assignee_id = $1
 		 ORDER BY
 			CASE WHEN t.is_completed THEN 1 ELSE 0 END,
-			CASE t.priority WHEN 'high' THEN 0 WHEN 'medium' THEN 1 ELSE 2 END,
-			t.created_at DESC`,
+			t.assignee_position,
+			t.id`,
 		userID,
 	)
 	if err != nil {
@@ -602,8 +942,10 @@ func (s *Store) GetAssignedTasks(userID int) ([]*types.Task, error) {
 	return tasks, rows.Err()
 }
 
-func (s *Store) ListUsers() ([]*types.UserLookup, error) {
-	rows, err := s.db.Query(
+func (s *Store) ListUsers(ctx context.Context) ([]*types.UserLookup, error) {
+	ctx = tracing.WithStatementName(ctx, "tracker.ListUsers")
+	rows, err := s.db.QueryContext(
+		ctx,
 		`SELECT id, TRIM(CONCAT(first_name, ' ', last_name)) AS full_name
 		 FROM users
 		 ORDER BY first_name, last_name, id`,
@@ -624,21 +966,82 @@ func (s *Store) ListUsers() ([]*types.UserLookup, error) {
 	return users, rows.Err()
 }
 
+// notifyTask notifies a task's assignee when it changed from
+// prevAssigneeID and the users mentioned in it but not in prevMentions.
+func notifyTask(ctx context.Context, tx *sql.Tx, task *types.Task, actorID int, prevAssigneeID *int, prevMentions []string) error {
+	event := notification.Event{Type: notification.Assigned, ActorID: actorID, GoalID: task.GoalID, TaskID: &task.ID}
+	if task.AssigneeID != nil && (prevAssigneeID == nil || *prevAssigneeID != *task.AssigneeID) {
+		if err := notification.NotifyUser(ctx, tx, *task.AssigneeID, event); err != nil {
+			return err
+		}
+	}
+	event.Type = notification.Mentioned
+	mentions := notification.NewMentions(prevMentions, notification.Mentions(task.Title, task.Description))
+	return notification.NotifyMentions(ctx, tx, mentions, event)
+}
+
+// forUpdate locks the rows a read-modify-write transaction reads. SQLite
+// has no row locks; its writers are serialized.
+func (s *Store) forUpdate() string {
+	if s.sqlite {
+		return ""
+	}
+	return " FOR UPDATE"
+}
+
+// missingOrForbidden explains why an ownership-scoped statement matched no
+// rows: ErrNotFound when the row does not exist, ErrForbidden otherwise.
+func (s *Store) missingOrForbidden(ctx context.Context, table string, id int) error {
+	ctx = tracing.WithStatementName(ctx, "tracker.CheckOwnership")
+	var exists bool
+	err := s.db.QueryRowContext(
+		ctx,
+		"SELECT EXISTS (SELECT 1 FROM "+table+" WHERE id = $1)",
+		id,
+	).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return ErrForbidden
+}
+
 type rowScanner interface {
 	Scan(dest ...any) error
 }
 
+// withExtraColumns lets the scanRowInto helpers read rows that carry
+// additional trailing columns, which are scanned into extra.
+func withExtraColumns(row rowScanner, extra ...any) rowScanner {
+	return extraColumnsScanner{row: row, extra: extra}
+}
+
+type extraColumnsScanner struct {
+	row   rowScanner
+	extra []any
+}
+
+func (e extraColumnsScanner) Scan(dest ...any) error {
+	return e.row.Scan(append(dest, e.extra...)...)
+}
+
 func scanRowIntoGoal(row rowScanner) (*types.Goal, error) {
 	g := new(types.Goal)
-	if err := row.Scan(&g.ID, &g.Title, &g.Description, &g.Priority, &g.Status, &g.OwnerID, &g.CreatedAt); err != nil {
+	var dueDate sql.NullString
+	if err := row.Scan(&g.ID, &g.Title, &g.Description, &g.Priority, &g.Status, &g.OwnerID, &dueDate, &g.AutoStatus, &g.ProgressWeight, &g.CreatedAt); err != nil {
 		return nil, err
 	}
+	g.DueDate = nullableString(dueDate)
 	return g, nil
 }
 
 func scanRowIntoTask(row rowScanner) (*types.Task, error) {
 	task := new(types.Task)
 	var assigneeID sql.NullInt64
+	var dueDate, recurrence sql.NullString
+	var estimate sql.NullInt64
 	if err := row.Scan(
 		&task.ID,
 		&task.GoalID,
@@ -648,11 +1051,17 @@ func scanRowIntoTask(row rowScanner) (*types.Task, error) {
 		&task.IsCompleted,
 		&assigneeID,
 		&task.CreatedBy,
+		&dueDate,
+		&recurrence,
+		&estimate,
 		&task.CreatedAt,
 	); err != nil {
 		return nil, err
 	}
 	task.Priority = normalizePriority(task.Priority)
+	task.DueDate = nullableString(dueDate)
+	task.Recurrence = recurrence.String
+	task.EstimateMinutes = nullableInt(estimate)
 	if assigneeID.Valid {
 		value := int(assigneeID.Int64)
 		task.AssigneeID = &value
@@ -665,6 +1074,8 @@ func scanRowIntoTaskWithLookups(row rowScanner) (*types.Task, error) {
 	var assigneeID sql.NullInt64
 	var assigneeName sql.NullString
 	var creatorName sql.NullString
+	var dueDate, recurrence sql.NullString
+	var estimate sql.NullInt64
 	if err := row.Scan(
 		&task.ID,
 		&task.GoalID,
@@ -674,6 +1085,9 @@ func scanRowIntoTaskWithLookups(row rowScanner) (*types.Task, error) {
 		&task.IsCompleted,
 		&assigneeID,
 		&task.CreatedBy,
+		&dueDate,
+		&recurrence,
+		&estimate,
 		&task.CreatedAt,
 		&task.GoalTitle,
 		&assigneeName,
@@ -682,6 +1096,9 @@ func scanRowIntoTaskWithLookups(row rowScanner) (*types.Task, error) {
 		return nil, err
 	}
 	task.Priority = normalizePriority(task.Priority)
+	task.DueDate = nullableString(dueDate)
+	task.Recurrence = recurrence.String
+	task.EstimateMinutes = nullableInt(estimate)
 	if assigneeID.Valid {
 		value := int(assigneeID.Int64)
 		task.AssigneeID = &value
@@ -695,6 +1112,38 @@ func scanRowIntoTaskWithLookups(row rowScanner) (*types.Task, error) {
 	return task, nil
 }
 
+func nullableString(value sql.NullString) *string {
+	if !value.Valid {
+		return nil
+	}
+	return &value.String
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	result := int(value.Int64)
+	return &result
+}
+
+// assignedAt is the assigned_at of a new task: now when it has an
+// assignee.
+func assignedAt(assigneeID *int, now time.Time) *time.Time {
+	if assigneeID == nil {
+		return nil
+	}
+	return &now
+}
+
+// nullIfEmpty stores an empty optional text value as NULL.
+func nullIfEmpty(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
 func normalizePriority(priority string) string {
 	switch priority {
 	case "high", "medium", "low":
@@ -704,6 +1153,15 @@ func normalizePriority(priority string) string {
 	}
 }
 
+func normalizeProgressWeight(weight string) string {
+	switch weight {
+	case "count", "priority", "estimate":
+		return weight
+	default:
+		return "count"
+	}
+}
+
 func normalizeGoalStatus(status string) string {
 	switch status {
 	case "todo", "in_progress", "achieved":
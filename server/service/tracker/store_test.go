@@ -28,6 +28,8 @@ func (s stubScanner) Scan(dest ...any) error {
 			*d = s.values[i].(time.Time)
 		case *sql.NullInt64:
 			*d = s.values[i].(sql.NullInt64)
+		case *sql.NullString:
+			*d = s.values[i].(sql.NullString)
 		default:
 			return errors.New("unsupported destination type")
 		}
@@ -38,12 +40,12 @@ func (s stubScanner) Scan(dest ...any) error {
 func TestScanRowIntoGoal(t *testing.T) {
 	now := time.Now()
 	goal, err := scanRowIntoGoal(stubScanner{
-		values: []any{1, "Build app", "Description", "high", "in_progress", 2, now},
+		values: []any{1, "Build app", "Description", "high", "in_progress", 2, sql.NullString{String: "2026-03-31", Valid: true}, true, "priority", now},
 	})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if goal.ID != 1 || goal.OwnerID != 2 || goal.Priority != "high" || goal.Status != "in_progress" {
+	if goal.ID != 1 || goal.OwnerID != 2 || goal.Priority != "high" || goal.Status != "in_progress" || goal.DueDate == nil || *goal.DueDate != "2026-03-31" || !goal.AutoStatus || goal.ProgressWeight != "priority" {
 		t.Fatalf("unexpected goal data: %+v", goal)
 	}
 }
@@ -60,6 +62,9 @@ func TestScanRowIntoTask(t *testing.T) {
 			true,
 			sql.NullInt64{Int64: 4, Valid: true},
 			3,
+			sql.NullString{},
+			sql.NullString{String: "FREQ=DAILY", Valid: true},
+			sql.NullInt64{Int64: 90, Valid: true},
 			now,
 		},
 	})
@@ -67,7 +72,7 @@ func TestScanRowIntoTask(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if task.ID != 1 || task.GoalID != 2 || !task.IsCompleted || task.Priority != "low" || task.AssigneeID == nil || *task.AssigneeID != 4 {
+	if task.ID != 1 || task.GoalID != 2 || !task.IsCompleted || task.Priority != "low" || task.AssigneeID == nil || *task.AssigneeID != 4 || task.DueDate != nil || task.Recurrence != "FREQ=DAILY" || task.EstimateMinutes == nil || *task.EstimateMinutes != 90 {
 		t.Fatalf("unexpected task data: %+v", task)
 	}
 }
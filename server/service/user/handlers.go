@@ -1,22 +1,30 @@
 package user
 
 import (
-	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/metrics"
 	"VyacheslavKuchumov/test-backend/service/auth"
 	"VyacheslavKuchumov/test-backend/types"
 	"VyacheslavKuchumov/test-backend/utils"
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"time"
+)
 
-	"github.com/go-playground/validator/v10"
+var (
+	errUserExists         = errors.New("user with this email already exists")
+	errInvalidCredentials = errors.New("User not found, invalid email or password")
 )
 
 type Handler struct {
-	store types.UserStore
+	store         types.UserStore
+	jwtSecret     []byte
+	jwtExpiration time.Duration
 }
 
-func NewHandler(store types.UserStore) *Handler {
-	return &Handler{store: store}
+func NewHandler(store types.UserStore, jwtSecret []byte, jwtExpiration time.Duration) *Handler {
+	return &Handler{store: store, jwtSecret: jwtSecret, jwtExpiration: jwtExpiration}
 }
 
 // HandleLogin godoc
@@ -28,21 +36,33 @@ func NewHandler(store types.UserStore) *Handler {
 // @Param payload body types.LoginUserPayload true "Login payload"
 // @Success 200 {object} types.LoginResponse
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
 // @Router /login [post]
 func (h *Handler) HandleLogin(w http.ResponseWriter, r *http.Request) {
 	var payload types.LoginUserPayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
+		return
+	}
+
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
 		return
 	}
 
-	token, err := h.createSessionToken(payload)
+	token, err := h.createSessionToken(r.Context(), payload)
+	if errors.Is(err, errInvalidCredentials) {
+		metrics.LoginFailed()
+		utils.WriteProblem(w, http.StatusBadRequest, utils.CodeInvalidCredentials, err.Error(), nil)
+		return
+	}
 	if err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	auth.SetAuthCookie(w, token)
+	metrics.LoginSucceeded()
+	auth.SetAuthCookie(w, token, h.jwtExpiration)
 	utils.WriteJSON(w, http.StatusOK, types.LoginResponse{Token: token})
 }
 
@@ -55,17 +75,28 @@ func (h *Handler) HandleLogin(w http.ResponseWriter, r *http.Request) {
 // @Param payload body types.RegisterUserPayload true "Registration payload"
 // @Success 201 {object} map[string]interface{}
 // @Failure 400 {object} types.ErrorResponse
+// @Failure 409 {object} types.ErrorResponse
+// @Failure 500 {object} types.ErrorResponse
 // @Router /register [post]
 func (h *Handler) HandleRegister(w http.ResponseWriter, r *http.Request) {
 	var payload types.RegisterUserPayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
 		return
 	}
 
-	err := h.registerUser(payload)
+	if err := utils.Validate.Struct(payload); err != nil {
+		utils.WriteValidationError(w, err)
+		return
+	}
+
+	err := h.registerUser(r.Context(), payload)
+	if errors.Is(err, errUserExists) {
+		utils.WriteError(w, http.StatusConflict, err)
+		return
+	}
 	if err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
 	}
 
@@ -79,19 +110,20 @@ func (h *Handler) HandleRegister(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {object} types.UserProfile
-// @Failure 403 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /profile [get]
 func (h *Handler) HandleGetProfile(w http.ResponseWriter, r *http.Request) {
 	userID := auth.GetUserIDFromContext(r.Context())
 	if userID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
-	user, err := h.store.GetUserByID(userID)
+	user, err := h.store.GetUserByID(r.Context(), userID)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -108,31 +140,31 @@ func (h *Handler) HandleGetProfile(w http.ResponseWriter, r *http.Request) {
 // @Param payload body types.UpdateProfilePayload true "Profile payload"
 // @Success 200 {object} types.UserProfile
 // @Failure 400 {object} types.ErrorResponse
-// @Failure 403 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /profile [put]
 func (h *Handler) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 	userID := auth.GetUserIDFromContext(r.Context())
 	if userID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
 	var payload types.UpdateProfilePayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
 		return
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid payload %v", errors))
+		utils.WriteValidationError(w, err)
 		return
 	}
 
-	user, err := h.store.UpdateUserProfile(userID, payload)
+	user, err := h.store.UpdateUserProfile(r.Context(), userID, payload)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -149,36 +181,38 @@ func (h *Handler) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
 // @Param payload body types.UpdatePasswordPayload true "Password payload"
 // @Success 204 {object} nil
 // @Failure 400 {object} types.ErrorResponse
-// @Failure 403 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
+// @Failure 404 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /profile/password [put]
 func (h *Handler) HandleUpdatePassword(w http.ResponseWriter, r *http.Request) {
 	userID := auth.GetUserIDFromContext(r.Context())
 	if userID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
 	var payload types.UpdatePasswordPayload
 	if err := utils.ParseJSON(r, &payload); err != nil {
-		utils.WriteError(w, http.StatusBadRequest, err)
+		utils.WriteJSONError(w, err)
 		return
 	}
 
 	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("invalid payload %v", errors))
+		utils.WriteValidationError(w, err)
 		return
 	}
 
-	user, err := h.store.GetUserByID(userID)
+	user, err := h.store.GetUserByID(r.Context(), userID)
 	if err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
 	if !auth.ComparePasswords(user.Password, payload.CurrentPassword) {
-		utils.WriteError(w, http.StatusBadRequest, fmt.Errorf("current password is invalid"))
+		utils.WriteProblem(w, http.StatusBadRequest, utils.CodeValidationFailed, "current password is invalid", []types.FieldError{
+			{Field: "currentPassword", Code: "invalid", Message: "is invalid"},
+		})
 		return
 	}
 
@@ -188,8 +222,8 @@ func (h *Handler) HandleUpdatePassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.store.UpdateUserPassword(userID, hashedPassword); err != nil {
-		utils.WriteError(w, http.StatusInternalServerError, err)
+	if err := h.store.UpdateUserPassword(r.Context(), userID, hashedPassword); err != nil {
+		utils.WriteStoreError(w, err, storeErrors)
 		return
 	}
 
@@ -203,17 +237,17 @@ func (h *Handler) HandleUpdatePassword(w http.ResponseWriter, r *http.Request) {
 // @Produce json
 // @Security BearerAuth
 // @Success 200 {array} types.UserLookup
-// @Failure 403 {object} types.ErrorResponse
+// @Failure 401 {object} types.ErrorResponse
 // @Failure 500 {object} types.ErrorResponse
 // @Router /users/lookup [get]
 func (h *Handler) HandleListUsers(w http.ResponseWriter, r *http.Request) {
 	userID := auth.GetUserIDFromContext(r.Context())
 	if userID <= 0 {
-		utils.WriteError(w, http.StatusForbidden, fmt.Errorf("permission denied"))
+		utils.WriteError(w, http.StatusUnauthorized, fmt.Errorf("authentication required"))
 		return
 	}
 
-	users, err := h.store.ListUsers()
+	users, err := h.store.ListUsers(r.Context())
 	if err != nil {
 		utils.WriteError(w, http.StatusInternalServerError, err)
 		return
@@ -222,15 +256,10 @@ func (h *Handler) HandleListUsers(w http.ResponseWriter, r *http.Request) {
 	utils.WriteJSON(w, http.StatusOK, users)
 }
 
-func (h *Handler) registerUser(payload types.RegisterUserPayload) error {
-	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		return fmt.Errorf("Invalid payload %v", errors)
-	}
-
-	_, err := h.store.GetUserByEmail(payload.Email)
+func (h *Handler) registerUser(ctx context.Context, payload types.RegisterUserPayload) error {
+	_, err := h.store.GetUserByEmail(ctx, payload.Email)
 	if err == nil {
-		return fmt.Errorf("User with email %s already exists", payload.Email)
+		return errUserExists
 	}
 
 	hashedPassword, err := auth.HashPassword(payload.Password)
@@ -238,7 +267,7 @@ func (h *Handler) registerUser(payload types.RegisterUserPayload) error {
 		return err
 	}
 
-	err = h.store.CreateUser(types.User{
+	err = h.store.CreateUser(ctx, types.User{
 		FirstName: payload.FirstName,
 		LastName:  payload.LastName,
 		Email:     payload.Email,
@@ -251,22 +280,16 @@ func (h *Handler) registerUser(payload types.RegisterUserPayload) error {
 	return nil
 }
 
-func (h *Handler) createSessionToken(payload types.LoginUserPayload) (string, error) {
-	if err := utils.Validate.Struct(payload); err != nil {
-		errors := err.(validator.ValidationErrors)
-		return "", fmt.Errorf("Invalid payload %v", errors)
-	}
-
-	u, err := h.store.GetUserByEmail(payload.Email)
+func (h *Handler) createSessionToken(ctx context.Context, payload types.LoginUserPayload) (string, error) {
+	u, err := h.store.GetUserByEmail(ctx, payload.Email)
 	if err != nil {
-		return "", fmt.Errorf("User not found, invalid email or password")
+		return "", errInvalidCredentials
 	}
 
 	if !auth.ComparePasswords(u.Password, payload.Password) {
-		return "", fmt.Errorf("User not found, invalid email or password")
+		return "", errInvalidCredentials
 	}
-	secret := []byte(config.Envs.JWTSecret)
-	token, err := auth.CreateJWT(secret, u.ID)
+	token, err := auth.CreateJWT(h.jwtSecret, h.jwtExpiration, u.ID)
 	if err != nil {
 		return "", err
 	}
@@ -274,6 +297,11 @@ func (h *Handler) createSessionToken(payload types.LoginUserPayload) (string, er
 	return token, nil
 }
 
+// storeErrors gives the status for a user the store cannot find.
+var storeErrors = map[error]int{
+	ErrNotFound: http.StatusNotFound,
+}
+
 func toUserProfile(user *types.User) types.UserProfile {
 	return types.UserProfile{
 		ID:        user.ID,
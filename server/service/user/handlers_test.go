@@ -10,13 +10,14 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 )
 
 func TestUserServiceHandlers(t *testing.T) {
 	userStore := &mockUserStore{userByEmail: map[string]*types.User{}}
-	handler := NewHandler(userStore)
+	handler := NewHandler(userStore, []byte("secret"), time.Hour)
 
 	t.Run("should fail if the user payload is invalid", func(t *testing.T) {
 		payload := types.RegisterUserPayload{
@@ -68,6 +69,29 @@ func TestUserServiceHandlers(t *testing.T) {
 		}
 	})
 
+	t.Run("should reject a duplicate email with conflict", func(t *testing.T) {
+		payload := types.RegisterUserPayload{
+			FirstName: "correct_user",
+			LastName:  "fsdsd",
+			Email:     "test123@gmail.com",
+			Password:  "asdfd",
+		}
+		marshaled, _ := json.Marshal(payload)
+		req, err := http.NewRequest(http.MethodPost, "/register", bytes.NewBuffer(marshaled))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rr := httptest.NewRecorder()
+		router := chi.NewRouter()
+
+		router.HandleFunc("/register", handler.HandleRegister)
+		router.ServeHTTP(rr, req)
+
+		if rr.Code != http.StatusConflict {
+			t.Errorf("Expected status code %d, got %d", http.StatusConflict, rr.Code)
+		}
+	})
+
 	t.Run("should fail login when user does not exist", func(t *testing.T) {
 		payload := types.LoginUserPayload{
 			Email:    "missing@example.com",
@@ -213,7 +237,7 @@ func (m *mockUserStore) ensure() {
 	}
 }
 
-func (m *mockUserStore) GetUserByEmail(email string) (*types.User, error) {
+func (m *mockUserStore) GetUserByEmail(ctx context.Context, email string) (*types.User, error) {
 	m.ensure()
 	u, ok := m.userByEmail[email]
 	if !ok {
@@ -222,7 +246,7 @@ func (m *mockUserStore) GetUserByEmail(email string) (*types.User, error) {
 	return u, nil
 }
 
-func (m *mockUserStore) GetUserByID(id int) (*types.User, error) {
+func (m *mockUserStore) GetUserByID(ctx context.Context, id int) (*types.User, error) {
 	m.ensure()
 	u, ok := m.userByID[id]
 	if !ok {
@@ -231,7 +255,7 @@ func (m *mockUserStore) GetUserByID(id int) (*types.User, error) {
 	return u, nil
 }
 
-func (m *mockUserStore) CreateUser(user types.User) error {
+func (m *mockUserStore) CreateUser(ctx context.Context, user types.User) error {
 	m.ensure()
 	if user.ID == 0 {
 		user.ID = len(m.userByID) + 1
@@ -242,7 +266,7 @@ func (m *mockUserStore) CreateUser(user types.User) error {
 	return nil
 }
 
-func (m *mockUserStore) UpdateUserProfile(userID int, payload types.UpdateProfilePayload) (*types.User, error) {
+func (m *mockUserStore) UpdateUserProfile(ctx context.Context, userID int, payload types.UpdateProfilePayload) (*types.User, error) {
 	m.ensure()
 	u, ok := m.userByID[userID]
 	if !ok {
@@ -254,7 +278,7 @@ func (m *mockUserStore) UpdateUserProfile(userID int, payload types.UpdateProfil
 	return u, nil
 }
 
-func (m *mockUserStore) UpdateUserPassword(userID int, hashedPassword string) error {
+func (m *mockUserStore) UpdateUserPassword(ctx context.Context, userID int, hashedPassword string) error {
 	m.ensure()
 	u, ok := m.userByID[userID]
 	if !ok {
@@ -264,7 +288,7 @@ func (m *mockUserStore) UpdateUserPassword(userID int, hashedPassword string) er
 	return nil
 }
 
-func (m *mockUserStore) ListUsers() ([]*types.UserLookup, error) {
+func (m *mockUserStore) ListUsers(ctx context.Context) ([]*types.UserLookup, error) {
 	m.ensure()
 	users := make([]*types.UserLookup, 0, len(m.userByID))
 	for _, u := range m.userByID {
@@ -1,11 +1,15 @@
 package user
 
 import (
+	"VyacheslavKuchumov/test-backend/tracing"
 	"VyacheslavKuchumov/test-backend/types"
+	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 )
 
+var ErrNotFound = errors.New("user not found")
+
 type Store struct {
 	db *sql.DB
 }
@@ -14,14 +18,16 @@ func NewStore(db *sql.DB) *Store {
 	return &Store{db: db}
 }
 
-func (s *Store) GetUserByEmail(email string) (*types.User, error) {
-	row := s.db.QueryRow(
+func (s *Store) GetUserByEmail(ctx context.Context, email string) (*types.User, error) {
+	ctx = tracing.WithStatementName(ctx, "user.GetUserByEmail")
+	row := s.db.QueryRowContext(
+		ctx,
 		"SELECT id, first_name, last_name, email, password, created_at FROM users WHERE email = $1",
 		email,
 	)
 	u, err := scanRowIntoUser(row)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found")
+		return nil, ErrNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -29,14 +35,16 @@ func (s *Store) GetUserByEmail(email string) (*types.User, error) {
 	return u, nil
 }
 
-func (s *Store) GetUserByID(id int) (*types.User, error) {
-	row := s.db.QueryRow(
+func (s *Store) GetUserByID(ctx context.Context, id int) (*types.User, error) {
+	ctx = tracing.WithStatementName(ctx, "user.GetUserByID")
+	row := s.db.QueryRowContext(
+		ctx,
 		"SELECT id, first_name, last_name, email, password, created_at FROM users WHERE id = $1",
 		id,
 	)
 	u, err := scanRowIntoUser(row)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found")
+		return nil, ErrNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -44,8 +52,10 @@ func (s *Store) GetUserByID(id int) (*types.User, error) {
 	return u, nil
 }
 
-func (s *Store) CreateUser(user types.User) error {
-	_, err := s.db.Exec(
+func (s *Store) CreateUser(ctx context.Context, user types.User) error {
+	ctx = tracing.WithStatementName(ctx, "user.CreateUser")
+	_, err := s.db.ExecContext(
+		ctx,
 		"INSERT INTO users (first_name, last_name, email, password) VALUES ($1, $2, $3, $4)",
 		user.FirstName, user.LastName, user.Email, user.Password,
 	)
@@ -56,8 +66,10 @@ func (s *Store) CreateUser(user types.User) error {
 	return nil
 }
 
-func (s *Store) UpdateUserProfile(userID int, payload types.UpdateProfilePayload) (*types.User, error) {
-	row := s.db.QueryRow(
+func (s *Store) UpdateUserProfile(ctx context.Context, userID int, payload types.UpdateProfilePayload) (*types.User, error) {
+	ctx = tracing.WithStatementName(ctx, "user.UpdateUserProfile")
+	row := s.db.QueryRowContext(
+		ctx,
 		`UPDATE users
 		 SET first_name = $1, last_name = $2
 		 WHERE id = $3
@@ -69,7 +81,7 @@ func (s *Store) UpdateUserProfile(userID int, payload types.UpdateProfilePayload
 
 	u, err := scanRowIntoUser(row)
 	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("user not found")
+		return nil, ErrNotFound
 	}
 	if err != nil {
 		return nil, err
@@ -77,8 +89,10 @@ func (s *Store) UpdateUserProfile(userID int, payload types.UpdateProfilePayload
 	return u, nil
 }
 
-func (s *Store) UpdateUserPassword(userID int, hashedPassword string) error {
-	result, err := s.db.Exec(
+func (s *Store) UpdateUserPassword(ctx context.Context, userID int, hashedPassword string) error {
+	ctx = tracing.WithStatementName(ctx, "user.UpdateUserPassword")
+	result, err := s.db.ExecContext(
+		ctx,
 		`UPDATE users
 		 SET password = $1
 		 WHERE id = $2`,
@@ -94,13 +108,15 @@ func (s *Store) UpdateUserPassword(userID int, hashedPassword string) error {
 		return err
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("user not found")
+		return ErrNotFound
 	}
 	return nil
 }
 
-func (s *Store) ListUsers() ([]*types.UserLookup, error) {
-	rows, err := s.db.Query(
+func (s *Store) ListUsers(ctx context.Context) ([]*types.UserLookup, error) {
+	ctx = tracing.WithStatementName(ctx, "user.ListUsers")
+	rows, err := s.db.QueryContext(
+		ctx,
 		`SELECT id, TRIM(CONCAT(first_name, ' ', last_name)) AS full_name
 		 FROM users
 		 ORDER BY first_name, last_name, id`,
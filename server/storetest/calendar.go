@@ -0,0 +1,52 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/calendar"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testCalendarTokens(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	first, second := strings.Repeat("a", 64), strings.Repeat("b", 64)
+
+	if _, err := s.Calendar.GetUserIDByCalendarToken(ctx, first); !errors.Is(err, calendar.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound before a token is set, got %v", err)
+	}
+	if err := s.Calendar.SetCalendarToken(ctx, ada, first); err != nil {
+		t.Fatal(err)
+	}
+	if id, err := s.Calendar.GetUserIDByCalendarToken(ctx, first); err != nil || id != ada {
+		t.Fatalf("expected token to resolve to %d, got %d, %v", ada, id, err)
+	}
+	if err := s.Calendar.SetCalendarToken(ctx, bob, first); err == nil {
+		t.Fatal("expected a token hash used by another user to be rejected")
+	}
+
+	if err := s.Calendar.SetCalendarToken(ctx, ada, second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Calendar.GetUserIDByCalendarToken(ctx, first); !errors.Is(err, calendar.ErrNotFound) {
+		t.Fatalf("expected the replaced token to stop resolving, got %v", err)
+	}
+	if id, err := s.Calendar.GetUserIDByCalendarToken(ctx, second); err != nil || id != ada {
+		t.Fatalf("expected new token to resolve to %d, got %d, %v", ada, id, err)
+	}
+
+	if err := s.Calendar.SetCalendarToken(ctx, 999999, first); err == nil {
+		t.Fatal("expected a token for an unknown user to be rejected")
+	}
+
+	for range 2 {
+		if err := s.Calendar.DeleteCalendarToken(ctx, ada); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Calendar.GetUserIDByCalendarToken(ctx, second); !errors.Is(err, calendar.ErrNotFound) {
+		t.Fatalf("expected the deleted token to stop resolving, got %v", err)
+	}
+}
@@ -0,0 +1,194 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/digest"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testDigestSettings(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	cat := createUser(t, s, "Cat", "Stevens", "cat@example.com")
+
+	settings, err := s.Digests.GetDigestSettings(ctx, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *settings != digest.DefaultSettings() {
+		t.Fatalf("expected the default settings, got %+v", settings)
+	}
+
+	weekly := types.DigestSettings{Frequency: digest.Weekly, TimeZone: "Europe/Berlin", SendHour: 7, Weekday: 5}
+	if settings, err = s.Digests.SetDigestSettings(ctx, ada, weekly); err != nil {
+		t.Fatal(err)
+	}
+	if *settings != weekly {
+		t.Fatalf("expected the weekly settings, got %+v", settings)
+	}
+	for _, userID := range []int{bob, cat} {
+		if _, err := s.Digests.SetDigestSettings(ctx, userID, types.DigestSettings{Frequency: digest.Daily, TimeZone: "UTC", SendHour: 8, Weekday: 1}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := s.Digests.SetDigestSettings(ctx, cat, types.DigestSettings{Frequency: digest.Off, TimeZone: "UTC", SendHour: 8, Weekday: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Users whose digest is off are not recipients.
+	recipients, err := s.DigestOutbox.DigestRecipients(ctx, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recipients) != 2 || recipients[0].UserID != ada || recipients[1].UserID != bob {
+		t.Fatalf("expected Ada and Bob, got %+v", recipients)
+	}
+	if r := recipients[0]; r.Email != "ada@example.com" || r.Name != "Ada Lovelace" || r.DigestSettings != weekly {
+		t.Fatalf("unexpected recipient %+v", r)
+	}
+	if page, err := s.DigestOutbox.DigestRecipients(ctx, ada, 10); err != nil || len(page) != 1 || page[0].UserID != bob {
+		t.Fatalf("expected Bob after Ada, got %+v, %v", page, err)
+	}
+	if page, err := s.DigestOutbox.DigestRecipients(ctx, 0, 1); err != nil || len(page) != 1 || page[0].UserID != ada {
+		t.Fatalf("expected a page of Ada, got %+v, %v", page, err)
+	}
+
+	// A date's digest is claimed once, and not after a later date's.
+	sentAt := time.Date(2026, 3, 27, 6, 0, 0, 0, time.UTC)
+	if claimed, err := s.DigestOutbox.ClaimDigest(ctx, bob, "2026-03-27", sentAt); err != nil || !claimed {
+		t.Fatalf("expected the digest to be claimed, got %v, %v", claimed, err)
+	}
+	for _, sentOn := range []string{"2026-03-27", "2026-03-26"} {
+		if claimed, err := s.DigestOutbox.ClaimDigest(ctx, bob, sentOn, sentAt); err != nil || claimed {
+			t.Fatalf("expected the digest of %s not to be claimed, got %v, %v", sentOn, claimed, err)
+		}
+	}
+	if claimed, err := s.DigestOutbox.ClaimDigest(ctx, cat, "2026-03-27", sentAt); err != nil || claimed {
+		t.Fatalf("expected no claim while the digest is off, got %v, %v", claimed, err)
+	}
+	settings, err = s.Digests.GetDigestSettings(ctx, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if settings.LastSentOn == nil || *settings.LastSentOn != "2026-03-27" || settings.LastSentAt == nil || !settings.LastSentAt.Equal(sentAt) {
+		t.Fatalf("expected the claim to be recorded, got %+v", settings)
+	}
+
+	// Changing the settings keeps the last send; releasing restores it.
+	if settings, err = s.Digests.SetDigestSettings(ctx, bob, weekly); err != nil || settings.LastSentOn == nil {
+		t.Fatalf("expected the last send to be kept, got %+v, %v", settings, err)
+	}
+	if err := s.DigestOutbox.ReleaseDigest(ctx, bob, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if settings, err = s.Digests.GetDigestSettings(ctx, bob); err != nil || settings.LastSentOn != nil || settings.LastSentAt != nil {
+		t.Fatalf("expected the claim to be released, got %+v, %v", settings, err)
+	}
+	if claimed, err := s.DigestOutbox.ClaimDigest(ctx, bob, "2026-03-27", sentAt); err != nil || !claimed {
+		t.Fatalf("expected the released digest to be claimed again, got %v, %v", claimed, err)
+	}
+}
+
+func testBuildDigest(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	launch := createGoal(t, s, ada, "Launch", "high", "todo")
+	docs := createGoal(t, s, ada, "Docs", "low", "todo")
+	other := createGoal(t, s, bob, "Other", "medium", "todo")
+
+	yesterday, tomorrow := "2026-03-26", "2026-03-28"
+	newTask := func(goalID, creatorID int, title string, dueDate *string) *types.Task {
+		t.Helper()
+		task, err := s.Tracker.CreateTask(ctx, goalID, creatorID, types.CreateTaskPayload{Title: title, Priority: "medium", AssigneeID: &bob, DueDate: dueDate})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task
+	}
+	undated := newTask(launch, ada, "Undated", nil)
+	later := newTask(launch, ada, "Later", &tomorrow)
+	late := newTask(launch, ada, "Late", &yesterday)
+	write := newTask(docs, ada, "Write", nil)
+	done := newTask(launch, ada, "Done", nil)
+	mine := newTask(other, bob, "Mine", nil)
+	createTask(t, s, launch, ada, "Unassigned", "low", nil)
+
+	since := time.Now().Truncate(time.Microsecond)
+	completeTask(t, s, done.ID, launch, "Done", "medium", &bob)
+	completeTask(t, s, mine.ID, other, "Mine", "medium", &bob)
+	// Saving a task unchanged keeps it assigned before since; a new and a
+	// reassigned task are newly assigned.
+	if _, err := s.Tracker.UpdateTask(ctx, later.ID, ada, types.UpdateTaskPayload{
+		GoalID: launch, Title: "Later", Priority: "medium", AssigneeID: &bob, DueDate: &tomorrow,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	fresh := newTask(docs, ada, "Fresh", nil)
+	if _, err := s.Tracker.AssignTask(ctx, write.ID, ada, types.AssignTaskPayload{AssigneeID: &ada}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Tracker.AssignTask(ctx, write.ID, ada, types.AssignTaskPayload{AssigneeID: &bob}); err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := s.Digests.BuildDigest(ctx, bob, since, "2026-03-27")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Name != "Bob Builder" || d.Date != "2026-03-27" || !d.Since.Equal(since) {
+		t.Fatalf("unexpected digest header %+v", d)
+	}
+	// Goals are ordered by title and tasks by due date, undated last.
+	if len(d.Goals) != 2 || d.Goals[0].ID != docs || d.Goals[0].Title != "Docs" || d.Goals[1].ID != launch {
+		t.Fatalf("expected Docs and Launch, got %+v", d.Goals)
+	}
+	assertIDs(t, digestTaskIDs(d.Goals[0].Tasks), write.ID, fresh.ID)
+	assertIDs(t, digestTaskIDs(d.Goals[1].Tasks), late.ID, later.ID, undated.ID)
+	if task := d.Goals[1].Tasks[0]; !task.Overdue || task.DueDate == nil || *task.DueDate != yesterday ||
+		task.Title != "Late" || task.GoalTitle != "Launch" || task.Priority != "medium" {
+		t.Fatalf("expected Late to be overdue, got %+v", task)
+	}
+	if d.Goals[1].Tasks[1].Overdue || d.Goals[1].Tasks[2].Overdue {
+		t.Fatalf("expected only Late to be overdue, got %+v", d.Goals[1].Tasks)
+	}
+	assertIDs(t, digestTaskIDs(d.NewlyAssigned), write.ID, fresh.ID)
+	// Bob owns only Other, where Mine was completed.
+	if len(d.Completed) != 1 || d.Completed[0].TaskID != mine.ID || d.Completed[0].GoalTitle != "Other" ||
+		d.Completed[0].AssigneeName != "Bob Builder" || d.Completed[0].CompletedAt.Before(since) {
+		t.Fatalf("expected Mine to be completed, got %+v", d.Completed)
+	}
+
+	adas, err := s.Digests.BuildDigest(ctx, ada, since, "2026-03-27")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(adas.Goals) != 0 || len(adas.NewlyAssigned) != 0 || len(adas.Completed) != 1 || adas.Completed[0].TaskID != done.ID {
+		t.Fatalf("expected only Done in Ada's digest, got %+v", adas)
+	}
+
+	// Nothing happened after now.
+	d, err = s.Digests.BuildDigest(ctx, bob, time.Now().Add(time.Hour), "2026-03-27")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Goals) != 2 || len(d.NewlyAssigned) != 0 || len(d.Completed) != 0 {
+		t.Fatalf("expected only open tasks, got %+v", d)
+	}
+
+	if _, err := s.Digests.BuildDigest(ctx, 999999, since, "2026-03-27"); !errors.Is(err, digest.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing user, got %v", err)
+	}
+}
+
+func digestTaskIDs(tasks []*types.DigestTask) []int {
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
@@ -0,0 +1,247 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testStreamGoals(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	first := createGoal(t, s, ada, "Ship it", "low", "todo")
+	empty := createGoal(t, s, bob, "Someday", "high", "achieved")
+	later := createTask(t, s, first, ada, "Write docs", "low", &bob)
+	earlier := createTask(t, s, first, ada, "Fix bugs", "high", nil)
+	completeTask(t, s, earlier, first, "Fix bugs", "high", nil)
+
+	var goals []*types.GoalWithTasks
+	if err := s.Export.StreamGoals(ctx, bob, func(goal *types.GoalWithTasks) error {
+		goals = append(goals, goal)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(goals) != 2 || goals[0].ID != first || goals[1].ID != empty {
+		t.Fatalf("expected every goal in id order, got %+v", goals)
+	}
+	if goals[0].OwnerName != "Ada Lovelace" {
+		t.Fatalf("unexpected owner name %q", goals[0].OwnerName)
+	}
+	assertIDs(t, taskIDs(goals[0].Tasks), later, earlier)
+	if goals[1].Tasks == nil || len(goals[1].Tasks) != 0 {
+		t.Fatalf("expected an empty task list, got %v", goals[1].Tasks)
+	}
+
+	task := goals[0].Tasks[0]
+	if task.AssigneeID == nil || *task.AssigneeID != bob || task.AssigneeName != "Bob Builder" || task.CreatedByName != "Ada Lovelace" {
+		t.Fatalf("unexpected lookups %+v", task)
+	}
+	if !goals[0].Tasks[1].IsCompleted || task.CreatedAt.IsZero() {
+		t.Fatalf("unexpected task fields %+v", goals[0].Tasks[1])
+	}
+
+	stop := errors.New("stop")
+	calls := 0
+	err := s.Export.StreamGoals(ctx, ada, func(*types.GoalWithTasks) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Fatalf("expected the callback error after one call, got %v after %d", err, calls)
+	}
+}
+
+func testInstanceRoundTrip(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	goal := createGoal(t, s, ada, "Ship it", "high", "in_progress")
+	someday := createGoal(t, s, bob, "Someday", "low", "todo")
+	if _, err := s.Tracker.UpdateGoal(ctx, someday, bob, types.CreateGoalPayload{
+		Title: "Someday", Priority: "low", AutoStatus: true, ProgressWeight: "estimate",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	task := createTask(t, s, goal, ada, "Write docs", "medium", &bob)
+	completeTask(t, s, task, goal, "Write docs", "medium", &bob)
+	fixBugs := createTask(t, s, goal, ada, "Fix bugs", "high", nil)
+	if _, err := s.Tracker.MoveTask(ctx, fixBugs, ada, types.MoveTaskPayload{BeforeID: &task}); err != nil {
+		t.Fatal(err)
+	}
+	dueDate := "2026-03-31"
+	estimate := 90
+	release, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{
+		Title: "Release", Priority: "high", DueDate: &dueDate, Recurrence: "FREQ=MONTHLY;BYMONTHDAY=31", EstimateMinutes: &estimate,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Recurrence.CreateNextOccurrence(ctx, release.ID, "2026-05-31"); err != nil {
+		t.Fatal(err)
+	}
+	worked := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	if _, err := s.Time.AddTimeEntry(ctx, bob, task, worked, 1800, "draft"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Time.StartTimer(ctx, ada, task, worked, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	before := exportInstance(t, s)
+	if len(before.Users) != 2 || len(before.Goals) != 2 || len(before.Goals[0].Tasks) != 4 {
+		t.Fatalf("unexpected export %+v", before)
+	}
+	if exported := before.Goals[0].Tasks[2]; exported.DueDate == nil || *exported.DueDate != dueDate ||
+		exported.Recurrence != "FREQ=MONTHLY;BYMONTHDAY=31" || !exported.Recurred ||
+		exported.EstimateMinutes == nil || *exported.EstimateMinutes != estimate {
+		t.Fatalf("expected the due date, recurrence state and estimate to be exported, got %+v", exported)
+	}
+	if tasks := before.Goals[0].Tasks; tasks[0].CompletedAt == nil || tasks[1].CompletedAt != nil {
+		t.Fatalf("expected a completion time on the completed task only, got %v and %v", tasks[0].CompletedAt, tasks[1].CompletedAt)
+	}
+	if tasks := before.Goals[0].Tasks; tasks[1].Position >= tasks[0].Position ||
+		tasks[0].AssigneePosition == nil || tasks[1].AssigneePosition != nil {
+		t.Fatalf("expected the goal and assignee positions to be exported, got %+v and %+v", tasks[0], tasks[1])
+	}
+	if entries := before.Goals[0].Tasks[0].TimeEntries; len(entries) != 1 || entries[0].UserID != bob ||
+		!entries[0].StartedAt.Equal(worked) || entries[0].DurationSeconds != 1800 || entries[0].Note != "draft" {
+		t.Fatalf("expected only the finished time entry to be exported, got %+v", entries)
+	}
+	if exported := before.Goals[1]; !exported.AutoStatus || exported.ProgressWeight != "estimate" {
+		t.Fatalf("expected the auto status and progress weighting to be exported, got %+v", exported)
+	}
+	if before.Users[0].PasswordHash != "hashed" {
+		t.Fatalf("expected the password hash to be exported, got %q", before.Users[0].PasswordHash)
+	}
+
+	// Add a user the instance does not have yet.
+	snapshot := before
+	snapshot.Users = append(snapshot.Users, types.InstanceUser{
+		ID: 99, FirstName: "Cy", LastName: "Clone", Email: "cy@example.com", PasswordHash: "hashed-cy",
+	})
+	snapshot.Goals = append(snapshot.Goals, types.InstanceGoal{
+		ID: 50, Title: "Imported", Priority: "low", Status: "todo", OwnerID: 99,
+		Tasks: []types.InstanceTask{{ID: 51, Title: "Say hi", Priority: "low", CreatedBy: 99, AssigneeID: &before.Users[0].ID}},
+	})
+
+	summary, err := s.Export.ImportInstance(ctx, snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *summary != (types.ImportSummary{UsersCreated: 1, UsersMatched: 2, Goals: 3, Tasks: 5, TimeEntries: 1}) {
+		t.Fatalf("unexpected summary %+v", *summary)
+	}
+
+	after := exportInstance(t, s)
+	if len(after.Users) != 3 || len(after.Goals) != 5 {
+		t.Fatalf("expected 3 users and 5 goals after import, got %d and %d", len(after.Users), len(after.Goals))
+	}
+	cy, err := s.Users.GetUserByEmail(ctx, "cy@example.com")
+	if err != nil || cy.Password != "hashed-cy" {
+		t.Fatalf("expected the imported user with its hash, got %+v, %v", cy, err)
+	}
+
+	for i, original := range before.Goals {
+		copied := after.Goals[len(before.Goals)+i]
+		if copied.ID == original.ID || copied.Title != original.Title || copied.Priority != original.Priority ||
+			copied.Status != original.Status || copied.OwnerID != original.OwnerID || !copied.CreatedAt.Equal(original.CreatedAt) ||
+			copied.AutoStatus != original.AutoStatus || copied.ProgressWeight != original.ProgressWeight ||
+			(copied.DueDate == nil) != (original.DueDate == nil) || (copied.DueDate != nil && *copied.DueDate != *original.DueDate) {
+			t.Fatalf("goal %d did not round-trip: %+v vs %+v", original.ID, copied, original)
+		}
+		if len(copied.Tasks) != len(original.Tasks) {
+			t.Fatalf("goal %d: expected %d tasks, got %d", original.ID, len(original.Tasks), len(copied.Tasks))
+		}
+		for j, originalTask := range original.Tasks {
+			copiedTask := copied.Tasks[j]
+			copiedTask.ID, originalTask.ID = 0, 0
+			if !sameInstanceTask(copiedTask, originalTask) {
+				t.Fatalf("task did not round-trip: %+v vs %+v", copiedTask, originalTask)
+			}
+		}
+	}
+
+	imported := after.Goals[len(after.Goals)-1]
+	if imported.OwnerID != cy.ID || imported.Tasks[0].CreatedBy != cy.ID || *imported.Tasks[0].AssigneeID != ada || imported.CreatedAt.IsZero() {
+		t.Fatalf("expected references remapped to stored users, got %+v", imported)
+	}
+}
+
+func testImportRejectsUnknownReferences(t *testing.T, s Stores) {
+	createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+
+	_, err := s.Export.ImportInstance(context.Background(), types.InstanceSnapshot{
+		Version: 1,
+		Users: []types.InstanceUser{
+			{ID: 1, FirstName: "New", LastName: "User", Email: "new@example.com", PasswordHash: "hashed"},
+		},
+		Goals: []types.InstanceGoal{
+			{ID: 1, Title: "Orphan", Priority: "low", Status: "todo", OwnerID: 2},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error for an owner missing from the snapshot")
+	}
+
+	if _, err := s.Users.GetUserByEmail(context.Background(), "new@example.com"); err == nil {
+		t.Fatal("expected a failed import to leave no users behind")
+	}
+}
+
+func exportInstance(t *testing.T, s Stores) types.InstanceSnapshot {
+	t.Helper()
+	snapshot := types.InstanceSnapshot{Version: 1}
+	err := s.Export.ExportInstance(
+		context.Background(),
+		func(u *types.InstanceUser) error {
+			snapshot.Users = append(snapshot.Users, *u)
+			return nil
+		},
+		func(g *types.InstanceGoal) error {
+			snapshot.Goals = append(snapshot.Goals, *g)
+			return nil
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snapshot
+}
+
+func sameInstanceTask(a, b types.InstanceTask) bool {
+	if (a.AssigneeID == nil) != (b.AssigneeID == nil) || (a.AssigneeID != nil && *a.AssigneeID != *b.AssigneeID) {
+		return false
+	}
+	if (a.DueDate == nil) != (b.DueDate == nil) || (a.DueDate != nil && *a.DueDate != *b.DueDate) {
+		return false
+	}
+	if (a.EstimateMinutes == nil) != (b.EstimateMinutes == nil) || (a.EstimateMinutes != nil && *a.EstimateMinutes != *b.EstimateMinutes) {
+		return false
+	}
+	if a.Position != b.Position || (a.AssigneePosition == nil) != (b.AssigneePosition == nil) ||
+		(a.AssigneePosition != nil && *a.AssigneePosition != *b.AssigneePosition) {
+		return false
+	}
+	if (a.CompletedAt == nil) != (b.CompletedAt == nil) || (a.CompletedAt != nil && !a.CompletedAt.Equal(*b.CompletedAt)) {
+		return false
+	}
+	if len(a.TimeEntries) != len(b.TimeEntries) {
+		return false
+	}
+	for i, entry := range a.TimeEntries {
+		other := b.TimeEntries[i]
+		if entry.UserID != other.UserID || !entry.StartedAt.Equal(other.StartedAt) ||
+			entry.DurationSeconds != other.DurationSeconds || entry.Note != other.Note {
+			return false
+		}
+	}
+	a.AssigneeID, b.AssigneeID = nil, nil
+	return a.CreatedAt.Equal(b.CreatedAt) && a.Title == b.Title && a.Description == b.Description &&
+		a.Priority == b.Priority && a.IsCompleted == b.IsCompleted && a.CreatedBy == b.CreatedBy &&
+		a.Recurrence == b.Recurrence && a.Recurred == b.Recurred
+}
@@ -0,0 +1,101 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"testing"
+	"time"
+)
+
+func testCreateGoalsWithTasks(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	shipped := time.Date(2026, time.March, 2, 16, 30, 0, 0, time.UTC)
+
+	ids, err := s.Import.CreateGoalsWithTasks(ctx, ada, []types.ImportedGoal{
+		{
+			Goal: types.CreateGoalPayload{Title: "Launch", Description: "From Jira", Priority: "high", Status: "in_progress"},
+			Tasks: []types.ImportedTask{
+				{Task: types.CreateTaskPayload{Title: "Write copy", Priority: "low", AssigneeID: &bob}},
+				{Task: types.CreateTaskPayload{Title: "Ship it", Priority: "high"}, IsCompleted: true, CompletedAt: &shipped},
+				{Task: types.CreateTaskPayload{Title: "Celebrate", Priority: "low"}, IsCompleted: true},
+			},
+		},
+		{Goal: types.CreateGoalPayload{Title: "Empty list", Priority: "medium", Status: "todo"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 {
+		t.Fatalf("expected two goal ids, got %v", ids)
+	}
+
+	goal, err := s.Tracker.GetGoalWithTasks(ctx, ids[0], ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goal.OwnerID != ada || goal.Description != "From Jira" || goal.Status != "in_progress" || len(goal.Tasks) != 3 {
+		t.Fatalf("unexpected goal %+v", goal)
+	}
+	for _, task := range goal.Tasks {
+		if task.CreatedBy != ada {
+			t.Fatalf("expected tasks created by the importing user, got %+v", task)
+		}
+		switch task.Title {
+		case "Write copy":
+			if task.AssigneeID == nil || *task.AssigneeID != bob || task.IsCompleted {
+				t.Fatalf("unexpected task %+v", task)
+			}
+		case "Ship it":
+			if task.AssigneeID != nil || !task.IsCompleted {
+				t.Fatalf("unexpected task %+v", task)
+			}
+		}
+	}
+
+	empty, err := s.Tracker.GetGoalWithTasks(ctx, ids[1], ada)
+	if err != nil || len(empty.Tasks) != 0 {
+		t.Fatalf("expected an empty goal, got %+v, %v", empty, err)
+	}
+
+	// The source's completion time is kept; without one it stays unknown
+	// rather than becoming the import time.
+	for _, task := range exportInstance(t, s).Goals[0].Tasks {
+		switch task.Title {
+		case "Ship it":
+			if task.CompletedAt == nil || !task.CompletedAt.Equal(shipped) {
+				t.Fatalf("expected the source completion time, got %v", task.CompletedAt)
+			}
+		case "Celebrate", "Write copy":
+			if task.CompletedAt != nil {
+				t.Fatalf("expected no completion time on %q, got %v", task.Title, task.CompletedAt)
+			}
+		}
+	}
+}
+
+func testCreateGoalsWithTasksRollback(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	missing := ada + 100
+
+	_, err := s.Import.CreateGoalsWithTasks(ctx, ada, []types.ImportedGoal{
+		{Goal: types.CreateGoalPayload{Title: "First", Priority: "low", Status: "todo"}},
+		{
+			Goal:  types.CreateGoalPayload{Title: "Second", Priority: "low", Status: "todo"},
+			Tasks: []types.ImportedTask{{Task: types.CreateTaskPayload{Title: "Orphan", Priority: "low", AssigneeID: &missing}}},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unknown assignee")
+	}
+
+	goals, err := s.Tracker.GetGoalsByOwner(ctx, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(goals) != 0 {
+		t.Fatalf("expected a failed import to leave no goals behind, got %d", len(goals))
+	}
+}
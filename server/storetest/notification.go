@@ -0,0 +1,320 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testNotificationEvents(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	cat := createUser(t, s, "Cat", "Stevens", "cat@example.com")
+	goal := createGoal(t, s, ada, "Launch", "high", "todo")
+
+	// The assignee and the mentioned users are notified, but not the actor.
+	task, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{
+		Title: "Write docs", Description: "Ask @CAT@example.com and @ada@example.com", Priority: "high", AssigneeID: &bob,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	createTask(t, s, goal, ada, "Own task", "low", &ada)
+
+	bobs := listNotifications(t, s, bob, types.NotificationQuery{Limit: 10})
+	if bobs.UnreadCount != 1 || len(bobs.Notifications) != 1 {
+		t.Fatalf("expected one notification for Bob, got %+v", bobs)
+	}
+	n := bobs.Notifications[0]
+	if n.Type != notification.Assigned || n.ActorID == nil || *n.ActorID != ada || n.ActorName != "Ada Lovelace" ||
+		n.GoalID != goal || n.GoalTitle != "Launch" || n.TaskID == nil || *n.TaskID != task.ID || n.TaskTitle != "Write docs" ||
+		n.ReadAt != nil || n.CreatedAt.IsZero() {
+		t.Fatalf("unexpected assignment %+v", n)
+	}
+	cats := listNotifications(t, s, cat, types.NotificationQuery{Limit: 10})
+	if len(cats.Notifications) != 1 || cats.Notifications[0].Type != notification.Mentioned {
+		t.Fatalf("expected Cat to be mentioned, got %+v", cats)
+	}
+	if adas := listNotifications(t, s, ada, types.NotificationQuery{Limit: 10}); len(adas.Notifications) != 0 {
+		t.Fatalf("expected no notifications for the actor, got %+v", adas)
+	}
+
+	// Saving the task unchanged notifies nobody; reassigning it notifies
+	// the new assignee.
+	if _, err := s.Tracker.UpdateTask(ctx, task.ID, ada, types.UpdateTaskPayload{
+		GoalID: goal, Title: task.Title, Description: task.Description, Priority: "high", AssigneeID: &bob,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Tracker.AssignTask(ctx, task.ID, ada, types.AssignTaskPayload{AssigneeID: &cat}); err != nil {
+		t.Fatal(err)
+	}
+	if bobs := listNotifications(t, s, bob, types.NotificationQuery{Limit: 10}); bobs.UnreadCount != 1 {
+		t.Fatalf("expected no new notification for Bob, got %+v", bobs)
+	}
+	cats = listNotifications(t, s, cat, types.NotificationQuery{Limit: 10})
+	if len(cats.Notifications) != 2 || cats.Notifications[0].Type != notification.Assigned {
+		t.Fatalf("expected Cat's assignment first, got %+v", cats)
+	}
+
+	// Completing the last task of an auto-status goal notifies its owner
+	// and assignees; there is no actor when the requester is unknown.
+	auto, err := s.Tracker.CreateGoal(ctx, ada, types.CreateGoalPayload{Title: "Auto", Priority: "low", AutoStatus: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	only := createTask(t, s, auto.ID, ada, "Only", "low", &bob)
+	completeTask(t, s, only, auto.ID, "Only", "low", &bob)
+	for _, userID := range []int{ada, bob} {
+		latest := listNotifications(t, s, userID, types.NotificationQuery{Limit: 1}).Notifications
+		if len(latest) != 1 || latest[0].Type != notification.GoalAchieved || latest[0].GoalID != auto.ID ||
+			latest[0].ActorID != nil || latest[0].TaskID != nil {
+			t.Fatalf("expected user %d to hear the goal was achieved, got %+v", userID, latest)
+		}
+	}
+
+	// Preferences skip users who chose none.
+	if _, err := s.Notifications.SetNotificationPreferences(ctx, bob, []types.NotificationPreference{
+		{Type: notification.GoalAchieved, Channel: notification.None},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	createTask(t, s, goal, ada, "Review", "low", &bob)
+	before := listNotifications(t, s, bob, types.NotificationQuery{Limit: 10}).UnreadCount
+	if _, err := s.Tracker.UpdateGoal(ctx, goal, ada, types.CreateGoalPayload{Title: "Launch", Priority: "high", Status: "achieved"}); err != nil {
+		t.Fatal(err)
+	}
+	if after := listNotifications(t, s, bob, types.NotificationQuery{Limit: 10}).UnreadCount; after != before {
+		t.Fatalf("expected Bob not to be notified, got %d unread after %d", after, before)
+	}
+	latest := listNotifications(t, s, cat, types.NotificationQuery{Limit: 1}).Notifications
+	if len(latest) != 1 || latest[0].Type != notification.GoalAchieved || latest[0].GoalID != goal || *latest[0].ActorID != ada {
+		t.Fatalf("expected Cat to hear the goal was achieved, got %+v", latest)
+	}
+	// Saving an achieved goal again notifies nobody.
+	if _, err := s.Tracker.UpdateGoal(ctx, goal, ada, types.CreateGoalPayload{Title: "Launch", Priority: "high", Status: "achieved"}); err != nil {
+		t.Fatal(err)
+	}
+	if again := listNotifications(t, s, cat, types.NotificationQuery{Limit: 1}).Notifications; again[0].ID != latest[0].ID {
+		t.Fatalf("expected no new notification, got %+v", again[0])
+	}
+
+	// Deleting a task deletes its notifications.
+	if err := s.Tracker.DeleteTask(ctx, task.ID, ada); err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range listNotifications(t, s, cat, types.NotificationQuery{Limit: 10}).Notifications {
+		if n.TaskID != nil && *n.TaskID == task.ID {
+			t.Fatalf("expected the task's notifications to be deleted, got %+v", n)
+		}
+	}
+}
+
+func testNotificationInbox(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	goal := createGoal(t, s, ada, "Launch", "high", "todo")
+	for _, title := range []string{"First", "Second", "Third"} {
+		createTask(t, s, goal, ada, title, "low", &bob)
+	}
+
+	page := listNotifications(t, s, bob, types.NotificationQuery{Limit: 2})
+	if page.UnreadCount != 3 || len(page.Notifications) != 2 ||
+		page.Notifications[0].TaskTitle != "Third" || page.Notifications[1].TaskTitle != "Second" {
+		t.Fatalf("expected the two newest notifications, got %+v", page.Notifications)
+	}
+	next := listNotifications(t, s, bob, types.NotificationQuery{Limit: 2, BeforeID: page.Notifications[1].ID})
+	if len(next.Notifications) != 1 || next.Notifications[0].TaskTitle != "First" {
+		t.Fatalf("expected the oldest notification on the next page, got %+v", next.Notifications)
+	}
+	first := next.Notifications[0].ID
+
+	if err := s.Notifications.MarkNotificationRead(ctx, first, ada); !errors.Is(err, notification.ErrForbidden) {
+		t.Fatalf("expected ErrForbidden for another user's notification, got %v", err)
+	}
+	if err := s.Notifications.MarkNotificationRead(ctx, first+1000, bob); !errors.Is(err, notification.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing notification, got %v", err)
+	}
+	for range 2 {
+		if err := s.Notifications.MarkNotificationRead(ctx, first, bob); err != nil {
+			t.Fatal(err)
+		}
+	}
+	unread := listNotifications(t, s, bob, types.NotificationQuery{Limit: 10, UnreadOnly: true})
+	if unread.UnreadCount != 2 || len(unread.Notifications) != 2 {
+		t.Fatalf("expected two unread notifications, got %+v", unread)
+	}
+	all := listNotifications(t, s, bob, types.NotificationQuery{Limit: 10})
+	if read := all.Notifications[2]; read.ID != first || read.ReadAt == nil {
+		t.Fatalf("expected the first notification to be read, got %+v", read)
+	}
+
+	if err := s.Notifications.MarkAllNotificationsRead(ctx, bob); err != nil {
+		t.Fatal(err)
+	}
+	all = listNotifications(t, s, bob, types.NotificationQuery{Limit: 10})
+	if all.UnreadCount != 0 || len(all.Notifications) != 3 {
+		t.Fatalf("expected every notification read, got %+v", all)
+	}
+	for _, n := range all.Notifications {
+		if n.ReadAt == nil {
+			t.Fatalf("expected a read time, got %+v", n)
+		}
+	}
+}
+
+func testNotificationPreferences(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+
+	preferences, err := s.Notifications.GetNotificationPreferences(ctx, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(preferences) != len(notification.Types) {
+		t.Fatalf("expected a preference per event type, got %+v", preferences)
+	}
+	for i, preference := range preferences {
+		if preference != (types.NotificationPreference{Type: notification.Types[i], Channel: notification.InApp}) {
+			t.Fatalf("expected in-app by default, got %+v", preference)
+		}
+	}
+
+	for _, channel := range []string{notification.None, notification.Email} {
+		preferences, err = s.Notifications.SetNotificationPreferences(ctx, ada, []types.NotificationPreference{
+			{Type: notification.DueSoon, Channel: channel},
+			{Type: notification.Assigned, Channel: notification.Email},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(preferences) != len(notification.Types) || preferences[0].Channel != notification.Email ||
+			preferences[1].Channel != notification.InApp || preferences[2].Channel != channel {
+			t.Fatalf("expected the changed preferences, got %+v", preferences)
+		}
+	}
+	stored, err := s.Notifications.GetNotificationPreferences(ctx, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range stored {
+		if stored[i] != preferences[i] {
+			t.Fatalf("expected %+v, got %+v", preferences[i], stored[i])
+		}
+	}
+}
+
+func testNotificationOutbox(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	goal := createGoal(t, s, ada, "Launch", "high", "todo")
+	if _, err := s.Notifications.SetNotificationPreferences(ctx, bob, []types.NotificationPreference{
+		{Type: notification.Assigned, Channel: notification.Email},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	today := time.Now().UTC().Format(time.DateOnly)
+	tomorrow := time.Now().UTC().AddDate(0, 0, 1).Format(time.DateOnly)
+	nextWeek := time.Now().UTC().AddDate(0, 0, 7).Format(time.DateOnly)
+	task, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{Title: "Due today", Priority: "high", AssigneeID: &bob, DueDate: &today})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Email notifications are not listed in the app.
+	if bobs := listNotifications(t, s, bob, types.NotificationQuery{Limit: 10}); len(bobs.Notifications) != 0 {
+		t.Fatalf("expected no in-app notifications, got %+v", bobs)
+	}
+	now := time.Now()
+	emails, err := s.Outbox.PendingEmails(ctx, now, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(emails) != 1 || emails[0].Attempts != 0 || emails[0].To != "bob@example.com" || emails[0].RecipientName != "Bob Builder" ||
+		emails[0].Type != notification.Assigned || emails[0].TaskTitle != "Due today" || emails[0].ActorName != "Ada Lovelace" {
+		t.Fatalf("expected Bob's assignment email, got %+v", emails)
+	}
+	if more, err := s.Outbox.PendingEmails(ctx, now, emails[0].ID, 10); err != nil || len(more) != 0 {
+		t.Fatalf("expected no emails after the last one, got %+v, %v", more, err)
+	}
+
+	claimed, err := s.Outbox.ClaimEmail(ctx, emails[0].ID, time.Now())
+	if err != nil || !claimed {
+		t.Fatalf("expected the email to be claimed, got %v, %v", claimed, err)
+	}
+	if claimed, err := s.Outbox.ClaimEmail(ctx, emails[0].ID, time.Now()); err != nil || claimed {
+		t.Fatalf("expected a second claim to fail, got %v, %v", claimed, err)
+	}
+	if pending, err := s.Outbox.PendingEmails(ctx, now, 0, 10); err != nil || len(pending) != 0 {
+		t.Fatalf("expected no pending emails, got %+v, %v", pending, err)
+	}
+
+	// A released email waits for its retry time.
+	retryAt := now.Add(time.Hour)
+	if err := s.Outbox.ReleaseEmail(ctx, emails[0].ID, retryAt); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := s.Outbox.PendingEmails(ctx, now, 0, 10); err != nil || len(pending) != 0 {
+		t.Fatalf("expected the released email to wait, got %+v, %v", pending, err)
+	}
+	if pending, err := s.Outbox.PendingEmails(ctx, retryAt, 0, 10); err != nil || len(pending) != 1 || pending[0].Attempts != 1 {
+		t.Fatalf("expected the released email to be pending after one attempt, got %+v, %v", pending, err)
+	}
+
+	// Open assigned tasks due in the window are reminded of once per due
+	// date.
+	done, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{Title: "Done", Priority: "low", AssigneeID: &bob, DueDate: &today})
+	if err != nil {
+		t.Fatal(err)
+	}
+	completeTask(t, s, done.ID, goal, "Done", "low", &bob)
+	if _, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{Title: "Unassigned", Priority: "low", DueDate: &today}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{Title: "Later", Priority: "low", AssigneeID: &bob, DueDate: &nextWeek}); err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []int{1, 0} {
+		reminded, err := s.Outbox.NotifyDueSoon(ctx, today, tomorrow)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reminded != expected {
+			t.Fatalf("run %d: expected %d reminders, got %d", i, expected, reminded)
+		}
+	}
+	bobs := listNotifications(t, s, bob, types.NotificationQuery{Limit: 10})
+	if len(bobs.Notifications) != 1 {
+		t.Fatalf("expected one reminder, got %+v", bobs.Notifications)
+	}
+	if n := bobs.Notifications[0]; n.Type != notification.DueSoon || *n.TaskID != task.ID || n.DueDate == nil ||
+		*n.DueDate != today || n.ActorID != nil {
+		t.Fatalf("unexpected reminder %+v", n)
+	}
+
+	// A new due date is reminded of again.
+	if _, err := s.Tracker.UpdateTask(ctx, task.ID, ada, types.UpdateTaskPayload{
+		GoalID: goal, Title: "Due today", Priority: "high", AssigneeID: &bob, DueDate: &tomorrow,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if reminded, err := s.Outbox.NotifyDueSoon(ctx, today, tomorrow); err != nil || reminded != 1 {
+		t.Fatalf("expected the moved task to be reminded of, got %d, %v", reminded, err)
+	}
+}
+
+func listNotifications(t *testing.T, s Stores, userID int, query types.NotificationQuery) *types.NotificationList {
+	t.Helper()
+	list, err := s.Notifications.ListNotifications(context.Background(), userID, query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return list
+}
@@ -0,0 +1,216 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+)
+
+func testMoveTask(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, ada, "Ship it", "medium", "todo")
+	otherGoalID := createGoal(t, s, ada, "Other", "medium", "todo")
+
+	a := createTask(t, s, goalID, ada, "Task a", "low", &bob)
+	b := createTask(t, s, goalID, ada, "Task b", "high", &bob)
+	c := createTask(t, s, goalID, ada, "Task c", "medium", &bob)
+	d := createTask(t, s, goalID, ada, "Task d", "low", nil)
+	elsewhere := createTask(t, s, otherGoalID, ada, "Elsewhere", "low", &bob)
+
+	move := func(taskID, requesterID int, payload types.MoveTaskPayload) error {
+		t.Helper()
+		task, err := s.Tracker.MoveTask(ctx, taskID, requesterID, payload)
+		if err == nil && task.ID != taskID {
+			t.Fatalf("expected task %d, got %+v", taskID, task)
+		}
+		return err
+	}
+	goalOrder := func(expected ...int) {
+		t.Helper()
+		goal, err := s.Tracker.GetGoalWithTasks(ctx, goalID, ada)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, taskIDs(goal.Tasks), expected...)
+	}
+	assignedOrder := func(expected ...int) {
+		t.Helper()
+		tasks, err := s.Tracker.GetAssignedTasks(ctx, bob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, taskIDs(tasks), expected...)
+	}
+
+	goalOrder(a, b, c, d)
+	if err := move(d, ada, types.MoveTaskPayload{AfterID: &a}); err != nil {
+		t.Fatal(err)
+	}
+	goalOrder(a, d, b, c)
+	if err := move(a, ada, types.MoveTaskPayload{BeforeID: &c}); err != nil {
+		t.Fatal(err)
+	}
+	goalOrder(d, b, a, c)
+	if err := move(c, ada, types.MoveTaskPayload{AfterID: &d, BeforeID: &b}); err != nil {
+		t.Fatal(err)
+	}
+	goalOrder(d, c, b, a)
+	if err := move(d, ada, types.MoveTaskPayload{AfterID: &a}); err != nil {
+		t.Fatal(err)
+	}
+	goalOrder(c, b, a, d)
+
+	// The assigned list is ordered separately.
+	assignedOrder(a, b, c, elsewhere)
+	if err := move(elsewhere, bob, types.MoveTaskPayload{List: "assigned", BeforeID: &a}); err != nil {
+		t.Fatal(err)
+	}
+	assignedOrder(elsewhere, a, b, c)
+	goalOrder(c, b, a, d)
+
+	// Moving into the same gap again and again renumbers the list.
+	expected := []int{c, b, a, d}
+	for range 40 {
+		last := expected[len(expected)-1]
+		if err := move(last, ada, types.MoveTaskPayload{AfterID: &expected[0], BeforeID: &expected[1]}); err != nil {
+			t.Fatal(err)
+		}
+		expected = append([]int{expected[0], last}, expected[1:len(expected)-1]...)
+		goalOrder(expected...)
+	}
+
+	// Completed tasks stay after the open ones.
+	completeTask(t, s, expected[0], goalID, "Task done", "low", &bob)
+	goalOrder(append(expected[1:], expected[0])...)
+
+	if err := move(999, ada, types.MoveTaskPayload{AfterID: &a}); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound, got %v", err)
+	}
+	if err := move(a, bob, types.MoveTaskPayload{AfterID: &b}); !errors.Is(err, tracker.ErrForbidden) {
+		t.Fatalf("expected tracker.ErrForbidden for another user's goal, got %v", err)
+	}
+	if err := move(d, bob, types.MoveTaskPayload{List: "assigned", AfterID: &a}); !errors.Is(err, tracker.ErrForbidden) {
+		t.Fatalf("expected tracker.ErrForbidden for another user's task, got %v", err)
+	}
+	if err := move(a, ada, types.MoveTaskPayload{AfterID: &elsewhere}); !errors.Is(err, tracker.ErrInvalidAnchor) {
+		t.Fatalf("expected tracker.ErrInvalidAnchor for an anchor in another goal, got %v", err)
+	}
+	if err := move(a, bob, types.MoveTaskPayload{List: "assigned", BeforeID: &d}); !errors.Is(err, tracker.ErrInvalidAnchor) {
+		t.Fatalf("expected tracker.ErrInvalidAnchor for an unassigned anchor, got %v", err)
+	}
+	if err := move(a, bob, types.MoveTaskPayload{List: "assigned", AfterID: &c, BeforeID: &elsewhere}); !errors.Is(err, tracker.ErrInvalidAnchor) {
+		t.Fatalf("expected tracker.ErrInvalidAnchor for anchors out of order, got %v", err)
+	}
+}
+
+func testTaskPlacement(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, ada, "Ship it", "medium", "todo")
+	otherGoalID := createGoal(t, s, ada, "Other", "medium", "todo")
+
+	first := createTask(t, s, goalID, ada, "First", "low", &bob)
+	second := createTask(t, s, goalID, ada, "Second", "low", nil)
+	other := createTask(t, s, otherGoalID, ada, "Other", "high", &bob)
+	if _, err := s.Tracker.MoveTask(ctx, second, ada, types.MoveTaskPayload{BeforeID: &first}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A task moved to another goal goes last there.
+	if _, err := s.Tracker.UpdateTask(ctx, other, ada, types.UpdateTaskPayload{
+		GoalID: goalID, Title: "Other", Priority: "high", AssigneeID: &bob,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	goal, err := s.Tracker.GetGoalWithTasks(ctx, goalID, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, taskIDs(goal.Tasks), second, first, other)
+
+	// A newly assigned task goes last on the assignee's list and keeps its
+	// place while the assignee stays.
+	if _, err := s.Tracker.MoveTask(ctx, other, bob, types.MoveTaskPayload{List: "assigned", BeforeID: &first}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Tracker.AssignTask(ctx, second, ada, types.AssignTaskPayload{AssigneeID: &bob}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Tracker.AssignTask(ctx, other, ada, types.AssignTaskPayload{AssigneeID: &bob}); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err := s.Tracker.GetAssignedTasks(ctx, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, taskIDs(tasks), other, first, second)
+
+	// Unassigning takes the task off the list; reassigning puts it last.
+	if _, err := s.Tracker.AssignTask(ctx, other, ada, types.AssignTaskPayload{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Tracker.AssignTask(ctx, other, ada, types.AssignTaskPayload{AssigneeID: &bob}); err != nil {
+		t.Fatal(err)
+	}
+	tasks, err = s.Tracker.GetAssignedTasks(ctx, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, taskIDs(tasks), first, second, other)
+}
+
+func testConcurrentMoves(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Stone", "bob@example.com")
+
+	// Each task is in a goal of its own, so only the assigned list's lock
+	// serializes the moves. Enough of them land in one gap to renumber it.
+	const moves = 30
+	anchor := createTask(t, s, createGoal(t, s, ada, "Anchor", "medium", "todo"), ada, "Anchor", "low", &bob)
+	next := createTask(t, s, createGoal(t, s, ada, "Next", "medium", "todo"), ada, "Next", "low", &bob)
+	var moved []int
+	for i := range moves {
+		title := fmt.Sprintf("Task %d", i)
+		moved = append(moved, createTask(t, s, createGoal(t, s, ada, title, "medium", "todo"), ada, title, "low", &bob))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, moves)
+	for _, taskID := range moved {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.Tracker.MoveTask(ctx, taskID, bob, types.MoveTaskPayload{List: "assigned", AfterID: &anchor})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Each move went straight after the anchor, so the moved tasks sit
+	// between it and the next task in some order.
+	tasks, err := s.Tracker.GetAssignedTasks(ctx, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := taskIDs(tasks)
+	if len(ids) != moves+2 || ids[0] != anchor || ids[len(ids)-1] != next {
+		t.Fatalf("expected the moved tasks between %d and %d, got %v", anchor, next, ids)
+	}
+	between := slices.Sorted(slices.Values(ids[1 : len(ids)-1]))
+	assertIDs(t, between, moved...)
+}
@@ -0,0 +1,53 @@
+package storetest_test
+
+import (
+	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/migrator"
+	"VyacheslavKuchumov/test-backend/service/calendar"
+	"VyacheslavKuchumov/test-backend/service/digest"
+	"VyacheslavKuchumov/test-backend/service/export"
+	"VyacheslavKuchumov/test-backend/service/importer"
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/service/report"
+	"VyacheslavKuchumov/test-backend/service/template"
+	"VyacheslavKuchumov/test-backend/service/timetrack"
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/service/user"
+	"VyacheslavKuchumov/test-backend/storetest"
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// TestPostgresConformance runs the suite against the database in
+// TEST_DATABASE_URL. Every subtest truncates the tracker tables, so point it
+// at a throwaway database.
+func TestPostgresConformance(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := migrator.Up(context.Background(), db, config.DriverPostgres); err != nil {
+		t.Fatalf("migrate up: %v", err)
+	}
+
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		if _, err := db.Exec("TRUNCATE users, goals, tasks RESTART IDENTITY CASCADE"); err != nil {
+			t.Fatal(err)
+		}
+		notificationStore := notification.NewStore(db)
+		digestStore := digest.NewStore(db)
+		trackerStore := tracker.NewStore(db)
+		return storetest.Stores{Users: user.NewStore(db), Tracker: trackerStore, Export: export.NewStore(db), Import: importer.NewStore(db), Calendar: calendar.NewStore(db), Recurrence: trackerStore, Templates: template.NewStore(db), Time: timetrack.NewStore(db), Reports: report.NewStore(db), Notifications: notificationStore, Outbox: notificationStore, Digests: digestStore, DigestOutbox: digestStore}
+	})
+}
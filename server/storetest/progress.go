@@ -0,0 +1,188 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"testing"
+)
+
+func testGoalProgress(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+
+	byPriority, err := s.Tracker.CreateGoal(ctx, ada, types.CreateGoalPayload{
+		Title: "Weighted", Priority: "high", Status: "todo", ProgressWeight: "priority",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byPriority.ProgressWeight != "priority" || byPriority.AutoStatus {
+		t.Fatalf("unexpected goal %+v", byPriority)
+	}
+	high := createTask(t, s, byPriority.ID, ada, "High", "high", nil)
+	completeTask(t, s, high, byPriority.ID, "High", "high", nil)
+	createTask(t, s, byPriority.ID, ada, "Medium", "medium", nil)
+	createTask(t, s, byPriority.ID, ada, "Low", "low", nil)
+
+	byEstimate, err := s.Tracker.CreateGoal(ctx, ada, types.CreateGoalPayload{
+		Title: "Estimated", Priority: "medium", Status: "todo", ProgressWeight: "estimate",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sixty, thirty := 60, 30
+	for _, estimate := range []*int{&sixty, &thirty, nil} {
+		task, err := s.Tracker.CreateTask(ctx, byEstimate.ID, ada, types.CreateTaskPayload{
+			Title: "Step", Priority: "medium", EstimateMinutes: estimate,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if estimate == &sixty {
+			if _, err := s.Tracker.UpdateTask(ctx, task.ID, ada, types.UpdateTaskPayload{
+				GoalID: byEstimate.ID, Title: "Step", Priority: "medium", IsCompleted: true, EstimateMinutes: estimate,
+			}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	empty := createGoal(t, s, ada, "Nothing yet", "low", "todo")
+
+	expected := map[int]types.GoalProgress{
+		// 3 of 3+2+1.
+		byPriority.ID: {CompletedTasks: 1, TotalTasks: 3, Percent: 50},
+		// 60 of 60+30+45, the unestimated task weighing the mean.
+		byEstimate.ID: {CompletedTasks: 1, TotalTasks: 3, Percent: 44},
+		empty:         {},
+	}
+	goals, err := s.Tracker.GetGoalsByOwner(ctx, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(goals) != 3 {
+		t.Fatalf("expected 3 goals, got %d", len(goals))
+	}
+	for _, goal := range goals {
+		if goal.Progress == nil || *goal.Progress != expected[goal.ID] {
+			t.Fatalf("goal %d: expected progress %+v, got %+v", goal.ID, expected[goal.ID], goal.Progress)
+		}
+		single, err := s.Tracker.GetGoalWithTasks(ctx, goal.ID, ada)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if single.Progress == nil || *single.Progress != expected[goal.ID] {
+			t.Fatalf("goal %d: expected progress %+v, got %+v", goal.ID, expected[goal.ID], single.Progress)
+		}
+	}
+}
+
+func testAutoStatus(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+
+	created, err := s.Tracker.CreateGoal(ctx, ada, types.CreateGoalPayload{
+		Title: "Automatic", Priority: "high", Status: "achieved", AutoStatus: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.AutoStatus || created.Status != "todo" {
+		t.Fatalf("expected a new auto-status goal to be todo, got %+v", created)
+	}
+	goal := created.ID
+	manual := createGoal(t, s, ada, "Manual", "high", "todo")
+
+	first := createTask(t, s, goal, ada, "First", "high", nil)
+	second := createTask(t, s, goal, ada, "Second", "high", nil)
+	assertGoalStatus(t, s, goal, ada, "todo")
+
+	completeTask(t, s, first, goal, "First", "high", nil)
+	assertGoalStatus(t, s, goal, ada, "in_progress")
+	completeTask(t, s, second, goal, "Second", "high", nil)
+	assertGoalStatus(t, s, goal, ada, "achieved")
+
+	// Reopening a task reverts the goal.
+	if _, err := s.Tracker.UpdateTask(ctx, second, ada, types.UpdateTaskPayload{GoalID: goal, Title: "Second", Priority: "high"}); err != nil {
+		t.Fatal(err)
+	}
+	assertGoalStatus(t, s, goal, ada, "in_progress")
+
+	// Moving the open task away completes the goal it left.
+	if _, err := s.Tracker.UpdateTask(ctx, second, ada, types.UpdateTaskPayload{GoalID: manual, Title: "Second", Priority: "high"}); err != nil {
+		t.Fatal(err)
+	}
+	assertGoalStatus(t, s, goal, ada, "achieved")
+	assertGoalStatus(t, s, manual, ada, "todo")
+
+	third := createTask(t, s, goal, ada, "Third", "medium", nil)
+	assertGoalStatus(t, s, goal, ada, "in_progress")
+	if err := s.Tracker.DeleteTask(ctx, third, ada); err != nil {
+		t.Fatal(err)
+	}
+	assertGoalStatus(t, s, goal, ada, "achieved")
+
+	// Completing the manual goal's task leaves its status alone.
+	completeTask(t, s, second, manual, "Second", "high", nil)
+	assertGoalStatus(t, s, manual, ada, "todo")
+
+	// Turning auto status on derives the status; turning it off keeps the
+	// payload's.
+	updated, err := s.Tracker.UpdateGoal(ctx, manual, ada, types.CreateGoalPayload{
+		Title: "Manual", Priority: "high", Status: "todo", AutoStatus: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !updated.AutoStatus || updated.Status != "achieved" {
+		t.Fatalf("expected the goal to be achieved once auto, got %+v", updated)
+	}
+	updated, err = s.Tracker.UpdateGoal(ctx, manual, ada, types.CreateGoalPayload{
+		Title: "Manual", Priority: "high", Status: "in_progress",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.AutoStatus || updated.Status != "in_progress" {
+		t.Fatalf("expected the payload status once manual, got %+v", updated)
+	}
+
+	// The next occurrence of a recurring task reopens the goal.
+	dueDate := "2026-03-02"
+	weekly, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{
+		Title: "Weekly", Priority: "low", DueDate: &dueDate, Recurrence: "FREQ=WEEKLY",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Tracker.UpdateTask(ctx, weekly.ID, ada, types.UpdateTaskPayload{
+		GoalID: goal, Title: "Weekly", Priority: "low", IsCompleted: true, DueDate: &dueDate, Recurrence: "FREQ=WEEKLY",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	assertGoalStatus(t, s, goal, ada, "achieved")
+	if _, err := s.Recurrence.CreateNextOccurrence(ctx, weekly.ID, "2026-03-09"); err != nil {
+		t.Fatal(err)
+	}
+	assertGoalStatus(t, s, goal, ada, "in_progress")
+
+	ids, err := s.Import.CreateGoalsWithTasks(ctx, ada, []types.ImportedGoal{{
+		Goal:  types.CreateGoalPayload{Title: "Imported", Priority: "low", Status: "todo", AutoStatus: true},
+		Tasks: []types.ImportedTask{{Task: types.CreateTaskPayload{Title: "Done", Priority: "low"}, IsCompleted: true}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertGoalStatus(t, s, ids[0], ada, "achieved")
+}
+
+func assertGoalStatus(t *testing.T, s Stores, goalID, ownerID int, expected string) {
+	t.Helper()
+	goal, err := s.Tracker.GetGoalWithTasks(context.Background(), goalID, ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goal.Status != expected {
+		t.Fatalf("goal %d: expected status %q, got %q", goalID, expected, goal.Status)
+	}
+}
@@ -0,0 +1,118 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func testRecurringTasks(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	goal := createGoal(t, s, ada, "Operations", "medium", "in_progress")
+	today, past, future := "2026-03-02", "2026-02-27", "2026-03-09"
+
+	recurring := func(title, due string) *types.Task {
+		t.Helper()
+		task, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{
+			Title: title, Description: "Runbook in the wiki", Priority: "high", AssigneeID: &ada,
+			DueDate: &due, Recurrence: "FREQ=WEEKLY;BYDAY=MO",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return task
+	}
+	overdue := recurring("Rotate certificates", past)
+	dueToday := recurring("Review dependencies", today)
+	recurring("Check backups", future)
+	completed := recurring("Renew domain", future)
+	if _, err := s.Tracker.UpdateTask(ctx, completed.ID, ada, types.UpdateTaskPayload{
+		GoalID: goal, Title: completed.Title, Priority: completed.Priority, IsCompleted: true, AssigneeID: &ada,
+		DueDate: &future, Recurrence: completed.Recurrence,
+	}); err != nil {
+		t.Fatal(err)
+	}
+	createTask(t, s, goal, ada, "One-off task", "low", nil)
+
+	listed, err := s.Tracker.GetGoalWithTasks(ctx, goal, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, task := range listed.Tasks {
+		if want := map[bool]string{true: "FREQ=WEEKLY;BYDAY=MO"}[task.Title != "One-off task"]; task.Recurrence != want {
+			t.Fatalf("expected recurrence %q on %q, got %q", want, task.Title, task.Recurrence)
+		}
+	}
+
+	pending, err := s.Recurrence.PendingRecurringTasks(ctx, today, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{overdue.ID, dueToday.ID, completed.ID}; !slices.Equal(taskIDs(pending), want) {
+		t.Fatalf("expected pending tasks %v, got %v", want, taskIDs(pending))
+	}
+	if pending[0].Recurrence != "FREQ=WEEKLY;BYDAY=MO" || pending[0].DueDate == nil || *pending[0].DueDate != past {
+		t.Fatalf("expected the rule and due date on pending tasks, got %+v", pending[0])
+	}
+	page, err := s.Recurrence.PendingRecurringTasks(ctx, today, overdue.ID, 1)
+	if err != nil || !slices.Equal(taskIDs(page), []int{dueToday.ID}) {
+		t.Fatalf("expected paging after id %d, got %v, %v", overdue.ID, taskIDs(page), err)
+	}
+
+	next, err := s.Recurrence.CreateNextOccurrence(ctx, overdue.ID, future)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == nil || next.ID == overdue.ID || next.GoalID != goal || next.Title != overdue.Title ||
+		next.Description != overdue.Description || next.Priority != "high" || next.IsCompleted ||
+		next.AssigneeID == nil || *next.AssigneeID != ada || next.CreatedBy != ada ||
+		next.DueDate == nil || *next.DueDate != future || next.Recurrence != overdue.Recurrence {
+		t.Fatalf("unexpected next occurrence %+v", next)
+	}
+	again, err := s.Recurrence.CreateNextOccurrence(ctx, overdue.ID, future)
+	if err != nil || again != nil {
+		t.Fatalf("expected a second call to create nothing, got %+v, %v", again, err)
+	}
+
+	pending, err = s.Recurrence.PendingRecurringTasks(ctx, today, 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{dueToday.ID, completed.ID}; !slices.Equal(taskIDs(pending), want) {
+		t.Fatalf("expected recurred and upcoming tasks to be left out, got %v", taskIDs(pending))
+	}
+
+	missing, err := s.Recurrence.CreateNextOccurrence(ctx, 999999, future)
+	if err != nil || missing != nil {
+		t.Fatalf("expected nothing for a missing task, got %+v, %v", missing, err)
+	}
+}
+
+func testRecurrenceLock(t *testing.T, s Stores) {
+	ctx := context.Background()
+	errStop := errors.New("stop")
+
+	ran := false
+	locked, err := s.Recurrence.WithRecurrenceLock(ctx, func(ctx context.Context) error {
+		ran = true
+		inner, err := s.Recurrence.WithRecurrenceLock(ctx, func(context.Context) error {
+			t.Error("expected the lock to be held")
+			return nil
+		})
+		if err != nil || inner {
+			t.Errorf("expected a held lock to be reported, got %v, %v", inner, err)
+		}
+		return errStop
+	})
+	if !locked || !ran || !errors.Is(err, errStop) {
+		t.Fatalf("expected fn to run and its error returned, got %v, %v, %v", locked, ran, err)
+	}
+
+	locked, err = s.Recurrence.WithRecurrenceLock(ctx, func(context.Context) error { return nil })
+	if !locked || err != nil {
+		t.Fatalf("expected the lock to be released, got %v, %v", locked, err)
+	}
+}
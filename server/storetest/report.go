@@ -0,0 +1,135 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/report"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testReports(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	goal := createGoal(t, s, ada, "Ship v1", "high", "todo")
+
+	first := createTask(t, s, goal, ada, "First", "high", &bob)
+	second := createTask(t, s, goal, ada, "Second", "medium", &bob)
+	third := createTask(t, s, goal, ada, "Third", "low", &ada)
+	unassigned := createTask(t, s, goal, ada, "Unassigned", "high", nil)
+	createTask(t, s, goal, ada, "Open", "high", &bob)
+	completeTask(t, s, first, goal, "First", "high", &bob)
+	completeTask(t, s, third, goal, "Third", "low", &ada)
+	completeTask(t, s, unassigned, goal, "Unassigned", "high", nil)
+	// Reopening a task forgets its completion.
+	completeTask(t, s, second, goal, "Second", "medium", &bob)
+	if _, err := s.Tracker.UpdateTask(ctx, second, ada, types.UpdateTaskPayload{
+		GoalID: goal, Title: "Second", Priority: "medium", AssigneeID: &bob,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	from, to := now.AddDate(0, 0, -8), now.AddDate(0, 0, 8)
+
+	weeks, err := s.Reports.CompletedPerWeek(ctx, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weeks) != 2 || weeks[0].UserID != ada || weeks[1].UserID != bob {
+		t.Fatalf("expected Ada's and Bob's week, got %+v", weeks)
+	}
+	for _, week := range weeks {
+		weekStart, err := time.Parse(time.DateOnly, week.WeekStart)
+		if err != nil || weekStart.Weekday() != time.Monday || now.Sub(weekStart) > 7*24*time.Hour {
+			t.Fatalf("expected the week to start on the last Monday, got %+v", week)
+		}
+		if week.Completed != 1 {
+			t.Fatalf("expected one completed task, got %+v", week)
+		}
+	}
+	if weeks[1].Name != "Bob Builder" {
+		t.Fatalf("expected Bob's name, got %q", weeks[1].Name)
+	}
+	weeks, err = s.Reports.CompletedPerWeek(ctx, now.AddDate(0, 0, 1), to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(weeks) != 0 {
+		t.Fatalf("expected no completions after today, got %+v", weeks)
+	}
+
+	counts, err := s.Reports.OpenTasksByPriority(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedCounts := []types.PriorityCount{{Priority: "high", OpenTasks: 1}, {Priority: "medium", OpenTasks: 1}, {Priority: "low"}}
+	if len(counts) != len(expectedCounts) {
+		t.Fatalf("expected %d priorities, got %+v", len(expectedCounts), counts)
+	}
+	for i, count := range counts {
+		if *count != expectedCounts[i] {
+			t.Fatalf("expected %+v, got %+v", expectedCounts[i], *count)
+		}
+	}
+
+	cycle, err := s.Reports.CycleTime(ctx, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cycle.CompletedTasks != 3 || cycle.AverageSeconds < 0 || cycle.AverageSeconds > 60 {
+		t.Fatalf("expected 3 tasks completed within a minute, got %+v", cycle)
+	}
+	cycle, err = s.Reports.CycleTime(ctx, from, now.AddDate(0, 0, -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *cycle != (types.CycleTimeReport{}) {
+		t.Fatalf("expected an empty report, got %+v", cycle)
+	}
+
+	year, month, day := now.Date()
+	today := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	points, err := s.Reports.GoalBurnUp(ctx, goal, today.AddDate(0, 0, -2), today.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 4 {
+		t.Fatalf("expected 4 days, got %+v", points)
+	}
+	for i, point := range points {
+		if expected := today.AddDate(0, 0, i-2).Format(time.DateOnly); point.Date != expected {
+			t.Fatalf("point %d: expected %s, got %+v", i, expected, point)
+		}
+	}
+	if *points[0] != (types.BurnUpPoint{Date: points[0].Date}) {
+		t.Fatalf("expected no tasks two days ago, got %+v", points[0])
+	}
+	if last := points[3]; last.TotalTasks != 5 || last.CompletedTasks != 3 {
+		t.Fatalf("expected 3 of 5 tasks done tomorrow, got %+v", last)
+	}
+	if _, err := s.Reports.GoalBurnUp(ctx, goal+1000, today, today); !errors.Is(err, report.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a missing goal, got %v", err)
+	}
+
+	for threshold, expected := range map[int][]types.UserLoad{
+		0: {{UserID: bob, Name: "Bob Builder", OpenTasks: 2}},
+		1: {{UserID: bob, Name: "Bob Builder", OpenTasks: 2}},
+		2: {},
+	} {
+		users, err := s.Reports.OverloadedUsers(ctx, threshold)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if users == nil || len(users) != len(expected) {
+			t.Fatalf("threshold %d: expected %+v, got %+v", threshold, expected, users)
+		}
+		for i, user := range users {
+			if *user != expected[i] {
+				t.Fatalf("threshold %d: expected %+v, got %+v", threshold, expected[i], *user)
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package storetest_test
+
+import (
+	"VyacheslavKuchumov/test-backend/config"
+	"VyacheslavKuchumov/test-backend/db"
+	"VyacheslavKuchumov/test-backend/migrator"
+	"VyacheslavKuchumov/test-backend/service/calendar"
+	"VyacheslavKuchumov/test-backend/service/digest"
+	"VyacheslavKuchumov/test-backend/service/export"
+	"VyacheslavKuchumov/test-backend/service/importer"
+	"VyacheslavKuchumov/test-backend/service/notification"
+	"VyacheslavKuchumov/test-backend/service/report"
+	"VyacheslavKuchumov/test-backend/service/template"
+	"VyacheslavKuchumov/test-backend/service/timetrack"
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/service/user"
+	"VyacheslavKuchumov/test-backend/storetest"
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteConformance(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Stores {
+		database, err := db.NewSQLiteStorage(context.Background(), config.Config{SQLitePath: filepath.Join(t.TempDir(), "tracker.db")})
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { database.Close() })
+
+		if err := migrator.Up(context.Background(), database, config.DriverSQLite); err != nil {
+			t.Fatalf("migrate up: %v", err)
+		}
+		notificationStore := notification.NewSQLiteStore(database)
+		digestStore := digest.NewSQLiteStore(database)
+		trackerStore := tracker.NewSQLiteStore(database)
+		return storetest.Stores{Users: user.NewStore(database), Tracker: trackerStore, Export: export.NewSQLiteStore(database), Import: importer.NewStore(database), Calendar: calendar.NewStore(database), Recurrence: trackerStore, Templates: template.NewStore(database), Time: timetrack.NewStore(database), Reports: report.NewSQLiteStore(database), Notifications: notificationStore, Outbox: notificationStore, Digests: digestStore, DigestOutbox: digestStore}
+	})
+}
@@ -0,0 +1,607 @@
+// Package storetest is a conformance suite for implementations of
+// types.UserStore, types.GoalTaskStore, types.ExportStore,
+// types.ImportStore, types.CalendarStore, types.RecurrenceStore,
+// types.TemplateStore, types.TimeStore, types.ReportStore,
+// types.NotificationStore, types.NotificationOutbox, types.DigestStore and
+// types.DigestOutbox. The
+// PostgreSQL stores and the in-memory stores in package memstore both run
+// it, which keeps the fakes used by handler tests honest.
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/tracker"
+	"VyacheslavKuchumov/test-backend/service/user"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"testing"
+)
+
+// Stores is one isolated, empty set of stores.
+type Stores struct {
+	Users         types.UserStore
+	Tracker       types.GoalTaskStore
+	Export        types.ExportStore
+	Import        types.ImportStore
+	Calendar      types.CalendarStore
+	Recurrence    types.RecurrenceStore
+	Templates     types.TemplateStore
+	Time          types.TimeStore
+	Reports       types.ReportStore
+	Notifications types.NotificationStore
+	Outbox        types.NotificationOutbox
+	Digests       types.DigestStore
+	DigestOutbox  types.DigestOutbox
+}
+
+// Run runs the suite. newStores must return empty stores that share a
+// backing database and are not shared with other subtests.
+func Run(t *testing.T, newStores func(t *testing.T) Stores) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, s Stores)
+	}{
+		{"users are created and looked up", testCreateAndGetUser},
+		{"duplicate email is rejected", testDuplicateEmail},
+		{"missing users are not found", testMissingUser},
+		{"profile and password updates", testUpdateUser},
+		{"users are listed by name", testListUsers},
+		{"goals are created with normalized fields", testCreateGoal},
+		{"only the owner updates a goal", testUpdateGoalOwnership},
+		{"only the owner deletes a goal and its tasks", testDeleteGoalOwnership},
+		{"goals are listed for every user in board order", testGoalsOrder},
+		{"goal tasks are ordered and carry lookups", testGoalWithTasks},
+		{"only the goal owner creates tasks", testCreateTaskOwnership},
+		{"tasks are updated and moved between goals", testUpdateTask},
+		{"only the goal owner assigns tasks", testAssignTaskOwnership},
+		{"only the goal owner deletes tasks", testDeleteTaskOwnership},
+		{"assigned tasks are ordered with lookups", testAssignedTasks},
+		{"board lists open tasks per user", testUsersWithCurrentTasks},
+		{"tasks are moved between anchors on goal and assigned lists", testMoveTask},
+		{"moved and reassigned tasks go last on their new list", testTaskPlacement},
+		{"concurrent moves on an assigned list are serialized", testConcurrentMoves},
+		{"due dates are stored, listed and cleared", testDueDates},
+		{"goal progress is weighted by count, priority or estimate", testGoalProgress},
+		{"auto-status goals follow their tasks", testAutoStatus},
+		{"recurring tasks are listed and recur once", testRecurringTasks},
+		{"recurrence lock is exclusive", testRecurrenceLock},
+		{"templates are private to their owner", testTemplates},
+		{"one timer runs per user", testTimers},
+		{"time entries are listed and deleted by their user", testTimeEntries},
+		{"time is reported per goal and per user", testTimeReports},
+		{"workload reports count completions and open tasks", testReports},
+		{"task and goal events notify users", testNotificationEvents},
+		{"notifications are paged and marked read", testNotificationInbox},
+		{"notification preferences default to in-app", testNotificationPreferences},
+		{"email and due soon notifications are dispatched once", testNotificationOutbox},
+		{"digest settings default to off and claims are exclusive", testDigestSettings},
+		{"digests list open, newly assigned and completed tasks", testBuildDigest},
+		{"export streams every goal with its tasks", testStreamGoals},
+		{"instance export round-trips through import", testInstanceRoundTrip},
+		{"import with unknown references changes nothing", testImportRejectsUnknownReferences},
+		{"imported goals are created with their tasks", testCreateGoalsWithTasks},
+		{"import with an unknown assignee changes nothing", testCreateGoalsWithTasksRollback},
+		{"calendar tokens are replaced and resolved", testCalendarTokens},
+		{"canceled context is reported", testCanceledContext},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.fn(t, newStores(t))
+		})
+	}
+}
+
+func testCreateAndGetUser(t *testing.T, s Stores) {
+	ctx := context.Background()
+	id := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+
+	byID, err := s.Users.GetUserByID(ctx, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byID.Email != "ada@example.com" || byID.FirstName != "Ada" || byID.Password != "hashed" {
+		t.Fatalf("unexpected user %+v", byID)
+	}
+	if byID.CreatedAt.IsZero() {
+		t.Fatal("expected created_at to be set")
+	}
+}
+
+func testDuplicateEmail(t *testing.T, s Stores) {
+	createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	err := s.Users.CreateUser(context.Background(), types.User{
+		FirstName: "Other", LastName: "Ada", Email: "ada@example.com", Password: "hashed",
+	})
+	if err == nil {
+		t.Fatal("expected an error for a duplicate email")
+	}
+}
+
+func testMissingUser(t *testing.T, s Stores) {
+	ctx := context.Background()
+	if _, err := s.Users.GetUserByID(ctx, 999); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("expected user.ErrNotFound, got %v", err)
+	}
+	if _, err := s.Users.GetUserByEmail(ctx, "nobody@example.com"); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("expected user.ErrNotFound, got %v", err)
+	}
+}
+
+func testUpdateUser(t *testing.T, s Stores) {
+	ctx := context.Background()
+	id := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+
+	updated, err := s.Users.UpdateUserProfile(ctx, id, types.UpdateProfilePayload{FirstName: "Augusta", LastName: "King"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.FirstName != "Augusta" || updated.LastName != "King" || updated.Email != "ada@example.com" {
+		t.Fatalf("unexpected user %+v", updated)
+	}
+	if _, err := s.Users.UpdateUserProfile(ctx, 999, types.UpdateProfilePayload{FirstName: "X", LastName: "Y"}); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("expected user.ErrNotFound, got %v", err)
+	}
+
+	if err := s.Users.UpdateUserPassword(ctx, id, "rehashed"); err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.Users.GetUserByEmail(ctx, "ada@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.Password != "rehashed" {
+		t.Fatalf("expected password to be updated, got %q", u.Password)
+	}
+	if err := s.Users.UpdateUserPassword(ctx, 999, "x"); !errors.Is(err, user.ErrNotFound) {
+		t.Fatalf("expected user.ErrNotFound, got %v", err)
+	}
+}
+
+func testListUsers(t *testing.T, s Stores) {
+	ctx := context.Background()
+	zed := createUser(t, s, "Zed", "Adams", "zed@example.com")
+	amyB := createUser(t, s, "Amy", "Brown", "amy.b@example.com")
+	amyA := createUser(t, s, "Amy", "Archer", "amy.a@example.com")
+
+	for _, users := range []func(context.Context) ([]*types.UserLookup, error){s.Users.ListUsers, s.Tracker.ListUsers} {
+		list, err := users(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertIDs(t, lookupIDs(list), amyA, amyB, zed)
+		if list[0].Name != "Amy Archer" {
+			t.Fatalf("expected full name, got %q", list[0].Name)
+		}
+	}
+}
+
+func testCreateGoal(t *testing.T, s Stores) {
+	owner := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	goal, err := s.Tracker.CreateGoal(context.Background(), owner, types.CreateGoalPayload{
+		Title: "Ship it", Description: "", Priority: "urgent", Status: "unknown",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goal.ID == 0 || goal.OwnerID != owner || goal.CreatedAt.IsZero() {
+		t.Fatalf("unexpected goal %+v", goal)
+	}
+	if goal.Priority != "medium" || goal.Status != "todo" {
+		t.Fatalf("expected normalized priority and status, got %q and %q", goal.Priority, goal.Status)
+	}
+}
+
+func testUpdateGoalOwnership(t *testing.T, s Stores) {
+	ctx := context.Background()
+	owner := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	other := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, owner, "Ship it", "medium", "todo")
+
+	payload := types.CreateGoalPayload{Title: "Renamed", Priority: "high", Status: "in_progress"}
+	if _, err := s.Tracker.UpdateGoal(ctx, goalID, other, payload); !errors.Is(err, tracker.ErrForbidden) {
+		t.Fatalf("expected tracker.ErrForbidden, got %v", err)
+	}
+	if _, err := s.Tracker.UpdateGoal(ctx, 999, owner, payload); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound, got %v", err)
+	}
+
+	goal, err := s.Tracker.UpdateGoal(ctx, goalID, owner, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goal.Title != "Renamed" || goal.Priority != "high" || goal.Status != "in_progress" {
+		t.Fatalf("unexpected goal %+v", goal)
+	}
+}
+
+func testDeleteGoalOwnership(t *testing.T, s Stores) {
+	ctx := context.Background()
+	owner := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	other := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, owner, "Ship it", "medium", "todo")
+	createTask(t, s, goalID, owner, "Write docs", "low", &other)
+
+	if err := s.Tracker.DeleteGoal(ctx, goalID, other); !errors.Is(err, tracker.ErrForbidden) {
+		t.Fatalf("expected tracker.ErrForbidden, got %v", err)
+	}
+	if err := s.Tracker.DeleteGoal(ctx, 999, owner); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound, got %v", err)
+	}
+	if err := s.Tracker.DeleteGoal(ctx, goalID, owner); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Tracker.GetGoalWithTasks(ctx, goalID, owner); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound after delete, got %v", err)
+	}
+	tasks, err := s.Tracker.GetAssignedTasks(ctx, other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tasks) != 0 {
+		t.Fatalf("expected tasks to be deleted with their goal, got %d", len(tasks))
+	}
+}
+
+func testGoalsOrder(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Stone", "bob@example.com")
+
+	achieved := createGoal(t, s, ada, "Done already", "high", "achieved")
+	lowOld := createGoal(t, s, ada, "Low old", "low", "todo")
+	lowNew := createGoal(t, s, bob, "Low new", "low", "todo")
+	high := createGoal(t, s, bob, "High", "high", "in_progress")
+
+	goals, err := s.Tracker.GetGoalsByOwner(ctx, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]int, 0, len(goals))
+	for _, goal := range goals {
+		ids = append(ids, goal.ID)
+	}
+	assertIDs(t, ids, high, lowNew, lowOld, achieved)
+	if goals[0].OwnerName != "Bob Stone" {
+		t.Fatalf("expected owner name, got %q", goals[0].OwnerName)
+	}
+	if goals[0].Tasks == nil || len(goals[0].Tasks) != 0 {
+		t.Fatalf("expected an empty task list, got %v", goals[0].Tasks)
+	}
+}
+
+func testGoalWithTasks(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, ada, "Ship it", "medium", "todo")
+
+	done := createTask(t, s, goalID, ada, "Done high", "high", nil)
+	lowFirst := createTask(t, s, goalID, ada, "Low first", "low", &bob)
+	highOpen := createTask(t, s, goalID, ada, "High open", "high", nil)
+	lowSecond := createTask(t, s, goalID, ada, "Low second", "low", nil)
+	completeTask(t, s, done, goalID, "Done high", "high", nil)
+
+	goal, err := s.Tracker.GetGoalWithTasks(ctx, goalID, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, taskIDs(goal.Tasks), lowFirst, highOpen, lowSecond, done)
+
+	if goal.OwnerName != "Ada Lovelace" {
+		t.Fatalf("expected owner name, got %q", goal.OwnerName)
+	}
+	assigned := goal.Tasks[0]
+	if assigned.GoalTitle != "Ship it" || assigned.AssigneeName != "Bob Stone" || assigned.CreatedByName != "Ada Lovelace" {
+		t.Fatalf("unexpected lookups %+v", assigned)
+	}
+	if assigned.AssigneeID == nil || *assigned.AssigneeID != bob {
+		t.Fatalf("expected assignee %d, got %v", bob, assigned.AssigneeID)
+	}
+	if goal.Tasks[1].AssigneeID != nil || goal.Tasks[1].AssigneeName != "" {
+		t.Fatalf("expected unassigned task, got %+v", goal.Tasks[1])
+	}
+
+	if _, err := s.Tracker.GetGoalWithTasks(ctx, 999, ada); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound, got %v", err)
+	}
+}
+
+func testCreateTaskOwnership(t *testing.T, s Stores) {
+	ctx := context.Background()
+	owner := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	other := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, owner, "Ship it", "medium", "todo")
+
+	payload := types.CreateTaskPayload{Title: "Write docs", Priority: "low", AssigneeID: &other}
+	if _, err := s.Tracker.CreateTask(ctx, goalID, other, payload); !errors.Is(err, tracker.ErrForbidden) {
+		t.Fatalf("expected tracker.ErrForbidden, got %v", err)
+	}
+	if _, err := s.Tracker.CreateTask(ctx, 999, owner, payload); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound, got %v", err)
+	}
+
+	task, err := s.Tracker.CreateTask(ctx, goalID, owner, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.GoalID != goalID || task.CreatedBy != owner || task.IsCompleted || task.AssigneeID == nil || *task.AssigneeID != other {
+		t.Fatalf("unexpected task %+v", task)
+	}
+
+	missing := 999
+	if _, err := s.Tracker.CreateTask(ctx, goalID, owner, types.CreateTaskPayload{Title: "Bad", Priority: "low", AssigneeID: &missing}); err == nil {
+		t.Fatal("expected an error for an unknown assignee")
+	}
+}
+
+func testUpdateTask(t *testing.T, s Stores) {
+	ctx := context.Background()
+	owner := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	first := createGoal(t, s, owner, "First", "medium", "todo")
+	second := createGoal(t, s, owner, "Second", "medium", "todo")
+	taskID := createTask(t, s, first, owner, "Write docs", "low", nil)
+
+	payload := types.UpdateTaskPayload{GoalID: second, Title: "Write more docs", Priority: "high", IsCompleted: true, AssigneeID: &owner}
+	task, err := s.Tracker.UpdateTask(ctx, taskID, owner, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.GoalID != second || task.Title != "Write more docs" || task.Priority != "high" || !task.IsCompleted || task.AssigneeID == nil {
+		t.Fatalf("unexpected task %+v", task)
+	}
+
+	moved, err := s.Tracker.GetGoalWithTasks(ctx, second, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, taskIDs(moved.Tasks), taskID)
+
+	if _, err := s.Tracker.UpdateTask(ctx, 999, owner, payload); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound for a missing task, got %v", err)
+	}
+	payload.GoalID = 999
+	if _, err := s.Tracker.UpdateTask(ctx, taskID, owner, payload); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound for a missing goal, got %v", err)
+	}
+}
+
+func testAssignTaskOwnership(t *testing.T, s Stores) {
+	ctx := context.Background()
+	owner := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	other := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, owner, "Ship it", "medium", "todo")
+	taskID := createTask(t, s, goalID, owner, "Write docs", "low", nil)
+
+	if _, err := s.Tracker.AssignTask(ctx, taskID, other, types.AssignTaskPayload{AssigneeID: &other}); !errors.Is(err, tracker.ErrForbidden) {
+		t.Fatalf("expected tracker.ErrForbidden, got %v", err)
+	}
+	if _, err := s.Tracker.AssignTask(ctx, 999, owner, types.AssignTaskPayload{AssigneeID: &other}); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound, got %v", err)
+	}
+
+	task, err := s.Tracker.AssignTask(ctx, taskID, owner, types.AssignTaskPayload{AssigneeID: &other})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.AssigneeID == nil || *task.AssigneeID != other {
+		t.Fatalf("expected assignee %d, got %v", other, task.AssigneeID)
+	}
+
+	task, err = s.Tracker.AssignTask(ctx, taskID, owner, types.AssignTaskPayload{AssigneeID: nil})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if task.AssigneeID != nil {
+		t.Fatalf("expected task to be unassigned, got %v", *task.AssigneeID)
+	}
+}
+
+func testDeleteTaskOwnership(t *testing.T, s Stores) {
+	ctx := context.Background()
+	owner := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	other := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, owner, "Ship it", "medium", "todo")
+	taskID := createTask(t, s, goalID, owner, "Write docs", "low", nil)
+
+	if err := s.Tracker.DeleteTask(ctx, taskID, other); !errors.Is(err, tracker.ErrForbidden) {
+		t.Fatalf("expected tracker.ErrForbidden, got %v", err)
+	}
+	if err := s.Tracker.DeleteTask(ctx, 999, owner); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound, got %v", err)
+	}
+	if err := s.Tracker.DeleteTask(ctx, taskID, owner); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Tracker.DeleteTask(ctx, taskID, owner); !errors.Is(err, tracker.ErrNotFound) {
+		t.Fatalf("expected tracker.ErrNotFound after delete, got %v", err)
+	}
+}
+
+func testAssignedTasks(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, ada, "Ship it", "medium", "todo")
+
+	done := createTask(t, s, goalID, ada, "Done", "high", &bob)
+	lowOld := createTask(t, s, goalID, ada, "Low old", "low", &bob)
+	lowNew := createTask(t, s, goalID, ada, "Low new", "low", &bob)
+	high := createTask(t, s, goalID, ada, "High", "high", &bob)
+	createTask(t, s, goalID, ada, "Someone else", "high", &ada)
+	completeTask(t, s, done, goalID, "Done", "high", &bob)
+
+	tasks, err := s.Tracker.GetAssignedTasks(ctx, bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertIDs(t, taskIDs(tasks), lowOld, lowNew, high, done)
+	if tasks[0].GoalTitle != "Ship it" || tasks[0].AssigneeName != "Bob Stone" || tasks[0].CreatedByName != "Ada Lovelace" {
+		t.Fatalf("unexpected lookups %+v", tasks[0])
+	}
+}
+
+func testUsersWithCurrentTasks(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Stone", "bob@example.com")
+	goalID := createGoal(t, s, ada, "Ship it", "medium", "todo")
+
+	done := createTask(t, s, goalID, ada, "Done", "high", &ada)
+	lowOld := createTask(t, s, goalID, ada, "Low old", "low", &ada)
+	lowNew := createTask(t, s, goalID, ada, "Low new", "low", &ada)
+	high := createTask(t, s, goalID, ada, "High", "high", &ada)
+	createTask(t, s, goalID, ada, "Unassigned", "high", nil)
+	completeTask(t, s, done, goalID, "Done", "high", &ada)
+
+	boards, err := s.Tracker.GetUsersWithCurrentTasks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(boards) != 2 || boards[0].ID != ada || boards[1].ID != bob {
+		t.Fatalf("expected boards for every user ordered by name, got %+v", boards)
+	}
+	if boards[0].Name != "Ada Lovelace" || boards[0].Email != "ada@example.com" {
+		t.Fatalf("unexpected board %+v", boards[0])
+	}
+	assertIDs(t, taskIDs(boards[0].Tasks), lowOld, lowNew, high)
+	if boards[1].Tasks == nil || len(boards[1].Tasks) != 0 {
+		t.Fatalf("expected an empty task list for a user without tasks, got %v", boards[1].Tasks)
+	}
+	if boards[0].Tasks[0].GoalTitle != "Ship it" || boards[0].Tasks[0].CreatedByName != "Ada Lovelace" {
+		t.Fatalf("unexpected lookups %+v", boards[0].Tasks[0])
+	}
+}
+
+func testCanceledContext(t *testing.T, s Stores) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.Tracker.GetGoalsByOwner(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if _, err := s.Users.GetUserByID(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+}
+
+func testDueDates(t *testing.T, s Stores) {
+	ctx := context.Background()
+	owner := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	goalDue, taskDue := "2026-03-31", "2026-02-01"
+
+	goal, err := s.Tracker.CreateGoal(ctx, owner, types.CreateGoalPayload{Title: "Ship it", Priority: "high", Status: "todo", DueDate: &goalDue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	task, err := s.Tracker.CreateTask(ctx, goal.ID, owner, types.CreateTaskPayload{Title: "Write docs", Priority: "low", AssigneeID: &owner, DueDate: &taskDue})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if goal.DueDate == nil || *goal.DueDate != goalDue || task.DueDate == nil || *task.DueDate != taskDue {
+		t.Fatalf("expected due dates on create, got goal %v and task %v", goal.DueDate, task.DueDate)
+	}
+
+	listed, err := s.Tracker.GetGoalWithTasks(ctx, goal.ID, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *listed.DueDate != goalDue || *listed.Tasks[0].DueDate != taskDue {
+		t.Fatalf("expected due dates on read, got %+v", listed)
+	}
+	assigned, err := s.Tracker.GetAssignedTasks(ctx, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assigned) != 1 || assigned[0].DueDate == nil || *assigned[0].DueDate != taskDue {
+		t.Fatalf("expected the due date on assigned tasks, got %+v", assigned)
+	}
+
+	updatedGoal, err := s.Tracker.UpdateGoal(ctx, goal.ID, owner, types.CreateGoalPayload{Title: "Ship it", Priority: "high", Status: "todo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	updatedTask, err := s.Tracker.UpdateTask(ctx, task.ID, owner, types.UpdateTaskPayload{GoalID: goal.ID, Title: "Write docs", Priority: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updatedGoal.DueDate != nil || updatedTask.DueDate != nil {
+		t.Fatalf("expected updates without a due date to clear it, got goal %v and task %v", updatedGoal.DueDate, updatedTask.DueDate)
+	}
+}
+
+func createUser(t *testing.T, s Stores, firstName, lastName, email string) int {
+	t.Helper()
+	ctx := context.Background()
+	if err := s.Users.CreateUser(ctx, types.User{
+		FirstName: firstName, LastName: lastName, Email: email, Password: "hashed",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	u, err := s.Users.GetUserByEmail(ctx, email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.ID
+}
+
+func createGoal(t *testing.T, s Stores, ownerID int, title, priority, status string) int {
+	t.Helper()
+	goal, err := s.Tracker.CreateGoal(context.Background(), ownerID, types.CreateGoalPayload{
+		Title: title, Priority: priority, Status: status,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return goal.ID
+}
+
+func createTask(t *testing.T, s Stores, goalID, creatorID int, title, priority string, assigneeID *int) int {
+	t.Helper()
+	task, err := s.Tracker.CreateTask(context.Background(), goalID, creatorID, types.CreateTaskPayload{
+		Title: title, Priority: priority, AssigneeID: assigneeID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return task.ID
+}
+
+func completeTask(t *testing.T, s Stores, taskID, goalID int, title, priority string, assigneeID *int) {
+	t.Helper()
+	if _, err := s.Tracker.UpdateTask(context.Background(), taskID, 0, types.UpdateTaskPayload{
+		GoalID: goalID, Title: title, Priority: priority, IsCompleted: true, AssigneeID: assigneeID,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func taskIDs(tasks []*types.Task) []int {
+	ids := make([]int, 0, len(tasks))
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	return ids
+}
+
+func lookupIDs(users []*types.UserLookup) []int {
+	ids := make([]int, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	return ids
+}
+
+func assertIDs(t *testing.T, got []int, expected ...int) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("expected ids %v, got %v", expected, got)
+	}
+	for i := range got {
+		if got[i] != expected[i] {
+			t.Fatalf("expected ids %v, got %v", expected, got)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/template"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func testTemplates(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	week, day := 7, 1
+
+	onboarding, err := s.Templates.CreateTemplate(ctx, ada, types.CreateTemplatePayload{
+		Name: "Onboarding", Title: "Onboard {{client}}", Description: "Kick-off for {{client}}", Priority: "high", DueInDays: &week,
+		Tasks: []types.TemplateTask{
+			{Title: "Send welcome pack", Priority: "medium", DueInDays: &day},
+			{Title: "Schedule kick-off with {{client}}", Description: "Invite the team", Priority: "high"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if onboarding.ID == 0 || onboarding.OwnerID != ada || onboarding.CreatedAt.IsZero() || len(onboarding.Tasks) != 2 ||
+		onboarding.DueInDays == nil || *onboarding.DueInDays != week {
+		t.Fatalf("unexpected template %+v", onboarding)
+	}
+	empty, err := s.Templates.CreateTemplate(ctx, ada, types.CreateTemplatePayload{Name: "Audit", Title: "Yearly audit", Priority: "low"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Templates.CreateTemplate(ctx, bob, types.CreateTemplatePayload{Name: "Bob's", Title: "Build", Priority: "low"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Templates.CreateTemplate(ctx, 999999, types.CreateTemplatePayload{Name: "Orphan", Title: "Orphan", Priority: "low"}); err == nil {
+		t.Fatal("expected a template for an unknown owner to be rejected")
+	}
+
+	listed, err := s.Templates.ListTemplates(ctx, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(listed) != 2 || listed[0].ID != empty.ID || listed[1].ID != onboarding.ID {
+		t.Fatalf("expected ada's templates ordered by name, got %+v", listed)
+	}
+	if listed[0].Tasks == nil || len(listed[0].Tasks) != 0 || listed[0].DueInDays != nil {
+		t.Fatalf("expected an empty task list and no due offset, got %+v", listed[0])
+	}
+	if !reflect.DeepEqual(listed[1].Tasks, onboarding.Tasks) || listed[1].Title != "Onboard {{client}}" {
+		t.Fatalf("expected tasks in order, got %+v", listed[1].Tasks)
+	}
+
+	got, err := s.Templates.GetTemplate(ctx, onboarding.ID, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Tasks, onboarding.Tasks) || got.Name != "Onboarding" || !got.CreatedAt.Equal(onboarding.CreatedAt) {
+		t.Fatalf("unexpected template %+v", got)
+	}
+	if _, err := s.Templates.GetTemplate(ctx, onboarding.ID, bob); !errors.Is(err, template.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for another user's template, got %v", err)
+	}
+
+	if err := s.Templates.DeleteTemplate(ctx, onboarding.ID, bob); !errors.Is(err, template.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound deleting another user's template, got %v", err)
+	}
+	if err := s.Templates.DeleteTemplate(ctx, onboarding.ID, ada); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Templates.DeleteTemplate(ctx, onboarding.ID, ada); !errors.Is(err, template.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for a deleted template, got %v", err)
+	}
+	if _, err := s.Templates.GetTemplate(ctx, onboarding.ID, ada); !errors.Is(err, template.ErrNotFound) {
+		t.Fatalf("expected the deleted template to be gone, got %v", err)
+	}
+}
@@ -0,0 +1,240 @@
+package storetest
+
+import (
+	"VyacheslavKuchumov/test-backend/service/timetrack"
+	"VyacheslavKuchumov/test-backend/types"
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func testTimers(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	goal := createGoal(t, s, ada, "Ship it", "high", "todo")
+	docs := createTask(t, s, goal, ada, "Write docs", "low", nil)
+	bugs := createTask(t, s, goal, ada, "Fix bugs", "high", nil)
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	if _, err := s.Time.GetRunningTimer(ctx, ada); !errors.Is(err, timetrack.ErrNoTimer) {
+		t.Fatalf("expected timetrack.ErrNoTimer before starting, got %v", err)
+	}
+	running, err := s.Time.StartTimer(ctx, ada, docs, start, "outline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if running.ID == 0 || running.TaskID != docs || running.UserID != ada || !running.StartedAt.Equal(start) ||
+		running.EndedAt != nil || running.DurationSeconds != nil || running.Note != "outline" {
+		t.Fatalf("unexpected running timer %+v", running)
+	}
+	if _, err := s.Time.StartTimer(ctx, ada, bugs, start.Add(time.Minute), ""); !errors.Is(err, timetrack.ErrTimerRunning) {
+		t.Fatalf("expected timetrack.ErrTimerRunning for a second timer, got %v", err)
+	}
+	if _, err := s.Time.StartTimer(ctx, bob, bugs, start, ""); err != nil {
+		t.Fatalf("expected another user's timer to start, got %v", err)
+	}
+	if _, err := s.Time.StartTimer(ctx, bob, 999999, start, ""); !errors.Is(err, timetrack.ErrNotFound) {
+		t.Fatalf("expected timetrack.ErrNotFound for a missing task, got %v", err)
+	}
+
+	current, err := s.Time.GetRunningTimer(ctx, ada)
+	if err != nil || current.ID != running.ID {
+		t.Fatalf("expected the running timer, got %+v, %v", current, err)
+	}
+
+	stopped, err := s.Time.StopTimer(ctx, ada, start.Add(25*time.Minute+10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped.ID != running.ID || stopped.EndedAt == nil || !stopped.EndedAt.Equal(start.Add(25*time.Minute+10*time.Second)) ||
+		stopped.DurationSeconds == nil || *stopped.DurationSeconds != 1510 {
+		t.Fatalf("unexpected stopped timer %+v", stopped)
+	}
+	if _, err := s.Time.StopTimer(ctx, ada, start.Add(time.Hour)); !errors.Is(err, timetrack.ErrNoTimer) {
+		t.Fatalf("expected timetrack.ErrNoTimer when stopping twice, got %v", err)
+	}
+	if _, err := s.Time.GetRunningTimer(ctx, ada); !errors.Is(err, timetrack.ErrNoTimer) {
+		t.Fatalf("expected no running timer after stopping, got %v", err)
+	}
+	if _, err := s.Time.StartTimer(ctx, ada, bugs, start.Add(time.Hour), ""); err != nil {
+		t.Fatalf("expected a new timer after stopping, got %v", err)
+	}
+
+	// A clock that went backwards records zero seconds.
+	backwards, err := s.Time.StopTimer(ctx, bob, start.Add(-time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backwards.DurationSeconds == nil || *backwards.DurationSeconds != 0 {
+		t.Fatalf("expected a zero duration, got %+v", backwards)
+	}
+}
+
+func testTimeEntries(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	goal := createGoal(t, s, ada, "Ship it", "high", "todo")
+	docs := createTask(t, s, goal, ada, "Write docs", "low", nil)
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+
+	first, err := s.Time.AddTimeEntry(ctx, ada, docs, start, 1800, "draft")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.EndedAt == nil || !first.EndedAt.Equal(start.Add(30*time.Minute)) || first.DurationSeconds == nil || *first.DurationSeconds != 1800 {
+		t.Fatalf("unexpected entry %+v", first)
+	}
+	second, err := s.Time.AddTimeEntry(ctx, bob, docs, start.Add(2*time.Hour), 600, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	running, err := s.Time.StartTimer(ctx, ada, docs, start.Add(3*time.Hour), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Time.AddTimeEntry(ctx, ada, 999999, start, 60, ""); !errors.Is(err, timetrack.ErrNotFound) {
+		t.Fatalf("expected timetrack.ErrNotFound for a missing task, got %v", err)
+	}
+
+	entries, err := s.Time.ListTimeEntries(ctx, docs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[0].ID != running.ID || entries[1].ID != second.ID || entries[2].ID != first.ID {
+		t.Fatalf("expected the entries newest first, got %+v", entries)
+	}
+	if entries[1].UserName != "Bob Builder" || entries[2].Note != "draft" {
+		t.Fatalf("unexpected entry lookups %+v", entries)
+	}
+	if _, err := s.Time.ListTimeEntries(ctx, 999999); !errors.Is(err, timetrack.ErrNotFound) {
+		t.Fatalf("expected timetrack.ErrNotFound for a missing task, got %v", err)
+	}
+	other := createTask(t, s, goal, ada, "Fix bugs", "high", nil)
+	if empty, err := s.Time.ListTimeEntries(ctx, other); err != nil || empty == nil || len(empty) != 0 {
+		t.Fatalf("expected an empty list, got %v, %v", empty, err)
+	}
+
+	if err := s.Time.DeleteTimeEntry(ctx, second.ID, ada); !errors.Is(err, timetrack.ErrForbidden) {
+		t.Fatalf("expected timetrack.ErrForbidden for another user's entry, got %v", err)
+	}
+	if err := s.Time.DeleteTimeEntry(ctx, 999999, ada); !errors.Is(err, timetrack.ErrNotFound) {
+		t.Fatalf("expected timetrack.ErrNotFound for a missing entry, got %v", err)
+	}
+	if err := s.Time.DeleteTimeEntry(ctx, running.ID, ada); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Time.GetRunningTimer(ctx, ada); !errors.Is(err, timetrack.ErrNoTimer) {
+		t.Fatalf("expected the deleted timer to be gone, got %v", err)
+	}
+
+	if err := s.Tracker.DeleteTask(ctx, docs, ada); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Time.DeleteTimeEntry(ctx, first.ID, ada); !errors.Is(err, timetrack.ErrNotFound) {
+		t.Fatalf("expected entries to be deleted with their task, got %v", err)
+	}
+}
+
+func testTimeReports(t *testing.T, s Stores) {
+	ctx := context.Background()
+	ada := createUser(t, s, "Ada", "Lovelace", "ada@example.com")
+	bob := createUser(t, s, "Bob", "Builder", "bob@example.com")
+	goal := createGoal(t, s, ada, "Ship it", "high", "todo")
+	otherGoal := createGoal(t, s, bob, "Someday", "low", "todo")
+	estimate := 120
+	docs, err := s.Tracker.CreateTask(ctx, goal, ada, types.CreateTaskPayload{Title: "Write docs", Priority: "low", EstimateMinutes: &estimate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if docs.EstimateMinutes == nil || *docs.EstimateMinutes != estimate {
+		t.Fatalf("expected the estimate to be stored, got %+v", docs)
+	}
+	bugs := createTask(t, s, goal, ada, "Fix bugs", "high", nil)
+	chores := createTask(t, s, otherGoal, bob, "Chores", "low", nil)
+	march := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	april := time.Date(2026, time.April, 1, 9, 0, 0, 0, time.UTC)
+
+	addEntry := func(userID, taskID int, startedAt time.Time, seconds int) {
+		t.Helper()
+		if _, err := s.Time.AddTimeEntry(ctx, userID, taskID, startedAt, seconds, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	addEntry(ada, docs.ID, march, 3600)
+	addEntry(bob, docs.ID, march, 1200)
+	addEntry(bob, bugs, april, 1790)
+	addEntry(bob, chores, march, 600)
+	if _, err := s.Time.StartTimer(ctx, ada, bugs, march, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	all := [2]time.Time{time.Date(1970, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, time.December, 31, 0, 0, 0, 0, time.UTC)}
+	report, err := s.Time.GoalTimeReport(ctx, goal, all[0], all[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Title != "Ship it" || report.EstimateMinutes != 120 || report.SpentMinutes != 110 || len(report.Tasks) != 2 {
+		t.Fatalf("unexpected goal report %+v", report)
+	}
+	if task := report.Tasks[0]; task.TaskID != docs.ID || task.EstimateMinutes == nil || *task.EstimateMinutes != 120 || task.SpentMinutes != 80 {
+		t.Fatalf("unexpected task time %+v", task)
+	}
+	if task := report.Tasks[1]; task.TaskID != bugs || task.EstimateMinutes != nil || task.SpentMinutes != 30 {
+		t.Fatalf("expected the running timer to be left out, got %+v", task)
+	}
+	if len(report.Users) != 2 || report.Users[0].UserID != ada || report.Users[0].SpentMinutes != 60 ||
+		report.Users[1].UserID != bob || report.Users[1].Name != "Bob Builder" || report.Users[1].SpentMinutes != 50 {
+		t.Fatalf("expected users by time spent, got %+v", report.Users)
+	}
+
+	inMarch, err := s.Time.GoalTimeReport(ctx, goal, march.Truncate(24*time.Hour), april.Truncate(24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inMarch.SpentMinutes != 80 || inMarch.Tasks[1].SpentMinutes != 0 || len(inMarch.Users) != 2 {
+		t.Fatalf("expected only March entries, got %+v", inMarch)
+	}
+
+	bobReport, err := s.Time.UserTimeReport(ctx, bob, all[0], all[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bobReport.Name != "Bob Builder" || bobReport.SpentMinutes != 60 || len(bobReport.Goals) != 2 ||
+		bobReport.Goals[0].GoalID != goal || bobReport.Goals[0].SpentMinutes != 50 ||
+		bobReport.Goals[1].GoalID != otherGoal || bobReport.Goals[1].Title != "Someday" || bobReport.Goals[1].SpentMinutes != 10 {
+		t.Fatalf("unexpected user report %+v", bobReport)
+	}
+	idle := createUser(t, s, "Cy", "Clone", "cy@example.com")
+	if empty, err := s.Time.UserTimeReport(ctx, idle, all[0], all[1]); err != nil || empty.Goals == nil || len(empty.Goals) != 0 || empty.SpentMinutes != 0 {
+		t.Fatalf("expected an empty report, got %+v, %v", empty, err)
+	}
+
+	if _, err := s.Time.GoalTimeReport(ctx, 999999, all[0], all[1]); !errors.Is(err, timetrack.ErrNotFound) {
+		t.Fatalf("expected timetrack.ErrNotFound for a missing goal, got %v", err)
+	}
+	if _, err := s.Time.UserTimeReport(ctx, 999999, all[0], all[1]); !errors.Is(err, timetrack.ErrNotFound) {
+		t.Fatalf("expected timetrack.ErrNotFound for a missing user, got %v", err)
+	}
+
+	withTasks, err := s.Tracker.GetGoalWithTasks(ctx, goal, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if withTasks.EstimateMinutes == nil || *withTasks.EstimateMinutes != 120 || withTasks.SpentMinutes == nil || *withTasks.SpentMinutes != 110 {
+		t.Fatalf("expected goal totals, got %+v", withTasks)
+	}
+
+	if _, err := s.Tracker.UpdateTask(ctx, docs.ID, ada, types.UpdateTaskPayload{GoalID: goal, Title: docs.Title, Priority: docs.Priority}); err != nil {
+		t.Fatal(err)
+	}
+	withTasks, err = s.Tracker.GetGoalWithTasks(ctx, goal, ada)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *withTasks.EstimateMinutes != 0 || withTasks.Tasks[1].EstimateMinutes != nil {
+		t.Fatalf("expected the estimate to be cleared, got %+v", withTasks)
+	}
+}
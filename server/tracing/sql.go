@@ -0,0 +1,60 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type statementNameKey struct{}
+
+// WithStatementName names the SQL spans started with ctx, e.g.
+// "tracker.GetGoalsByOwner".
+func WithStatementName(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, statementNameKey{}, name)
+}
+
+// QueryTracer is a pgx.QueryTracer that wraps every query in a client span.
+type QueryTracer struct{}
+
+var _ pgx.QueryTracer = QueryTracer{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	operation := sqlOperation(data.SQL)
+	name, _ := ctx.Value(statementNameKey{}).(string)
+	if name == "" {
+		name = operation
+	}
+
+	ctx, _ = tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation.name", operation),
+			attribute.String("db.query.text", data.SQL),
+		),
+	)
+	return ctx
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	span := trace.SpanFromContext(ctx)
+	if data.Err != nil {
+		span.RecordError(data.Err)
+		span.SetStatus(codes.Error, data.Err.Error())
+	}
+	span.SetAttributes(attribute.Int64("db.response.rows_affected", data.CommandTag.RowsAffected()))
+	span.End()
+}
+
+func sqlOperation(sql string) string {
+	fields := strings.Fields(sql)
+	if len(fields) == 0 {
+		return "SQL"
+	}
+	return strings.ToUpper(fields[0])
+}
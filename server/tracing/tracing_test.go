@@ -0,0 +1,73 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newRecorder(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestMiddlewareNamesSpanAfterRoute(t *testing.T) {
+	recorder := newRecorder(t)
+
+	r := chi.NewRouter()
+	r.Use(Middleware)
+	r.Get("/goals/{goalID}/tasks", func(w http.ResponseWriter, r *http.Request) {
+		SetUserID(r.Context(), 42)
+		w.WriteHeader(http.StatusTeapot)
+	})
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/goals/7/tasks", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /goals/{goalID}/tasks" {
+		t.Fatalf("unexpected span name %q", span.Name())
+	}
+
+	attrs := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	if attrs["http.route"].AsString() != "/goals/{goalID}/tasks" ||
+		attrs["http.response.status_code"].AsInt64() != http.StatusTeapot ||
+		attrs["enduser.id"].AsInt64() != 42 {
+		t.Fatalf("unexpected attributes: %v", span.Attributes())
+	}
+}
+
+func TestQueryTracerUsesStatementName(t *testing.T) {
+	recorder := newRecorder(t)
+
+	ctx := WithStatementName(context.Background(), "tracker.GetGoalsByOwner")
+	ctx = QueryTracer{}.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "  select id from goals"})
+	QueryTracer{}.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "tracker.GetGoalsByOwner" {
+		t.Fatalf("expected one span named after the statement, got %v", spans)
+	}
+	for _, kv := range spans[0].Attributes() {
+		if kv.Key == "db.operation.name" && kv.Value.AsString() != "SELECT" {
+			t.Fatalf("expected SELECT operation, got %q", kv.Value.AsString())
+		}
+	}
+}
@@ -1,9 +1,44 @@
 package types
 
+import "time"
+
+// ErrorResponse is an RFC 7807 problem details document served with the
+// application/problem+json content type.
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Type        string       `json:"type"`
+	Title       string       `json:"title"`
+	Status      int          `json:"status"`
+	Detail      string       `json:"detail,omitempty"`
+	Code        string       `json:"code"`
+	FieldErrors []FieldError `json:"fieldErrors,omitempty"`
+	RequestID   string       `json:"requestId,omitempty"`
+}
+
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
 }
 
 type LoginResponse struct {
 	Token string `json:"token"`
 }
+
+type HealthResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// GoalsExport is the JSON body of GET /export. The server streams it, so
+// goals are written as they are read.
+type GoalsExport struct {
+	ExportedAt time.Time        `json:"exportedAt"`
+	Goals      []*GoalWithTasks `json:"goals"`
+}
+
+// CalendarTokenResponse is returned when a calendar feed token is created.
+// The token is not stored and cannot be shown again.
+type CalendarTokenResponse struct {
+	Token string `json:"token"`
+	URL   string `json:"url"`
+}
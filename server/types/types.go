@@ -1,89 +1,272 @@
 package types
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type UserStore interface {
-	GetUserByEmail(email string) (*User, error)
-	GetUserByID(id int) (*User, error)
-	CreateUser(User) error
-	UpdateUserProfile(userID int, payload UpdateProfilePayload) (*User, error)
-	UpdateUserPassword(userID int, hashedPassword string) error
-	ListUsers() ([]*UserLookup, error)
+	GetUserByEmail(ctx context.Context, email string) (*User, error)
+	GetUserByID(ctx context.Context, id int) (*User, error)
+	CreateUser(ctx context.Context, user User) error
+	UpdateUserProfile(ctx context.Context, userID int, payload UpdateProfilePayload) (*User, error)
+	UpdateUserPassword(ctx context.Context, userID int, hashedPassword string) error
+	ListUsers(ctx context.Context) ([]*UserLookup, error)
 }
 
 type GoalTaskStore interface {
-	CreateGoal(ownerID int, payload CreateGoalPayload) (*Goal, error)
-	UpdateGoal(goalID, ownerID int, payload CreateGoalPayload) (*Goal, error)
-	DeleteGoal(goalID, ownerID int) error
-	GetGoalsByOwner(ownerID int) ([]*GoalWithTasks, error)
-	GetGoalWithTasks(goalID, ownerID int) (*GoalWithTasks, error)
-	GetUsersWithCurrentTasks() ([]*UserTasksBoard, error)
-	CreateTask(goalID, creatorID int, payload CreateTaskPayload) (*Task, error)
-	UpdateTask(taskID, requesterID int, payload UpdateTaskPayload) (*Task, error)
-	DeleteTask(taskID, requesterID int) error
-	AssignTask(taskID, requesterID int, payload AssignTaskPayload) (*Task, error)
-	GetAssignedTasks(userID int) ([]*Task, error)
-	ListUsers() ([]*UserLookup, error)
+	CreateGoal(ctx context.Context, ownerID int, payload CreateGoalPayload) (*Goal, error)
+	UpdateGoal(ctx context.Context, goalID, ownerID int, payload CreateGoalPayload) (*Goal, error)
+	DeleteGoal(ctx context.Context, goalID, ownerID int) error
+	GetGoalsByOwner(ctx context.Context, ownerID int) ([]*GoalWithTasks, error)
+	GetGoalWithTasks(ctx context.Context, goalID, ownerID int) (*GoalWithTasks, error)
+	GetUsersWithCurrentTasks(ctx context.Context) ([]*UserTasksBoard, error)
+	CreateTask(ctx context.Context, goalID, creatorID int, payload CreateTaskPayload) (*Task, error)
+	MoveTask(ctx context.Context, taskID, requesterID int, payload MoveTaskPayload) (*Task, error)
+	UpdateTask(ctx context.Context, taskID, requesterID int, payload UpdateTaskPayload) (*Task, error)
+	DeleteTask(ctx context.Context, taskID, requesterID int) error
+	AssignTask(ctx context.Context, taskID, requesterID int, payload AssignTaskPayload) (*Task, error)
+	GetAssignedTasks(ctx context.Context, userID int) ([]*Task, error)
+	ListUsers(ctx context.Context) ([]*UserLookup, error)
+}
+
+type ExportStore interface {
+	StreamGoals(ctx context.Context, userID int, fn func(*GoalWithTasks) error) error
+	ExportInstance(ctx context.Context, users func(*InstanceUser) error, goals func(*InstanceGoal) error) error
+	ImportInstance(ctx context.Context, snapshot InstanceSnapshot) (*ImportSummary, error)
+}
+
+type ImportStore interface {
+	CreateGoalsWithTasks(ctx context.Context, ownerID int, goals []ImportedGoal) ([]int, error)
+}
+
+// CalendarStore keeps one calendar feed token per user. Only a hash of the
+// token is stored.
+type CalendarStore interface {
+	SetCalendarToken(ctx context.Context, userID int, tokenHash string) error
+	DeleteCalendarToken(ctx context.Context, userID int) error
+	GetUserIDByCalendarToken(ctx context.Context, tokenHash string) (int, error)
+}
+
+// RecurrenceStore backs the recurring task scheduler.
+type RecurrenceStore interface {
+	// WithRecurrenceLock runs fn while holding a lock shared by every server
+	// using the database. It returns false without calling fn when the lock
+	// is held elsewhere.
+	WithRecurrenceLock(ctx context.Context, fn func(ctx context.Context) error) (bool, error)
+	// PendingRecurringTasks lists, in id order after afterID, recurring tasks
+	// without a next occurrence that are completed or due on or before today.
+	PendingRecurringTasks(ctx context.Context, today string, afterID, limit int) ([]*Task, error)
+	// CreateNextOccurrence copies the task as an open task due on dueDate and
+	// marks the task as recurred, in one transaction. It returns nil when the
+	// task has already recurred or no longer exists.
+	CreateNextOccurrence(ctx context.Context, taskID int, dueDate string) (*Task, error)
+}
+
+// TemplateStore keeps goal templates. Templates are private to the user who
+// created them.
+type TemplateStore interface {
+	ListTemplates(ctx context.Context, ownerID int) ([]*Template, error)
+	GetTemplate(ctx context.Context, templateID, ownerID int) (*Template, error)
+	CreateTemplate(ctx context.Context, ownerID int, payload CreateTemplatePayload) (*Template, error)
+	DeleteTemplate(ctx context.Context, templateID, ownerID int) error
+}
+
+// TimeStore keeps time logged on tasks. An entry without an end is a running
+// timer; each user has at most one. Reports count finished entries only.
+type TimeStore interface {
+	StartTimer(ctx context.Context, userID, taskID int, startedAt time.Time, note string) (*TimeEntry, error)
+	StopTimer(ctx context.Context, userID int, endedAt time.Time) (*TimeEntry, error)
+	GetRunningTimer(ctx context.Context, userID int) (*TimeEntry, error)
+	AddTimeEntry(ctx context.Context, userID, taskID int, startedAt time.Time, seconds int, note string) (*TimeEntry, error)
+	ListTimeEntries(ctx context.Context, taskID int) ([]*TimeEntry, error)
+	DeleteTimeEntry(ctx context.Context, entryID, userID int) error
+	// GoalTimeReport and UserTimeReport count entries started in [from, to).
+	GoalTimeReport(ctx context.Context, goalID int, from, to time.Time) (*GoalTimeReport, error)
+	UserTimeReport(ctx context.Context, userID int, from, to time.Time) (*UserTimeReport, error)
+}
+
+// ReportStore computes the team workload reports. Completions count by
+// completed_at in [from, to).
+type ReportStore interface {
+	CompletedPerWeek(ctx context.Context, from, to time.Time) ([]*WeeklyCompletion, error)
+	OpenTasksByPriority(ctx context.Context) ([]*PriorityCount, error)
+	CycleTime(ctx context.Context, from, to time.Time) (*CycleTimeReport, error)
+	// GoalBurnUp returns one point per UTC day from the day of from to the
+	// day of to, inclusive.
+	GoalBurnUp(ctx context.Context, goalID int, from, to time.Time) ([]*BurnUpPoint, error)
+	// OverloadedUsers returns the users with more than threshold open
+	// assigned tasks.
+	OverloadedUsers(ctx context.Context, threshold int) ([]*UserLoad, error)
+}
+
+// NotificationStore keeps in-app notifications and each user's delivery
+// preferences. Notifications are created by the stores that change tasks
+// and goals, in the same transaction as the change.
+type NotificationStore interface {
+	ListNotifications(ctx context.Context, userID int, query NotificationQuery) (*NotificationList, error)
+	MarkNotificationRead(ctx context.Context, notificationID, userID int) error
+	MarkAllNotificationsRead(ctx context.Context, userID int) error
+	// GetNotificationPreferences returns a preference for every event type.
+	GetNotificationPreferences(ctx context.Context, userID int) ([]NotificationPreference, error)
+	SetNotificationPreferences(ctx context.Context, userID int, preferences []NotificationPreference) ([]NotificationPreference, error)
+}
+
+// NotificationOutbox backs the notification dispatcher, which reminds
+// assignees of due tasks and emails notifications.
+type NotificationOutbox interface {
+	// NotifyDueSoon notifies the assignees of open tasks due between from
+	// and through (YYYY-MM-DD, inclusive), once per due date, and returns
+	// how many tasks it notified about.
+	NotifyDueSoon(ctx context.Context, from, through string) (int, error)
+	// PendingEmails lists, in id order after afterID, email notifications
+	// that have not been sent and are not waiting to be retried after now.
+	PendingEmails(ctx context.Context, now time.Time, afterID, limit int) ([]*NotificationEmail, error)
+	// ClaimEmail marks the email as sent at sentAt. It returns false when
+	// the email was already claimed, by this server or another.
+	ClaimEmail(ctx context.Context, notificationID int, sentAt time.Time) (bool, error)
+	// ReleaseEmail marks a claimed email as unsent after a failed send,
+	// counts the attempt and holds the email back until retryAt.
+	ReleaseEmail(ctx context.Context, notificationID int, retryAt time.Time) error
+}
+
+// DigestStore keeps each user's digest settings and builds the digest.
+type DigestStore interface {
+	// GetDigestSettings returns the default settings when the user has not
+	// chosen any.
+	GetDigestSettings(ctx context.Context, userID int) (*DigestSettings, error)
+	SetDigestSettings(ctx context.Context, userID int, settings DigestSettings) (*DigestSettings, error)
+	// BuildDigest gathers the user's open assigned tasks, the tasks assigned
+	// to them since since and the tasks completed since since on goals they
+	// own. Tasks due before today (YYYY-MM-DD) are overdue.
+	BuildDigest(ctx context.Context, userID int, since time.Time, today string) (*Digest, error)
+}
+
+// DigestOutbox backs the digest scheduler, which emails each opted-in user
+// their digest.
+type DigestOutbox interface {
+	// DigestRecipients lists, in user id order after afterID, the users
+	// whose digest is not off.
+	DigestRecipients(ctx context.Context, afterID, limit int) ([]*DigestRecipient, error)
+	BuildDigest(ctx context.Context, userID int, since time.Time, today string) (*Digest, error)
+	// ClaimDigest records the digest of the local date sentOn (YYYY-MM-DD)
+	// as sent at sentAt. It returns false when the digest of that date or a
+	// later one was already claimed, by this server or another.
+	ClaimDigest(ctx context.Context, userID int, sentOn string, sentAt time.Time) (bool, error)
+	// ReleaseDigest restores the previous send of a claimed digest so that
+	// it is retried.
+	ReleaseDigest(ctx context.Context, userID int, lastSentOn *string, lastSentAt *time.Time) error
+}
+
+type HealthStore interface {
+	Ping(ctx context.Context) error
+	MigrationVersion(ctx context.Context) (version uint, dirty bool, err error)
 }
 
 type Goal struct {
-	ID          int       `json:"id"`
-	Title       string    `json:"title"`
-	Description string    `json:"description"`
-	Priority    string    `json:"priority"`
-	Status      string    `json:"status"`
-	OwnerID     int       `json:"ownerId"`
-	OwnerName   string    `json:"ownerName,omitempty"`
-	CreatedAt   time.Time `json:"createdAt"`
+	ID          int     `json:"id"`
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Priority    string  `json:"priority"`
+	Status      string  `json:"status"`
+	OwnerID     int     `json:"ownerId"`
+	OwnerName   string  `json:"ownerName,omitempty"`
+	DueDate     *string `json:"dueDate,omitempty"`
+	// AutoStatus derives Status from the goal's tasks: todo until one is
+	// completed, then in_progress, and achieved once all are.
+	AutoStatus bool `json:"autoStatus"`
+	// ProgressWeight is how tasks count towards Progress: count, priority
+	// or estimate.
+	ProgressWeight string    `json:"progressWeight"`
+	CreatedAt      time.Time `json:"createdAt"`
 }
 
 type GoalWithTasks struct {
 	Goal
-	Tasks []*Task `json:"tasks"`
+	// Progress is set by GetGoalsByOwner and GetGoalWithTasks.
+	Progress *GoalProgress `json:"progress,omitempty"`
+	// EstimateMinutes and SpentMinutes total the tasks' estimates and the
+	// finished time entries on them. Only GetGoalWithTasks reports them.
+	EstimateMinutes *int    `json:"estimateMinutes,omitempty"`
+	SpentMinutes    *int    `json:"spentMinutes,omitempty"`
+	Tasks           []*Task `json:"tasks"`
 }
 
 type CreateGoalPayload struct {
 	Title       string `json:"title" validate:"required,min=3,max=255"`
 	Description string `json:"description" validate:"max=2000"`
 	Priority    string `json:"priority" validate:"required,oneof=high medium low"`
-	Status      string `json:"status" validate:"required,oneof=todo in_progress achieved"`
+	// Status is ignored, and may be omitted, when AutoStatus is set.
+	Status string `json:"status" validate:"required_unless=AutoStatus true,omitempty,oneof=todo in_progress achieved"`
+	// DueDate is a calendar date (YYYY-MM-DD); nil or omitted clears it.
+	DueDate        *string `json:"dueDate,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	AutoStatus     bool    `json:"autoStatus"`
+	ProgressWeight string  `json:"progressWeight,omitempty" validate:"omitempty,oneof=count priority estimate"`
+}
+
+// GoalProgress rolls up a goal's tasks. Percent is the completed share of
+// the tasks' weight, rounded down, so it is 100 only when all are done.
+type GoalProgress struct {
+	CompletedTasks int `json:"completedTasks"`
+	TotalTasks     int `json:"totalTasks"`
+	Percent        int `json:"percent"`
 }
 
 type Task struct {
-	ID            int       `json:"id"`
-	GoalID        int       `json:"goalId"`
-	GoalTitle     string    `json:"goalTitle,omitempty"`
-	Title         string    `json:"title"`
-	Description   string    `json:"description"`
-	Priority      string    `json:"priority"`
-	IsCompleted   bool      `json:"isCompleted"`
-	AssigneeID    *int      `json:"assigneeId,omitempty"`
-	AssigneeName  string    `json:"assigneeName,omitempty"`
-	CreatedBy     int       `json:"createdBy"`
-	CreatedByName string    `json:"createdByName,omitempty"`
-	CreatedAt     time.Time `json:"createdAt"`
+	ID              int       `json:"id"`
+	GoalID          int       `json:"goalId"`
+	GoalTitle       string    `json:"goalTitle,omitempty"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Priority        string    `json:"priority"`
+	IsCompleted     bool      `json:"isCompleted"`
+	AssigneeID      *int      `json:"assigneeId,omitempty"`
+	AssigneeName    string    `json:"assigneeName,omitempty"`
+	CreatedBy       int       `json:"createdBy"`
+	CreatedByName   string    `json:"createdByName,omitempty"`
+	DueDate         *string   `json:"dueDate,omitempty"`
+	Recurrence      string    `json:"recurrence,omitempty"`
+	EstimateMinutes *int      `json:"estimateMinutes,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
 }
 
 type CreateTaskPayload struct {
-	Title       string `json:"title" validate:"required,min=3,max=255"`
-	Description string `json:"description" validate:"max=2000"`
-	Priority    string `json:"priority" validate:"required,oneof=high medium low"`
-	AssigneeID  *int   `json:"assigneeId,omitempty"`
+	Title       string  `json:"title" validate:"required,min=3,max=255"`
+	Description string  `json:"description" validate:"max=2000"`
+	Priority    string  `json:"priority" validate:"required,oneof=high medium low"`
+	AssigneeID  *int    `json:"assigneeId,omitempty"`
+	DueDate     *string `json:"dueDate,omitempty" validate:"required_with=Recurrence,omitempty,datetime=2006-01-02"`
+	// Recurrence is an RRULE such as FREQ=WEEKLY;BYDAY=MO. It needs a due
+	// date, which anchors the series.
+	Recurrence      string `json:"recurrence,omitempty" validate:"omitempty,rrule"`
+	EstimateMinutes *int   `json:"estimateMinutes,omitempty" validate:"omitempty,min=0,max=100000"`
 }
 
 type UpdateTaskPayload struct {
-	GoalID      int    `json:"goalId" validate:"required,min=1"`
-	Title       string `json:"title" validate:"required,min=3,max=255"`
-	Description string `json:"description" validate:"max=2000"`
-	Priority    string `json:"priority" validate:"required,oneof=high medium low"`
-	IsCompleted bool   `json:"isCompleted"`
-	AssigneeID  *int   `json:"assigneeId,omitempty"`
+	GoalID          int     `json:"goalId" validate:"required,min=1"`
+	Title           string  `json:"title" validate:"required,min=3,max=255"`
+	Description     string  `json:"description" validate:"max=2000"`
+	Priority        string  `json:"priority" validate:"required,oneof=high medium low"`
+	IsCompleted     bool    `json:"isCompleted"`
+	AssigneeID      *int    `json:"assigneeId,omitempty"`
+	DueDate         *string `json:"dueDate,omitempty" validate:"required_with=Recurrence,omitempty,datetime=2006-01-02"`
+	Recurrence      string  `json:"recurrence,omitempty" validate:"omitempty,rrule"`
+	EstimateMinutes *int    `json:"estimateMinutes,omitempty" validate:"omitempty,min=0,max=100000"`
 }
 
 type AssignTaskPayload struct {
 	AssigneeID *int `json:"assigneeId"`
 }
 
+// MoveTaskPayload places a task after AfterID, before BeforeID, or between
+// the two. List is goal, the goal's task list, or assigned, the
+// requester's assigned list.
+type MoveTaskPayload struct {
+	List     string `json:"list" validate:"omitempty,oneof=goal assigned"`
+	AfterID  *int   `json:"afterId" validate:"required_without=BeforeID,omitempty,min=1"`
+	BeforeID *int   `json:"beforeId" validate:"required_without=AfterID,omitempty,min=1"`
+}
+
 type UserLookup struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
@@ -134,3 +317,374 @@ type LoginUserPayload struct {
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required"`
 }
+
+// InstanceSnapshot is the admin export document. IDs only link records
+// within the document; import assigns new ones.
+type InstanceSnapshot struct {
+	Version    int            `json:"version" validate:"required,eq=1"`
+	ExportedAt time.Time      `json:"exportedAt"`
+	Users      []InstanceUser `json:"users" validate:"dive"`
+	Goals      []InstanceGoal `json:"goals" validate:"dive"`
+}
+
+type InstanceUser struct {
+	ID           int       `json:"id" validate:"required"`
+	FirstName    string    `json:"firstName" validate:"required,max=255"`
+	LastName     string    `json:"lastName" validate:"required,max=255"`
+	Email        string    `json:"email" validate:"required,email,max=255"`
+	PasswordHash string    `json:"passwordHash" validate:"required,max=255"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type InstanceGoal struct {
+	ID          int     `json:"id" validate:"required"`
+	Title       string  `json:"title" validate:"required,max=255"`
+	Description string  `json:"description"`
+	Priority    string  `json:"priority" validate:"required,oneof=high medium low"`
+	Status      string  `json:"status" validate:"required,oneof=todo in_progress achieved"`
+	OwnerID     int     `json:"ownerId" validate:"required"`
+	DueDate     *string `json:"dueDate,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	AutoStatus  bool    `json:"autoStatus,omitempty"`
+	// ProgressWeight defaults to count when omitted.
+	ProgressWeight string         `json:"progressWeight,omitempty" validate:"omitempty,oneof=count priority estimate"`
+	CreatedAt      time.Time      `json:"createdAt"`
+	Tasks          []InstanceTask `json:"tasks" validate:"dive"`
+}
+
+type InstanceTask struct {
+	ID              int     `json:"id" validate:"required"`
+	Title           string  `json:"title" validate:"required,max=255"`
+	Description     string  `json:"description"`
+	Priority        string  `json:"priority" validate:"required,oneof=high medium low"`
+	IsCompleted     bool    `json:"isCompleted"`
+	AssigneeID      *int    `json:"assigneeId,omitempty"`
+	CreatedBy       int     `json:"createdBy" validate:"required"`
+	DueDate         *string `json:"dueDate,omitempty" validate:"required_with=Recurrence,omitempty,datetime=2006-01-02"`
+	Recurrence      string  `json:"recurrence,omitempty" validate:"omitempty,rrule"`
+	Recurred        bool    `json:"recurred,omitempty"`
+	EstimateMinutes *int    `json:"estimateMinutes,omitempty" validate:"omitempty,min=0,max=100000"`
+	// Position and AssigneePosition order the task on its goal's and its
+	// assignee's lists.
+	Position         float64  `json:"position,omitempty"`
+	AssigneePosition *float64 `json:"assigneePosition,omitempty"`
+	// TimeEntries are the finished entries on the task.
+	TimeEntries []InstanceTimeEntry `json:"timeEntries,omitempty" validate:"dive"`
+	CreatedAt   time.Time           `json:"createdAt"`
+	// CompletedAt is when the task was completed; nil for open tasks and
+	// tasks completed before completion times were recorded.
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+type InstanceTimeEntry struct {
+	UserID          int       `json:"userId" validate:"required"`
+	StartedAt       time.Time `json:"startedAt" validate:"required"`
+	DurationSeconds int       `json:"durationSeconds" validate:"min=0"`
+	Note            string    `json:"note,omitempty"`
+}
+
+type ImportSummary struct {
+	UsersCreated int `json:"usersCreated"`
+	UsersMatched int `json:"usersMatched"`
+	Goals        int `json:"goals"`
+	Tasks        int `json:"tasks"`
+	TimeEntries  int `json:"timeEntries"`
+}
+
+// ImportedGoal is a goal parsed from another tool's export, created by
+// ImportStore.CreateGoalsWithTasks together with its tasks.
+type ImportedGoal struct {
+	Goal  CreateGoalPayload
+	Tasks []ImportedTask
+}
+
+type ImportedTask struct {
+	Task        CreateTaskPayload
+	IsCompleted bool
+	// CompletedAt is when the other tool recorded the task as completed;
+	// nil when it does not say.
+	CompletedAt *time.Time
+}
+
+// TimeEntry is time a user logged on a task. EndedAt and DurationSeconds
+// are nil while the entry is a running timer.
+type TimeEntry struct {
+	ID              int        `json:"id"`
+	TaskID          int        `json:"taskId"`
+	UserID          int        `json:"userId"`
+	UserName        string     `json:"userName,omitempty"`
+	StartedAt       time.Time  `json:"startedAt"`
+	EndedAt         *time.Time `json:"endedAt,omitempty"`
+	DurationSeconds *int       `json:"durationSeconds,omitempty"`
+	Note            string     `json:"note"`
+}
+
+type StartTimerPayload struct {
+	Note string `json:"note" validate:"max=1000"`
+}
+
+type CreateTimeEntryPayload struct {
+	Minutes int `json:"minutes" validate:"required,min=1,max=1440"`
+	// StartedAt defaults to Minutes before now.
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+	Note      string     `json:"note" validate:"max=1000"`
+}
+
+// GoalTimeReport is the time spent on a goal's tasks, per task and per user.
+// Minutes are rounded to the nearest minute.
+type GoalTimeReport struct {
+	GoalID          int            `json:"goalId"`
+	Title           string         `json:"title"`
+	EstimateMinutes int            `json:"estimateMinutes"`
+	SpentMinutes    int            `json:"spentMinutes"`
+	Tasks           []TaskTime     `json:"tasks"`
+	Users           []UserTimeItem `json:"users"`
+}
+
+type TaskTime struct {
+	TaskID          int    `json:"taskId"`
+	Title           string `json:"title"`
+	EstimateMinutes *int   `json:"estimateMinutes,omitempty"`
+	SpentMinutes    int    `json:"spentMinutes"`
+}
+
+type UserTimeItem struct {
+	UserID       int    `json:"userId"`
+	Name         string `json:"name"`
+	SpentMinutes int    `json:"spentMinutes"`
+}
+
+// UserTimeReport is the time a user spent, per goal.
+type UserTimeReport struct {
+	UserID       int            `json:"userId"`
+	Name         string         `json:"name"`
+	SpentMinutes int            `json:"spentMinutes"`
+	Goals        []GoalTimeItem `json:"goals"`
+}
+
+type GoalTimeItem struct {
+	GoalID       int    `json:"goalId"`
+	Title        string `json:"title"`
+	SpentMinutes int    `json:"spentMinutes"`
+}
+
+// WeeklyCompletion counts the tasks a user completed in the week starting
+// WeekStart, a Monday in UTC.
+type WeeklyCompletion struct {
+	UserID    int    `json:"userId"`
+	Name      string `json:"name"`
+	WeekStart string `json:"weekStart"`
+	Completed int    `json:"completed"`
+}
+
+type PriorityCount struct {
+	Priority  string `json:"priority"`
+	OpenTasks int    `json:"openTasks"`
+}
+
+// CycleTimeReport averages the time from creation to completion of the
+// tasks completed in the range.
+type CycleTimeReport struct {
+	CompletedTasks int `json:"completedTasks"`
+	AverageSeconds int `json:"averageSeconds"`
+}
+
+// BurnUpPoint is the size of a goal and its completed part at the end of
+// Date (UTC).
+type BurnUpPoint struct {
+	Date           string `json:"date"`
+	TotalTasks     int    `json:"totalTasks"`
+	CompletedTasks int    `json:"completedTasks"`
+}
+
+type UserLoad struct {
+	UserID    int    `json:"userId"`
+	Name      string `json:"name"`
+	OpenTasks int    `json:"openTasks"`
+}
+
+type OverloadReport struct {
+	Threshold int         `json:"threshold"`
+	Users     []*UserLoad `json:"users"`
+}
+
+// Template is a goal with tasks that can be instantiated repeatedly. Titles
+// and descriptions may contain placeholders, written as the name in double
+// braces, and due dates are kept as day offsets from the date the template
+// is instantiated.
+type Template struct {
+	ID          int            `json:"id"`
+	OwnerID     int            `json:"ownerId"`
+	Name        string         `json:"name"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Priority    string         `json:"priority"`
+	DueInDays   *int           `json:"dueInDays,omitempty"`
+	Tasks       []TemplateTask `json:"tasks"`
+	CreatedAt   time.Time      `json:"createdAt"`
+}
+
+type TemplateTask struct {
+	Title       string `json:"title" validate:"required,min=3,max=255"`
+	Description string `json:"description" validate:"max=2000"`
+	Priority    string `json:"priority" validate:"required,oneof=high medium low"`
+	DueInDays   *int   `json:"dueInDays,omitempty" validate:"omitempty,min=0,max=3650"`
+}
+
+// CreateTemplatePayload defines a template. With FromGoalID the goal fields
+// and tasks are copied from that goal instead and must be omitted.
+type CreateTemplatePayload struct {
+	Name       string `json:"name" validate:"required,min=3,max=255"`
+	FromGoalID *int   `json:"fromGoalId,omitempty" validate:"omitempty,min=1"`
+	// Placeholders maps placeholder names to the text they stand for in the
+	// saved goal, e.g. {"client": "Acme"}; every occurrence of the text
+	// becomes the placeholder.
+	Placeholders map[string]string `json:"placeholders,omitempty" validate:"dive,keys,placeholder,endkeys,required"`
+	Title        string            `json:"title" validate:"required,min=3,max=255"`
+	Description  string            `json:"description" validate:"max=2000"`
+	Priority     string            `json:"priority" validate:"required,oneof=high medium low"`
+	DueInDays    *int              `json:"dueInDays,omitempty" validate:"omitempty,min=0,max=3650"`
+	Tasks        []TemplateTask    `json:"tasks" validate:"max=200,dive"`
+}
+
+type InstantiateTemplatePayload struct {
+	// StartDate is the date due-date offsets count from (YYYY-MM-DD); it
+	// defaults to today in UTC.
+	StartDate *string `json:"startDate,omitempty" validate:"omitempty,datetime=2006-01-02"`
+	// Values maps placeholder names to their replacement, e.g.
+	// {"client": "Acme"}. Every placeholder the template uses needs one.
+	Values map[string]string `json:"values,omitempty" validate:"dive,keys,placeholder,endkeys,max=255"`
+}
+
+// ImportReport describes what POST /imports/{source} created, or would
+// create when dryRun is set.
+type ImportReport struct {
+	Source        string             `json:"source"`
+	DryRun        bool               `json:"dryRun"`
+	Goals         []ImportReportGoal `json:"goals"`
+	UnmappedUsers []string           `json:"unmappedUsers"`
+	Errors        []FieldError       `json:"errors"`
+}
+
+type ImportReportGoal struct {
+	// ID is the created goal's id; zero in a dry run.
+	ID        int    `json:"id,omitempty"`
+	Ref       string `json:"ref"`
+	Title     string `json:"title"`
+	Status    string `json:"status"`
+	Priority  string `json:"priority"`
+	Tasks     int    `json:"tasks"`
+	Assigned  int    `json:"assigned"`
+	Completed int    `json:"completed"`
+}
+
+// Notification tells a user about an assignment, mention, comment, due
+// date or achieved goal. Message is rendered from the other fields.
+type Notification struct {
+	ID        int        `json:"id"`
+	Type      string     `json:"type"`
+	Message   string     `json:"message"`
+	ActorID   *int       `json:"actorId,omitempty"`
+	ActorName string     `json:"actorName,omitempty"`
+	GoalID    int        `json:"goalId"`
+	GoalTitle string     `json:"goalTitle"`
+	TaskID    *int       `json:"taskId,omitempty"`
+	TaskTitle string     `json:"taskTitle,omitempty"`
+	DueDate   *string    `json:"dueDate,omitempty"`
+	ReadAt    *time.Time `json:"readAt,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+type NotificationList struct {
+	UnreadCount   int             `json:"unreadCount"`
+	Notifications []*Notification `json:"notifications"`
+}
+
+// NotificationQuery pages through notifications newest first: BeforeID,
+// when set, continues after the last notification of the previous page.
+type NotificationQuery struct {
+	UnreadOnly bool
+	BeforeID   int
+	Limit      int
+}
+
+// NotificationEmail is a notification to be delivered by email.
+type NotificationEmail struct {
+	Notification
+	To            string
+	RecipientName string
+	// Attempts counts the failed sends so far.
+	Attempts int
+}
+
+type NotificationPreference struct {
+	Type    string `json:"type" validate:"required,oneof=assigned mentioned due_soon goal_achieved"`
+	Channel string `json:"channel" validate:"required,oneof=in_app email none"`
+}
+
+type UpdateNotificationPreferencesPayload struct {
+	Preferences []NotificationPreference `json:"preferences" validate:"required,min=1,dive"`
+}
+
+// DigestSettings choose when a user's digest is emailed: daily, or weekly
+// on Weekday (0 is Sunday), once SendHour has struck in TimeZone.
+type DigestSettings struct {
+	Frequency  string     `json:"frequency"`
+	TimeZone   string     `json:"timeZone"`
+	SendHour   int        `json:"sendHour"`
+	Weekday    int        `json:"weekday"`
+	LastSentOn *string    `json:"lastSentOn,omitempty"`
+	LastSentAt *time.Time `json:"lastSentAt,omitempty"`
+}
+
+type UpdateDigestSettingsPayload struct {
+	Frequency string `json:"frequency" validate:"required,oneof=off daily weekly"`
+	TimeZone  string `json:"timeZone" validate:"required,max=64"`
+	SendHour  int    `json:"sendHour" validate:"min=0,max=23"`
+	Weekday   int    `json:"weekday" validate:"min=0,max=6"`
+}
+
+// DigestRecipient is a user due a digest, with their settings.
+type DigestRecipient struct {
+	UserID int
+	Email  string
+	Name   string
+	DigestSettings
+}
+
+// Digest summarizes a user's work since Since. Goals groups their open
+// assigned tasks; NewlyAssigned lists those assigned since Since and
+// Completed the tasks completed since Since on goals they own.
+type Digest struct {
+	Name          string              `json:"name"`
+	Frequency     string              `json:"frequency"`
+	Date          string              `json:"date"`
+	Since         time.Time           `json:"since"`
+	Goals         []*DigestGoal       `json:"goals"`
+	NewlyAssigned []*DigestTask       `json:"newlyAssigned"`
+	Completed     []*DigestCompletion `json:"completed"`
+}
+
+type DigestGoal struct {
+	ID    int           `json:"id"`
+	Title string        `json:"title"`
+	Tasks []*DigestTask `json:"tasks"`
+}
+
+type DigestTask struct {
+	ID        int     `json:"id"`
+	GoalID    int     `json:"goalId"`
+	GoalTitle string  `json:"goalTitle"`
+	Title     string  `json:"title"`
+	Priority  string  `json:"priority"`
+	DueDate   *string `json:"dueDate,omitempty"`
+	Overdue   bool    `json:"overdue"`
+}
+
+type DigestCompletion struct {
+	TaskID       int       `json:"taskId"`
+	Title        string    `json:"title"`
+	GoalID       int       `json:"goalId"`
+	GoalTitle    string    `json:"goalTitle"`
+	AssigneeName string    `json:"assigneeName,omitempty"`
+	CompletedAt  time.Time `json:"completedAt"`
+}
@@ -0,0 +1,142 @@
+package utils
+
+import (
+	"VyacheslavKuchumov/test-backend/types"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+const ProblemContentType = "application/problem+json"
+
+// Stable machine-readable error codes returned in the "code" member.
+const (
+	CodeBadRequest         = "bad_request"
+	CodeInvalidJSON        = "invalid_json"
+	CodeValidationFailed   = "validation_failed"
+	CodeInvalidCredentials = "invalid_credentials"
+	CodeUnauthorized       = "unauthorized"
+	CodeForbidden          = "forbidden"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodeTimeout            = "timeout"
+	CodeInternal           = "internal_error"
+)
+
+// WriteProblem writes a problem details response. The request ID set on the
+// response headers by the logging middleware is echoed in the body.
+func WriteProblem(w http.ResponseWriter, status int, code, detail string, fieldErrors []types.FieldError) {
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(types.ErrorResponse{
+		Type:        "about:blank",
+		Title:       http.StatusText(status),
+		Status:      status,
+		Detail:      detail,
+		Code:        code,
+		FieldErrors: fieldErrors,
+		RequestID:   w.Header().Get("X-Request-ID"),
+	})
+}
+
+// WriteValidationError writes a 400 problem with one field error per failed
+// validator rule. Errors that are not validator.ValidationErrors are reported
+// as a generic bad request.
+func WriteValidationError(w http.ResponseWriter, err error) {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		WriteError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	WriteProblem(w, http.StatusBadRequest, CodeValidationFailed, "invalid payload", FieldErrors(validationErrors))
+}
+
+func FieldErrors(validationErrors validator.ValidationErrors) []types.FieldError {
+	fieldErrors := make([]types.FieldError, 0, len(validationErrors))
+	for _, fe := range validationErrors {
+		fieldErrors = append(fieldErrors, types.FieldError{
+			Field:   fe.Field(),
+			Code:    fe.Tag(),
+			Message: fieldErrorMessage(fe),
+		})
+	}
+	return fieldErrors
+}
+
+func fieldErrorMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email address"
+	case "min":
+		return fmt.Sprintf("must be at least %s%s", fe.Param(), sizeUnit(fe.Kind()))
+	case "max":
+		return fmt.Sprintf("must be at most %s%s", fe.Param(), sizeUnit(fe.Kind()))
+	case "datetime":
+		return "must be a date in YYYY-MM-DD format"
+	case "required_with":
+		return "is required when " + lowerFirst(fe.Param()) + " is set"
+	case "required_without":
+		return "is required when " + lowerFirst(fe.Param()) + " is not set"
+	case "required_unless":
+		// The param is the other field and its value, e.g. "AutoStatus true".
+		field, value, _ := strings.Cut(fe.Param(), " ")
+		return "is required unless " + lowerFirst(field) + " is " + value
+	case "rrule":
+		return "must be FREQ=DAILY, FREQ=WEEKLY;BYDAY=<weekdays> or FREQ=MONTHLY;BYMONTHDAY=<day>"
+	case "placeholder":
+		return "must start with a letter and contain only letters, digits and underscores"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(fe.Param()), ", ")
+	default:
+		return fmt.Sprintf("failed %q validation", fe.Tag())
+	}
+}
+
+func codeForStatus(status int) string {
+	switch status {
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	default:
+		if status >= http.StatusInternalServerError {
+			return CodeInternal
+		}
+		return CodeBadRequest
+	}
+}
+
+// lowerFirst turns a Go field name such as "Recurrence" into the JSON name
+// clients send.
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
+// sizeUnit names what min and max count for a field of the given kind.
+func sizeUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return " items"
+	default:
+		return ""
+	}
+}
@@ -1,14 +1,44 @@
 package utils
 
 import (
+	"VyacheslavKuchumov/test-backend/recurrence"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"regexp"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 )
 
-var Validate = validator.New()
+var Validate = newValidator()
+
+func newValidator() *validator.Validate {
+	v := validator.New()
+	// Report field errors using the JSON names clients send.
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	v.RegisterValidation("rrule", func(fl validator.FieldLevel) bool {
+		_, err := recurrence.Parse(fl.Field().String())
+		return err == nil
+	})
+	v.RegisterValidation("placeholder", func(fl validator.FieldLevel) bool {
+		return placeholderName.MatchString(fl.Field().String())
+	})
+	return v
+}
+
+// placeholderName matches the names allowed in template placeholders such
+// as {{client}}.
+var placeholderName = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]{0,63}$`)
 
 func ParseJSON(r *http.Request, payload any) error {
 	if r.Body == nil {
@@ -24,6 +54,30 @@ func WriteJSON(w http.ResponseWriter, status int, v any) error {
 	return json.NewEncoder(w).Encode(v)
 }
 
+// WriteError writes err as a problem details response, deriving the code
+// from the status.
 func WriteError(w http.ResponseWriter, status int, err error) {
-	WriteJSON(w, status, map[string]string{"error": err.Error()})
+	WriteProblem(w, status, codeForStatus(status), err.Error(), nil)
+}
+
+// WriteJSONError reports a request body that could not be decoded.
+func WriteJSONError(w http.ResponseWriter, err error) {
+	WriteProblem(w, http.StatusBadRequest, CodeInvalidJSON, err.Error(), nil)
+}
+
+// WriteStoreError reports an error returned by a store: with the status
+// statuses gives the sentinel error it wraps, as a timeout when the request's
+// database deadline passed, and as an internal error otherwise.
+func WriteStoreError(w http.ResponseWriter, err error, statuses map[error]int) {
+	for target, status := range statuses {
+		if errors.Is(err, target) {
+			WriteError(w, status, err)
+			return
+		}
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		WriteError(w, http.StatusGatewayTimeout, err)
+		return
+	}
+	WriteError(w, http.StatusInternalServerError, err)
 }
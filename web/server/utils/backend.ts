@@ -36,7 +36,8 @@ export async function callBackend(
     const statusCode = Number(error?.statusCode || error?.status || error?.response?.status) || 500
     const payload = error?.data || error?.response?._data || {}
     const statusMessage =
-      payload?.error ||
+      payload?.detail ||
+      payload?.title ||
       payload?.message ||
       error?.statusMessage ||
       error?.message ||
@@ -44,7 +45,12 @@ export async function callBackend(
 
     throw createError({
       statusCode,
-      statusMessage
+      statusMessage,
+      data: {
+        code: payload?.code,
+        fieldErrors: payload?.fieldErrors || [],
+        requestId: payload?.requestId
+      }
     })
   }
 }